@@ -57,7 +57,9 @@ type Counters struct {
 func (f HttpFetcher) Fetch(id string, url string, host string, pollId uint64, pollFinishedChan chan<- uint64) {
 	log.Debugf("poll %v %v fetch start\n", pollId, time.Now())
 	req, err := http.NewRequest("GET", url, nil)
-	// TODO: change this to use f.Headers. -jse
+	for name, value := range f.Headers {
+		req.Header.Set(name, value)
+	}
 	req.Header.Set("User-Agent", f.UserAgent)
 	req.Header.Set("Connection", "keep-alive")
 	req.Host = host
@@ -31,6 +31,7 @@ const (
 	Ok       Status = 0
 	Warning  Status = 1
 	Critical Status = 2
+	Unknown  Status = 3
 )
 
 func Exit(status Status, msg string) {
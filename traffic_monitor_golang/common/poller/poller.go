@@ -20,9 +20,11 @@ package poller
  */
 
 import (
+	"context"
 	"math/rand"
 	"net/http"
 	"os"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -34,8 +36,9 @@ import (
 	to "github.com/apache/incubator-trafficcontrol/traffic_ops/client"
 )
 
+// Poller polls until the given context is done. Poll blocks, and doesn't return until the poller has stopped, including any in-flight polls.
 type Poller interface {
-	Poll()
+	Poll(ctx context.Context)
 }
 
 type HttpPoller struct {
@@ -117,11 +120,17 @@ func NewMonitorConfig(interval time.Duration) MonitorConfigPoller {
 	}
 }
 
-func (p MonitorConfigPoller) Poll() {
+// Poll polls Traffic Ops for the monitor config until the given context is done. If the poller stops for any other reason, the process exits, because the Monitor can't run without a MonitorConfigPoller.
+func (p MonitorConfigPoller) Poll(ctx context.Context) {
 	tick := time.NewTicker(p.Interval)
 	defer tick.Stop()
 	defer func() {
-		if err := recover(); err != nil {
+		err := recover()
+		if err == nil && ctx.Err() != nil {
+			log.Infof("MonitorConfigPoller: stopped\n")
+			return
+		}
+		if err != nil {
 			log.Errorf("MonitorConfigPoller panic: %v\n", err)
 		} else {
 			log.Errorf("MonitorConfigPoller failed without panic\n")
@@ -130,6 +139,8 @@ func (p MonitorConfigPoller) Poll() {
 	}()
 	for {
 		select {
+		case <-ctx.Done():
+			return
 		case opsConfig := <-p.OpsConfigChannel:
 			log.Infof("MonitorConfigPoller: received new opsConfig: %v\n", opsConfig)
 			p.OpsConfig = opsConfig
@@ -156,7 +167,11 @@ func (p MonitorConfigPoller) Poll() {
 					log.Errorf("MonitorConfigPoller: %s\n %v\n", err, monitorConfig)
 				} else {
 					log.Debugln("MonitorConfigPoller: fetched monitorConfig")
-					p.ConfigChannel <- MonitorCfg{CDN: p.OpsConfig.CdnName, Cfg: *monitorConfig}
+					select {
+					case p.ConfigChannel <- MonitorCfg{CDN: p.OpsConfig.CdnName, Cfg: *monitorConfig}:
+					case <-ctx.Done():
+						return
+					}
 				}
 			} else {
 				log.Warnln("MonitorConfigPoller: skipping this iteration, Session is nil")
@@ -176,22 +191,31 @@ type HTTPPollInfo struct {
 	Handler  handler.Handler
 }
 
-func (p HttpPoller) Poll() {
+// Poll polls the URLs received on the ConfigChannel until the given context is done. When the context is done, Poll stops starting new polls, and returns when all in-flight polls have finished and been handled.
+func (p HttpPoller) Poll(ctx context.Context) {
 	if p.Config.noSleep {
 		log.Debugf("HttpPoller using InsomniacPoll\n")
-		p.InsomniacPoll()
+		p.InsomniacPoll(ctx)
 	} else {
 		log.Debugf("HttpPoller using SleepPoll\n")
-		p.SleepPoll()
+		p.SleepPoll(ctx)
 	}
 }
 
-func (p HttpPoller) SleepPoll() {
+func (p HttpPoller) SleepPoll(ctx context.Context) {
 	// iterationCount := uint64(0)
 	// iterationCount++ // on tick<:
 	// case p.TickChan <- iterationCount:
 	killChans := map[string]chan<- struct{}{}
-	for newConfig := range p.ConfigChannel {
+	pollers := sync.WaitGroup{}
+	defer pollers.Wait()
+	for {
+		var newConfig HttpPollerConfig
+		select {
+		case <-ctx.Done():
+			return
+		case newConfig = <-p.ConfigChannel:
+		}
 		deletions, additions := diffConfigs(p.Config, newConfig)
 		for _, id := range deletions {
 			killChan := killChans[id]
@@ -208,7 +232,11 @@ func (p HttpPoller) SleepPoll() {
 				fetcher.Client = &c // copy the client, so we don't change other fetchers.
 				fetcher.Client.Timeout = info.Timeout
 			}
-			go sleepPoller(info.Interval, info.ID, info.URL, info.Host, fetcher, kill)
+			pollers.Add(1)
+			go func(info HTTPPollInfo) {
+				defer pollers.Done()
+				sleepPoller(ctx, info.Interval, info.ID, info.URL, info.Host, fetcher, kill)
+			}(info)
 		}
 		p.Config = newConfig
 	}
@@ -224,9 +252,15 @@ func mustDie(die <-chan struct{}) bool {
 }
 
 // TODO iterationCount and/or p.TickChan?
-func sleepPoller(interval time.Duration, id string, url string, host string, fetcher fetcher.Fetcher, die <-chan struct{}) {
+func sleepPoller(ctx context.Context, interval time.Duration, id string, url string, host string, fetcher fetcher.Fetcher, die <-chan struct{}) {
 	pollSpread := time.Duration(rand.Float64()*float64(interval/time.Nanosecond)) * time.Nanosecond
-	time.Sleep(pollSpread)
+	select {
+	case <-time.After(pollSpread):
+	case <-die:
+		return
+	case <-ctx.Done():
+		return
+	}
 	tick := time.NewTicker(interval)
 	lastTime := time.Now()
 	for {
@@ -247,6 +281,9 @@ func sleepPoller(interval time.Duration, id string, url string, host string, fet
 		case <-die:
 			tick.Stop()
 			return
+		case <-ctx.Done():
+			tick.Stop()
+			return
 		}
 	}
 }
@@ -254,14 +291,22 @@ func sleepPoller(interval time.Duration, id string, url string, host string, fet
 const InsomniacPollerEmptySleepDuration = time.Millisecond * time.Duration(100)
 
 // InsomniacPoll polls using a single thread, which never sleeps. This exists to work around a bug observed in OpenStack CentOS 6.5 kernel 2.6.32 wherin sleep gets progressively slower. This should be removed and Poll() changed to call SleepPoll() when the bug is tracked down and fixed for production.
-func (p HttpPoller) InsomniacPoll() {
+func (p HttpPoller) InsomniacPoll(ctx context.Context) {
 	// iterationCount := uint64(0)
 	// iterationCount++ // on tick<:
 	// case p.TickChan <- iterationCount:
 	killChan := make(chan struct{})
 	pollRunning := false // TODO find less awkward way to not kill the first loop
 	pollerId := rand.Int63()
-	for newCfg := range p.ConfigChannel {
+	pollers := sync.WaitGroup{}
+	defer pollers.Wait()
+	for {
+		var newCfg HttpPollerConfig
+		select {
+		case <-ctx.Done():
+			return
+		case newCfg = <-p.ConfigChannel:
+		}
 		// TODO add a more efficient function than diffConfigs for this func, since we only need to know whether anything changed
 		deletions, additions := diffConfigs(p.Config, newCfg)
 		if len(deletions) == 0 && len(additions) == 0 {
@@ -269,7 +314,11 @@ func (p HttpPoller) InsomniacPoll() {
 		}
 
 		if pollRunning {
-			killChan <- struct{}{}
+			select {
+			case killChan <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
 		}
 		pollRunning = true
 
@@ -283,12 +332,17 @@ func (p HttpPoller) InsomniacPoll() {
 				Timeout:  pollCfg.Timeout,
 			})
 		}
-		go insomniacPoller(pollerId, polls, p.FetcherTemplate, killChan)
+		pollers.Add(1)
+		go func(polls []HTTPPollInfo) {
+			defer pollers.Done()
+			insomniacPoller(ctx, pollerId, polls, p.FetcherTemplate, killChan)
+		}(polls)
 		p.Config = newCfg
 	}
 }
 
-func insomniacPoller(pollerId int64, polls []HTTPPollInfo, fetcherTemplate fetcher.HttpFetcher, die <-chan struct{}) {
+// insomniacPoller polls the given polls until it receives on the die chan or the context is done, and then returns once all in-flight polls have finished.
+func insomniacPoller(ctx context.Context, pollerId int64, polls []HTTPPollInfo, fetcherTemplate fetcher.HttpFetcher, die <-chan struct{}) {
 	heap := Heap{PollerID: pollerId}
 	start := time.Now()
 	fetchers := map[string]fetcher.Fetcher{}
@@ -312,7 +366,9 @@ func insomniacPoller(pollerId int64, polls []HTTPPollInfo, fetcherTemplate fetch
 		return b
 	}
 
+	inFlight := sync.WaitGroup{}
 	poll := func(p HeapPollInfo) {
+		defer inFlight.Done()
 		start := time.Now()
 		pollId := atomic.AddUint64(&debugPollNum, 1)
 		// TODO change pollFinishedChan to callback, for performance
@@ -326,15 +382,17 @@ func insomniacPoller(pollerId int64, polls []HTTPPollInfo, fetcherTemplate fetch
 	}
 
 	for {
+		if mustDie(die) || ctx.Err() != nil {
+			inFlight.Wait()
+			return
+		}
 		p, ok := heap.Pop()
 		if !ok {
 			ThreadSleep(InsomniacPollerEmptySleepDuration)
 			continue
 		}
-		if mustDie(die) {
-			return
-		}
 		ThreadSleep(p.Next.Sub(time.Now()))
+		inFlight.Add(1)
 		go poll(p)
 	}
 }
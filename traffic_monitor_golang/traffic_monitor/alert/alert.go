@@ -0,0 +1,398 @@
+// Package alert evaluates configured alert rules against the Traffic Monitor's state, and sends notifications when alerts start firing and resolve.
+//
+// Each rule produces a condition per subject, e.g. per cache or per cachegroup. A condition must hold for the rule's duration before its alert fires. Firing alerts are deduplicated: notifiers are called once when an alert starts firing, again every repeat interval if one is configured, and once when it resolves. Alerts matching an active silence are tracked, but not notified.
+package alert
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	dsdata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/deliveryservicedata"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+)
+
+// RuleType is the kind of condition an alert rule checks.
+type RuleType string
+
+const (
+	// RuleTypeCacheDown fires for each cache which is unavailable, excluding caches whose Traffic Ops status is OFFLINE or ADMIN_DOWN.
+	RuleTypeCacheDown = RuleType("cache_down")
+	// RuleTypeCacheGroupAvailable fires for each cachegroup whose fraction of available caches is below the rule's threshold. OFFLINE and ADMIN_DOWN caches aren't counted.
+	RuleTypeCacheGroupAvailable = RuleType("cachegroup_available")
+	// RuleTypeDeliveryServiceThreshold fires for each delivery service whose total value of the rule's stat, e.g. `tps_5xx` or `kbps`, is above the rule's threshold.
+	RuleTypeDeliveryServiceThreshold = RuleType("deliveryservice_threshold")
+	// RuleTypePeerUnreachable fires for each peer Traffic Monitor which is in the peer set, but unavailable.
+	RuleTypePeerUnreachable = RuleType("peer_unreachable")
+	// RuleTypeTrafficOpsFetch fires when the most recent request to Traffic Ops failed.
+	RuleTypeTrafficOpsFetch = RuleType("traffic_ops_fetch")
+)
+
+// TrafficOpsSubject is the subject of RuleTypeTrafficOpsFetch alerts.
+const TrafficOpsSubject = "traffic_ops"
+
+// DefaultInterval is how often rules are evaluated, if the config interval is 0.
+const DefaultInterval = 10 * time.Second
+
+// Status is whether an alert is firing or resolved.
+type Status string
+
+const (
+	StatusFiring   = Status("firing")
+	StatusResolved = Status("resolved")
+)
+
+// Config is the alerting configuration. If there are no rules, alerting is disabled.
+// RepeatIntervalMs is how often notifiers are called again for an alert which is still firing; if 0, they are only called when it starts firing and when it resolves.
+type Config struct {
+	IntervalMs       uint64           `json:"interval_ms"`
+	RepeatIntervalMs uint64           `json:"repeat_interval_ms"`
+	Rules            []Rule           `json:"rules"`
+	Silences         []Silence        `json:"silences"`
+	Notifiers        []NotifierConfig `json:"notifiers"`
+}
+
+// Interval returns how often rules are evaluated.
+func (c Config) Interval() time.Duration {
+	if c.IntervalMs == 0 {
+		return DefaultInterval
+	}
+	return time.Duration(c.IntervalMs) * time.Millisecond
+}
+
+// Rule is an alert rule. Threshold is used by the cachegroup and delivery service rules, and Stat by the delivery service rule. If Match is not empty, it's a regular expression, and only subjects matching it alert. ForMs is how long the condition must hold before the alert fires. Notifiers are the names of the notifiers to call; if empty, all notifiers are called.
+type Rule struct {
+	Name      string   `json:"name"`
+	Type      RuleType `json:"type"`
+	ForMs     uint64   `json:"for_ms"`
+	Threshold float64  `json:"threshold"`
+	Stat      string   `json:"stat"`
+	Match     string   `json:"match"`
+	Severity  string   `json:"severity"`
+	Notifiers []string `json:"notifiers"`
+}
+
+// Silence suppresses notifications for the alerts of the given rule and subject between Start and End. An empty Rule or Subject matches all rules or subjects. A zero Start or End is unbounded.
+type Silence struct {
+	Rule    string    `json:"rule"`
+	Subject string    `json:"subject"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Comment string    `json:"comment"`
+}
+
+// Active returns whether the silence is in effect at the given time.
+func (s Silence) Active(t time.Time) bool {
+	return (s.Start.IsZero() || !t.Before(s.Start)) && (s.End.IsZero() || t.Before(s.End))
+}
+
+// Matches returns whether the silence applies to the given alert at the given time.
+func (s Silence) Matches(a Alert, t time.Time) bool {
+	return s.Active(t) && (s.Rule == "" || s.Rule == a.Rule) && (s.Subject == "" || s.Subject == a.Subject)
+}
+
+// Alert is a firing or resolved alert. Value is the value which triggered the alert, e.g. the fraction of available caches, and is 0 for rules without a threshold. EndsAt is nil while the alert is firing.
+type Alert struct {
+	Rule        string     `json:"rule"`
+	Type        RuleType   `json:"type"`
+	Severity    string     `json:"severity"`
+	Subject     string     `json:"subject"`
+	Description string     `json:"description"`
+	Value       float64    `json:"value"`
+	Status      Status     `json:"status"`
+	StartsAt    time.Time  `json:"startsAt"`
+	EndsAt      *time.Time `json:"endsAt,omitempty"`
+}
+
+// Notification is an alert to send, and the names of the notifiers to send it to.
+type Notification struct {
+	Alert     Alert
+	Notifiers []string
+}
+
+// State is the Traffic Monitor state which rules are evaluated against. TrafficOpsErr is the error of the most recent request to Traffic Ops, or nil if it succeeded.
+type State struct {
+	Caches           map[enum.CacheName]CacheState
+	DeliveryServices map[enum.DeliveryServiceName]dsdata.StatCacheStats
+	Peers            map[enum.TrafficMonitorName]PeerState
+	TrafficOpsErr    error
+}
+
+// CacheState is the state of a single cache. Status is the cache's Traffic Ops status.
+type CacheState struct {
+	CacheGroup enum.CacheGroupName
+	Status     enum.CacheStatus
+	Available  bool
+}
+
+// PeerState is the state of a peer Traffic Monitor. Online is whether the peer is in the peer set, from Traffic Ops, the static peer list or gossip, and Available whether this monitor's polls of it are succeeding.
+type PeerState struct {
+	Online    bool
+	Available bool
+}
+
+// condition is a rule's condition which holds for a single subject.
+type condition struct {
+	subject     string
+	value       float64
+	description string
+}
+
+// rule is a validated Rule, with its compiled match expression.
+type rule struct {
+	Rule
+	match *regexp.Regexp
+}
+
+// newRule validates the given rule, returning an error if it's invalid.
+func newRule(r Rule, notifiers map[string]struct{}) (rule, error) {
+	if r.Name == "" {
+		return rule{}, fmt.Errorf("rule missing name")
+	}
+	switch r.Type {
+	case RuleTypeCacheDown, RuleTypePeerUnreachable, RuleTypeTrafficOpsFetch:
+	case RuleTypeCacheGroupAvailable:
+		if r.Threshold <= 0 || r.Threshold > 1 {
+			return rule{}, fmt.Errorf("rule '%v' threshold %v must be greater than 0 and at most 1", r.Name, r.Threshold)
+		}
+	case RuleTypeDeliveryServiceThreshold:
+		if _, ok := (dsdata.StatCacheStats{}).Numeric(r.Stat); !ok {
+			return rule{}, fmt.Errorf("rule '%v' has invalid stat '%v'", r.Name, r.Stat)
+		}
+	default:
+		return rule{}, fmt.Errorf("rule '%v' has invalid type '%v'", r.Name, r.Type)
+	}
+	for _, name := range r.Notifiers {
+		if _, ok := notifiers[name]; !ok {
+			return rule{}, fmt.Errorf("rule '%v' has unknown notifier '%v'", r.Name, name)
+		}
+	}
+	compiled := rule{Rule: r}
+	if r.Match != "" {
+		match, err := regexp.Compile(r.Match)
+		if err != nil {
+			return rule{}, fmt.Errorf("rule '%v' has invalid match: %v", r.Name, err)
+		}
+		compiled.match = match
+	}
+	return compiled, nil
+}
+
+// conditions returns the conditions of this rule which hold in the given state, for subjects matching the rule.
+func (r rule) conditions(s State) []condition {
+	conds := []condition{}
+	add := func(subject string, value float64, description string) {
+		if r.match != nil && !r.match.MatchString(subject) {
+			return
+		}
+		conds = append(conds, condition{subject: subject, value: value, description: description})
+	}
+
+	switch r.Type {
+	case RuleTypeCacheDown:
+		for name, cache := range s.Caches {
+			if monitored(cache.Status) && !cache.Available {
+				add(string(name), 0, fmt.Sprintf("cache %v is unavailable", name))
+			}
+		}
+	case RuleTypeCacheGroupAvailable:
+		total := map[enum.CacheGroupName]int{}
+		available := map[enum.CacheGroupName]int{}
+		for _, cache := range s.Caches {
+			if !monitored(cache.Status) {
+				continue
+			}
+			total[cache.CacheGroup]++
+			if cache.Available {
+				available[cache.CacheGroup]++
+			}
+		}
+		for cacheGroup, n := range total {
+			if fraction := float64(available[cacheGroup]) / float64(n); fraction < r.Threshold {
+				add(string(cacheGroup), fraction, fmt.Sprintf("cachegroup %v has %v of %v caches available (%.0f%%), below %.0f%%", cacheGroup, available[cacheGroup], n, fraction*100, r.Threshold*100))
+			}
+		}
+	case RuleTypeDeliveryServiceThreshold:
+		for name, stats := range s.DeliveryServices {
+			if v, _ := stats.Numeric(r.Stat); v > r.Threshold {
+				add(string(name), v, fmt.Sprintf("delivery service %v %v is %.2f, above %v", name, r.Stat, v, r.Threshold))
+			}
+		}
+	case RuleTypePeerUnreachable:
+		for name, peer := range s.Peers {
+			if peer.Online && !peer.Available {
+				add(string(name), 0, fmt.Sprintf("peer %v is unreachable", name))
+			}
+		}
+	case RuleTypeTrafficOpsFetch:
+		if s.TrafficOpsErr != nil {
+			add(TrafficOpsSubject, 0, fmt.Sprintf("requesting Traffic Ops: %v", s.TrafficOpsErr))
+		}
+	}
+	return conds
+}
+
+// monitored returns whether caches with the given Traffic Ops status are expected to be available. OFFLINE and ADMIN_DOWN caches are not.
+func monitored(status enum.CacheStatus) bool {
+	return status != enum.CacheStatusOffline && status != enum.CacheStatusAdminDown
+}
+
+// key identifies an alert, by rule and subject.
+type key struct {
+	rule    string
+	subject string
+}
+
+// firingAlert is a firing alert, and when its notifiers were last called. If notified is zero, they haven't been called, e.g. because the alert was silenced.
+type firingAlert struct {
+	alert    Alert
+	notified time.Time
+}
+
+// Engine evaluates alert rules, tracking pending and firing alerts between evaluations. It is NOT threadsafe, and MUST NOT be used by multiple goroutines.
+type Engine struct {
+	rules          []rule
+	silences       []Silence
+	repeatInterval time.Duration
+	pending        map[key]time.Time
+	firing         map[key]*firingAlert
+}
+
+// NewEngine returns a new Engine for the given config, or an error if any rule is invalid.
+func NewEngine(cfg Config) (*Engine, error) {
+	notifiers := map[string]struct{}{}
+	for _, n := range cfg.Notifiers {
+		notifiers[n.Name] = struct{}{}
+	}
+	e := &Engine{
+		silences:       cfg.Silences,
+		repeatInterval: time.Duration(cfg.RepeatIntervalMs) * time.Millisecond,
+		pending:        map[key]time.Time{},
+		firing:         map[key]*firingAlert{},
+	}
+	names := map[string]struct{}{}
+	for _, r := range cfg.Rules {
+		if _, ok := names[r.Name]; ok {
+			return nil, fmt.Errorf("duplicate rule name '%v'", r.Name)
+		}
+		names[r.Name] = struct{}{}
+		compiled, err := newRule(r, notifiers)
+		if err != nil {
+			return nil, err
+		}
+		e.rules = append(e.rules, compiled)
+	}
+	return e, nil
+}
+
+// Evaluate evaluates all rules against the given state at the given time, and returns the notifications to send, sorted by rule and subject.
+func (e *Engine) Evaluate(s State, now time.Time) []Notification {
+	notifications := []Notification{}
+	active := map[key]struct{}{}
+	for _, r := range e.rules {
+		for _, cond := range r.conditions(s) {
+			k := key{rule: r.Name, subject: cond.subject}
+			active[k] = struct{}{}
+			if f, ok := e.firing[k]; ok {
+				f.alert.Value = cond.value
+				f.alert.Description = cond.description
+				continue
+			}
+			since, ok := e.pending[k]
+			if !ok {
+				since = now
+				e.pending[k] = since
+			}
+			if now.Sub(since) < time.Duration(r.ForMs)*time.Millisecond {
+				continue
+			}
+			delete(e.pending, k)
+			e.firing[k] = &firingAlert{alert: Alert{Rule: r.Name, Type: r.Type, Severity: r.Severity, Subject: cond.subject, Description: cond.description, Value: cond.value, Status: StatusFiring, StartsAt: since}}
+		}
+	}
+
+	for k := range e.pending {
+		if _, ok := active[k]; !ok {
+			delete(e.pending, k)
+		}
+	}
+
+	for _, r := range e.rules {
+		for k, f := range e.firing {
+			if k.rule != r.Name {
+				continue
+			}
+			if _, ok := active[k]; !ok {
+				delete(e.firing, k)
+				// Resolutions are sent for alerts whose firing was notified, even if they're now silenced, so receivers never see a resolution without its alert, nor keep an alert which never resolves. Silences only stop firing notifications.
+				if f.notified.IsZero() {
+					continue
+				}
+				resolved := f.alert
+				resolved.Status = StatusResolved
+				endsAt := now
+				resolved.EndsAt = &endsAt
+				notifications = append(notifications, Notification{Alert: resolved, Notifiers: r.Notifiers})
+				continue
+			}
+			if e.silenced(f.alert, now) {
+				continue
+			}
+			if f.notified.IsZero() || (e.repeatInterval > 0 && now.Sub(f.notified) >= e.repeatInterval) {
+				f.notified = now
+				notifications = append(notifications, Notification{Alert: f.alert, Notifiers: r.Notifiers})
+			}
+		}
+	}
+
+	sort.Sort(notificationsByKey(notifications))
+	return notifications
+}
+
+func (e *Engine) silenced(a Alert, now time.Time) bool {
+	for _, s := range e.silences {
+		if s.Matches(a, now) {
+			return true
+		}
+	}
+	return false
+}
+
+type alertsByKey []Alert
+
+func (a alertsByKey) Len() int      { return len(a) }
+func (a alertsByKey) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a alertsByKey) Less(i, j int) bool {
+	if a[i].Rule == a[j].Rule {
+		return a[i].Subject < a[j].Subject
+	}
+	return a[i].Rule < a[j].Rule
+}
+
+type notificationsByKey []Notification
+
+func (n notificationsByKey) Len() int      { return len(n) }
+func (n notificationsByKey) Swap(i, j int) { n[i], n[j] = n[j], n[i] }
+func (n notificationsByKey) Less(i, j int) bool {
+	return alertsByKey{n[i].Alert, n[j].Alert}.Less(0, 1)
+}
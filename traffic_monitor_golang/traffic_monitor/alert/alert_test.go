@@ -0,0 +1,203 @@
+package alert
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	dsdata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/deliveryservicedata"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+)
+
+func TestNewEngineInvalid(t *testing.T) {
+	invalid := map[string]Config{
+		"missing name":        {Rules: []Rule{{Type: RuleTypeCacheDown}}},
+		"invalid type":        {Rules: []Rule{{Name: "a", Type: "nonexistent"}}},
+		"threshold too large": {Rules: []Rule{{Name: "a", Type: RuleTypeCacheGroupAvailable, Threshold: 1.5}}},
+		"invalid stat":        {Rules: []Rule{{Name: "a", Type: RuleTypeDeliveryServiceThreshold, Stat: "nonexistent"}}},
+		"invalid match":       {Rules: []Rule{{Name: "a", Type: RuleTypeCacheDown, Match: "("}}},
+		"unknown notifier":    {Rules: []Rule{{Name: "a", Type: RuleTypeCacheDown, Notifiers: []string{"nonexistent"}}}},
+		"duplicate name":      {Rules: []Rule{{Name: "a", Type: RuleTypeCacheDown}, {Name: "a", Type: RuleTypePeerUnreachable}}},
+	}
+	for name, cfg := range invalid {
+		if _, err := NewEngine(cfg); err == nil {
+			t.Errorf("NewEngine %v expected error, actual nil", name)
+		}
+	}
+}
+
+func TestEngineConditions(t *testing.T) {
+	e, err := NewEngine(Config{Rules: []Rule{
+		{Name: "cache-down", Type: RuleTypeCacheDown, Match: "^edge-"},
+		{Name: "cg-available", Type: RuleTypeCacheGroupAvailable, Threshold: 0.5},
+		{Name: "ds-5xx", Type: RuleTypeDeliveryServiceThreshold, Stat: "tps_5xx", Threshold: 10},
+		{Name: "peer", Type: RuleTypePeerUnreachable},
+		{Name: "to", Type: RuleTypeTrafficOpsFetch},
+	}})
+	if err != nil {
+		t.Fatalf("NewEngine expected nil error, actual %v", err)
+	}
+	s := State{
+		Caches: map[enum.CacheName]CacheState{
+			"edge-a":  {CacheGroup: "cg-1", Status: enum.CacheStatusReported, Available: false},
+			"edge-b":  {CacheGroup: "cg-1", Status: enum.CacheStatusReported, Available: false},
+			"edge-c":  {CacheGroup: "cg-1", Status: enum.CacheStatusOnline, Available: true},
+			"edge-d":  {CacheGroup: "cg-1", Status: enum.CacheStatusAdminDown, Available: false},
+			"mid-a":   {CacheGroup: "cg-2", Status: enum.CacheStatusReported, Available: false},
+			"mid-b":   {CacheGroup: "cg-2", Status: enum.CacheStatusReported, Available: true},
+			"edge-cg": {CacheGroup: "cg-3", Status: enum.CacheStatusReported, Available: true},
+		},
+		DeliveryServices: map[enum.DeliveryServiceName]dsdata.StatCacheStats{
+			"ds-a": {Tps5xx: dsdata.StatFloat{Value: 11}},
+			"ds-b": {Tps5xx: dsdata.StatFloat{Value: 10}},
+		},
+		Peers: map[enum.TrafficMonitorName]PeerState{
+			"tm-a": {Online: true, Available: false},
+			"tm-b": {Online: false, Available: false},
+			"tm-c": {Online: true, Available: true},
+		},
+		TrafficOpsErr: errors.New("connection refused"),
+	}
+
+	notifications := e.Evaluate(s, time.Now())
+	expected := []struct {
+		rule    string
+		subject string
+	}{
+		{"cache-down", "edge-a"},
+		{"cache-down", "edge-b"},
+		{"cg-available", "cg-1"},
+		{"ds-5xx", "ds-a"},
+		{"peer", "tm-a"},
+		{"to", TrafficOpsSubject},
+	}
+	if len(notifications) != len(expected) {
+		t.Fatalf("Evaluate expected %v notifications, actual %+v", len(expected), notifications)
+	}
+	for i, n := range notifications {
+		if n.Alert.Rule != expected[i].rule || n.Alert.Subject != expected[i].subject || n.Alert.Status != StatusFiring {
+			t.Errorf("Evaluate notification %v expected firing %v %v, actual %+v", i, expected[i].rule, expected[i].subject, n.Alert)
+		}
+	}
+	if v := notifications[2].Alert.Value; v != 1.0/3.0 {
+		t.Errorf("Evaluate cachegroup available value expected %v, actual %v", 1.0/3.0, v)
+	}
+}
+
+func TestEngineLifecycle(t *testing.T) {
+	e, err := NewEngine(Config{
+		RepeatIntervalMs: uint64(time.Minute / time.Millisecond),
+		Rules:            []Rule{{Name: "cache-down", Type: RuleTypeCacheDown, ForMs: uint64(2 * time.Minute / time.Millisecond), Severity: "critical"}},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine expected nil error, actual %v", err)
+	}
+	down := State{Caches: map[enum.CacheName]CacheState{"edge-a": {Status: enum.CacheStatusReported}}}
+	up := State{Caches: map[enum.CacheName]CacheState{"edge-a": {Status: enum.CacheStatusReported, Available: true}}}
+	start := time.Now()
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	if n := e.Evaluate(down, at(0)); len(n) != 0 {
+		t.Errorf("Evaluate before for duration expected no notifications, actual %+v", n)
+	}
+	if n := e.Evaluate(up, at(time.Minute)); len(n) != 0 {
+		t.Errorf("Evaluate recovered while pending expected no notifications, actual %+v", n)
+	}
+	e.Evaluate(down, at(2*time.Minute))
+	if n := e.Evaluate(down, at(3*time.Minute)); len(n) != 0 {
+		t.Errorf("Evaluate pending reset by recovery expected no notifications, actual %+v", n)
+	}
+
+	n := e.Evaluate(down, at(4*time.Minute))
+	if len(n) != 1 || n[0].Alert.Status != StatusFiring || !n[0].Alert.StartsAt.Equal(at(2*time.Minute)) || n[0].Alert.Severity != "critical" {
+		t.Fatalf("Evaluate after for duration expected firing alert starting at pending time, actual %+v", n)
+	}
+	if b, err := json.Marshal(n[0].Alert); err != nil || strings.Contains(string(b), "endsAt") {
+		t.Errorf("firing alert JSON expected no endsAt, actual %s error %v", b, err)
+	}
+	if n := e.Evaluate(down, at(4*time.Minute+30*time.Second)); len(n) != 0 {
+		t.Errorf("Evaluate still firing expected deduplicated, actual %+v", n)
+	}
+	if _, ok := e.firing[key{rule: "cache-down", subject: "edge-a"}]; len(e.firing) != 1 || !ok {
+		t.Errorf("Evaluate still firing expected edge-a firing, actual %+v", e.firing)
+	}
+	if n := e.Evaluate(down, at(5*time.Minute)); len(n) != 1 || n[0].Alert.Status != StatusFiring {
+		t.Errorf("Evaluate after repeat interval expected firing notification, actual %+v", n)
+	}
+
+	n = e.Evaluate(up, at(6*time.Minute))
+	if len(n) != 1 || n[0].Alert.Status != StatusResolved || n[0].Alert.EndsAt == nil || !n[0].Alert.EndsAt.Equal(at(6*time.Minute)) {
+		t.Errorf("Evaluate recovered expected resolved notification, actual %+v", n)
+	}
+	if len(e.firing) != 0 {
+		t.Errorf("Evaluate recovered expected none firing, actual %+v", e.firing)
+	}
+}
+
+func TestEngineSilence(t *testing.T) {
+	start := time.Now()
+	e, err := NewEngine(Config{
+		Rules:    []Rule{{Name: "cache-down", Type: RuleTypeCacheDown}},
+		Silences: []Silence{{Rule: "cache-down", Subject: "edge-a", Start: start, End: start.Add(time.Hour)}},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine expected nil error, actual %v", err)
+	}
+	down := State{Caches: map[enum.CacheName]CacheState{
+		"edge-a": {Status: enum.CacheStatusReported},
+		"edge-b": {Status: enum.CacheStatusReported},
+	}}
+
+	n := e.Evaluate(down, start)
+	if len(n) != 1 || n[0].Alert.Subject != "edge-b" {
+		t.Errorf("Evaluate with silence expected only edge-b, actual %+v", n)
+	}
+	if len(e.firing) != 2 {
+		t.Errorf("Evaluate with silence expected silenced alerts firing, actual %+v", e.firing)
+	}
+	if n := e.Evaluate(down, start.Add(time.Hour)); len(n) != 1 || n[0].Alert.Subject != "edge-a" {
+		t.Errorf("Evaluate after silence ends expected edge-a, actual %+v", n)
+	}
+}
+
+func TestEngineSilenceResolved(t *testing.T) {
+	start := time.Now()
+	e, err := NewEngine(Config{Rules: []Rule{{Name: "cache-down", Type: RuleTypeCacheDown}}})
+	if err != nil {
+		t.Fatalf("NewEngine expected nil error, actual %v", err)
+	}
+	down := State{Caches: map[enum.CacheName]CacheState{"edge-a": {Status: enum.CacheStatusReported}}}
+	up := State{Caches: map[enum.CacheName]CacheState{"edge-a": {Status: enum.CacheStatusReported, Available: true}}}
+
+	if n := e.Evaluate(down, start); len(n) != 1 || n[0].Alert.Status != StatusFiring {
+		t.Fatalf("Evaluate down expected firing notification, actual %+v", n)
+	}
+	e.silences = []Silence{{Rule: "cache-down", Subject: "edge-a", Start: start, End: start.Add(time.Hour)}}
+	if n := e.Evaluate(down, start.Add(time.Minute)); len(n) != 0 {
+		t.Errorf("Evaluate silenced expected no notifications, actual %+v", n)
+	}
+	if n := e.Evaluate(up, start.Add(2*time.Minute)); len(n) != 1 || n[0].Alert.Status != StatusResolved {
+		t.Errorf("Evaluate recovered while silenced expected resolved notification, actual %+v", n)
+	}
+}
@@ -0,0 +1,219 @@
+package alert
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log/syslog"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// NotifierType is the kind of receiver a notifier sends alerts to.
+type NotifierType string
+
+const (
+	// NotifierTypeWebhook POSTs each alert as JSON to the notifier's URL. Any response other than 2xx is an error.
+	NotifierTypeWebhook = NotifierType("webhook")
+	// NotifierTypeSyslog writes each alert to syslog, at a priority from the alert's severity. If the notifier's Network and Address are empty, the local syslog is used.
+	NotifierTypeSyslog = NotifierType("syslog")
+	// NotifierTypeExec runs the notifier's command for each alert, with the alert as JSON on stdin, and its fields in ALERT_ environment variables. A non-zero exit is an error.
+	NotifierTypeExec = NotifierType("exec")
+)
+
+// DefaultNotifierTimeout is how long a notifier may take to send an alert, if the config timeout is 0.
+const DefaultNotifierTimeout = 10 * time.Second
+
+// NotifierConfig is the configuration of a single notifier. URL is used by webhook notifiers; Network, Address and Tag by syslog notifiers; and Command and Args by exec notifiers.
+type NotifierConfig struct {
+	Name      string       `json:"name"`
+	Type      NotifierType `json:"type"`
+	URL       string       `json:"url"`
+	Network   string       `json:"network"`
+	Address   string       `json:"address"`
+	Tag       string       `json:"tag"`
+	Command   string       `json:"command"`
+	Args      []string     `json:"args"`
+	TimeoutMs uint64       `json:"timeout_ms"`
+}
+
+func (c NotifierConfig) timeout() time.Duration {
+	if c.TimeoutMs == 0 {
+		return DefaultNotifierTimeout
+	}
+	return time.Duration(c.TimeoutMs) * time.Millisecond
+}
+
+// Notifier sends alerts to a receiver.
+type Notifier interface {
+	Notify(ctx context.Context, a Alert) error
+}
+
+// NewNotifiers creates the configured notifiers, returning them by name, or an error if any config is invalid.
+func NewNotifiers(cfgs []NotifierConfig) (map[string]Notifier, error) {
+	notifiers := map[string]Notifier{}
+	for _, cfg := range cfgs {
+		if cfg.Name == "" {
+			return nil, fmt.Errorf("notifier missing name")
+		}
+		if _, ok := notifiers[cfg.Name]; ok {
+			return nil, fmt.Errorf("duplicate notifier name '%v'", cfg.Name)
+		}
+		n, err := NewNotifier(cfg)
+		if err != nil {
+			return nil, err
+		}
+		notifiers[cfg.Name] = n
+	}
+	return notifiers, nil
+}
+
+// NewNotifier creates a notifier from the given config, or returns an error if the config is invalid.
+func NewNotifier(cfg NotifierConfig) (Notifier, error) {
+	switch cfg.Type {
+	case NotifierTypeWebhook:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("webhook notifier '%v' missing url", cfg.Name)
+		}
+		return WebhookNotifier{URL: cfg.URL, Client: &http.Client{Timeout: cfg.timeout()}}, nil
+	case NotifierTypeSyslog:
+		if (cfg.Network == "") != (cfg.Address == "") {
+			return nil, fmt.Errorf("syslog notifier '%v' must have both network and address, or neither", cfg.Name)
+		}
+		return SyslogNotifier{Network: cfg.Network, Address: cfg.Address, Tag: cfg.Tag}, nil
+	case NotifierTypeExec:
+		if cfg.Command == "" {
+			return nil, fmt.Errorf("exec notifier '%v' missing command", cfg.Name)
+		}
+		return ExecNotifier{Command: cfg.Command, Args: cfg.Args, Timeout: cfg.timeout()}, nil
+	}
+	return nil, fmt.Errorf("notifier '%v' has invalid type '%v'", cfg.Name, cfg.Type)
+}
+
+// WebhookNotifier POSTs alerts as JSON to a URL.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// Notify POSTs the alert to the webhook's URL.
+func (n WebhookNotifier) Notify(ctx context.Context, a Alert) error {
+	body, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("marshalling alert: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating webhook request: %v", err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting webhook: %v", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("posting webhook: %v returned %v", n.URL, resp.Status)
+	}
+	return nil
+}
+
+// SyslogNotifier writes alerts to syslog. Each alert dials syslog anew, so a syslog restart doesn't break notifications.
+type SyslogNotifier struct {
+	Network string
+	Address string
+	Tag     string
+}
+
+// Notify writes the alert to syslog.
+func (n SyslogNotifier) Notify(ctx context.Context, a Alert) error {
+	w, err := syslog.Dial(n.Network, n.Address, syslogPriority(a)|syslog.LOG_DAEMON, n.Tag)
+	if err != nil {
+		return fmt.Errorf("dialing syslog: %v", err)
+	}
+	defer w.Close()
+	if _, err := w.Write([]byte(alertMessage(a))); err != nil {
+		return fmt.Errorf("writing syslog: %v", err)
+	}
+	return nil
+}
+
+// syslogPriority returns the syslog severity of the given alert. Resolved alerts are always LOG_NOTICE.
+func syslogPriority(a Alert) syslog.Priority {
+	if a.Status == StatusResolved {
+		return syslog.LOG_NOTICE
+	}
+	switch a.Severity {
+	case "critical":
+		return syslog.LOG_CRIT
+	case "warning":
+		return syslog.LOG_WARNING
+	case "info":
+		return syslog.LOG_INFO
+	}
+	return syslog.LOG_ERR
+}
+
+// alertMessage returns a single line human-readable description of the alert.
+func alertMessage(a Alert) string {
+	return fmt.Sprintf("%v rule=%v subject=%v severity=%v: %v", a.Status, a.Rule, a.Subject, a.Severity, a.Description)
+}
+
+// ExecNotifier runs a command for each alert.
+type ExecNotifier struct {
+	Command string
+	Args    []string
+	Timeout time.Duration
+}
+
+// Notify runs the notifier's command, with the alert as JSON on stdin, and in the environment variables ALERT_RULE, ALERT_TYPE, ALERT_SEVERITY, ALERT_SUBJECT, ALERT_STATUS, ALERT_VALUE and ALERT_DESCRIPTION.
+func (n ExecNotifier) Notify(ctx context.Context, a Alert) error {
+	body, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("marshalling alert: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, n.Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, n.Command, n.Args...)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(),
+		"ALERT_RULE="+a.Rule,
+		"ALERT_TYPE="+string(a.Type),
+		"ALERT_SEVERITY="+a.Severity,
+		"ALERT_SUBJECT="+a.Subject,
+		"ALERT_STATUS="+string(a.Status),
+		"ALERT_VALUE="+strconv.FormatFloat(a.Value, 'f', -1, 64),
+		"ALERT_DESCRIPTION="+a.Description,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("running %v: %v: %s", n.Command, err, bytes.TrimSpace(out))
+	}
+	return nil
+}
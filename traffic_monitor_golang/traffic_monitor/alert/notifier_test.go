@@ -0,0 +1,151 @@
+package alert
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testAlert = Alert{Rule: "cache-down", Type: RuleTypeCacheDown, Severity: "critical", Subject: "edge-a", Description: "cache edge-a is unavailable", Status: StatusFiring, StartsAt: time.Unix(1500000000, 0).UTC()}
+
+func TestNewNotifiersInvalid(t *testing.T) {
+	invalid := map[string][]NotifierConfig{
+		"missing name":    {{Type: NotifierTypeSyslog}},
+		"duplicate name":  {{Name: "a", Type: NotifierTypeSyslog}, {Name: "a", Type: NotifierTypeSyslog}},
+		"invalid type":    {{Name: "a", Type: "nonexistent"}},
+		"webhook no url":  {{Name: "a", Type: NotifierTypeWebhook}},
+		"syslog no net":   {{Name: "a", Type: NotifierTypeSyslog, Address: "localhost:514"}},
+		"exec no command": {{Name: "a", Type: NotifierTypeExec}},
+	}
+	for name, cfgs := range invalid {
+		if _, err := NewNotifiers(cfgs); err == nil {
+			t.Errorf("NewNotifiers %v expected error, actual nil", name)
+		}
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	received := make(chan Alert, 1)
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a := Alert{}
+		if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+			t.Errorf("webhook receiver decoding expected nil error, actual %v", err)
+		}
+		received <- a
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	n, err := NewNotifier(NotifierConfig{Name: "hook", Type: NotifierTypeWebhook, URL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewNotifier expected nil error, actual %v", err)
+	}
+	if err := n.Notify(context.Background(), testAlert); err != nil {
+		t.Fatalf("webhook Notify expected nil error, actual %v", err)
+	}
+	if a := <-received; a.Rule != testAlert.Rule || a.Subject != testAlert.Subject || !a.StartsAt.Equal(testAlert.StartsAt) {
+		t.Errorf("webhook receiver expected %+v, actual %+v", testAlert, a)
+	}
+
+	status = http.StatusInternalServerError
+	if err := n.Notify(context.Background(), testAlert); err == nil {
+		t.Errorf("webhook Notify with receiver error expected error, actual nil")
+	}
+	<-received
+}
+
+func TestSyslogNotifier(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening for syslog expected nil error, actual %v", err)
+	}
+	defer conn.Close()
+
+	n, err := NewNotifier(NotifierConfig{Name: "syslog", Type: NotifierTypeSyslog, Network: "udp", Address: conn.LocalAddr().String(), Tag: "traffic_monitor"})
+	if err != nil {
+		t.Fatalf("NewNotifier expected nil error, actual %v", err)
+	}
+	if err := n.Notify(context.Background(), testAlert); err != nil {
+		t.Fatalf("syslog Notify expected nil error, actual %v", err)
+	}
+
+	buf := make([]byte, 2048)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	bufLen, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("reading syslog expected nil error, actual %v", err)
+	}
+	msg := string(buf[:bufLen])
+	// LOG_DAEMON|LOG_CRIT is 3*8+2
+	if !strings.HasPrefix(msg, "<26>") || !strings.Contains(msg, "traffic_monitor") || !strings.Contains(msg, "subject=edge-a") {
+		t.Errorf("syslog message expected <26> priority, tag and subject, actual '%v'", msg)
+	}
+}
+
+func TestExecNotifier(t *testing.T) {
+	dir, err := ioutil.TempDir("", "alert-exec")
+	if err != nil {
+		t.Fatalf("creating temp dir expected nil error, actual %v", err)
+	}
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "out")
+
+	n, err := NewNotifier(NotifierConfig{Name: "script", Type: NotifierTypeExec, Command: "/bin/sh", Args: []string{"-c", `echo "$ALERT_STATUS $ALERT_SUBJECT" > "$0" && cat >> "$0"`, out}})
+	if err != nil {
+		t.Fatalf("NewNotifier expected nil error, actual %v", err)
+	}
+	if err := n.Notify(context.Background(), testAlert); err != nil {
+		t.Fatalf("exec Notify expected nil error, actual %v", err)
+	}
+	written, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatalf("reading exec output expected nil error, actual %v", err)
+	}
+	lines := strings.SplitN(string(written), "\n", 2)
+	if lines[0] != "firing edge-a" {
+		t.Errorf("exec environment expected 'firing edge-a', actual '%v'", lines[0])
+	}
+	a := Alert{}
+	if len(lines) != 2 || json.Unmarshal([]byte(lines[1]), &a) != nil || a.Rule != testAlert.Rule {
+		t.Errorf("exec stdin expected alert JSON, actual '%v'", string(written))
+	}
+
+	n, _ = NewNotifier(NotifierConfig{Name: "fail", Type: NotifierTypeExec, Command: "/bin/sh", Args: []string{"-c", "exit 1"}})
+	if err := n.Notify(context.Background(), testAlert); err == nil {
+		t.Errorf("exec Notify with failing command expected error, actual nil")
+	}
+
+	n, _ = NewNotifier(NotifierConfig{Name: "slow", Type: NotifierTypeExec, Command: "/bin/sleep", Args: []string{"10"}, TimeoutMs: 100})
+	start := time.Now()
+	if err := n.Notify(context.Background(), testAlert); err == nil || time.Since(start) > 5*time.Second {
+		t.Errorf("exec Notify with slow command expected timeout error, actual %v after %v", err, time.Since(start))
+	}
+}
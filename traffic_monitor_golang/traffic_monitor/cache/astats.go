@@ -21,6 +21,9 @@ package cache
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 )
 
 // Astats contains ATS data returned from the Astats ATS plugin. This includes generic stats, as well as fixed system stats.
@@ -33,6 +36,7 @@ type Astats struct {
 type AstatsSystem struct {
 	InfName           string `json:"inf.name"`
 	InfSpeed          int    `json:"inf.speed"`
+	InfSpeeds         string `json:"inf.speeds"`
 	ProcNetDev        string `json:"proc.net.dev"`
 	ProcLoadavg       string `json:"proc.loadavg"`
 	ConfigLoadRequest int    `json:"configReloadRequests"`
@@ -49,3 +53,70 @@ func Unmarshal(body []byte) (Astats, error) {
 	err := json.Unmarshal(body, &aStats)
 	return aStats, err
 }
+
+// AstatsInterface is the data of a single network interface, from the system stats returned by the Astats plugin.
+type AstatsInterface struct {
+	BytesOut int64
+	BytesIn  int64
+	// Speed is the interface speed in Mbps.
+	Speed int
+}
+
+// Interfaces parses the data of each network interface in the system stats. The proc.net.dev contains a line for each interface, and inf.speeds a comma-delimited list of `name:speed`. Older Astats versions don't return inf.speeds, in which case a single interface is given the inf.speed.
+func (system AstatsSystem) Interfaces() (map[string]AstatsInterface, error) {
+	// proc.net.dev lines look like
+	// "bond0:8495786321839 31960528603    0    0    0     0          0   2349716 143283576747316 101104535041    0    0    0     0       0          0"
+	interfaces := map[string]AstatsInterface{}
+	for _, line := range strings.Split(system.ProcNetDev, "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) < 2 {
+			continue
+		}
+		name := strings.TrimSpace(parts[0])
+		numbers := strings.Fields(parts[1])
+		if len(numbers) < 9 {
+			return nil, fmt.Errorf("proc.net.dev interface '%s' unknown format '%s'", name, line)
+		}
+		bytesIn, err := strconv.ParseInt(numbers[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("converting proc.net.dev interface '%s' bytes in: %v", name, err)
+		}
+		bytesOut, err := strconv.ParseInt(numbers[8], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("converting proc.net.dev interface '%s' bytes out: %v", name, err)
+		}
+		interfaces[name] = AstatsInterface{BytesIn: bytesIn, BytesOut: bytesOut}
+	}
+	if len(interfaces) == 0 {
+		return nil, fmt.Errorf("no interfaces found in proc.net.dev '%s'", system.ProcNetDev)
+	}
+
+	if system.InfSpeeds == "" {
+		if len(interfaces) == 1 {
+			for name, iface := range interfaces {
+				iface.Speed = system.InfSpeed
+				interfaces[name] = iface
+			}
+		}
+		return interfaces, nil
+	}
+
+	for _, nameSpeed := range strings.Split(system.InfSpeeds, ",") {
+		parts := strings.SplitN(nameSpeed, ":", 2)
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("inf.speeds unknown format '%s'", system.InfSpeeds)
+		}
+		speed, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("converting inf.speeds interface '%s' speed: %v", parts[0], err)
+		}
+		name := strings.TrimSpace(parts[0])
+		iface, ok := interfaces[name]
+		if !ok {
+			continue // interfaces missing from proc.net.dev have no bandwidth to compute
+		}
+		iface.Speed = speed
+		interfaces[name] = iface
+	}
+	return interfaces, nil
+}
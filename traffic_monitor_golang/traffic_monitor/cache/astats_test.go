@@ -23,6 +23,7 @@ package cache
 import (
 	"fmt"
 	"io/ioutil"
+	"reflect"
 	"testing"
 )
 
@@ -40,3 +41,45 @@ func TestAstats(t *testing.T) {
 	}
 	fmt.Printf("Found %v key/val pairs in ats\n", len(aStats.Ats))
 }
+
+func TestAstatsSystemInterfaces(t *testing.T) {
+	system := AstatsSystem{
+		InfSpeed:   20000,
+		InfSpeeds:  "eth0:10000,eth1:10000",
+		ProcNetDev: "eth0:100 2 0 0 0 0 0 0 1000 3 0 0 0 0 0 0\neth1: 200 4 0 0 0 0 0 0 2000 5 0 0 0 0 0 0",
+	}
+	interfaces, err := system.Interfaces()
+	if err != nil {
+		t.Fatalf("Interfaces expected nil err, actual: %v", err)
+	}
+	expected := map[string]AstatsInterface{
+		"eth0": AstatsInterface{BytesIn: 100, BytesOut: 1000, Speed: 10000},
+		"eth1": AstatsInterface{BytesIn: 200, BytesOut: 2000, Speed: 10000},
+	}
+	if !reflect.DeepEqual(interfaces, expected) {
+		t.Errorf("Interfaces expected %+v, actual %+v", expected, interfaces)
+	}
+	if bytes, err := outBytes(system); err != nil || bytes != 3000 {
+		t.Errorf("outBytes expected 3000, actual %v err %v", bytes, err)
+	}
+
+	// older astats return a single interface, and no inf.speeds
+	system = AstatsSystem{
+		InfSpeed:   10000,
+		ProcNetDev: "bond0:8495786321839 31960528603    0    0    0     0          0   2349716 143283576747316 101104535041    0    0    0     0       0          0",
+	}
+	interfaces, err = system.Interfaces()
+	if err != nil {
+		t.Fatalf("Interfaces expected nil err, actual: %v", err)
+	}
+	expected = map[string]AstatsInterface{
+		"bond0": AstatsInterface{BytesIn: 8495786321839, BytesOut: 143283576747316, Speed: 10000},
+	}
+	if !reflect.DeepEqual(interfaces, expected) {
+		t.Errorf("Interfaces expected %+v, actual %+v", expected, interfaces)
+	}
+
+	if _, err := (AstatsSystem{}).Interfaces(); err == nil {
+		t.Errorf("Interfaces with empty proc.net.dev expected error, actual nil")
+	}
+}
@@ -24,8 +24,6 @@ import (
 	"fmt"
 	"io"
 	"net/url"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
@@ -40,10 +38,11 @@ import (
 
 // Handler is a cache handler, which fulfills the common/handler `Handler` interface.
 type Handler struct {
-	resultChan         chan Result
-	Notify             int
-	ToData             *todata.TODataThreadsafe
-	MultipleSpaceRegex *regexp.Regexp
+	resultChan chan Result
+	Notify     int
+	ToData     *todata.TODataThreadsafe
+	// Family is the IP address family this handler's results were polled over.
+	Family enum.AddressFamily
 }
 
 func (h Handler) ResultChan() <-chan Result {
@@ -52,12 +51,18 @@ func (h Handler) ResultChan() <-chan Result {
 
 // NewHandler returns a new cache handler. Note this handler does NOT precomputes stat data before calling ResultChan, and Result.Precomputed will be nil
 func NewHandler() Handler {
-	return Handler{resultChan: make(chan Result), MultipleSpaceRegex: regexp.MustCompile(" +")}
+	return Handler{resultChan: make(chan Result), Family: enum.AddressFamilyIPv4}
 }
 
 // NewPrecomputeHandler constructs a new cache Handler, which precomputes stat data and populates result.Precomputed before passing to ResultChan.
 func NewPrecomputeHandler(toData todata.TODataThreadsafe) Handler {
-	return Handler{resultChan: make(chan Result), MultipleSpaceRegex: regexp.MustCompile(" +"), ToData: &toData}
+	return Handler{resultChan: make(chan Result), ToData: &toData, Family: enum.AddressFamilyIPv4}
+}
+
+// WithFamily returns a copy of this handler, which marks its results as polled over the given address family. The returned handler sends results to the same ResultChan as this handler, so one manager can process polls of both families.
+func (handler Handler) WithFamily(family enum.AddressFamily) Handler {
+	handler.Family = family
+	return handler
 }
 
 // Precompute returns whether this handler precomputes data before passing the result to the ResultChan
@@ -87,6 +92,8 @@ type Result struct {
 	PollFinished    chan<- uint64
 	PrecomputedData PrecomputedData
 	Available       bool
+	// Family is the IP address family this result was polled over.
+	Family enum.AddressFamily
 }
 
 // HasStat returns whether the given stat is in the Result.
@@ -98,16 +105,28 @@ func (result *Result) HasStat(stat string) bool {
 	if _, ok := result.Astats.Ats[stat]; ok {
 		return true
 	}
+	if _, ok := InterfaceComputedStats(result.Vitals)[stat]; ok {
+		return true
+	}
 	return false
 }
 
-// Vitals is the vitals data returned from a cache.
+// Vitals is the vitals data returned from a cache. The bytes and kbps are summed across all the cache's polled interfaces.
 type Vitals struct {
 	LoadAvg    float64
 	BytesOut   int64
 	BytesIn    int64
 	KbpsOut    int64
 	MaxKbpsOut int64
+	Interfaces map[string]InterfaceVitals
+}
+
+// InterfaceVitals is the vitals data of a single network interface of a cache.
+type InterfaceVitals struct {
+	BytesOut   int64
+	BytesIn    int64
+	KbpsOut    int64
+	MaxKbpsOut int64
 }
 
 // Stat is a generic stat, including the untyped value and the time the stat was taken.
@@ -194,6 +213,9 @@ func ComputedStats() map[string]StatComputeFunc {
 		"system.inf.speed": func(info ResultInfo, serverInfo to.TrafficServer, serverProfile to.TMProfile, combinedState peer.IsAvailable) interface{} {
 			return info.System.InfSpeed
 		},
+		"system.inf.speeds": func(info ResultInfo, serverInfo to.TrafficServer, serverProfile to.TMProfile, combinedState peer.IsAvailable) interface{} {
+			return info.System.InfSpeeds
+		},
 		"system.lastReload": func(info ResultInfo, serverInfo to.TrafficServer, serverProfile to.TMProfile, combinedState peer.IsAvailable) interface{} {
 			return info.System.LastReload
 		},
@@ -212,8 +234,30 @@ func ComputedStats() map[string]StatComputeFunc {
 	}
 }
 
+// InterfaceStatPrefix is the prefix of computed stats of individual network interfaces, which are named `system.inf.{interface}.{stat}`.
+const InterfaceStatPrefix = "system.inf."
+
+// InterfaceComputedStats returns the computed stats of each individual network interface in the given vitals, mapped to their values. Unlike ComputedStats, the names depend on the cache's interfaces, e.g. `system.inf.eth0.kbps`, so they can be used in thresholds for a particular interface.
+func InterfaceComputedStats(vitals Vitals) map[string]interface{} {
+	stats := map[string]interface{}{}
+	for name, iface := range vitals.Interfaces {
+		prefix := InterfaceStatPrefix + name + "."
+		stats[prefix+"availableBandwidthInKbps"] = iface.MaxKbpsOut - iface.KbpsOut
+		stats[prefix+"bytesIn"] = iface.BytesIn
+		stats[prefix+"bytesOut"] = iface.BytesOut
+		stats[prefix+"kbps"] = iface.KbpsOut
+		stats[prefix+"maxKbps"] = iface.MaxKbpsOut
+	}
+	return stats
+}
+
 // StatsMarshall encodes the stats in JSON, encoding up to historyCount of each stat. If statsToUse is empty, all stats are encoded; otherwise, only the given stats are encoded. If wildcard is true, stats which contain the text in each statsToUse are returned, instead of exact stat names. If cacheType is not CacheTypeInvalid, only stats for the given type are returned. If hosts is not empty, only the given hosts are returned.
 func StatsMarshall(statResultHistory ResultStatHistory, statInfo ResultInfoHistory, combinedStates peer.Crstates, monitorConfig to.TrafficMonitorConfigMap, statMaxKbpses Kbpses, filter Filter, params url.Values) ([]byte, error) {
+	return json.Marshal(CreateStats(statResultHistory, statInfo, combinedStates, monitorConfig, filter, params))
+}
+
+// CreateStats returns the stats of each cache, including computed stats, filtered by the given filter. See `StatsMarshall`.
+func CreateStats(statResultHistory ResultStatHistory, statInfo ResultInfoHistory, combinedStates peer.Crstates, monitorConfig to.TrafficMonitorConfigMap, filter Filter, params url.Values) Stats {
 	stats := Stats{
 		CommonAPIData: srvhttp.GetCommonAPIData(params, time.Now()),
 		Caches:        map[enum.CacheName]map[string][]ResultStatVal{},
@@ -272,10 +316,17 @@ func StatsMarshall(statResultHistory ResultStatHistory, statInfo ResultInfoHisto
 				}
 				stats.Caches[id][stat] = append(stats.Caches[id][stat], ResultStatVal{Val: statValF(resultInfo, serverInfo, serverProfile, combinedStatesCache), Time: t, Span: 1}) // combinedState will default to unavailable
 			}
+
+			for stat, statVal := range InterfaceComputedStats(resultInfo.Vitals) {
+				if !filter.UseStat(stat) {
+					continue
+				}
+				stats.Caches[id][stat] = append(stats.Caches[id][stat], ResultStatVal{Val: statVal, Time: t, Span: 1})
+			}
 		}
 	}
 
-	return json.Marshal(stats)
+	return stats
 }
 
 // Handle handles results fetched from a cache, parsing the raw Reader data and passing it along to a chan for further processing.
@@ -287,6 +338,7 @@ func (handler Handler) Handle(id string, r io.Reader, reqTime time.Duration, req
 		RequestTime:  reqTime,
 		PollID:       pollID,
 		PollFinished: pollFinished,
+		Family:       handler.Family,
 	}
 
 	if reqErr != nil {
@@ -335,29 +387,17 @@ func (handler Handler) Handle(id string, r io.Reader, reqTime time.Duration, req
 	handler.resultChan <- result
 }
 
-// outBytes takes the proc.net.dev string, and the interface name, and returns the bytes field
-func outBytes(procNetDev, iface string, multipleSpaceRegex *regexp.Regexp) (int64, error) {
-	if procNetDev == "" {
-		return 0, fmt.Errorf("procNetDev empty")
-	}
-	if iface == "" {
-		return 0, fmt.Errorf("iface empty")
+// outBytes returns the bytes out of the given astats system stats, summed across all its interfaces.
+func outBytes(system AstatsSystem) (int64, error) {
+	interfaces, err := system.Interfaces()
+	if err != nil {
+		return 0, err
 	}
-	ifacePos := strings.Index(procNetDev, iface)
-	if ifacePos == -1 {
-		return 0, fmt.Errorf("interface '%s' not found in proc.net.dev '%s'", iface, procNetDev)
+	bytes := int64(0)
+	for _, iface := range interfaces {
+		bytes += iface.BytesOut
 	}
-
-	procNetDevIfaceBytes := procNetDev[ifacePos+len(iface)+1:]
-	procNetDevIfaceBytes = strings.TrimLeft(procNetDevIfaceBytes, " ")
-	procNetDevIfaceBytes = multipleSpaceRegex.ReplaceAllLiteralString(procNetDevIfaceBytes, " ")
-	procNetDevIfaceBytesArr := strings.Split(procNetDevIfaceBytes, " ") // this could be made faster with a custom function (DFA?) that splits and ignores duplicate spaces at the same time
-	if len(procNetDevIfaceBytesArr) < 10 {
-		return 0, fmt.Errorf("proc.net.dev iface '%v' unknown format '%s'", iface, procNetDev)
-	}
-	procNetDevIfaceBytes = procNetDevIfaceBytesArr[8]
-
-	return strconv.ParseInt(procNetDevIfaceBytes, 10, 64)
+	return bytes, nil
 }
 
 // precompute does the calculations which are possible with only this one cache result.
@@ -367,7 +407,7 @@ func (handler Handler) precompute(result Result) Result {
 	stats := map[enum.DeliveryServiceName]dsdata.Stat{}
 
 	var err error
-	if result.PrecomputedData.OutBytes, err = outBytes(result.Astats.System.ProcNetDev, result.Astats.System.InfName, handler.MultipleSpaceRegex); err != nil {
+	if result.PrecomputedData.OutBytes, err = outBytes(result.Astats.System); err != nil {
 		result.PrecomputedData.OutBytes = 0
 		log.Errorf("addkbps %s handle precomputing outbytes '%v'\n", result.ID, err)
 	}
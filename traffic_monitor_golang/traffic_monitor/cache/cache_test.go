@@ -29,13 +29,14 @@ import (
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/srvhttp"
 	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
+	to "github.com/apache/incubator-trafficcontrol/traffic_ops/client"
 )
 
 func TestHandlerPrecompute(t *testing.T) {
 	if NewHandler().Precompute() {
 		t.Errorf("expected NewHandler().Precompute() false, actual true")
 	}
-	if !NewPrecomputeHandler(todata.NewThreadsafe(), peer.NewCRStatesPeersThreadsafe()).Precompute() {
+	if !NewPrecomputeHandler(todata.NewThreadsafe()).Precompute() {
 		t.Errorf("expected NewPrecomputeHandler().Precompute() true, actual false")
 	}
 }
@@ -56,11 +57,10 @@ func (f DummyFilterNever) WithinStatHistoryMax(i int) bool {
 }
 
 func TestStatsMarshall(t *testing.T) {
-	hist := randResultHistory()
 	filter := DummyFilterNever{}
 	params := url.Values{}
 	beforeStatsMarshall := time.Now()
-	bytes, err := StatsMarshall(hist, filter, params)
+	bytes, err := StatsMarshall(ResultStatHistory{}, ResultInfoHistory{}, peer.NewCrstates(), to.TrafficMonitorConfigMap{}, Kbpses{}, filter, params)
 	afterStatsMarshall := time.Now()
 	if err != nil {
 		t.Fatalf("StatsMarshall return expected nil err, actual err: %v", err)
@@ -33,9 +33,16 @@ const AvailableStatusReported = "REPORTED"
 
 // CacheAvailableStatus is the available status of the given cache. It includes a boolean available/unavailable flag, and a descriptive string.
 type AvailableStatus struct {
+	// Available is whether the cache is available. This is the IPv4 availability, and the availability for clients which don't distinguish address families.
 	Available bool
-	Status    string
-	Why       string
+	// AvailableIPv6 is whether the cache is available to IPv6 clients. This requires the cache be Available, and its last IPv6 health poll be healthy. It is always false for caches without an IPv6 address.
+	AvailableIPv6 bool
+	// HealthyIPv6 is whether the last IPv6 health poll of the cache was healthy, irrespective of its IPv4 availability.
+	HealthyIPv6 bool
+	Status      string
+	Why         string
+	// WhyIPv6 describes why the cache's last IPv6 health poll was healthy or unhealthy. It is empty if the cache has never been polled over IPv6.
+	WhyIPv6 string
 	// UnavailableStat is the stat whose threshold made the cache unavailable. If this is the empty string, the cache is unavailable for a non-threshold reason. This exists so a poller (health, stat) won't mark an unavailable cache as available if the stat whose threshold was reached isn't available on that poller.
 	UnavailableStat string
 	// Poller is the name of the poller which set this available status
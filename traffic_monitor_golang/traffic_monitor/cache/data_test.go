@@ -21,7 +21,6 @@ package cache
 
 import (
 	"errors"
-	"fmt"
 	dsdata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/deliveryservicedata"
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
 	"math/rand"
@@ -60,13 +59,13 @@ func TestAvailableStatusesCopy(t *testing.T) {
 		b := a.Copy()
 
 		if !reflect.DeepEqual(a, b) {
-			t.Errorf("expected a and b DeepEqual, actual copied map not equal", a, b)
+			t.Errorf("expected a and b DeepEqual, actual copied map not equal: %v %v", a, b)
 		}
 
 		// verify a and b don't point to the same map
 		a[enum.CacheName(randStr())] = AvailableStatus{Available: randBool(), Status: randStr()}
 		if reflect.DeepEqual(a, b) {
-			t.Errorf("expected a != b, actual a and b point to the same map", a)
+			t.Errorf("expected a != b, actual a and b point to the same map: %v", a)
 		}
 	}
 }
@@ -130,7 +129,7 @@ func randStatCacheStats() dsdata.StatCacheStats {
 		Tps3xx:      dsdata.StatFloat{Value: rand.Float64(), StatMeta: randStatMeta()},
 		Tps2xx:      dsdata.StatFloat{Value: rand.Float64(), StatMeta: randStatMeta()},
 		ErrorString: dsdata.StatString{Value: randStr(), StatMeta: randStatMeta()},
-		TpsTotal:    dsdata.StatInt{Value: rand.Int63(), StatMeta: randStatMeta()},
+		TpsTotal:    dsdata.StatFloat{Value: rand.Float64(), StatMeta: randStatMeta()},
 	}
 }
 
@@ -206,7 +205,7 @@ func randPrecomputedData() PrecomputedData {
 func randResult() Result {
 	return Result{
 		ID:              enum.CacheName(randStr()),
-		Error:           fmt.Errorf(randStr()),
+		Error:           errors.New(randStr()),
 		Astats:          randAstats(),
 		Time:            time.Now(),
 		RequestTime:     time.Millisecond * time.Duration(rand.Int()),
@@ -244,13 +243,13 @@ func TestResultHistoryCopy(t *testing.T) {
 		b := a.Copy()
 
 		if !reflect.DeepEqual(a, b) {
-			t.Errorf("expected a and b DeepEqual, actual copied map not equal", a, b)
+			t.Errorf("expected a and b DeepEqual, actual copied map not equal: %v %v", a, b)
 		}
 
 		// verify a and b don't point to the same map
 		a[enum.CacheName(randStr())] = randResultSlice()
 		if reflect.DeepEqual(a, b) {
-			t.Errorf("expected a != b, actual a and b point to the same map", a)
+			t.Errorf("expected a != b, actual a and b point to the same map: %v", a)
 		}
 	}
 }
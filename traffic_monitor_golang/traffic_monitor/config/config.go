@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/log"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/alert"
 )
 
 // LogLocation is a location to log to. This may be stdout, stderr, null (/dev/null), or a valid file path.
@@ -46,27 +47,53 @@ const (
 
 // Config is the configuration for the application. It includes myriad data, such as polling intervals and log locations.
 type Config struct {
-	CacheHealthPollingInterval   time.Duration `json:"-"`
-	CacheStatPollingInterval     time.Duration `json:"-"`
-	MonitorConfigPollingInterval time.Duration `json:"-"`
-	HTTPTimeout                  time.Duration `json:"-"`
-	PeerPollingInterval          time.Duration `json:"-"`
-	PeerOptimistic               bool          `json:"peer_optimistic"`
-	MaxEvents                    uint64        `json:"max_events"`
-	MaxStatHistory               uint64        `json:"max_stat_history"`
-	MaxHealthHistory             uint64        `json:"max_health_history"`
-	HealthFlushInterval          time.Duration `json:"-"`
-	StatFlushInterval            time.Duration `json:"-"`
-	LogLocationError             string        `json:"log_location_error"`
-	LogLocationWarning           string        `json:"log_location_warning"`
-	LogLocationInfo              string        `json:"log_location_info"`
-	LogLocationDebug             string        `json:"log_location_debug"`
-	LogLocationEvent             string        `json:"log_location_event"`
-	ServeReadTimeout             time.Duration `json:"-"`
-	ServeWriteTimeout            time.Duration `json:"-"`
-	HealthToStatRatio            uint64        `json:"health_to_stat_ratio"`
-	HTTPPollNoSleep              bool          `json:"http_poll_no_sleep"`
-	StaticFileDir                string        `json:"static_file_dir"`
+	CacheHealthPollingInterval   time.Duration      `json:"-"`
+	CacheStatPollingInterval     time.Duration      `json:"-"`
+	MonitorConfigPollingInterval time.Duration      `json:"-"`
+	HTTPTimeout                  time.Duration      `json:"-"`
+	PeerPollingInterval          time.Duration      `json:"-"`
+	PeerOptimistic               bool               `json:"peer_optimistic"`
+	MaxEvents                    uint64             `json:"max_events"`
+	MaxStatHistory               uint64             `json:"max_stat_history"`
+	MaxHealthHistory             uint64             `json:"max_health_history"`
+	HealthFlushInterval          time.Duration      `json:"-"`
+	StatFlushInterval            time.Duration      `json:"-"`
+	LogLocationError             string             `json:"log_location_error"`
+	LogLocationWarning           string             `json:"log_location_warning"`
+	LogLocationInfo              string             `json:"log_location_info"`
+	LogLocationDebug             string             `json:"log_location_debug"`
+	LogLocationEvent             string             `json:"log_location_event"`
+	ServeReadTimeout             time.Duration      `json:"-"`
+	ServeWriteTimeout            time.Duration      `json:"-"`
+	HealthToStatRatio            uint64             `json:"health_to_stat_ratio"`
+	HTTPPollNoSleep              bool               `json:"http_poll_no_sleep"`
+	StaticFileDir                string             `json:"static_file_dir"`
+	LogLocationAccess            string             `json:"log_location_access"`
+	APITokens                    map[string]string  `json:"api_tokens"`
+	APIUsers                     map[string]APIUser `json:"api_users"`
+	APIRateLimitPerSecond        float64            `json:"api_rate_limit_per_second"`
+	APIRateLimitBurst            int                `json:"api_rate_limit_burst"`
+	ShutdownDrain                time.Duration      `json:"-"`
+	Alerts                       alert.Config       `json:"alerts"`
+	CacheGroupMinAvailable       float64            `json:"cachegroup_min_available"`
+	StaticPeers                  []StaticPeer       `json:"static_peers"`
+	PeerGossip                   bool               `json:"peer_gossip"`
+	PeerAdvertiseURL             string             `json:"peer_advertise_url"`
+	PeerGossipExpiry             time.Duration      `json:"-"`
+	PeerGossipSecret             string             `json:"peer_gossip_secret"`
+	TrafficOpsCache              bool               `json:"traffic_ops_cache"`
+}
+
+// StaticPeer is a peer Traffic Monitor to poll regardless of Traffic Ops. URL is the peer's base URL, e.g. `http://tm-2.example.net:80`.
+type StaticPeer struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// APIUser is a user allowed to request the API with HTTP basic authentication. The Scope is `read` or `admin`.
+type APIUser struct {
+	Password string `json:"password"`
+	Scope    string `json:"scope"`
 }
 
 // DefaultConfig is the default configuration for the application, if no configuration file is given, or if a given config setting doesn't exist in the config file.
@@ -92,6 +119,9 @@ var DefaultConfig = Config{
 	HealthToStatRatio:            4,
 	HTTPPollNoSleep:              false,
 	StaticFileDir:                StaticFileDir,
+	LogLocationAccess:            LogLocationNull,
+	ShutdownDrain:                5 * time.Second,
+	PeerGossipExpiry:             5 * time.Minute,
 }
 
 // MarshalJSON marshals custom millisecond durations. Aliasing inspired by http://choly.ca/post/go-json-marshalling/
@@ -108,6 +138,8 @@ func (c *Config) MarshalJSON() ([]byte, error) {
 		StatFlushIntervalMs            uint64 `json:"stat_flush_interval_ms"`
 		ServeReadTimeoutMs             uint64 `json:"serve_read_timeout_ms"`
 		ServeWriteTimeoutMs            uint64 `json:"serve_write_timeout_ms"`
+		ShutdownDrainMs                uint64 `json:"shutdown_drain_ms"`
+		PeerGossipExpiryMs             uint64 `json:"peer_gossip_expiry_ms"`
 		*Alias
 	}{
 		CacheHealthPollingIntervalMs:   uint64(c.CacheHealthPollingInterval / time.Millisecond),
@@ -118,6 +150,8 @@ func (c *Config) MarshalJSON() ([]byte, error) {
 		PeerOptimistic:                 bool(true),
 		HealthFlushIntervalMs:          uint64(c.HealthFlushInterval / time.Millisecond),
 		StatFlushIntervalMs:            uint64(c.StatFlushInterval / time.Millisecond),
+		ShutdownDrainMs:                uint64(c.ShutdownDrain / time.Millisecond),
+		PeerGossipExpiryMs:             uint64(c.PeerGossipExpiry / time.Millisecond),
 		Alias:                          (*Alias)(c),
 	})
 }
@@ -136,6 +170,8 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 		StatFlushIntervalMs            *uint64 `json:"stat_flush_interval_ms"`
 		ServeReadTimeoutMs             *uint64 `json:"serve_read_timeout_ms"`
 		ServeWriteTimeoutMs            *uint64 `json:"serve_write_timeout_ms"`
+		ShutdownDrainMs                *uint64 `json:"shutdown_drain_ms"`
+		PeerGossipExpiryMs             *uint64 `json:"peer_gossip_expiry_ms"`
 		*Alias
 	}{
 		Alias: (*Alias)(c),
@@ -171,9 +207,29 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 	if aux.ServeWriteTimeoutMs != nil {
 		c.ServeWriteTimeout = time.Duration(*aux.ServeWriteTimeoutMs) * time.Millisecond
 	}
+	if aux.ShutdownDrainMs != nil {
+		c.ShutdownDrain = time.Duration(*aux.ShutdownDrainMs) * time.Millisecond
+	}
+	if aux.PeerGossipExpiryMs != nil {
+		c.PeerGossipExpiry = time.Duration(*aux.PeerGossipExpiryMs) * time.Millisecond
+	}
 	if aux.PeerOptimistic != nil {
 		c.PeerOptimistic = *aux.PeerOptimistic
 	}
+	if c.CacheGroupMinAvailable < 0 || c.CacheGroupMinAvailable > 1 {
+		return fmt.Errorf("cachegroup_min_available %v must be between 0 and 1", c.CacheGroupMinAvailable)
+	}
+	for _, p := range c.StaticPeers {
+		if p.Name == "" || p.URL == "" {
+			return fmt.Errorf("static peer %+v must have a name and url", p)
+		}
+	}
+	if c.PeerGossip && c.PeerAdvertiseURL == "" {
+		return fmt.Errorf("peer_gossip requires peer_advertise_url")
+	}
+	if c.PeerGossip && c.PeerGossipSecret == "" {
+		return fmt.Errorf("peer_gossip requires peer_gossip_secret")
+	}
 	return nil
 }
 
@@ -239,3 +295,15 @@ func GetLogWriters(cfg Config) (io.WriteCloser, io.WriteCloser, io.WriteCloser,
 	}
 	return eventW, errW, warnW, infoW, debugW, nil
 }
+
+// GetAccessLogWriter returns the writer for the HTTP access log, or nil if the access log location is null, so requests aren't needlessly formatted.
+func GetAccessLogWriter(cfg Config) (io.WriteCloser, error) {
+	if cfg.LogLocationAccess == "" || cfg.LogLocationAccess == LogLocationNull {
+		return nil, nil
+	}
+	w, err := getLogWriter(cfg.LogLocationAccess)
+	if err != nil {
+		return nil, fmt.Errorf("getting log access writer %v: %v", cfg.LogLocationAccess, err)
+	}
+	return w, nil
+}
@@ -23,15 +23,20 @@ import (
 	"fmt"
 
 	ds "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/deliveryservice"
+	dsdata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/deliveryservicedata"
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/threadsafe"
 	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
 )
 
 func srvAPIBandwidthKbps(toData todata.TODataThreadsafe, lastStats threadsafe.LastStats) []byte {
-	kbpsStats := lastStats.Get()
+	return []byte(fmt.Sprintf("%f", bandwidthKbps(lastStats.Get())))
+}
+
+// bandwidthKbps returns the total bandwidth of all caches, in kilobits per second.
+func bandwidthKbps(kbpsStats dsdata.LastStats) float64 {
 	sum := float64(0.0)
 	for _, data := range kbpsStats.Caches {
 		sum += data.Bytes.PerSec / ds.BytesPerKilobit
 	}
-	return []byte(fmt.Sprintf("%f", sum))
+	return sum
 }
@@ -22,14 +22,19 @@ package datareq
 import (
 	"fmt"
 
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/cache"
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/threadsafe"
 )
 
 func srvAPIBandwidthCapacityKbps(statMaxKbpses threadsafe.CacheKbpses) []byte {
-	maxKbpses := statMaxKbpses.Get()
+	return []byte(fmt.Sprintf("%d", bandwidthCapacityKbps(statMaxKbpses.Get())))
+}
+
+// bandwidthCapacityKbps returns the total bandwidth capacity of all caches, in kilobits per second.
+func bandwidthCapacityKbps(maxKbpses cache.Kbpses) int64 {
 	cap := int64(0)
 	for _, kbps := range maxKbpses {
 		cap += kbps
 	}
-	return []byte(fmt.Sprintf("%d", cap))
+	return cap
 }
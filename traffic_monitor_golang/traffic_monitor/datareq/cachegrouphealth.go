@@ -0,0 +1,100 @@
+package datareq
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"sort"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/cache"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/health"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
+	to "github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+)
+
+// CacheGroupHealth is the health of a single cachegroup. Caches and AvailableCaches don't count OFFLINE or ADMIN_DOWN caches, as delivery service disabled locations don't. AvailableFraction is the fraction of the cachegroup's caches which are available, and MinAvailable the configured minimum fraction. Available is whether the cachegroup as a whole has any available caches, and at least the minimum fraction. DisabledDeliveryServices are the delivery services the cachegroup is disabled for, which are computed from only the caches assigned to each delivery service, so a cachegroup may be disabled for a delivery service even if it's available overall.
+// Kbps, CapacityKbps and HeadroomKbps are the sums over the cachegroup's available caches, as in the capacity endpoint.
+type CacheGroupHealth struct {
+	Caches                   int                        `json:"caches"`
+	AvailableCaches          int                        `json:"availableCaches"`
+	AvailableFraction        float64                    `json:"availableFraction"`
+	MinAvailable             float64                    `json:"minAvailable"`
+	Available                bool                       `json:"available"`
+	DisabledDeliveryServices []enum.DeliveryServiceName `json:"disabledDeliveryServices"`
+	Kbps                     float64                    `json:"kbps"`
+	CapacityKbps             float64                    `json:"capacityKbps"`
+	HeadroomKbps             float64                    `json:"headroomKbps"`
+}
+
+// createCacheGroupHealth returns the health of each cachegroup. Delivery service disabled locations are taken from the combined states, so they include the results of peers.
+func createCacheGroupHealth(toData todata.TOData, statInfoHistory cache.ResultInfoHistory, combinedStates peer.Crstates, mc to.TrafficMonitorConfigMap, minAvailable float64) map[enum.CacheGroupName]CacheGroupHealth {
+	capacity := createCapacity(CapacityQuery{Threshold: DefaultCapacityThreshold}, toData, statInfoHistory, combinedStates)
+
+	statuses := health.CacheStatuses(mc)
+	caches := map[enum.CacheGroupName]int{}
+	availableCaches := map[enum.CacheGroupName]int{}
+	for cacheName := range toData.ServerTypes {
+		if health.OutOfService(statuses[cacheName]) {
+			continue
+		}
+		cacheGroup := toData.ServerCachegroups[cacheName]
+		caches[cacheGroup]++
+		if combinedStates.Caches[cacheName].IsAvailable {
+			availableCaches[cacheGroup]++
+		}
+	}
+
+	disabled := map[enum.CacheGroupName][]enum.DeliveryServiceName{}
+	for dsName, ds := range combinedStates.Deliveryservice {
+		for _, cacheGroup := range ds.DisabledLocations {
+			disabled[cacheGroup] = append(disabled[cacheGroup], dsName)
+		}
+	}
+
+	cacheGroups := map[enum.CacheGroupName]CacheGroupHealth{}
+	for cacheGroup, c := range capacity.CacheGroups {
+		h := CacheGroupHealth{
+			Caches:                   caches[cacheGroup],
+			AvailableCaches:          availableCaches[cacheGroup],
+			MinAvailable:             minAvailable,
+			Available:                health.CacheGroupAvailable(availableCaches[cacheGroup], caches[cacheGroup], minAvailable),
+			DisabledDeliveryServices: disabled[cacheGroup],
+			Kbps:                     c.Kbps,
+			CapacityKbps:             c.CapacityKbps,
+			HeadroomKbps:             c.HeadroomKbps,
+		}
+		if h.Caches > 0 {
+			h.AvailableFraction = float64(h.AvailableCaches) / float64(h.Caches)
+		}
+		if h.DisabledDeliveryServices == nil {
+			h.DisabledDeliveryServices = []enum.DeliveryServiceName{} // serialize to the JSON `[]`, not `null`
+		}
+		sort.Sort(deliveryServiceNames(h.DisabledDeliveryServices))
+		cacheGroups[cacheGroup] = h
+	}
+	return cacheGroups
+}
+
+type deliveryServiceNames []enum.DeliveryServiceName
+
+func (n deliveryServiceNames) Len() int           { return len(n) }
+func (n deliveryServiceNames) Swap(i, j int)      { n[i], n[j] = n[j], n[i] }
+func (n deliveryServiceNames) Less(i, j int) bool { return n[i] < n[j] }
@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package datareq
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/cache"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
+	to "github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+)
+
+func TestCreateCacheGroupHealth(t *testing.T) {
+	toData := *todata.New()
+	for _, name := range []enum.CacheName{"edge-a1", "edge-a2", "edge-a3", "edge-a4"} {
+		toData.ServerTypes[name] = enum.CacheTypeEdge
+		toData.ServerCachegroups[name] = "cg-a"
+	}
+	states := peer.NewCrstates()
+	states.Caches["edge-a1"] = peer.IsAvailable{IsAvailable: true}
+	states.Caches["edge-a2"] = peer.IsAvailable{IsAvailable: true}
+	states.Deliveryservice["ds-b"] = peer.Deliveryservice{DisabledLocations: []enum.CacheGroupName{"cg-a"}}
+	states.Deliveryservice["ds-a"] = peer.Deliveryservice{DisabledLocations: []enum.CacheGroupName{"cg-a"}}
+	infos := cache.ResultInfoHistory{"edge-a1": testInfoHistory(time.Now(), 400, 0, 1000, 1)}
+
+	mc := to.TrafficMonitorConfigMap{TrafficServer: map[string]to.TrafficServer{}}
+	cgs := createCacheGroupHealth(toData, infos, states, mc, 0.75)
+	cg, ok := cgs["cg-a"]
+	if !ok {
+		t.Fatalf("createCacheGroupHealth expected cg-a, actual %+v", cgs)
+	}
+	if cg.Caches != 4 || cg.AvailableCaches != 2 || cg.AvailableFraction != 0.5 || cg.Available || cg.MinAvailable != 0.75 {
+		t.Errorf("createCacheGroupHealth expected 2 of 4 available below 0.75 minimum, actual %+v", cg)
+	}
+	if cg.HeadroomKbps != 600 {
+		t.Errorf("createCacheGroupHealth expected 600 headroom, actual %v", cg.HeadroomKbps)
+	}
+	if len(cg.DisabledDeliveryServices) != 2 || cg.DisabledDeliveryServices[0] != "ds-a" || cg.DisabledDeliveryServices[1] != "ds-b" {
+		t.Errorf("createCacheGroupHealth expected disabled for [ds-a ds-b], actual %v", cg.DisabledDeliveryServices)
+	}
+
+	if cg := createCacheGroupHealth(toData, infos, states, mc, 0.5)["cg-a"]; !cg.Available {
+		t.Errorf("createCacheGroupHealth at minimum expected available, actual %+v", cg)
+	}
+
+	mc.TrafficServer["edge-a3"] = to.TrafficServer{HostName: "edge-a3", Status: enum.CacheStatusAdminDown.String()}
+	if cg := createCacheGroupHealth(toData, infos, states, mc, 0.6)["cg-a"]; cg.Caches != 3 || cg.AvailableCaches != 2 || cg.AvailableFraction != 2.0/3.0 || !cg.Available {
+		t.Errorf("createCacheGroupHealth with an ADMIN_DOWN cache expected 2 of 3 available above 0.6 minimum, actual %+v", cg)
+	}
+	mc.TrafficServer["edge-a1"] = to.TrafficServer{HostName: "edge-a1", Status: enum.CacheStatusOffline.String()}
+	mc.TrafficServer["edge-a2"] = to.TrafficServer{HostName: "edge-a2", Status: enum.CacheStatusOffline.String()}
+	mc.TrafficServer["edge-a4"] = to.TrafficServer{HostName: "edge-a4", Status: enum.CacheStatusAdminDown.String()}
+	if cg, ok := createCacheGroupHealth(toData, infos, states, mc, 0.5)["cg-a"]; !ok || cg.Caches != 0 || cg.Available {
+		t.Errorf("createCacheGroupHealth with every cache out of service expected unavailable with no caches, actual %+v %v", cg, ok)
+	}
+}
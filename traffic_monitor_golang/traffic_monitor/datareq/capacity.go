@@ -0,0 +1,212 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package datareq
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/cache"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
+)
+
+// Capacity is the bandwidth headroom of each cachegroup and delivery service, computed from available caches only.
+type Capacity struct {
+	CacheGroups      map[enum.CacheGroupName]CapacityStat      `json:"cachegroups"`
+	DeliveryServices map[enum.DeliveryServiceName]CapacityStat `json:"deliveryServices"`
+}
+
+// CapacityStat is the bandwidth and capacity of a set of caches, and the projected time until their bandwidth reaches the saturation threshold.
+// Kbps and CapacityKbps are the sum of the current bandwidth and maximum bandwidth of the available caches. Caches which are unavailable aren't routed to, so they contribute neither.
+// TrendKbpsPerSecond is the sum of the linear trend of each available cache's bandwidth, over its stat history.
+// SecondsToSaturation is the projected time until the bandwidth reaches the saturation threshold, at the current trend. It's 0 if the bandwidth already exceeds the threshold, and null if the bandwidth isn't increasing.
+type CapacityStat struct {
+	Caches              int      `json:"caches"`
+	AvailableCaches     int      `json:"availableCaches"`
+	Kbps                float64  `json:"kbps"`
+	CapacityKbps        float64  `json:"capacityKbps"`
+	HeadroomKbps        float64  `json:"headroomKbps"`
+	Utilization         float64  `json:"utilization"`
+	TrendKbpsPerSecond  float64  `json:"trendKbpsPerSecond"`
+	SecondsToSaturation *float64 `json:"secondsToSaturation"`
+}
+
+// DefaultCapacityThreshold is the fraction of capacity at which caches are considered saturated, if no threshold is given.
+const DefaultCapacityThreshold = 1.0
+
+// CapacityQuery is the capacity requested. See `NewCapacityQuery` for the query parameters used.
+type CapacityQuery struct {
+	Threshold float64
+	CacheType enum.CacheType
+}
+
+// NewCapacityQuery takes the HTTP query parameters and creates a CapacityQuery.
+// Query parameters used are `threshold` and `type`.
+// The `threshold` is the fraction of capacity at which caches are considered saturated, greater than 0 and at most 1. If it's empty, DefaultCapacityThreshold is used.
+// If `type` is empty, caches of all types are used. Otherwise, only caches of the given type are used.
+func NewCapacityQuery(params url.Values) (CapacityQuery, error) {
+	q := CapacityQuery{Threshold: DefaultCapacityThreshold}
+	if paramThreshold := params.Get("threshold"); paramThreshold != "" {
+		threshold, err := strconv.ParseFloat(paramThreshold, 64)
+		if err != nil || threshold <= 0 || threshold > 1 {
+			return CapacityQuery{}, fmt.Errorf("invalid threshold '%v', must be a number greater than 0 and at most 1", paramThreshold)
+		}
+		q.Threshold = threshold
+	}
+	if paramType := params.Get("type"); paramType != "" {
+		q.CacheType = enum.CacheTypeFromString(paramType)
+		if q.CacheType == enum.CacheTypeInvalid {
+			return CapacityQuery{}, fmt.Errorf("invalid cache type '%v'", paramType)
+		}
+	}
+	return q, nil
+}
+
+// cacheCapacity is the current bandwidth, maximum bandwidth, and bandwidth trend of a single cache.
+type cacheCapacity struct {
+	available bool
+	kbps      float64
+	maxKbps   float64
+	trend     float64
+}
+
+func createCapacity(q CapacityQuery, toData todata.TOData, statInfoHistory cache.ResultInfoHistory, combinedStates peer.Crstates) Capacity {
+	caches := map[enum.CacheName]cacheCapacity{}
+	for cacheName, cacheType := range toData.ServerTypes {
+		if q.CacheType != enum.CacheTypeInvalid && cacheType != q.CacheType {
+			continue
+		}
+		c := cacheCapacity{available: combinedStates.Caches[cacheName].IsAvailable}
+		if c.available {
+			if info, ok := latestResultInfo(statInfoHistory[cacheName]); ok {
+				c.kbps = float64(info.Vitals.KbpsOut)
+				c.maxKbps = float64(info.Vitals.MaxKbpsOut)
+			}
+			c.trend = kbpsTrend(statInfoHistory[cacheName])
+		}
+		caches[cacheName] = c
+	}
+
+	cacheGroupCaches := map[enum.CacheGroupName][]enum.CacheName{}
+	for cacheName := range caches {
+		cacheGroup := toData.ServerCachegroups[cacheName]
+		cacheGroupCaches[cacheGroup] = append(cacheGroupCaches[cacheGroup], cacheName)
+	}
+
+	capacity := Capacity{
+		CacheGroups:      map[enum.CacheGroupName]CapacityStat{},
+		DeliveryServices: map[enum.DeliveryServiceName]CapacityStat{},
+	}
+	for cacheGroup, cacheNames := range cacheGroupCaches {
+		capacity.CacheGroups[cacheGroup] = sumCapacity(q, caches, cacheNames)
+	}
+	for ds, cacheNames := range toData.DeliveryServiceServers {
+		dsCaches := []enum.CacheName{}
+		for _, cacheName := range cacheNames {
+			if _, ok := caches[cacheName]; ok {
+				dsCaches = append(dsCaches, cacheName)
+			}
+		}
+		if len(dsCaches) == 0 {
+			continue
+		}
+		capacity.DeliveryServices[ds] = sumCapacity(q, caches, dsCaches)
+	}
+	return capacity
+}
+
+// sumCapacity returns the capacity of the given caches. Caches which aren't in the given capacities are ignored.
+func sumCapacity(q CapacityQuery, caches map[enum.CacheName]cacheCapacity, cacheNames []enum.CacheName) CapacityStat {
+	s := CapacityStat{}
+	for _, cacheName := range cacheNames {
+		c, ok := caches[cacheName]
+		if !ok {
+			continue
+		}
+		s.Caches++
+		if !c.available {
+			continue
+		}
+		s.AvailableCaches++
+		s.Kbps += c.kbps
+		s.CapacityKbps += c.maxKbps
+		s.TrendKbpsPerSecond += c.trend
+	}
+	s.HeadroomKbps = s.CapacityKbps - s.Kbps
+	if s.CapacityKbps > 0 {
+		s.Utilization = s.Kbps / s.CapacityKbps
+	}
+	s.SecondsToSaturation = secondsToSaturation(s.Kbps, s.CapacityKbps*q.Threshold, s.TrendKbpsPerSecond)
+	return s
+}
+
+// secondsToSaturation returns the time until the given bandwidth reaches the saturation bandwidth, at the given trend in kbps per second. Returns nil if the bandwidth is below saturation, and isn't increasing.
+func secondsToSaturation(kbps float64, saturationKbps float64, trend float64) *float64 {
+	seconds := 0.0
+	if kbps >= saturationKbps {
+		return &seconds
+	}
+	if trend <= 0 {
+		return nil
+	}
+	seconds = (saturationKbps - kbps) / trend
+	return &seconds
+}
+
+// latestResultInfo returns the most recent non-errored result in the given history, and whether one exists.
+func latestResultInfo(history []cache.ResultInfo) (cache.ResultInfo, bool) {
+	for _, info := range history {
+		if info.Error == nil {
+			return info, true
+		}
+	}
+	return cache.ResultInfo{}, false
+}
+
+// kbpsTrend returns the least-squares linear trend of the bandwidth in the given history, in kbps per second. Errored results are ignored. If there are fewer than 2 results with distinct times, returns 0.
+func kbpsTrend(history []cache.ResultInfo) float64 {
+	n := 0.0
+	sumT, sumK, sumTT, sumTK := 0.0, 0.0, 0.0, 0.0
+	var start time.Time
+	for _, info := range history {
+		if info.Error != nil {
+			continue
+		}
+		if start.IsZero() {
+			start = info.Time
+		}
+		t := info.Time.Sub(start).Seconds() // relative to the latest result, to keep the sums small
+		k := float64(info.Vitals.KbpsOut)
+		n++
+		sumT += t
+		sumK += k
+		sumTT += t * t
+		sumTK += t * k
+	}
+	denominator := n*sumTT - sumT*sumT
+	if n < 2 || denominator == 0 {
+		return 0
+	}
+	return (n*sumTK - sumT*sumK) / denominator
+}
@@ -0,0 +1,115 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package datareq
+
+import (
+	"errors"
+	"math"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/cache"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
+)
+
+// testInfoHistory returns a stat history, newest first, of results 10 seconds apart whose kbps increases by the given kbps per second.
+func testInfoHistory(now time.Time, kbps int64, trend int64, maxKbps int64, n int) []cache.ResultInfo {
+	history := []cache.ResultInfo{}
+	for i := 0; i < n; i++ {
+		history = append(history, cache.ResultInfo{
+			Time:   now.Add(-time.Duration(i*10) * time.Second),
+			Vitals: cache.Vitals{KbpsOut: kbps - int64(i*10)*trend, MaxKbpsOut: maxKbps},
+		})
+	}
+	return history
+}
+
+func TestKbpsTrend(t *testing.T) {
+	now := time.Now()
+	history := testInfoHistory(now, 1000, 5, 10000, 5)
+	if trend := kbpsTrend(history); math.Abs(trend-5) > 0.0001 {
+		t.Errorf("kbpsTrend expected 5, actual %v", trend)
+	}
+	history[2].Error = errors.New("timeout")
+	history[2].Vitals = cache.Vitals{}
+	if trend := kbpsTrend(history); math.Abs(trend-5) > 0.0001 {
+		t.Errorf("kbpsTrend with errored result expected 5, actual %v", trend)
+	}
+	if trend := kbpsTrend(history[:1]); trend != 0 {
+		t.Errorf("kbpsTrend with one result expected 0, actual %v", trend)
+	}
+}
+
+func TestNewCapacityQuery(t *testing.T) {
+	if _, err := NewCapacityQuery(url.Values{"threshold": {"0"}}); err == nil {
+		t.Errorf("NewCapacityQuery with zero threshold expected error, actual nil")
+	}
+	if _, err := NewCapacityQuery(url.Values{"threshold": {"1.5"}}); err == nil {
+		t.Errorf("NewCapacityQuery with threshold over 1 expected error, actual nil")
+	}
+	q, err := NewCapacityQuery(url.Values{})
+	if err != nil || q.Threshold != DefaultCapacityThreshold || q.CacheType != enum.CacheTypeInvalid {
+		t.Errorf("NewCapacityQuery expected default threshold and no type, actual %+v %v", q, err)
+	}
+}
+
+func TestCreateCapacity(t *testing.T) {
+	now := time.Now()
+	toData := *todata.New()
+	states := peer.NewCrstates()
+	infos := cache.ResultInfoHistory{}
+	for _, name := range []enum.CacheName{"edge-a", "edge-b", "edge-c"} {
+		toData.ServerTypes[name] = enum.CacheTypeEdge
+		toData.ServerCachegroups[name] = "cg-1"
+		states.Caches[name] = peer.IsAvailable{IsAvailable: true}
+		infos[name] = testInfoHistory(now, 6000, 2, 10000, 5)
+	}
+	states.Caches["edge-c"] = peer.IsAvailable{IsAvailable: false}
+	toData.ServerTypes["mid-a"] = enum.CacheTypeMid
+	toData.ServerCachegroups["mid-a"] = "mid-cg"
+	states.Caches["mid-a"] = peer.IsAvailable{IsAvailable: true}
+	infos["mid-a"] = testInfoHistory(now, 1000, -1, 10000, 5)
+	toData.DeliveryServiceServers["ds-1"] = []enum.CacheName{"edge-a", "edge-c"}
+
+	capacity := createCapacity(CapacityQuery{Threshold: 0.9}, toData, infos, states)
+
+	cg := capacity.CacheGroups["cg-1"]
+	if cg.Caches != 3 || cg.AvailableCaches != 2 || cg.Kbps != 12000 || cg.CapacityKbps != 20000 || cg.HeadroomKbps != 8000 {
+		t.Errorf("createCapacity cg-1 expected 2 of 3 caches available with 12000 of 20000 kbps, actual %+v", cg)
+	}
+	// saturation at 18000 kbps, increasing 4 kbps per second
+	if cg.SecondsToSaturation == nil || math.Abs(*cg.SecondsToSaturation-1500) > 0.01 {
+		t.Errorf("createCapacity cg-1 expected 1500 seconds to saturation, actual %+v", cg.SecondsToSaturation)
+	}
+	if mid := capacity.CacheGroups["mid-cg"]; mid.SecondsToSaturation != nil {
+		t.Errorf("createCapacity decreasing mid-cg expected no saturation, actual %v", *mid.SecondsToSaturation)
+	}
+	if ds := capacity.DeliveryServices["ds-1"]; ds.Caches != 2 || ds.AvailableCaches != 1 || ds.HeadroomKbps != 4000 {
+		t.Errorf("createCapacity ds-1 expected 1 of 2 caches available with 4000 kbps headroom, actual %+v", ds)
+	}
+
+	capacity = createCapacity(CapacityQuery{Threshold: 1, CacheType: enum.CacheTypeMid}, toData, infos, states)
+	if _, ok := capacity.CacheGroups["cg-1"]; ok || len(capacity.DeliveryServices) != 0 {
+		t.Errorf("createCapacity mid type expected only mid cachegroups, actual %+v", capacity)
+	}
+}
@@ -20,22 +20,41 @@
 package datareq
 
 import (
+	"net/http"
 	"net/url"
 
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/threadsafe"
 )
 
-func srvTRState(params url.Values, localStates peer.CRStatesThreadsafe, combinedStates peer.CRStatesThreadsafe) ([]byte, error) {
+// srvCrStates returns a handler which serves the CR states in JSON, or in the compact binary encoding if the request accepts it, as peers do. Either is gzipped if the request accepts gzip.
+func srvCrStates(errorCount threadsafe.Uint, localStates peer.CRStatesThreadsafe, combinedStates peer.CRStatesThreadsafe) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		contentType := ContentTypeJSON
+		marshal := peer.CrstatesMarshall
+		if acceptsMediaType(r, peer.ContentTypeCrstatesBinary) {
+			contentType = peer.ContentTypeCrstatesBinary
+			marshal = peer.CrstatesMarshallBinary
+		}
+		w.Header().Set("Vary", "Accept, Accept-Encoding")
+		WrapParams(func(params url.Values, path string) ([]byte, int) {
+			bytes, err := srvTRState(params, marshal, localStates, combinedStates)
+			return WrapErrCode(errorCount, path, bytes, err)
+		}, contentType)(w, r)
+	}
+}
+
+func srvTRState(params url.Values, marshal func(peer.Crstates) ([]byte, error), localStates peer.CRStatesThreadsafe, combinedStates peer.CRStatesThreadsafe) ([]byte, error) {
 	if _, raw := params["raw"]; raw {
-		return srvTRStateSelf(localStates)
+		return srvTRStateSelf(marshal, localStates)
 	}
-	return srvTRStateDerived(combinedStates)
+	return srvTRStateDerived(marshal, combinedStates)
 }
 
-func srvTRStateDerived(combinedStates peer.CRStatesThreadsafe) ([]byte, error) {
-	return peer.CrstatesMarshall(combinedStates.Get())
+func srvTRStateDerived(marshal func(peer.Crstates) ([]byte, error), combinedStates peer.CRStatesThreadsafe) ([]byte, error) {
+	return marshal(combinedStates.Get())
 }
 
-func srvTRStateSelf(localStates peer.CRStatesThreadsafe) ([]byte, error) {
-	return peer.CrstatesMarshall(localStates.Get())
+func srvTRStateSelf(marshal func(peer.Crstates) ([]byte, error), localStates peer.CRStatesThreadsafe) ([]byte, error) {
+	return marshal(localStates.Get())
 }
@@ -0,0 +1,92 @@
+package datareq
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/threadsafe"
+)
+
+func TestSrvCrStates(t *testing.T) {
+	localStates := peer.NewCRStatesThreadsafe()
+	localStates.SetCache("edge-a", peer.NewIsAvailable(true, false))
+	combinedStates := peer.NewCRStatesThreadsafe()
+	h := srvCrStates(threadsafe.NewUint(), localStates, combinedStates)
+
+	tests := []struct {
+		accept      string
+		gzip        bool
+		contentType string
+	}{
+		{"", false, ContentTypeJSON},
+		{"application/json", true, ContentTypeJSON},
+		{peer.AcceptCrstates, false, peer.ContentTypeCrstatesBinary},
+		{peer.AcceptCrstates, true, peer.ContentTypeCrstatesBinary},
+		{"*/*", false, ContentTypeJSON},
+		{peer.ContentTypeCrstatesBinary + ";q=0, application/json", false, ContentTypeJSON},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/publish/CrStates?raw", nil)
+		if test.accept != "" {
+			r.Header.Set("Accept", test.accept)
+		}
+		if test.gzip {
+			r.Header.Set("Accept-Encoding", "gzip")
+		}
+		w := httptest.NewRecorder()
+		h(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("srvCrStates Accept '%v' expected %v, actual %v", test.accept, http.StatusOK, w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != test.contentType {
+			t.Errorf("srvCrStates Accept '%v' expected Content-Type %v, actual %v", test.accept, test.contentType, ct)
+		}
+		body := w.Body.Bytes()
+		if test.gzip {
+			if w.Header().Get("Content-Encoding") != "gzip" {
+				t.Fatalf("srvCrStates Accept-Encoding gzip expected gzip Content-Encoding, actual '%v'", w.Header().Get("Content-Encoding"))
+			}
+			zr, err := gzip.NewReader(w.Body)
+			if err != nil {
+				t.Fatalf("srvCrStates gzip reader expected nil error, actual %v", err)
+			}
+			if body, err = ioutil.ReadAll(zr); err != nil {
+				t.Fatalf("srvCrStates gunzip expected nil error, actual %v", err)
+			}
+		}
+		unmarshal := peer.CrstatesUnMarshall
+		if test.contentType == peer.ContentTypeCrstatesBinary {
+			unmarshal = peer.CrstatesUnMarshallBinary
+		}
+		states, err := unmarshal(body)
+		if err != nil {
+			t.Errorf("srvCrStates Accept '%v' decoding expected nil error, actual %v", test.accept, err)
+		} else if !reflect.DeepEqual(states.Caches, localStates.Get().Caches) {
+			t.Errorf("srvCrStates Accept '%v' expected caches %+v, actual %+v", test.accept, localStates.Get().Caches, states.Caches)
+		}
+	}
+}
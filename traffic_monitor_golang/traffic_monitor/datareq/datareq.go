@@ -38,6 +38,29 @@ import (
 	towrap "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopswrapper"
 )
 
+// PublicPaths are the health protocol endpoints, which Traffic Router and peer Traffic Monitors poll without authenticating. These never require authentication.
+var PublicPaths = []string{
+	"/publish/CrStates",
+	"/publish/CrConfig",
+	APIV2Prefix + "/crstates",
+	APIV2Prefix + "/crconfig",
+}
+
+// AdminPaths are the endpoints which expose the monitor's configuration, and require the admin scope when authentication is enabled.
+var AdminPaths = []string{
+	"/publish/ConfigDoc",
+	"/api/traffic-ops-uri",
+	"/api/monitor-config",
+	APIV2Prefix + "/config-doc",
+	APIV2Prefix + "/traffic-ops-uri",
+	APIV2Prefix + "/monitor-config",
+}
+
+// PeerPaths are the endpoints peer Traffic Monitors request to change this monitor's peer set. They require the peer gossip secret, or the admin scope, even if authentication is otherwise disabled.
+var PeerPaths = []string{
+	PeerAnnouncePath,
+}
+
 // MakeDispatchMap returns the map of paths to http.HandlerFuncs for dispatching.
 func MakeDispatchMap(
 	opsConfig threadsafe.OpsConfig,
@@ -62,6 +85,9 @@ func MakeDispatchMap(
 	lastStats threadsafe.LastStats,
 	unpolledCaches threadsafe.UnpolledCaches,
 	monitorConfig threadsafe.TrafficMonitorConfigMap,
+	cacheGroupMinAvailable float64,
+	membership peer.MembershipThreadsafe,
+	peerGossip bool,
 ) map[string]http.HandlerFunc {
 
 	// wrap composes all universal wrapper functions. Right now, it's only the UnpolledCheck, but there may be others later. For example, security headers.
@@ -73,10 +99,7 @@ func MakeDispatchMap(
 		"/publish/CrConfig": wrap(WrapAgeErr(errorCount, func() ([]byte, time.Time, error) {
 			return srvTRConfig(opsConfig, toSession)
 		}, ContentTypeJSON)),
-		"/publish/CrStates": wrap(WrapParams(func(params url.Values, path string) ([]byte, int) {
-			bytes, err := srvTRState(params, localStates, combinedStates)
-			return WrapErrCode(errorCount, path, bytes, err)
-		}, ContentTypeJSON)),
+		"/publish/CrStates": wrap(srvCrStates(errorCount, localStates, combinedStates)),
 		"/publish/CacheStats": wrap(WrapParams(func(params url.Values, path string) ([]byte, int) {
 			return srvCacheStats(params, errorCount, path, toData, statResultHistory, statInfoHistory, monitorConfig, combinedStates, statMaxKbpses)
 		}, ContentTypeJSON)),
@@ -87,7 +110,7 @@ func MakeDispatchMap(
 			return srvEventLog(events)
 		}, ContentTypeJSON)),
 		"/publish/PeerStates": wrap(WrapParams(func(params url.Values, path string) ([]byte, int) {
-			return srvPeerStates(params, errorCount, path, toData, peerStates)
+			return srvPeerStates(params, errorCount, path, toData, peerStates, membership)
 		}, ContentTypeJSON)),
 		"/publish/Stats": wrap(WrapErr(errorCount, func() ([]byte, error) {
 			return srvStats(staticAppData, healthPollInterval, lastHealthDurations, fetchCount, healthIteration, errorCount, peerStates)
@@ -125,7 +148,23 @@ func MakeDispatchMap(
 		"/api/monitor-config": wrap(WrapErr(errorCount, func() ([]byte, error) {
 			return srvMonitorConfig(monitorConfig)
 		}, ContentTypeJSON)),
+		"/api/top/caches": wrap(WrapParams(func(params url.Values, path string) ([]byte, int) {
+			return srvTopCaches(params, errorCount, path, toData, statResultHistory, statInfoHistory, monitorConfig, combinedStates)
+		}, ContentTypeJSON)),
+		"/api/top/deliveryservices": wrap(WrapParams(func(params url.Values, path string) ([]byte, int) {
+			return srvTopDeliveryServices(params, errorCount, path, toData, dsStats)
+		}, ContentTypeJSON)),
+		DSCachesPathPrefix: wrap(WrapParams(func(params url.Values, path string) ([]byte, int) {
+			return srvDSCaches(params, errorCount, path, toData, dsStats, combinedStates)
+		}, ContentTypeJSON)),
+	}
+
+	if peerGossip {
+		dispatchMap[PeerAnnouncePath] = srvPeerAnnounce(errorCount, membership) // not wrapped, peers must be able to announce while this monitor is starting. Announcements are authenticated by the server, see PeerPaths.
 	}
+
+	v2Endpoints := makeV2Endpoints(opsConfig, toSession, localStates, peerStates, combinedStates, statInfoHistory, statResultHistory, statMaxKbpses, healthHistory, dsStats, events, staticAppData, healthPollInterval, lastHealthDurations, fetchCount, healthIteration, errorCount, toData, localCacheStatus, lastStats, monitorConfig, cacheGroupMinAvailable)
+	dispatchMap = addV2Endpoints(dispatchMap, v2Endpoints, staticAppData.Version, errorCount, wrap)
 	return addTrailingSlashEndpoints(dispatchMap)
 }
 
@@ -313,12 +352,34 @@ func gzipIfAccepts(r *http.Request, w http.ResponseWriter, b []byte) ([]byte, er
 	zw := gzip.NewWriter(&buf)
 
 	if _, err := zw.Write(b); err != nil {
-		return nil, fmt.Errorf("gzipping bytes: %v")
+		return nil, fmt.Errorf("gzipping bytes: %v", err)
 	}
 
 	if err := zw.Close(); err != nil {
-		return nil, fmt.Errorf("closing gzip writer: %v")
+		return nil, fmt.Errorf("closing gzip writer: %v", err)
 	}
 
 	return buf.Bytes(), nil
 }
+
+// acceptsMediaType returns whether the request's Accept header includes the given media type, with a nonzero quality. Wildcards are not matched, so requests only get a media type other than the default if they explicitly ask for it.
+func acceptsMediaType(r *http.Request, mediaType string) bool {
+	for _, acceptHeader := range r.Header["Accept"] {
+		for _, accept := range strings.Split(stripAllWhitespace(acceptHeader), ",") {
+			params := strings.Split(accept, ";")
+			if !strings.EqualFold(params[0], mediaType) {
+				continue
+			}
+			accepted := true
+			for _, param := range params[1:] {
+				if q := strings.TrimPrefix(strings.ToLower(param), "q="); q != param && strings.Trim(q, "0.") == "" {
+					accepted = false // q=0 means not acceptable, per RFC 7231
+				}
+			}
+			if accepted {
+				return true
+			}
+		}
+	}
+	return false
+}
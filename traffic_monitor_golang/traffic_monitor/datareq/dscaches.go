@@ -0,0 +1,185 @@
+package datareq
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	dsdata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/deliveryservicedata"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/srvhttp"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/threadsafe"
+	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
+)
+
+// DSCachesPathPrefix is the path prefix of the delivery service caches endpoint, which is served at /api/deliveryservice/{ds}/caches.
+const DSCachesPathPrefix = "/api/deliveryservice/"
+
+// DSCachesPathSuffix is the path suffix of the delivery service caches endpoint.
+const DSCachesPathSuffix = "/caches"
+
+// DSCaches is the JSON object returned by the /api/deliveryservice/{ds}/caches endpoint, containing each cache's contribution to the delivery service's traffic and errors.
+type DSCaches struct {
+	srvhttp.CommonAPIData
+	DeliveryService enum.DeliveryServiceName `json:"deliveryService"`
+	Caches          []DSCache                `json:"caches"`
+}
+
+// DSCache is a single cache's stats for a delivery service. Reporting is whether the cache returned stats for the delivery service in its last poll; caches assigned to the delivery service which aren't reporting are included with zero stats, since a cache not reporting is often the misbehaving one.
+type DSCache struct {
+	Name       enum.CacheName      `json:"name"`
+	CacheGroup enum.CacheGroupName `json:"cachegroup"`
+	Type       enum.CacheType      `json:"type"`
+	Available  bool                `json:"available"`
+	Reporting  bool                `json:"reporting"`
+	Kbps       float64             `json:"kbps"`
+	TpsTotal   float64             `json:"tps_total"`
+	Tps2xx     float64             `json:"tps_2xx"`
+	Tps3xx     float64             `json:"tps_3xx"`
+	Tps4xx     float64             `json:"tps_4xx"`
+	Tps5xx     float64             `json:"tps_5xx"`
+	Status2xx  int64               `json:"status_2xx"`
+	Status3xx  int64               `json:"status_3xx"`
+	Status4xx  int64               `json:"status_4xx"`
+	Status5xx  int64               `json:"status_5xx"`
+	OutBytes   int64               `json:"out_bytes"`
+}
+
+// DSCachesQuery is the filter requested of the delivery service caches endpoint. If CacheGroup or CacheType is empty, caches aren't filtered by it.
+type DSCachesQuery struct {
+	DeliveryService enum.DeliveryServiceName
+	CacheGroup      enum.CacheGroupName
+	CacheType       enum.CacheType
+}
+
+// NewDSCachesQuery takes the request path and HTTP query parameters, and creates a DSCachesQuery. The delivery service is taken from the path, which must be of the form /api/deliveryservice/{ds}/caches.
+// Query parameters used are `cachegroup` and `type`.
+func NewDSCachesQuery(path string, params url.Values) (DSCachesQuery, error) {
+	invalidPathErr := fmt.Errorf("invalid path '%v', must be %v{ds}%v", path, DSCachesPathPrefix, DSCachesPathSuffix)
+	trimmed := strings.TrimSuffix(path, "/")
+	if !strings.HasPrefix(trimmed, DSCachesPathPrefix) {
+		return DSCachesQuery{}, invalidPathErr
+	}
+	rest := strings.TrimPrefix(trimmed, DSCachesPathPrefix)
+	if !strings.HasSuffix(rest, DSCachesPathSuffix) {
+		return DSCachesQuery{}, invalidPathErr
+	}
+	ds, err := url.PathUnescape(strings.TrimSuffix(rest, DSCachesPathSuffix))
+	if err != nil || ds == "" || strings.Contains(ds, "/") {
+		return DSCachesQuery{}, invalidPathErr
+	}
+
+	for param := range params {
+		if param != "cachegroup" && param != "type" {
+			return DSCachesQuery{}, fmt.Errorf("invalid query parameter '%v'", param)
+		}
+	}
+
+	q := DSCachesQuery{DeliveryService: enum.DeliveryServiceName(ds), CacheGroup: enum.CacheGroupName(params.Get("cachegroup"))}
+	if paramType := params.Get("type"); paramType != "" {
+		q.CacheType = enum.CacheTypeFromString(paramType)
+		if q.CacheType == enum.CacheTypeInvalid {
+			return DSCachesQuery{}, fmt.Errorf("invalid cache type '%v'", paramType)
+		}
+	}
+	return q, nil
+}
+
+func srvDSCaches(params url.Values, errorCount threadsafe.Uint, path string, toData todata.TODataThreadsafe, dsStats threadsafe.DSStatsReader, combinedStates peer.CRStatesThreadsafe) ([]byte, int) {
+	q, err := NewDSCachesQuery(path, params)
+	if err != nil {
+		HandleErr(errorCount, path, err)
+		return []byte(err.Error()), http.StatusBadRequest
+	}
+	dsCaches, ok := createDSCaches(q, toData.Get(), dsStats.Get(), combinedStates.Get(), params)
+	if !ok {
+		err := fmt.Errorf("delivery service '%v' not found", q.DeliveryService)
+		HandleErr(errorCount, path, err)
+		return []byte(err.Error()), http.StatusNotFound
+	}
+	bytes, err := json.Marshal(dsCaches)
+	return WrapErrCode(errorCount, path, bytes, err)
+}
+
+// createDSCaches returns the stats of each cache assigned to, or reporting stats for, the requested delivery service, sorted by name. Returns false if the delivery service doesn't exist.
+func createDSCaches(q DSCachesQuery, toData todata.TOData, dsStats dsdata.StatsReadonly, combinedStates peer.Crstates, params url.Values) (DSCaches, bool) {
+	stat, statOk := dsStats.Get(q.DeliveryService)
+	servers, serversOk := toData.DeliveryServiceServers[q.DeliveryService]
+	if !statOk && !serversOk {
+		return DSCaches{}, false
+	}
+
+	cacheNames := map[enum.CacheName]struct{}{}
+	for _, cacheName := range servers {
+		cacheNames[cacheName] = struct{}{}
+	}
+	reporting := map[enum.CacheName]struct{}{}
+	if statOk {
+		for _, cacheName := range stat.Common().CachesReportingNames() {
+			cacheNames[cacheName] = struct{}{}
+			reporting[cacheName] = struct{}{}
+		}
+	}
+
+	caches := []DSCache{}
+	for cacheName := range cacheNames {
+		cacheGroup := toData.ServerCachegroups[cacheName]
+		cacheType := toData.ServerTypes[cacheName]
+		if q.CacheGroup != "" && cacheGroup != q.CacheGroup {
+			continue
+		}
+		if q.CacheType != enum.CacheTypeInvalid && cacheType != q.CacheType {
+			continue
+		}
+		c := DSCache{Name: cacheName, CacheGroup: cacheGroup, Type: cacheType, Available: combinedStates.Caches[cacheName].IsAvailable}
+		_, c.Reporting = reporting[cacheName]
+		if statOk {
+			if s, ok := stat.Cache(cacheName); ok {
+				c.Kbps = s.Kbps.Value
+				c.TpsTotal = s.TpsTotal.Value
+				c.Tps2xx = s.Tps2xx.Value
+				c.Tps3xx = s.Tps3xx.Value
+				c.Tps4xx = s.Tps4xx.Value
+				c.Tps5xx = s.Tps5xx.Value
+				c.Status2xx = s.Status2xx.Value
+				c.Status3xx = s.Status3xx.Value
+				c.Status4xx = s.Status4xx.Value
+				c.Status5xx = s.Status5xx.Value
+				c.OutBytes = s.OutBytes.Value
+			}
+		}
+		caches = append(caches, c)
+	}
+	sort.Sort(dsCachesByName(caches))
+	return DSCaches{CommonAPIData: srvhttp.GetCommonAPIData(params, time.Now()), DeliveryService: q.DeliveryService, Caches: caches}, true
+}
+
+type dsCachesByName []DSCache
+
+func (c dsCachesByName) Len() int           { return len(c) }
+func (c dsCachesByName) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c dsCachesByName) Less(i, j int) bool { return c[i].Name < c[j].Name }
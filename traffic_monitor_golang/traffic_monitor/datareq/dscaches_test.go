@@ -0,0 +1,96 @@
+package datareq
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"net/url"
+	"testing"
+
+	dsdata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/deliveryservicedata"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
+)
+
+func TestNewDSCachesQuery(t *testing.T) {
+	invalid := []string{"/api/deliveryservice/", "/api/deliveryservice/caches", "/api/deliveryservice/ds-a", "/api/deliveryservice/a/b/caches", "/api/deliveryservice//caches"}
+	for _, path := range invalid {
+		if _, err := NewDSCachesQuery(path, url.Values{}); err == nil {
+			t.Errorf("NewDSCachesQuery %v expected error, actual nil", path)
+		}
+	}
+	if _, err := NewDSCachesQuery("/api/deliveryservice/ds-a/caches", url.Values{"foo": {"bar"}}); err == nil {
+		t.Errorf("NewDSCachesQuery with invalid parameter expected error, actual nil")
+	}
+	q, err := NewDSCachesQuery("/api/deliveryservice/ds-a/caches/", url.Values{"cachegroup": {"cg-1"}, "type": {"edge"}})
+	if err != nil {
+		t.Fatalf("NewDSCachesQuery expected nil error, actual %v", err)
+	}
+	if q.DeliveryService != "ds-a" || q.CacheGroup != "cg-1" || q.CacheType != enum.CacheTypeEdge {
+		t.Errorf("NewDSCachesQuery expected ds-a cg-1 edge, actual %+v", q)
+	}
+}
+
+func TestCreateDSCaches(t *testing.T) {
+	toData := *todata.New()
+	toData.DeliveryServiceServers["ds-a"] = []enum.CacheName{"edge-a", "edge-b", "edge-c"}
+	for _, name := range []enum.CacheName{"edge-a", "edge-b", "edge-c"} {
+		toData.ServerCachegroups[name] = "cg-1"
+		toData.ServerTypes[name] = enum.CacheTypeEdge
+	}
+	toData.ServerCachegroups["edge-c"] = "cg-2"
+
+	stat := dsdata.NewStat()
+	stat.CommonStats.CachesReporting["edge-a"] = true
+	stat.CommonStats.CachesReporting["edge-b"] = true
+	stat.Caches["edge-a"] = dsdata.StatCacheStats{Kbps: dsdata.StatFloat{Value: 100}, Tps5xx: dsdata.StatFloat{Value: 0.5}, Status5xx: dsdata.StatInt{Value: 12}}
+	stat.Caches["edge-b"] = dsdata.StatCacheStats{Kbps: dsdata.StatFloat{Value: 200}, Tps5xx: dsdata.StatFloat{Value: 40}, Status5xx: dsdata.StatInt{Value: 900}}
+	dsStats := dsdata.NewStats()
+	dsStats.DeliveryService["ds-a"] = *stat
+
+	crStates := peer.NewCrstates()
+	crStates.Caches["edge-a"] = peer.IsAvailable{IsAvailable: true}
+
+	if _, ok := createDSCaches(DSCachesQuery{DeliveryService: "ds-nonexistent"}, toData, dsStats, crStates, url.Values{}); ok {
+		t.Errorf("createDSCaches nonexistent delivery service expected not found, actual found")
+	}
+
+	dsCaches, ok := createDSCaches(DSCachesQuery{DeliveryService: "ds-a"}, toData, dsStats, crStates, url.Values{})
+	if !ok {
+		t.Fatalf("createDSCaches expected found, actual not found")
+	}
+	if len(dsCaches.Caches) != 3 || dsCaches.Caches[0].Name != "edge-a" || dsCaches.Caches[1].Name != "edge-b" || dsCaches.Caches[2].Name != "edge-c" {
+		t.Fatalf("createDSCaches expected [edge-a edge-b edge-c], actual %+v", dsCaches.Caches)
+	}
+	if c := dsCaches.Caches[0]; !c.Available || !c.Reporting || c.Kbps != 100 || c.Tps5xx != 0.5 || c.Status5xx != 12 {
+		t.Errorf("createDSCaches edge-a expected available reporting 100kbps 0.5 tps_5xx 12 status_5xx, actual %+v", c)
+	}
+	if c := dsCaches.Caches[1]; c.Available || c.Tps5xx != 40 || c.Status5xx != 900 {
+		t.Errorf("createDSCaches edge-b expected unavailable 40 tps_5xx 900 status_5xx, actual %+v", c)
+	}
+	if c := dsCaches.Caches[2]; c.Reporting || c.Kbps != 0 {
+		t.Errorf("createDSCaches edge-c expected not reporting with no stats, actual %+v", c)
+	}
+
+	dsCaches, _ = createDSCaches(DSCachesQuery{DeliveryService: "ds-a", CacheGroup: "cg-2"}, toData, dsStats, crStates, url.Values{})
+	if len(dsCaches.Caches) != 1 || dsCaches.Caches[0].Name != "edge-c" {
+		t.Errorf("createDSCaches cachegroup expected [edge-c], actual %+v", dsCaches.Caches)
+	}
+}
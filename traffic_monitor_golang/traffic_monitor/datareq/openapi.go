@@ -0,0 +1,227 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package datareq
+
+import (
+	"net/http"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/health"
+)
+
+// OpenAPIVersion is the version of the OpenAPI specification the generated document conforms to.
+const OpenAPIVersion = "3.0.0"
+
+// OpenAPI is an OpenAPI document, describing the /api/v2 endpoints. It's generated at runtime from the endpoint definitions and their response types, so it's always in sync with what's served.
+type OpenAPI struct {
+	OpenAPI    string                 `json:"openapi"`
+	Info       OpenAPIInfo            `json:"info"`
+	Paths      map[string]OpenAPIPath `json:"paths"`
+	Components OpenAPIComponents      `json:"components"`
+}
+
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type OpenAPIPath struct {
+	Get *OpenAPIOperation `json:"get,omitempty"`
+}
+
+type OpenAPIOperation struct {
+	Summary     string                     `json:"summary"`
+	OperationID string                     `json:"operationId"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+}
+
+type OpenAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *OpenAPISchema `json:"schema"`
+}
+
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+type OpenAPIComponents struct {
+	Schemas map[string]*OpenAPISchema `json:"schemas"`
+}
+
+// OpenAPISchema is the subset of the OpenAPI Schema Object necessary to describe the Go types returned by the API. An empty schema allows any value.
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+}
+
+// openAPISchemaOverrides are the schemas of types which implement json.Marshaler, whose JSON can't be determined by reflection.
+var openAPISchemaOverrides = map[reflect.Type]OpenAPISchema{
+	reflect.TypeOf(time.Time{}):   OpenAPISchema{Type: "string", Format: "date-time"},
+	reflect.TypeOf(health.Time{}): OpenAPISchema{Type: "integer", Format: "int64", Description: "seconds since the epoch"},
+}
+
+// NewOpenAPI generates the OpenAPI document for the given endpoints. Every response is described as a V2Response envelope, whose `response` is the endpoint's Response type.
+func NewOpenAPI(endpoints []V2Endpoint, version string) OpenAPI {
+	doc := OpenAPI{
+		OpenAPI:    OpenAPIVersion,
+		Info:       OpenAPIInfo{Title: "Traffic Monitor API", Description: "Every response is an object containing the requested data in `response`, and any errors or warnings in `alerts`.", Version: version},
+		Paths:      map[string]OpenAPIPath{},
+		Components: OpenAPIComponents{Schemas: map[string]*OpenAPISchema{}},
+	}
+	alertsSchema := &OpenAPISchema{Type: "array", Items: doc.schema(reflect.TypeOf(V2Alert{}))}
+	errResponse := OpenAPIResponse{Description: "Error", Content: map[string]OpenAPIMediaType{ContentTypeJSON: {Schema: &OpenAPISchema{Type: "object", Properties: map[string]*OpenAPISchema{"alerts": alertsSchema}}}}}
+
+	for _, endpoint := range endpoints {
+		op := &OpenAPIOperation{
+			Summary:     endpoint.Summary,
+			OperationID: openAPIOperationID(endpoint.Path),
+			Responses: map[string]OpenAPIResponse{
+				"200": OpenAPIResponse{Description: "OK", Content: map[string]OpenAPIMediaType{ContentTypeJSON: {Schema: &OpenAPISchema{
+					Type: "object",
+					Properties: map[string]*OpenAPISchema{
+						"response": doc.schema(reflect.TypeOf(endpoint.Response)),
+						"alerts":   alertsSchema,
+					},
+				}}}},
+				"400": errResponse,
+				"500": errResponse,
+				"503": errResponse,
+			},
+		}
+		for _, param := range endpoint.Params {
+			op.Parameters = append(op.Parameters, OpenAPIParameter{Name: param.Name, In: "query", Description: param.Description, Required: param.Required, Schema: &OpenAPISchema{Type: param.Type}})
+		}
+		doc.Paths[endpoint.Path] = OpenAPIPath{Get: op}
+	}
+	return doc
+}
+
+// openAPIOperationID returns a unique operation ID for the given path, e.g. `/api/v2/top/caches` becomes `getTopCaches`.
+func openAPIOperationID(p string) string {
+	id := "get"
+	for _, part := range strings.FieldsFunc(strings.TrimPrefix(p, APIV2Prefix), func(r rune) bool { return r == '/' || r == '-' || r == '.' }) {
+		id += strings.Title(part)
+	}
+	return id
+}
+
+// schema returns the schema of the given type. Named struct types are added to the document's components, and referenced.
+func (doc OpenAPI) schema(t reflect.Type) *OpenAPISchema {
+	if t == nil {
+		return &OpenAPISchema{}
+	}
+	if override, ok := openAPISchemaOverrides[t]; ok {
+		return &override
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return doc.schema(t.Elem())
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &OpenAPISchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &OpenAPISchema{Type: "number"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &OpenAPISchema{Type: "string", Format: "byte"}
+		}
+		return &OpenAPISchema{Type: "array", Items: doc.schema(t.Elem())}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: doc.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return doc.structSchema(t)
+		}
+		name := path.Base(t.PkgPath()) + "." + t.Name()
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			doc.Components.Schemas[name] = &OpenAPISchema{} // placeholder, in case the type is recursive
+			*doc.Components.Schemas[name] = *doc.structSchema(t)
+		}
+		return &OpenAPISchema{Ref: "#/components/schemas/" + name}
+	}
+	return &OpenAPISchema{} // interface{}, and anything else, may be any value
+}
+
+// structSchema returns the object schema of the given struct type, with a property for each field encoded by encoding/json.
+func (doc OpenAPI) structSchema(t reflect.Type) *OpenAPISchema {
+	s := &OpenAPISchema{Type: "object", Properties: map[string]*OpenAPISchema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if comma := strings.Index(tag, ","); comma != -1 {
+			name, opts = tag[:comma], tag[comma+1:]
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for k, v := range doc.structSchema(embedded).Properties {
+					s.Properties[k] = v
+				}
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(opts, "string") {
+			s.Properties[name] = &OpenAPISchema{Type: "string"}
+			continue
+		}
+		s.Properties[name] = doc.schema(field.Type)
+	}
+	return s
+}
+
+func srvOpenAPI(doc OpenAPI) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, r, doc, http.StatusOK)
+	}
+}
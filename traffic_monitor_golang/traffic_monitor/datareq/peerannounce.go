@@ -0,0 +1,68 @@
+package datareq
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/log"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/threadsafe"
+)
+
+// PeerAnnouncePath is the path peer Traffic Monitors POST their gossip announcements to, when peer gossip is enabled.
+const PeerAnnouncePath = "/api/peers/announce"
+
+// MaxPeerAnnounceBytes is the maximum size of a peer announcement request body.
+const MaxPeerAnnounceBytes = 1 << 20
+
+// srvPeerAnnounce returns a handler which adds the peers in POSTed announcements to the membership.
+func srvPeerAnnounce(errorCount threadsafe.Uint, membership peer.MembershipThreadsafe) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.EscapedPath()
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			log.Write(w, []byte(http.StatusText(http.StatusMethodNotAllowed)), path)
+			return
+		}
+		a := peer.Announcement{}
+		if err := json.NewDecoder(io.LimitReader(r.Body, MaxPeerAnnounceBytes)).Decode(&a); err != nil {
+			handlePeerAnnounceErr(w, errorCount, path, fmt.Errorf("decoding announcement: %v", err))
+			return
+		}
+		if a.Name == "" || a.URL == "" {
+			handlePeerAnnounceErr(w, errorCount, path, fmt.Errorf("announcement must have a name and url"))
+			return
+		}
+		membership.Announce(a, time.Now())
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func handlePeerAnnounceErr(w http.ResponseWriter, errorCount threadsafe.Uint, path string, err error) {
+	HandleErr(errorCount, path, err)
+	w.WriteHeader(http.StatusBadRequest)
+	log.Write(w, []byte(err.Error()), path)
+}
@@ -0,0 +1,61 @@
+package datareq
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/log"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/threadsafe"
+)
+
+func TestSrvPeerAnnounce(t *testing.T) {
+	discard := log.NopCloser(ioutil.Discard)
+	log.Init(discard, discard, discard, discard, discard)
+
+	membership := peer.NewMembershipThreadsafe("tm-self")
+	h := srvPeerAnnounce(threadsafe.NewUint(), membership)
+
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodGet, PeerAnnouncePath, nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("srvPeerAnnounce GET expected %v, actual %v", http.StatusMethodNotAllowed, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodPost, PeerAnnouncePath, strings.NewReader(`{"name": "tm-a"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("srvPeerAnnounce without url expected %v, actual %v", http.StatusBadRequest, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodPost, PeerAnnouncePath, strings.NewReader(`{"name": "tm-a", "url": "http://tm-a.example.net", "peers": {"tm-b": "http://tm-b.example.net"}}`)))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("srvPeerAnnounce expected %v, actual %v", http.StatusNoContent, w.Code)
+	}
+	if members := membership.Get(); len(members) != 2 || members["tm-a"].LastSeen.IsZero() {
+		t.Errorf("srvPeerAnnounce expected tm-a and tm-b members, actual %+v", members)
+	}
+}
@@ -32,10 +32,11 @@ import (
 	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
 )
 
-// APIPeerStates contains the data to be returned for an API call to get the peer states of a Traffic Monitor. This contains common API data returned by most endpoints, and a map of peers, to caches' states.
+// APIPeerStates contains the data to be returned for an API call to get the peer states of a Traffic Monitor. This contains common API data returned by most endpoints, a map of peers, to caches' states, and the peer set membership: each peer's URL, the sources it was learned from, and when it was last seen.
 type APIPeerStates struct {
 	srvhttp.CommonAPIData
-	Peers map[enum.TrafficMonitorName]map[enum.CacheName][]CacheState `json:"peers"`
+	Peers      map[enum.TrafficMonitorName]map[enum.CacheName][]CacheState `json:"peers"`
+	Membership map[enum.TrafficMonitorName]peer.Member                     `json:"membership"`
 }
 
 // CacheState represents the available state of a cache.
@@ -43,20 +44,27 @@ type CacheState struct {
 	Value bool `json:"value"`
 }
 
-func srvPeerStates(params url.Values, errorCount threadsafe.Uint, path string, toData todata.TODataThreadsafe, peerStates peer.CRStatesPeersThreadsafe) ([]byte, int) {
+func srvPeerStates(params url.Values, errorCount threadsafe.Uint, path string, toData todata.TODataThreadsafe, peerStates peer.CRStatesPeersThreadsafe, membership peer.MembershipThreadsafe) ([]byte, int) {
 	filter, err := NewPeerStateFilter(path, params, toData.Get().ServerTypes)
 	if err != nil {
 		HandleErr(errorCount, path, err)
 		return []byte(err.Error()), http.StatusBadRequest
 	}
-	bytes, err := json.Marshal(createAPIPeerStates(peerStates.GetCrstates(), peerStates.GetPeersOnline(), filter, params))
+	bytes, err := json.Marshal(createAPIPeerStates(peerStates.GetCrstates(), peerStates.GetPeersOnline(), membership.Get(), filter, params))
 	return WrapErrCode(errorCount, path, bytes, err)
 }
 
-func createAPIPeerStates(peerStates map[enum.TrafficMonitorName]peer.Crstates, peersOnline map[enum.TrafficMonitorName]bool, filter *PeerStateFilter, params url.Values) APIPeerStates {
+func createAPIPeerStates(peerStates map[enum.TrafficMonitorName]peer.Crstates, peersOnline map[enum.TrafficMonitorName]bool, members map[enum.TrafficMonitorName]peer.Member, filter *PeerStateFilter, params url.Values) APIPeerStates {
 	apiPeerStates := APIPeerStates{
 		CommonAPIData: srvhttp.GetCommonAPIData(params, time.Now()),
 		Peers:         map[enum.TrafficMonitorName]map[enum.CacheName][]CacheState{},
+		Membership:    map[enum.TrafficMonitorName]peer.Member{},
+	}
+
+	for name, member := range members {
+		if filter.UsePeer(name) {
+			apiPeerStates.Membership[name] = member
+		}
 	}
 
 	for peer, state := range peerStates {
@@ -71,6 +71,10 @@ func srvStats(staticAppData config.StaticAppData, healthPollInterval time.Durati
 }
 
 func getStats(staticAppData config.StaticAppData, pollingInterval time.Duration, lastHealthTimes map[enum.CacheName]time.Duration, fetchCount uint64, healthIteration uint64, errorCount uint64, peerStates peer.CRStatesPeersThreadsafe) ([]byte, error) {
+	return json.Marshal(JSONStats{Stats: createStats(staticAppData, pollingInterval, lastHealthTimes, fetchCount, healthIteration, errorCount, peerStates)})
+}
+
+func createStats(staticAppData config.StaticAppData, pollingInterval time.Duration, lastHealthTimes map[enum.CacheName]time.Duration, fetchCount uint64, healthIteration uint64, errorCount uint64, peerStates peer.CRStatesPeersThreadsafe) Stats {
 	longestPollCache, longestPollTime := getLongestPoll(lastHealthTimes)
 	var memStats runtime.MemStats
 	runtime.ReadMemStats(&memStats)
@@ -105,7 +109,7 @@ func getStats(staticAppData config.StaticAppData, pollingInterval time.Duration,
 
 	s.QueryInterval95thPercentile = getCacheTimePercentile(lastHealthTimes, 0.95).Nanoseconds() / util.MillisecondsPerNanosecond
 
-	return json.Marshal(JSONStats{Stats: s})
+	return s
 }
 
 func getLongestPoll(lastHealthTimes map[enum.CacheName]time.Duration) (enum.CacheName, time.Duration) {
@@ -0,0 +1,278 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package datareq
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/util"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/cache"
+	dsdata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/deliveryservicedata"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/srvhttp"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/threadsafe"
+	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
+	to "github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+)
+
+// DefaultTopLimit is the number of ranked objects returned by the top endpoints, if no limit is given.
+const DefaultTopLimit = 10
+
+// TopCaches is the JSON object returned by the /api/top/caches endpoint, containing caches ranked by the requested stat.
+type TopCaches struct {
+	srvhttp.CommonAPIData
+	Stat   string     `json:"stat"`
+	Caches []TopCache `json:"caches"`
+}
+
+// TopCache is a single ranked cache, with the latest value of the requested stat. Time is in milliseconds since the epoch.
+type TopCache struct {
+	Name       enum.CacheName      `json:"name"`
+	CacheGroup enum.CacheGroupName `json:"cachegroup"`
+	Type       enum.CacheType      `json:"type"`
+	Value      float64             `json:"value"`
+	Time       int64               `json:"time"`
+}
+
+// TopDeliveryServices is the JSON object returned by the /api/top/deliveryservices endpoint, containing delivery services ranked by the requested stat.
+type TopDeliveryServices struct {
+	srvhttp.CommonAPIData
+	Stat             string               `json:"stat"`
+	DeliveryServices []TopDeliveryService `json:"deliveryServices"`
+}
+
+// TopDeliveryService is a single ranked delivery service, with the current value of the requested stat.
+type TopDeliveryService struct {
+	Name  enum.DeliveryServiceName `json:"name"`
+	Type  enum.DSType              `json:"type"`
+	Value float64                  `json:"value"`
+}
+
+// TopQuery is the ranking requested of a top endpoint. See `NewTopQuery` for the query parameters used.
+type TopQuery struct {
+	Stat       string
+	Limit      int
+	Ascending  bool
+	CacheGroup enum.CacheGroupName
+	CacheType  enum.CacheType
+}
+
+// NewTopQuery takes the HTTP query parameters and creates a TopQuery.
+// Query parameters used are `stat`, `limit`, `order`, `cachegroup`, and `type`.
+// The `stat` is required.
+// If `limit` is empty, DefaultTopLimit objects are returned. If `limit` is 0, all objects are returned.
+// If `order` is `asc`, objects are ranked with the lowest value first. Otherwise, the highest value is first.
+// If `cachegroup` or `type` is empty, stats aren't filtered by cachegroup or cache type.
+func NewTopQuery(params url.Values) (TopQuery, error) {
+	validParams := map[string]struct{}{
+		"stat":       struct{}{},
+		"limit":      struct{}{},
+		"order":      struct{}{},
+		"cachegroup": struct{}{},
+		"type":       struct{}{},
+	}
+	for param := range params {
+		if _, ok := validParams[param]; !ok {
+			return TopQuery{}, fmt.Errorf("invalid query parameter '%v'", param)
+		}
+	}
+
+	q := TopQuery{Stat: params.Get("stat"), Limit: DefaultTopLimit}
+	if q.Stat == "" {
+		return TopQuery{}, fmt.Errorf("missing required query parameter 'stat'")
+	}
+
+	if paramLimit := params.Get("limit"); paramLimit != "" {
+		limit, err := strconv.Atoi(paramLimit)
+		if err != nil || limit < 0 {
+			return TopQuery{}, fmt.Errorf("invalid limit '%v', must be a non-negative integer", paramLimit)
+		}
+		q.Limit = limit
+	}
+
+	switch order := params.Get("order"); order {
+	case "", "desc":
+	case "asc":
+		q.Ascending = true
+	default:
+		return TopQuery{}, fmt.Errorf("invalid order '%v', must be 'asc' or 'desc'", order)
+	}
+
+	q.CacheGroup = enum.CacheGroupName(params.Get("cachegroup"))
+
+	if paramType := params.Get("type"); paramType != "" {
+		q.CacheType = enum.CacheTypeFromString(paramType)
+		if q.CacheType == enum.CacheTypeInvalid {
+			return TopQuery{}, fmt.Errorf("invalid cache type '%v'", paramType)
+		}
+	}
+	return q, nil
+}
+
+func srvTopCaches(params url.Values, errorCount threadsafe.Uint, path string, toData todata.TODataThreadsafe, statResultHistory threadsafe.ResultStatHistory, statInfoHistory threadsafe.ResultInfoHistory, monitorConfig threadsafe.TrafficMonitorConfigMap, combinedStates peer.CRStatesThreadsafe) ([]byte, int) {
+	q, err := NewTopQuery(params)
+	if err != nil {
+		HandleErr(errorCount, path, err)
+		return []byte(err.Error()), http.StatusBadRequest
+	}
+	bytes, err := json.Marshal(createTopCaches(q, toData.Get(), statResultHistory.Get(), statInfoHistory.Get(), monitorConfig.Get(), combinedStates.Get(), params))
+	return WrapErrCode(errorCount, path, bytes, err)
+}
+
+func srvTopDeliveryServices(params url.Values, errorCount threadsafe.Uint, path string, toData todata.TODataThreadsafe, dsStats threadsafe.DSStatsReader) ([]byte, int) {
+	q, err := NewTopQuery(params)
+	if err != nil {
+		HandleErr(errorCount, path, err)
+		return []byte(err.Error()), http.StatusBadRequest
+	}
+	if q.CacheGroup != "" && q.CacheType != enum.CacheTypeInvalid {
+		err := fmt.Errorf("delivery services may be ranked by cachegroup or type, but not both")
+		HandleErr(errorCount, path, err)
+		return []byte(err.Error()), http.StatusBadRequest
+	}
+	bytes, err := json.Marshal(createTopDeliveryServices(q, toData.Get(), dsStats.Get(), params))
+	return WrapErrCode(errorCount, path, bytes, err)
+}
+
+// cacheStatNumeric returns the latest value of the given stat for the given cache, its time, and whether the stat exists and is numeric. The stat may be a computed stat, or a stat returned by the cache, with or without the `ats.` prefix.
+func cacheStatNumeric(stat string, cacheName enum.CacheName, statResultHistory cache.ResultStatHistory, statInfoHistory cache.ResultInfoHistory, monitorConfig to.TrafficMonitorConfigMap, combinedStates peer.Crstates) (float64, time.Time, bool) {
+	if infos := statInfoHistory[cacheName]; len(infos) > 0 {
+		info := infos[0]
+		if computedStatF, ok := cache.ComputedStats()[stat]; ok {
+			serverInfo := monitorConfig.TrafficServer[string(cacheName)]
+			v, ok := util.ToNumeric(computedStatF(info, serverInfo, monitorConfig.Profile[serverInfo.Profile], combinedStates.Caches[cacheName]))
+			return v, info.Time, ok
+		}
+		if interfaceStat, ok := cache.InterfaceComputedStats(info.Vitals)[stat]; ok {
+			v, ok := util.ToNumeric(interfaceStat)
+			return v, info.Time, ok
+		}
+	}
+
+	vals := statResultHistory[cacheName][strings.TrimPrefix(stat, "ats.")]
+	if len(vals) == 0 {
+		return 0, time.Time{}, false
+	}
+	v, ok := util.ToNumeric(vals[0].Val)
+	return v, vals[0].Time, ok
+}
+
+func createTopCaches(q TopQuery, toData todata.TOData, statResultHistory cache.ResultStatHistory, statInfoHistory cache.ResultInfoHistory, monitorConfig to.TrafficMonitorConfigMap, combinedStates peer.Crstates, params url.Values) TopCaches {
+	cacheNames := map[enum.CacheName]struct{}{}
+	for cacheName := range statResultHistory {
+		cacheNames[cacheName] = struct{}{}
+	}
+	for cacheName := range statInfoHistory {
+		cacheNames[cacheName] = struct{}{}
+	}
+
+	caches := []TopCache{}
+	for cacheName := range cacheNames {
+		cacheGroup := toData.ServerCachegroups[cacheName]
+		cacheType := toData.ServerTypes[cacheName]
+		if q.CacheGroup != "" && cacheGroup != q.CacheGroup {
+			continue
+		}
+		if q.CacheType != enum.CacheTypeInvalid && cacheType != q.CacheType {
+			continue
+		}
+		v, t, ok := cacheStatNumeric(q.Stat, cacheName, statResultHistory, statInfoHistory, monitorConfig, combinedStates)
+		if !ok {
+			continue
+		}
+		caches = append(caches, TopCache{Name: cacheName, CacheGroup: cacheGroup, Type: cacheType, Value: v, Time: t.UnixNano() / int64(time.Millisecond)})
+	}
+
+	sort.Sort(topCaches{caches: caches, ascending: q.Ascending})
+	if q.Limit > 0 && len(caches) > q.Limit {
+		caches = caches[:q.Limit]
+	}
+	return TopCaches{CommonAPIData: srvhttp.GetCommonAPIData(params, time.Now()), Stat: q.Stat, Caches: caches}
+}
+
+func createTopDeliveryServices(q TopQuery, toData todata.TOData, dsStats dsdata.StatsReadonly, params url.Values) TopDeliveryServices {
+	dses := []TopDeliveryService{}
+	for dsName, dsType := range toData.DeliveryServiceTypes {
+		stat, ok := dsStats.Get(dsName)
+		if !ok {
+			continue
+		}
+		cacheStats := stat.Total()
+		switch {
+		case q.CacheGroup != "":
+			if cacheStats, ok = stat.CacheGroup(q.CacheGroup); !ok {
+				continue
+			}
+		case q.CacheType != enum.CacheTypeInvalid:
+			if cacheStats, ok = stat.Type(q.CacheType); !ok {
+				continue
+			}
+		}
+		v, ok := cacheStats.Numeric(q.Stat)
+		if !ok {
+			continue
+		}
+		dses = append(dses, TopDeliveryService{Name: dsName, Type: dsType, Value: v})
+	}
+
+	sort.Sort(topDeliveryServices{dses: dses, ascending: q.Ascending})
+	if q.Limit > 0 && len(dses) > q.Limit {
+		dses = dses[:q.Limit]
+	}
+	return TopDeliveryServices{CommonAPIData: srvhttp.GetCommonAPIData(params, time.Now()), Stat: q.Stat, DeliveryServices: dses}
+}
+
+// topCaches sorts caches by value, highest first unless ascending. Equal values are sorted by name, so rankings are stable across requests.
+type topCaches struct {
+	caches    []TopCache
+	ascending bool
+}
+
+func (t topCaches) Len() int      { return len(t.caches) }
+func (t topCaches) Swap(i, j int) { t.caches[i], t.caches[j] = t.caches[j], t.caches[i] }
+func (t topCaches) Less(i, j int) bool {
+	if t.caches[i].Value == t.caches[j].Value {
+		return t.caches[i].Name < t.caches[j].Name
+	}
+	return (t.caches[i].Value < t.caches[j].Value) == t.ascending
+}
+
+// topDeliveryServices sorts delivery services by value, highest first unless ascending. Equal values are sorted by name, so rankings are stable across requests.
+type topDeliveryServices struct {
+	dses      []TopDeliveryService
+	ascending bool
+}
+
+func (t topDeliveryServices) Len() int      { return len(t.dses) }
+func (t topDeliveryServices) Swap(i, j int) { t.dses[i], t.dses[j] = t.dses[j], t.dses[i] }
+func (t topDeliveryServices) Less(i, j int) bool {
+	if t.dses[i].Value == t.dses[j].Value {
+		return t.dses[i].Name < t.dses[j].Name
+	}
+	return (t.dses[i].Value < t.dses[j].Value) == t.ascending
+}
@@ -0,0 +1,107 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package datareq
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/cache"
+	dsdata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/deliveryservicedata"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
+	to "github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+)
+
+func TestNewTopQuery(t *testing.T) {
+	if _, err := NewTopQuery(url.Values{}); err == nil {
+		t.Errorf("NewTopQuery without stat expected error, actual nil")
+	}
+	if _, err := NewTopQuery(url.Values{"stat": {"kbps"}, "limit": {"-1"}}); err == nil {
+		t.Errorf("NewTopQuery with negative limit expected error, actual nil")
+	}
+	if _, err := NewTopQuery(url.Values{"stat": {"kbps"}, "foo": {"bar"}}); err == nil {
+		t.Errorf("NewTopQuery with invalid parameter expected error, actual nil")
+	}
+	q, err := NewTopQuery(url.Values{"stat": {"kbps"}, "order": {"asc"}, "type": {"mid"}})
+	if err != nil {
+		t.Fatalf("NewTopQuery expected nil error, actual %v", err)
+	}
+	if q.Limit != DefaultTopLimit || !q.Ascending || q.CacheType != enum.CacheTypeMid {
+		t.Errorf("NewTopQuery expected limit %v ascending mid, actual %+v", DefaultTopLimit, q)
+	}
+}
+
+func TestCreateTopCaches(t *testing.T) {
+	toData := *todata.New()
+	statHistory := cache.ResultStatHistory{}
+	now := time.Now()
+	conns := map[enum.CacheName]int{"edge-a": 20, "edge-b": 30, "edge-c": 10, "mid-a": 40}
+	for name, val := range conns {
+		toData.ServerCachegroups[name] = "cg-1"
+		toData.ServerTypes[name] = enum.CacheTypeEdge
+		statHistory[name] = cache.ResultStatValHistory{"proxy.process.http.current_client_connections": []cache.ResultStatVal{{Val: val, Time: now, Span: 1}}}
+	}
+	toData.ServerTypes["mid-a"] = enum.CacheTypeMid
+	toData.ServerCachegroups["edge-c"] = "cg-2"
+
+	q := TopQuery{Stat: "ats.proxy.process.http.current_client_connections", Limit: 2, CacheType: enum.CacheTypeEdge}
+	top := createTopCaches(q, toData, statHistory, cache.ResultInfoHistory{}, to.TrafficMonitorConfigMap{}, peer.NewCrstates(), url.Values{})
+	if len(top.Caches) != 2 || top.Caches[0].Name != "edge-b" || top.Caches[1].Name != "edge-a" {
+		t.Errorf("createTopCaches expected [edge-b edge-a], actual %+v", top.Caches)
+	}
+
+	q = TopQuery{Stat: "proxy.process.http.current_client_connections", Ascending: true, CacheGroup: "cg-1"}
+	top = createTopCaches(q, toData, statHistory, cache.ResultInfoHistory{}, to.TrafficMonitorConfigMap{}, peer.NewCrstates(), url.Values{})
+	if len(top.Caches) != 3 || top.Caches[0].Name != "edge-a" || top.Caches[2].Name != "mid-a" {
+		t.Errorf("createTopCaches expected [edge-a edge-b mid-a], actual %+v", top.Caches)
+	}
+}
+
+func TestCreateTopDeliveryServices(t *testing.T) {
+	toData := *todata.New()
+	dsStats := dsdata.NewStats()
+	tps5xx := map[enum.DeliveryServiceName]float64{"ds-a": 1.5, "ds-b": 0, "ds-c": 7}
+	for name, val := range tps5xx {
+		toData.DeliveryServiceTypes[name] = enum.DSTypeHTTP
+		stat := dsdata.NewStat()
+		stat.TotalStats.Tps5xx = dsdata.StatFloat{Value: val}
+		stat.CacheGroups["cg-1"] = dsdata.StatCacheStats{Tps5xx: dsdata.StatFloat{Value: val * 2}}
+		dsStats.DeliveryService[name] = *stat
+	}
+	delete(dsStats.DeliveryService["ds-c"].CacheGroups, "cg-1")
+
+	top := createTopDeliveryServices(TopQuery{Stat: "tps_5xx"}, toData, dsStats, url.Values{})
+	if len(top.DeliveryServices) != 3 || top.DeliveryServices[0].Name != "ds-c" || top.DeliveryServices[0].Value != 7 {
+		t.Errorf("createTopDeliveryServices expected ds-c first with 7, actual %+v", top.DeliveryServices)
+	}
+
+	top = createTopDeliveryServices(TopQuery{Stat: "tps_5xx", CacheGroup: "cg-1"}, toData, dsStats, url.Values{})
+	if len(top.DeliveryServices) != 2 || top.DeliveryServices[0].Name != "ds-a" || top.DeliveryServices[0].Value != 3 {
+		t.Errorf("createTopDeliveryServices cachegroup expected ds-a first with 3, actual %+v", top.DeliveryServices)
+	}
+
+	top = createTopDeliveryServices(TopQuery{Stat: "error_string"}, toData, dsStats, url.Values{})
+	if len(top.DeliveryServices) != 0 {
+		t.Errorf("createTopDeliveryServices non-numeric stat expected no delivery services, actual %+v", top.DeliveryServices)
+	}
+}
@@ -0,0 +1,159 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package datareq
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/log"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/threadsafe"
+)
+
+// APIV2Prefix is the path prefix of all version 2 API endpoints.
+const APIV2Prefix = "/api/v2"
+
+const (
+	V2AlertLevelError   = "error"
+	V2AlertLevelWarning = "warning"
+)
+
+// V2Response is the envelope of every version 2 API response. The requested data is in Response, which is omitted on error, and any errors or warnings are in Alerts.
+type V2Response struct {
+	Response interface{} `json:"response,omitempty"`
+	Alerts   []V2Alert   `json:"alerts,omitempty"`
+}
+
+// V2Alert is an error or warning returned by a version 2 API endpoint. The Level is one of the V2AlertLevel constants.
+type V2Alert struct {
+	Text  string `json:"text"`
+	Level string `json:"level"`
+}
+
+// V2Param is a query parameter accepted by a version 2 API endpoint. The Type is the OpenAPI type of the parameter, e.g. `string`, `integer`, or `boolean`.
+type V2Param struct {
+	Name        string
+	Type        string
+	Description string
+	Required    bool
+}
+
+// V2HandlerFunc returns the data requested by the given query parameters, to be encoded in the response envelope. If the error is non-nil, the returned code must be an HTTP error code. Errors with 4xx codes are returned to the client; errors with 5xx codes are logged, and only a generic error is returned, for security reasons.
+type V2HandlerFunc func(params url.Values) (interface{}, int, error)
+
+// V2Endpoint is a version 2 API endpoint. Response is a zero value of the type returned by Handler, used to generate the OpenAPI document. Legacy is the list of unversioned paths this endpoint supersedes, which are served as compatibility shims.
+type V2Endpoint struct {
+	Path     string
+	Summary  string
+	Params   []V2Param
+	Response interface{}
+	Legacy   []string
+	Handler  V2HandlerFunc
+}
+
+// validateV2Params returns an error if the given query parameters include any not accepted by the endpoint, or are missing any required parameters.
+func validateV2Params(endpoint V2Endpoint, params url.Values) error {
+	validParams := map[string]struct{}{}
+	for _, param := range endpoint.Params {
+		validParams[param.Name] = struct{}{}
+		if param.Required && params.Get(param.Name) == "" {
+			return fmt.Errorf("missing required query parameter '%v'", param.Name)
+		}
+	}
+	for param := range params {
+		if _, ok := validParams[param]; !ok {
+			return fmt.Errorf("invalid query parameter '%v'", param)
+		}
+	}
+	return nil
+}
+
+// WrapV2 wraps the given endpoint as an http.HandlerFunc, validating query parameters and encoding the handler's response or error in a V2Response envelope.
+func WrapV2(errorCount threadsafe.Uint, endpoint V2Endpoint) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+		if err := validateV2Params(endpoint, params); err != nil {
+			HandleErr(errorCount, r.URL.EscapedPath(), err)
+			writeJSON(w, r, V2Response{Alerts: []V2Alert{{Text: err.Error(), Level: V2AlertLevelError}}}, http.StatusBadRequest)
+			return
+		}
+
+		resp, code, err := endpoint.Handler(params)
+		if err != nil {
+			HandleErr(errorCount, r.URL.EscapedPath(), err)
+			text := err.Error()
+			if code < http.StatusBadRequest {
+				code = http.StatusInternalServerError
+			}
+			if code >= http.StatusInternalServerError {
+				text = http.StatusText(code)
+			}
+			writeJSON(w, r, V2Response{Alerts: []V2Alert{{Text: text, Level: V2AlertLevelError}}}, code)
+			return
+		}
+		writeJSON(w, r, V2Response{Response: resp}, code)
+	}
+}
+
+// writeJSON encodes the given object as JSON and writes it with the given code, gzipped if the request accepts it.
+func writeJSON(w http.ResponseWriter, r *http.Request, v interface{}, code int) {
+	bytes, err := json.Marshal(v)
+	if err == nil {
+		bytes, err = gzipIfAccepts(r, w, bytes)
+	}
+	if err != nil {
+		log.Errorf("encoding request '%v': %v\n", r.URL.EscapedPath(), err)
+		w.Header().Del("Content-Encoding")
+		code = http.StatusInternalServerError
+		bytes = []byte(http.StatusText(code))
+	} else {
+		w.Header().Set("Content-Type", ContentTypeJSON)
+	}
+	w.WriteHeader(code)
+	if _, err := w.Write(bytes); err != nil {
+		log.Warnf("received error writing data request %v: %v\n", r.URL.EscapedPath(), err)
+	}
+}
+
+// wrapLegacy wraps the handler of a deprecated, unversioned endpoint, adding headers pointing clients to the version 2 endpoint which supersedes it. The legacy response is otherwise unchanged.
+func wrapLegacy(successor string, f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+successor+`>; rel="successor-version"`)
+		f(w, r)
+	}
+}
+
+// addV2Endpoints adds the given endpoints, and the OpenAPI document describing them, to the dispatch map. The handlers of legacy paths superseded by an endpoint are wrapped as compatibility shims.
+func addV2Endpoints(dispatchMap map[string]http.HandlerFunc, endpoints []V2Endpoint, version string, errorCount threadsafe.Uint, wrap func(http.HandlerFunc) http.HandlerFunc) map[string]http.HandlerFunc {
+	for _, endpoint := range endpoints {
+		dispatchMap[endpoint.Path] = wrap(WrapV2(errorCount, endpoint))
+		for _, legacy := range endpoint.Legacy {
+			if f, ok := dispatchMap[legacy]; ok {
+				dispatchMap[legacy] = wrapLegacy(endpoint.Path, f)
+			}
+		}
+	}
+	// The OpenAPI document isn't wrapped, because it doesn't depend on caches being polled.
+	dispatchMap[APIV2Prefix+"/openapi.json"] = srvOpenAPI(NewOpenAPI(endpoints, version))
+	return dispatchMap
+}
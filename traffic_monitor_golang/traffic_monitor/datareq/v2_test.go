@@ -0,0 +1,181 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package datareq
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/log"
+	dsdata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/deliveryservicedata"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/health"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/threadsafe"
+)
+
+func testV2Endpoint() V2Endpoint {
+	return V2Endpoint{
+		Path:     APIV2Prefix + "/test",
+		Summary:  "test",
+		Params:   []V2Param{{Name: "name", Type: "string", Required: true}, {Name: "fail", Type: "string"}},
+		Response: V2Bandwidth{},
+		Handler: func(params url.Values) (interface{}, int, error) {
+			switch params.Get("fail") {
+			case "client":
+				return nil, http.StatusBadRequest, errors.New("bad name")
+			case "server":
+				return nil, http.StatusInternalServerError, errors.New("secret internal error")
+			}
+			return V2Bandwidth{Kbps: 42}, http.StatusOK, nil
+		},
+	}
+}
+
+func TestWrapV2(t *testing.T) {
+	discard := log.NopCloser(ioutil.Discard)
+	log.Init(discard, discard, discard, discard, discard)
+
+	f := WrapV2(threadsafe.NewUint(), testV2Endpoint())
+	tests := []struct {
+		query     string
+		code      int
+		alert     string
+		hasResult bool
+	}{
+		{"name=a", http.StatusOK, "", true},
+		{"", http.StatusBadRequest, "missing required query parameter 'name'", false},
+		{"name=a&foo=b", http.StatusBadRequest, "invalid query parameter 'foo'", false},
+		{"name=a&fail=client", http.StatusBadRequest, "bad name", false},
+		{"name=a&fail=server", http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError), false},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		f(w, httptest.NewRequest("GET", APIV2Prefix+"/test?"+test.query, nil))
+		if w.Code != test.code {
+			t.Errorf("WrapV2 query '%v' expected code %v, actual %v", test.query, test.code, w.Code)
+		}
+		resp := struct {
+			Response *V2Bandwidth `json:"response"`
+			Alerts   []V2Alert    `json:"alerts"`
+		}{}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("WrapV2 query '%v' expected JSON envelope, actual error %v", test.query, err)
+		}
+		if test.hasResult != (resp.Response != nil) {
+			t.Errorf("WrapV2 query '%v' expected response %v, actual %+v", test.query, test.hasResult, resp.Response)
+		}
+		if test.alert == "" && len(resp.Alerts) != 0 {
+			t.Errorf("WrapV2 query '%v' expected no alerts, actual %+v", test.query, resp.Alerts)
+		} else if test.alert != "" && (len(resp.Alerts) != 1 || resp.Alerts[0].Text != test.alert || resp.Alerts[0].Level != V2AlertLevelError) {
+			t.Errorf("WrapV2 query '%v' expected error alert '%v', actual %+v", test.query, test.alert, resp.Alerts)
+		}
+	}
+}
+
+func TestWrapLegacy(t *testing.T) {
+	w := httptest.NewRecorder()
+	wrapLegacy(APIV2Prefix+"/test", func(w http.ResponseWriter, r *http.Request) {})(w, httptest.NewRequest("GET", "/api/test", nil))
+	if w.Header().Get("Deprecation") != "true" {
+		t.Errorf("wrapLegacy expected Deprecation header, actual %v", w.Header())
+	}
+	if expected := "<" + APIV2Prefix + `/test>; rel="successor-version"`; w.Header().Get("Link") != expected {
+		t.Errorf("wrapLegacy expected Link '%v', actual '%v'", expected, w.Header().Get("Link"))
+	}
+}
+
+func TestNewOpenAPI(t *testing.T) {
+	events := testV2Endpoint()
+	events.Path = APIV2Prefix + "/events"
+	events.Response = []health.Event{}
+	doc := NewOpenAPI([]V2Endpoint{testV2Endpoint(), events}, "1.0")
+
+	op := doc.Paths[APIV2Prefix+"/test"].Get
+	if op == nil {
+		t.Fatalf("NewOpenAPI expected path %v, actual %+v", APIV2Prefix+"/test", doc.Paths)
+	}
+	if op.OperationID != "getTest" || len(op.Parameters) != 2 || !op.Parameters[0].Required {
+		t.Errorf("NewOpenAPI expected operation getTest with 2 parameters, actual %+v", op)
+	}
+	if ref := op.Responses["200"].Content[ContentTypeJSON].Schema.Properties["response"].Ref; ref != "#/components/schemas/datareq.V2Bandwidth" {
+		t.Errorf("NewOpenAPI expected response ref datareq.V2Bandwidth, actual '%v'", ref)
+	}
+
+	bw, ok := doc.Components.Schemas["datareq.V2Bandwidth"]
+	if !ok || bw.Properties["kbps"].Type != "number" || bw.Properties["capacityKbps"].Type != "integer" {
+		t.Errorf("NewOpenAPI expected V2Bandwidth schema with kbps number and capacityKbps integer, actual %+v", bw)
+	}
+	event, ok := doc.Components.Schemas["health.Event"]
+	if !ok || event.Properties["time"].Type != "integer" || event.Properties["isAvailable"].Type != "boolean" {
+		t.Errorf("NewOpenAPI expected health.Event schema with time integer and isAvailable boolean, actual %+v", event)
+	}
+	if _, err := json.Marshal(doc); err != nil {
+		t.Errorf("NewOpenAPI expected document to marshal, actual error %v", err)
+	}
+}
+
+func TestCreateV2DSStats(t *testing.T) {
+	stats := dsdata.NewStats()
+	stat := dsdata.Stat{CacheGroups: map[enum.CacheGroupName]dsdata.StatCacheStats{}, Types: map[enum.CacheType]dsdata.StatCacheStats{}}
+	stat.CommonStats.ErrorStr.Value = "1"
+	stat.CommonStats.StatusStr.Value = "true"
+	stat.CommonStats.IsAvailable.Value = true
+	stat.CommonStats.CachesAvailableNum.Value = 3
+	stat.CommonStats.CachesDisabled = []string{"cg-a"}
+	stat.TotalStats.Kbps.Value = 1.5
+	stats.DeliveryService["ds1"] = stat
+
+	filter, err := NewDSStatFilter("", url.Values{}, map[enum.DeliveryServiceName]enum.DSType{"ds1": enum.DSTypeHTTP})
+	if err != nil {
+		t.Fatalf("NewDSStatFilter expected nil error, actual %v", err)
+	}
+	v2Stats := createV2DSStats(stats.JSON(filter, url.Values{}))["ds1"]
+
+	tests := map[dsdata.StatName]interface{}{
+		"error-string":      "1",
+		"status":            "true",
+		"isAvailable":       true,
+		"caches-available":  3,
+		"disabledLocations": []string{"cg-a"},
+		"total.kbps":        1.5,
+	}
+	for name, expected := range tests {
+		vals := v2Stats[name]
+		if len(vals) != 1 {
+			t.Errorf("createV2DSStats %v expected 1 value, actual %v", name, len(vals))
+			continue
+		}
+		if expected, _ := json.Marshal(expected); string(expected) != mustMarshal(t, vals[0].Value) {
+			t.Errorf("createV2DSStats %v expected %s, actual %v", name, expected, mustMarshal(t, vals[0].Value))
+		}
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshalling %v: %v", v, err)
+	}
+	return string(b)
+}
@@ -0,0 +1,476 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package datareq
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/handler"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/cache"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/config"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/crconfig"
+	dsdata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/deliveryservicedata"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/health"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/threadsafe"
+	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
+	towrap "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopswrapper"
+	to "github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+)
+
+// V2StatValue is a single value of a cache or delivery service stat. Unlike the legacy endpoints, numeric and boolean values are JSON numbers and booleans, not strings. Time is in milliseconds since the epoch.
+type V2StatValue struct {
+	Value interface{} `json:"value"`
+	Time  int64       `json:"time"`
+	Span  uint64      `json:"span"`
+}
+
+// V2CacheCounts is the number of caches in each state, per this Traffic Monitor. Down caches are those marked unavailable, excluding caches whose Traffic Ops status is OFFLINE or ADMIN_DOWN.
+type V2CacheCounts struct {
+	Total     int `json:"total"`
+	Available int `json:"available"`
+	Down      int `json:"down"`
+}
+
+// V2Peer is the state of a peer Traffic Monitor, and the availability it reported for each cache.
+type V2Peer struct {
+	Online bool                    `json:"online"`
+	Caches map[enum.CacheName]bool `json:"caches"`
+}
+
+// V2Bandwidth is the current bandwidth and total bandwidth capacity of all caches, in kilobits per second.
+type V2Bandwidth struct {
+	Kbps         float64 `json:"kbps"`
+	CapacityKbps int64   `json:"capacityKbps"`
+}
+
+// V2Version is the version of this Traffic Monitor. Version is the full version string, as returned by the legacy /api/version endpoint.
+type V2Version struct {
+	Version        string `json:"version"`
+	Name           string `json:"name"`
+	GitRevision    string `json:"gitRevision"`
+	BuildTimestamp string `json:"buildTimestamp"`
+}
+
+// V2Stats contains statistics data about this running app. It contains the same data as the legacy Stats, with consistent names and typed values. Durations are in milliseconds.
+type V2Stats struct {
+	Name                          string `json:"name"`
+	Version                       string `json:"version"`
+	GitRevision                   string `json:"gitRevision"`
+	BuildTimestamp                string `json:"buildTimestamp"`
+	DeployDir                     string `json:"deployDir"`
+	UptimeSeconds                 uint64 `json:"uptimeSeconds"`
+	ErrorCount                    uint64 `json:"errorCount"`
+	FetchCount                    uint64 `json:"fetchCount"`
+	IterationCount                uint64 `json:"iterationCount"`
+	QueryIntervalTargetMs         int    `json:"queryIntervalTargetMs"`
+	QueryIntervalActualMs         int    `json:"queryIntervalActualMs"`
+	QueryIntervalDeltaMs          int    `json:"queryIntervalDeltaMs"`
+	LastQueryIntervalMs           int    `json:"lastQueryIntervalMs"`
+	QueryInterval95thPercentileMs int64  `json:"queryInterval95thPercentileMs"`
+	SlowestCache                  string `json:"slowestCache"`
+	OldestPolledPeer              string `json:"oldestPolledPeer"`
+	OldestPolledPeerMs            int64  `json:"oldestPolledPeerMs"`
+	Goroutines                    int    `json:"goroutines"`
+	LastGC                        string `json:"lastGarbageCollection"`
+	FreeMemoryMB                  uint64 `json:"freeMemoryMB"`
+	MemAllocBytes                 uint64 `json:"memoryBytesAllocated"`
+	MemTotalBytes                 uint64 `json:"totalBytesAllocated"`
+	MemSysBytes                   uint64 `json:"systemBytesAllocated"`
+}
+
+var (
+	v2CacheFilterParams = []V2Param{
+		{Name: "hc", Type: "integer", Description: "The history count, number of items to display."},
+		{Name: "stats", Type: "string", Description: "A comma separated list of stats to display."},
+		{Name: "wildcard", Type: "boolean", Description: "Controls whether specified stats should be treated as partial strings."},
+		{Name: "type", Type: "string", Description: "Only return caches of this type, e.g. EDGE or MID."},
+		{Name: "hosts", Type: "string", Description: "A comma separated list of caches to display."},
+	}
+	v2DSFilterParams = []V2Param{
+		{Name: "hc", Type: "integer", Description: "The history count, number of items to display."},
+		{Name: "stats", Type: "string", Description: "A comma separated list of stats to display."},
+		{Name: "wildcard", Type: "boolean", Description: "Controls whether specified stats should be treated as partial strings."},
+		{Name: "type", Type: "string", Description: "Only return delivery services of this type, e.g. HTTP or DNS."},
+		{Name: "deliveryservices", Type: "string", Description: "A comma separated list of delivery services to display."},
+	}
+	v2PeerFilterParams = []V2Param{
+		{Name: "type", Type: "string", Description: "Only return caches of this type, e.g. EDGE or MID."},
+		{Name: "peers", Type: "string", Description: "A comma separated list of peers to display."},
+	}
+	v2TopParams = []V2Param{
+		{Name: "stat", Type: "string", Description: "The stat to rank by.", Required: true},
+		{Name: "limit", Type: "integer", Description: "The number of objects to return. Defaults to 10. If 0, all objects are returned."},
+		{Name: "order", Type: "string", Description: "desc (the default) or asc."},
+		{Name: "cachegroup", Type: "string", Description: "Only rank by stats in this cachegroup."},
+		{Name: "type", Type: "string", Description: "Only rank by stats of caches of this type, e.g. EDGE or MID."},
+	}
+)
+
+// makeV2Endpoints returns the version 2 API endpoints. Each endpoint lists the legacy endpoints it supersedes.
+func makeV2Endpoints(
+	opsConfig threadsafe.OpsConfig,
+	toSession towrap.ITrafficOpsSession,
+	localStates peer.CRStatesThreadsafe,
+	peerStates peer.CRStatesPeersThreadsafe,
+	combinedStates peer.CRStatesThreadsafe,
+	statInfoHistory threadsafe.ResultInfoHistory,
+	statResultHistory threadsafe.ResultStatHistory,
+	statMaxKbpses threadsafe.CacheKbpses,
+	healthHistory threadsafe.ResultHistory,
+	dsStats threadsafe.DSStatsReader,
+	events health.ThreadsafeEvents,
+	staticAppData config.StaticAppData,
+	healthPollInterval time.Duration,
+	lastHealthDurations threadsafe.DurationMap,
+	fetchCount threadsafe.Uint,
+	healthIteration threadsafe.Uint,
+	errorCount threadsafe.Uint,
+	toData todata.TODataThreadsafe,
+	localCacheStatus threadsafe.CacheAvailableStatus,
+	lastStats threadsafe.LastStats,
+	monitorConfig threadsafe.TrafficMonitorConfigMap,
+	cacheGroupMinAvailable float64,
+) []V2Endpoint {
+	return []V2Endpoint{
+		{
+			Path:     APIV2Prefix + "/crstates",
+			Summary:  "The current state of this CDN per the health protocol.",
+			Params:   []V2Param{{Name: "raw", Type: "boolean", Description: "Return the state per this Traffic Monitor only, rather than combined with its peers."}},
+			Response: peer.Crstates{},
+			Legacy:   []string{"/publish/CrStates"},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				if _, raw := params["raw"]; raw {
+					return localStates.Get(), http.StatusOK, nil
+				}
+				return combinedStates.Get(), http.StatusOK, nil
+			},
+		},
+		{
+			Path:     APIV2Prefix + "/crconfig",
+			Summary:  "The CRConfig served to and consumed by Traffic Router.",
+			Response: crconfig.CRConfig{},
+			Legacy:   []string{"/publish/CrConfig"},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				bytes, _, err := srvTRConfig(opsConfig, toSession)
+				if err != nil {
+					return nil, http.StatusServiceUnavailable, err
+				}
+				crc := crconfig.CRConfig{}
+				if err := json.Unmarshal(bytes, &crc); err != nil {
+					return nil, http.StatusInternalServerError, fmt.Errorf("unmarshalling CRConfig: %v", err)
+				}
+				return crc, http.StatusOK, nil
+			},
+		},
+		{
+			Path:     APIV2Prefix + "/caches",
+			Summary:  "The status of each cache.",
+			Response: map[enum.CacheName]CacheStatus{},
+			Legacy:   []string{"/api/cache-statuses"},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				return createCacheStatuses(toData.Get().ServerTypes, statInfoHistory.Get(), statResultHistory.Get(), healthHistory.Get(), lastHealthDurations.Get(), localStates.Get().Caches, lastStats.Get(), localCacheStatus, statMaxKbpses, monitorConfig.Get().TrafficServer), http.StatusOK, nil
+			},
+		},
+		{
+			Path:     APIV2Prefix + "/caches/counts",
+			Summary:  "The number of caches, available caches, and down caches, per this Traffic Monitor.",
+			Response: V2CacheCounts{},
+			Legacy:   []string{"/api/cache-count", "/api/cache-available-count", "/api/cache-down-count"},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				caches := localStates.Get().Caches
+				return V2CacheCounts{Total: len(caches), Available: cacheAvailableCount(caches), Down: cacheDownCount(caches, monitorConfig.Get().TrafficServer)}, http.StatusOK, nil
+			},
+		},
+		{
+			Path:     APIV2Prefix + "/caches/stats",
+			Summary:  "Statistics gathered for each cache, including computed stats.",
+			Params:   v2CacheFilterParams,
+			Response: map[enum.CacheName]map[string][]V2StatValue{},
+			Legacy:   []string{"/publish/CacheStats"},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				filter, err := NewCacheStatFilter("", params, toData.Get().ServerTypes)
+				if err != nil {
+					return nil, http.StatusBadRequest, err
+				}
+				stats := cache.CreateStats(statResultHistory.Get(), statInfoHistory.Get(), combinedStates.Get(), monitorConfig.Get(), filter, params)
+				return createV2CacheStats(stats), http.StatusOK, nil
+			},
+		},
+		{
+			Path:     APIV2Prefix + "/caches/stat-summary",
+			Summary:  "The summary of each numeric cache stat over its history.",
+			Params:   v2CacheFilterParams,
+			Response: map[enum.CacheName]map[string]StatSummaryStat{},
+			Legacy:   []string{"/publish/StatSummary"},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				filter, err := NewCacheStatFilter("", params, toData.Get().ServerTypes)
+				if err != nil {
+					return nil, http.StatusBadRequest, err
+				}
+				return createStatSummary(statResultHistory.Get(), filter, params).Caches, http.StatusOK, nil
+			},
+		},
+		{
+			Path:     APIV2Prefix + "/deliveryservices/stats",
+			Summary:  "Statistics gathered for each delivery service.",
+			Params:   v2DSFilterParams,
+			Response: map[enum.DeliveryServiceName]map[dsdata.StatName][]V2StatValue{},
+			Legacy:   []string{"/publish/DsStats"},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				filter, err := NewDSStatFilter("", params, toData.Get().DeliveryServiceTypes)
+				if err != nil {
+					return nil, http.StatusBadRequest, err
+				}
+				return createV2DSStats(dsStats.Get().JSON(filter, params)), http.StatusOK, nil
+			},
+		},
+		{
+			Path:     APIV2Prefix + "/events",
+			Summary:  "Log of recent events.",
+			Response: []health.Event{},
+			Legacy:   []string{"/publish/EventLog"},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				return events.Get(), http.StatusOK, nil
+			},
+		},
+		{
+			Path:     APIV2Prefix + "/peers",
+			Summary:  "The health state reported by each peer Traffic Monitor.",
+			Params:   v2PeerFilterParams,
+			Response: map[enum.TrafficMonitorName]V2Peer{},
+			Legacy:   []string{"/publish/PeerStates"},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				filter, err := NewPeerStateFilter("", params, toData.Get().ServerTypes)
+				if err != nil {
+					return nil, http.StatusBadRequest, err
+				}
+				return createV2Peers(peerStates.GetCrstates(), peerStates.GetPeersOnline(), filter), http.StatusOK, nil
+			},
+		},
+		{
+			Path:     APIV2Prefix + "/stats",
+			Summary:  "The general statistics about Traffic Monitor.",
+			Response: V2Stats{},
+			Legacy:   []string{"/publish/Stats"},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				return newV2Stats(createStats(staticAppData, healthPollInterval, lastHealthDurations.Get(), fetchCount.Get(), healthIteration.Get(), errorCount.Get(), peerStates)), http.StatusOK, nil
+			},
+		},
+		{
+			Path:     APIV2Prefix + "/bandwidth",
+			Summary:  "The current bandwidth and bandwidth capacity of all caches.",
+			Response: V2Bandwidth{},
+			Legacy:   []string{"/api/bandwidth-kbps", "/api/bandwidth-capacity-kbps"},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				return V2Bandwidth{Kbps: bandwidthKbps(lastStats.Get()), CapacityKbps: bandwidthCapacityKbps(statMaxKbpses.Get())}, http.StatusOK, nil
+			},
+		},
+		{
+			Path:    APIV2Prefix + "/capacity",
+			Summary: "The bandwidth headroom of each cachegroup and delivery service, from available caches, and the projected time to saturation.",
+			Params: []V2Param{
+				{Name: "threshold", Type: "number", Description: "The fraction of capacity at which caches are considered saturated. Defaults to 1."},
+				{Name: "type", Type: "string", Description: "Only use caches of this type, e.g. EDGE or MID."},
+			},
+			Response: Capacity{},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				q, err := NewCapacityQuery(params)
+				if err != nil {
+					return nil, http.StatusBadRequest, err
+				}
+				return createCapacity(q, toData.Get(), statInfoHistory.Get(), combinedStates.Get()), http.StatusOK, nil
+			},
+		},
+		{
+			Path:     APIV2Prefix + "/cachegroups",
+			Summary:  "The health of each cachegroup: the fraction of available caches, whether it's above the configured minimum, the delivery services it's disabled for, and its bandwidth headroom.",
+			Response: map[enum.CacheGroupName]CacheGroupHealth{},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				return createCacheGroupHealth(toData.Get(), statInfoHistory.Get(), combinedStates.Get(), monitorConfig.Get(), cacheGroupMinAvailable), http.StatusOK, nil
+			},
+		},
+		{
+			Path:     APIV2Prefix + "/version",
+			Summary:  "The version of this Traffic Monitor.",
+			Response: V2Version{},
+			Legacy:   []string{"/api/version"},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				return V2Version{Version: string(srvAPIVersion(staticAppData)), Name: staticAppData.Name, GitRevision: staticAppData.GitRevision, BuildTimestamp: staticAppData.BuildTimestamp}, http.StatusOK, nil
+			},
+		},
+		{
+			Path:     APIV2Prefix + "/traffic-ops-uri",
+			Summary:  "The URI of the Traffic Ops this Traffic Monitor is configured to use.",
+			Response: "",
+			Legacy:   []string{"/api/traffic-ops-uri"},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				return opsConfig.Get().Url, http.StatusOK, nil
+			},
+		},
+		{
+			Path:     APIV2Prefix + "/monitor-config",
+			Summary:  "The monitoring configuration from Traffic Ops.",
+			Response: to.TrafficMonitorConfigMap{},
+			Legacy:   []string{"/api/monitor-config"},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				return monitorConfig.Get(), http.StatusOK, nil
+			},
+		},
+		{
+			Path:     APIV2Prefix + "/config-doc",
+			Summary:  "The overview of configuration options. The password is masked.",
+			Response: handler.OpsConfig{},
+			Legacy:   []string{"/publish/ConfigDoc"},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				opsConfigCopy := opsConfig.Get()
+				if opsConfigCopy.Password != "" {
+					opsConfigCopy.Password = "*****"
+				}
+				return opsConfigCopy, http.StatusOK, nil
+			},
+		},
+		{
+			Path:     APIV2Prefix + "/top/caches",
+			Summary:  "Caches ranked by the latest value of a stat.",
+			Params:   v2TopParams,
+			Response: []TopCache{},
+			Legacy:   []string{"/api/top/caches"},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				q, err := NewTopQuery(params)
+				if err != nil {
+					return nil, http.StatusBadRequest, err
+				}
+				return createTopCaches(q, toData.Get(), statResultHistory.Get(), statInfoHistory.Get(), monitorConfig.Get(), combinedStates.Get(), params).Caches, http.StatusOK, nil
+			},
+		},
+		{
+			Path:     APIV2Prefix + "/top/deliveryservices",
+			Summary:  "Delivery services ranked by the current value of a stat.",
+			Params:   v2TopParams,
+			Response: []TopDeliveryService{},
+			Legacy:   []string{"/api/top/deliveryservices"},
+			Handler: func(params url.Values) (interface{}, int, error) {
+				q, err := NewTopQuery(params)
+				if err != nil {
+					return nil, http.StatusBadRequest, err
+				}
+				if q.CacheGroup != "" && q.CacheType != enum.CacheTypeInvalid {
+					return nil, http.StatusBadRequest, fmt.Errorf("delivery services may be ranked by cachegroup or type, but not both")
+				}
+				return createTopDeliveryServices(q, toData.Get(), dsStats.Get(), params).DeliveryServices, http.StatusOK, nil
+			},
+		},
+	}
+}
+
+// createV2CacheStats converts the legacy cache stats to typed stat values.
+func createV2CacheStats(stats cache.Stats) map[enum.CacheName]map[string][]V2StatValue {
+	v2Stats := map[enum.CacheName]map[string][]V2StatValue{}
+	for cacheName, cacheStats := range stats.Caches {
+		v2CacheStats := map[string][]V2StatValue{}
+		for statName, vals := range cacheStats {
+			v2Vals := make([]V2StatValue, 0, len(vals))
+			for _, val := range vals {
+				v2Vals = append(v2Vals, V2StatValue{Value: val.Val, Time: val.Time.UnixNano() / int64(time.Millisecond), Span: val.Span})
+			}
+			v2CacheStats[statName] = v2Vals
+		}
+		v2Stats[cacheName] = v2CacheStats
+	}
+	return v2Stats
+}
+
+// createV2DSStats converts the legacy delivery service stats, whose values are all strings, to stat values with the type of the stat they came from.
+func createV2DSStats(stats dsdata.StatsOld) map[enum.DeliveryServiceName]map[dsdata.StatName][]V2StatValue {
+	v2Stats := map[enum.DeliveryServiceName]map[dsdata.StatName][]V2StatValue{}
+	for dsName, dsStats := range stats.DeliveryService {
+		v2DSStats := map[dsdata.StatName][]V2StatValue{}
+		for statName, vals := range dsStats {
+			v2Vals := make([]V2StatValue, 0, len(vals))
+			for _, val := range vals {
+				v2Vals = append(v2Vals, V2StatValue{Value: typedStatValue(val), Time: val.Time, Span: uint64(val.Span)})
+			}
+			v2DSStats[statName] = v2Vals
+		}
+		v2Stats[dsName] = v2DSStats
+	}
+	return v2Stats
+}
+
+// typedStatValue returns the value of the given legacy stat with its original type. Stats without a typed value are returned as their legacy value.
+func typedStatValue(stat dsdata.StatOld) interface{} {
+	if stat.Typed == nil {
+		return stat.Value
+	}
+	return stat.Typed
+}
+
+// createV2Peers returns the state of each peer, and the cache availability it reported, filtered by the given filter. Unlike the legacy endpoint, offline peers are included.
+func createV2Peers(peerStates map[enum.TrafficMonitorName]peer.Crstates, peersOnline map[enum.TrafficMonitorName]bool, filter *PeerStateFilter) map[enum.TrafficMonitorName]V2Peer {
+	peers := map[enum.TrafficMonitorName]V2Peer{}
+	for peerName, state := range peerStates {
+		if !filter.UsePeer(peerName) {
+			continue
+		}
+		v2Peer := V2Peer{Online: peersOnline[peerName], Caches: map[enum.CacheName]bool{}}
+		for cacheName, available := range state.Caches {
+			if !filter.UseCache(cacheName) {
+				continue
+			}
+			v2Peer.Caches[cacheName] = available.IsAvailable
+		}
+		peers[peerName] = v2Peer
+	}
+	return peers
+}
+
+func newV2Stats(s Stats) V2Stats {
+	return V2Stats{
+		Name:                          s.Name,
+		Version:                       s.Version,
+		GitRevision:                   s.GitRevision,
+		BuildTimestamp:                s.BuildTimestamp,
+		DeployDir:                     s.DeployDir,
+		UptimeSeconds:                 s.Uptime,
+		ErrorCount:                    s.ErrorCount,
+		FetchCount:                    s.FetchCount,
+		IterationCount:                s.IterationCount,
+		QueryIntervalTargetMs:         s.QueryIntervalTarget,
+		QueryIntervalActualMs:         s.QueryIntervalActual,
+		QueryIntervalDeltaMs:          s.QueryIntervalDelta,
+		LastQueryIntervalMs:           s.LastQueryInterval,
+		QueryInterval95thPercentileMs: s.QueryInterval95thPercentile,
+		SlowestCache:                  s.SlowestCache,
+		OldestPolledPeer:              s.OldestPolledPeer,
+		OldestPolledPeerMs:            s.OldestPolledPeerMs,
+		Goroutines:                    s.Microthreads,
+		LastGC:                        s.LastGC,
+		FreeMemoryMB:                  s.FreeMemoryMB,
+		MemAllocBytes:                 s.MemAllocBytes,
+		MemTotalBytes:                 s.MemTotalBytes,
+		MemSysBytes:                   s.MemSysBytes,
+	}
+}
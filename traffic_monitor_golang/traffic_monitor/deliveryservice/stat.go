@@ -246,8 +246,7 @@ func addDSPerSecStats(dsName enum.DeliveryServiceName, stat dsdata.Stat, lastSta
 				continue
 			}
 		}
-		cacheStats.Kbps.Value = lastStat.Caches[cacheName].Bytes.PerSec / BytesPerKilobit
-		stat.Caches[cacheName] = cacheStats
+		stat.Caches[cacheName] = addLastStatsToStatCacheStats(cacheStats, lastStat.Caches[cacheName])
 	}
 
 	lastStat = addLastDSStatTotals(lastStat, stat.CommonStats.CachesReporting, serverCachegroups, serverTypes)
@@ -47,6 +47,8 @@ type StatOld struct {
 	Value interface{} `json:"value"`
 	Span  int         `json:"span,omitempty"`  // TODO set? remove?
 	Index int         `json:"index,omitempty"` // TODO set? remove?
+	// Typed is the value with the type of the stat it came from, e.g. a bool or number, for APIs which don't stringify stats. It isn't part of the Traffic Monitor 1.0 JSON.
+	Typed interface{} `json:"-"`
 }
 
 // StatsOld is the old JSON representation of stats, from Traffic Monitor 1.0. It is designed to be serialized and returns from an API, and includes stat history for each delivery service, as well as data common to most endpoints.
@@ -67,6 +69,7 @@ type StatReadonly interface {
 	Common() StatCommonReadonly
 	CacheGroup(name enum.CacheGroupName) (StatCacheStats, bool)
 	Type(name enum.CacheType) (StatCacheStats, bool)
+	Cache(name enum.CacheName) (StatCacheStats, bool)
 	Total() StatCacheStats
 }
 
@@ -221,6 +224,37 @@ func (a StatCacheStats) Sum(b StatCacheStats) StatCacheStats {
 	}
 }
 
+// Numeric returns the value of the numeric stat with the given name, which is the stat's JSON name, e.g. `kbps` or `tps_5xx`, and whether the stat exists and is numeric.
+func (a StatCacheStats) Numeric(name string) (float64, bool) {
+	switch name {
+	case "out_bytes":
+		return float64(a.OutBytes.Value), true
+	case "status_5xx":
+		return float64(a.Status5xx.Value), true
+	case "status_4xx":
+		return float64(a.Status4xx.Value), true
+	case "status_3xx":
+		return float64(a.Status3xx.Value), true
+	case "status_2xx":
+		return float64(a.Status2xx.Value), true
+	case "in_bytes":
+		return a.InBytes.Value, true
+	case "kbps":
+		return a.Kbps.Value, true
+	case "tps_5xx":
+		return a.Tps5xx.Value, true
+	case "tps_4xx":
+		return a.Tps4xx.Value, true
+	case "tps_3xx":
+		return a.Tps3xx.Value, true
+	case "tps_2xx":
+		return a.Tps2xx.Value, true
+	case "tps_total":
+		return a.TpsTotal.Value, true
+	}
+	return 0, false
+}
+
 // Stat represents a complete delivery service stat, for a given poll, or at the time requested.
 type Stat struct {
 	CommonStats        StatCommon
@@ -287,6 +321,12 @@ func (a Stat) Type(name enum.CacheType) (StatCacheStats, bool) {
 	return t, ok
 }
 
+// Cache returns the data for the given cache in this stat. It is part of the StatCommonReadonly interface.
+func (a Stat) Cache(name enum.CacheName) (StatCacheStats, bool) {
+	c, ok := a.Caches[name]
+	return c, ok
+}
+
 // Total returns the aggregated total data in this stat. It is part of the StatCommonReadonly interface.
 func (a Stat) Total() StatCacheStats {
 	return a.TotalStats
@@ -444,47 +484,47 @@ func (a LastStatData) Sum(b LastStatData) LastStatData {
 }
 
 func addCommonData(s *StatsOld, c *StatCommon, deliveryService enum.DeliveryServiceName, t int64, filter Filter) *StatsOld {
-	add := func(name string, val interface{}) {
+	add := func(name string, val interface{}, typed interface{}) {
 		if filter.UseStat(name) {
-			s.DeliveryService[deliveryService][StatName(name)] = []StatOld{StatOld{Time: t, Value: val}}
+			s.DeliveryService[deliveryService][StatName(name)] = []StatOld{StatOld{Time: t, Value: val, Typed: typed}}
 		}
 	}
-	add("caches-configured", fmt.Sprintf("%d", c.CachesConfiguredNum.Value))
-	add("caches-reporting", fmt.Sprintf("%d", len(c.CachesReporting)))
-	add("error-string", c.ErrorStr.Value)
-	add("status", c.StatusStr.Value)
-	add("isHealthy", fmt.Sprintf("%t", c.IsHealthy.Value))
-	add("isAvailable", fmt.Sprintf("%t", c.IsAvailable.Value))
-	add("caches-available", fmt.Sprintf("%d", c.CachesAvailableNum.Value))
-	add("disabledLocations", c.CachesDisabled)
+	add("caches-configured", fmt.Sprintf("%d", c.CachesConfiguredNum.Value), c.CachesConfiguredNum.Value)
+	add("caches-reporting", fmt.Sprintf("%d", len(c.CachesReporting)), len(c.CachesReporting))
+	add("error-string", c.ErrorStr.Value, c.ErrorStr.Value)
+	add("status", c.StatusStr.Value, c.StatusStr.Value)
+	add("isHealthy", fmt.Sprintf("%t", c.IsHealthy.Value), c.IsHealthy.Value)
+	add("isAvailable", fmt.Sprintf("%t", c.IsAvailable.Value), c.IsAvailable.Value)
+	add("caches-available", fmt.Sprintf("%d", c.CachesAvailableNum.Value), c.CachesAvailableNum.Value)
+	add("disabledLocations", c.CachesDisabled, c.CachesDisabled)
 	return s
 }
 
 func addStatCacheStats(s *StatsOld, c StatCacheStats, deliveryService enum.DeliveryServiceName, prefix string, t int64, filter Filter) *StatsOld {
-	add := func(name, val string) {
+	add := func(name, val string, typed interface{}) {
 		if filter.UseStat(name) {
 			// This is for compatibility with the Traffic Monitor 1.0 API.
 			// TODO abstract this? Or deprecate and remove it?
 			if name == "isAvailable" || name == "error-string" {
-				s.DeliveryService[deliveryService][StatName("location."+prefix+name)] = []StatOld{StatOld{Time: t, Value: val}}
+				s.DeliveryService[deliveryService][StatName("location."+prefix+name)] = []StatOld{StatOld{Time: t, Value: val, Typed: typed}}
 			} else {
-				s.DeliveryService[deliveryService][StatName(prefix+name)] = []StatOld{StatOld{Time: t, Value: val}}
+				s.DeliveryService[deliveryService][StatName(prefix+name)] = []StatOld{StatOld{Time: t, Value: val, Typed: typed}}
 			}
 		}
 	}
-	add("out_bytes", strconv.Itoa(int(c.OutBytes.Value)))
-	add("isAvailable", fmt.Sprintf("%t", c.IsAvailable.Value))
-	add("status_5xx", strconv.Itoa(int(c.Status5xx.Value)))
-	add("status_4xx", strconv.Itoa(int(c.Status4xx.Value)))
-	add("status_3xx", strconv.Itoa(int(c.Status3xx.Value)))
-	add("status_2xx", strconv.Itoa(int(c.Status2xx.Value)))
-	add("in_bytes", strconv.Itoa(int(c.InBytes.Value)))
-	add("kbps", strconv.Itoa(int(c.Kbps.Value)))
-	add("tps_5xx", fmt.Sprintf("%f", c.Tps5xx.Value))
-	add("tps_4xx", fmt.Sprintf("%f", c.Tps4xx.Value))
-	add("tps_3xx", fmt.Sprintf("%f", c.Tps3xx.Value))
-	add("tps_2xx", fmt.Sprintf("%f", c.Tps2xx.Value))
-	add("error-string", c.ErrorString.Value)
-	add("tps_total", fmt.Sprintf("%f", c.TpsTotal.Value))
+	add("out_bytes", strconv.Itoa(int(c.OutBytes.Value)), c.OutBytes.Value)
+	add("isAvailable", fmt.Sprintf("%t", c.IsAvailable.Value), c.IsAvailable.Value)
+	add("status_5xx", strconv.Itoa(int(c.Status5xx.Value)), c.Status5xx.Value)
+	add("status_4xx", strconv.Itoa(int(c.Status4xx.Value)), c.Status4xx.Value)
+	add("status_3xx", strconv.Itoa(int(c.Status3xx.Value)), c.Status3xx.Value)
+	add("status_2xx", strconv.Itoa(int(c.Status2xx.Value)), c.Status2xx.Value)
+	add("in_bytes", strconv.Itoa(int(c.InBytes.Value)), c.InBytes.Value)
+	add("kbps", strconv.Itoa(int(c.Kbps.Value)), c.Kbps.Value)
+	add("tps_5xx", fmt.Sprintf("%f", c.Tps5xx.Value), c.Tps5xx.Value)
+	add("tps_4xx", fmt.Sprintf("%f", c.Tps4xx.Value), c.Tps4xx.Value)
+	add("tps_3xx", fmt.Sprintf("%f", c.Tps3xx.Value), c.Tps3xx.Value)
+	add("tps_2xx", fmt.Sprintf("%f", c.Tps2xx.Value), c.Tps2xx.Value)
+	add("error-string", c.ErrorString.Value, c.ErrorString.Value)
+	add("tps_total", fmt.Sprintf("%f", c.TpsTotal.Value), c.TpsTotal.Value)
 	return s
 }
@@ -182,3 +182,27 @@ func CacheStatusFromString(s string) CacheStatus {
 		return CacheStatusInvalid
 	}
 }
+
+// AddressFamily is the IP address family over which a cache was polled.
+type AddressFamily string
+
+const (
+	// AddressFamilyIPv4 represents a cache polled over IPv4.
+	AddressFamilyIPv4 = AddressFamily("ipv4")
+	// AddressFamilyIPv6 represents a cache polled over IPv6.
+	AddressFamilyIPv6 = AddressFamily("ipv6")
+	// AddressFamilyInvalid represents an invalid address family enumeration. Note this is the default construction for an AddressFamily.
+	AddressFamilyInvalid = AddressFamily("")
+)
+
+// String returns a string representation of this address family.
+func (f AddressFamily) String() string {
+	switch f {
+	case AddressFamilyIPv4:
+		return "IPv4"
+	case AddressFamilyIPv6:
+		return "IPv6"
+	default:
+		return "INVALIDADDRESSFAMILY"
+	}
+}
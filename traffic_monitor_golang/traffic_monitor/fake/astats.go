@@ -0,0 +1,194 @@
+package fake
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/cache"
+)
+
+const (
+	// DefaultInterfaceSpeedMbps is the interface speed of a new Cache, in megabits per second.
+	DefaultInterfaceSpeedMbps = 10000
+	// DefaultBytesPerSecond is the bandwidth of a new Cache. It's nonzero, because the monitor doesn't consider a cache polled until its bandwidth is, and doesn't serve until all caches are polled.
+	DefaultBytesPerSecond = 125000
+)
+
+// Cache is a fake cache, serving astats over HTTP from an httptest server. Its health and bandwidth may be changed at any time, and are reflected in the next poll. It's safe for multiple goroutines.
+type Cache struct {
+	Name           string
+	server         *httptest.Server
+	m              *sync.Mutex
+	start          time.Time
+	bytesPerSecond int64
+	bytesOutBase   int64
+	speedMbps      int
+	loadAvg        float64
+	notAvailable   bool
+	statusCode     int
+	stats          map[string]interface{}
+}
+
+// NewCache creates and starts a new fake cache with the given name. The cache is healthy, with DefaultBytesPerSecond bandwidth, until changed. Callers must call Close when finished.
+func NewCache(name string) *Cache {
+	c := &Cache{
+		Name:           name,
+		m:              &sync.Mutex{},
+		start:          time.Now(),
+		bytesPerSecond: DefaultBytesPerSecond,
+		bytesOutBase:   DefaultBytesPerSecond,
+		speedMbps:      DefaultInterfaceSpeedMbps,
+		loadAvg:        0.1,
+		statusCode:     http.StatusOK,
+		stats:          map[string]interface{}{"proxy.process.http.current_client_connections": 0},
+	}
+	c.server = httptest.NewServer(http.HandlerFunc(c.serveAstats))
+	return c
+}
+
+// Close shuts down the cache's server.
+func (c *Cache) Close() {
+	c.server.Close()
+}
+
+// Host returns the `ip:port` the cache's server is listening on.
+func (c *Cache) Host() string {
+	return c.server.Listener.Addr().String()
+}
+
+// Port returns the port the cache's server is listening on.
+func (c *Cache) Port() int {
+	_, portStr, err := net.SplitHostPort(c.Host())
+	if err != nil {
+		return 0
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return 0
+	}
+	return port
+}
+
+// SetNotAvailable sets the astats `system.notAvailable`, which causes the monitor to mark the cache unavailable.
+func (c *Cache) SetNotAvailable(notAvailable bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.notAvailable = notAvailable
+}
+
+// SetStatusCode sets the HTTP status code returned by the cache. A code other than 200 returns no astats, simulating a broken cache.
+func (c *Cache) SetStatusCode(code int) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.statusCode = code
+}
+
+// SetLoadAvg sets the one-minute load average returned in `system.proc.loadavg`.
+func (c *Cache) SetLoadAvg(loadAvg float64) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.loadAvg = loadAvg
+}
+
+// SetBytesPerSecond sets the rate at which the bytes out of each polled interface increase, which the monitor computes bandwidth from.
+func (c *Cache) SetBytesPerSecond(bytesPerSecond int64) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.bytesOut() // accumulate the bytes sent at the old rate
+	c.bytesPerSecond = bytesPerSecond
+}
+
+// SetInterfaceSpeed sets the speed of each polled interface, in megabits per second.
+func (c *Cache) SetInterfaceSpeed(mbps int) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.speedMbps = mbps
+}
+
+// SetStat sets the given ATS stat, e.g. `proxy.process.http.current_client_connections`.
+func (c *Cache) SetStat(name string, val interface{}) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.stats[name] = val
+}
+
+// bytesOut returns the total bytes out of each interface, increasing at the bytes per second rate. The bytes so far are accumulated in a base, so changing the rate doesn't change the bytes already sent. Callers must hold the lock.
+func (c *Cache) bytesOut() int64 {
+	now := time.Now()
+	c.bytesOutBase += int64(now.Sub(c.start).Seconds() * float64(c.bytesPerSecond))
+	c.start = now
+	return c.bytesOutBase
+}
+
+// serveAstats serves the cache's astats JSON. If the request has `application=system`, only system stats are returned, as the real astats does. The requested `inf.name` may be a comma-delimited list, in which case every interface is returned, with the same bytes and speed.
+func (c *Cache) serveAstats(w http.ResponseWriter, r *http.Request) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.statusCode != http.StatusOK {
+		w.WriteHeader(c.statusCode)
+		return
+	}
+
+	infNames := strings.Split(r.URL.Query().Get("inf.name"), ",")
+	bytesOut := c.bytesOut()
+	procNetDev := []string{}
+	infSpeeds := []string{}
+	for _, infName := range infNames {
+		// proc.net.dev lines are `name: bytesIn packetsIn errsIn dropIn fifoIn frameIn compressedIn multicastIn bytesOut ...`
+		procNetDev = append(procNetDev, fmt.Sprintf("%s: %d 0 0 0 0 0 0 0 %d 0 0 0 0 0 0 0", infName, bytesOut/10, bytesOut))
+		infSpeeds = append(infSpeeds, fmt.Sprintf("%s:%d", infName, c.speedMbps))
+	}
+
+	astats := cache.Astats{
+		Ats: map[string]interface{}{},
+		System: cache.AstatsSystem{
+			InfName:      strings.Join(infNames, ","),
+			InfSpeed:     c.speedMbps * len(infNames),
+			ProcNetDev:   strings.Join(procNetDev, "\n"),
+			ProcLoadavg:  fmt.Sprintf("%.2f 0.10 0.10 1/100 1000", c.loadAvg),
+			NotAvailable: c.notAvailable,
+		},
+	}
+	if len(infNames) > 1 {
+		astats.System.InfSpeeds = strings.Join(infSpeeds, ",")
+	}
+	if r.URL.Query().Get("application") != "system" {
+		for name, val := range c.stats {
+			astats.Ats[name] = val
+		}
+	}
+
+	bytes, err := json.Marshal(astats)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(bytes)
+}
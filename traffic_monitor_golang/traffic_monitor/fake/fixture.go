@@ -0,0 +1,222 @@
+// Package fake provides Traffic Ops fixtures and fake astats cache servers, for testing the Traffic Monitor end-to-end without a real Traffic Ops or caches.
+//
+// A Fixture is the Traffic Ops data of a CDN, served by a totest.Server, which may be mutated and served again while the monitor runs, to test how it reacts to Traffic Ops changes. A Cache is an httptest server serving astats, whose health and bandwidth may likewise be changed at any time.
+package fake
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/crconfig"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	to "github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client/totest"
+)
+
+const (
+	// CacheProfile is the profile of caches added to a fixture by NewFixture and AddCache.
+	CacheProfile = "FAKE_EDGE"
+	// MonitorProfile is the profile of the monitor in a fixture created by NewFixture.
+	MonitorProfile = "FAKE_MONITOR"
+	// CacheGroup is the cachegroup of caches added to a fixture by NewFixture and AddCache.
+	CacheGroup = "fake-cachegroup"
+	// DeliveryService is the delivery service assigned to caches added to a fixture by NewFixture and AddCache.
+	DeliveryService = "fake-ds"
+	// InterfaceName is the network interface of caches added to a fixture by NewFixture and AddCache.
+	InterfaceName = "eth0"
+	// PollIntervalMS is the peer, health, and Traffic Ops polling interval of a fixture created by NewFixture, in milliseconds. It's short, so tests see state changes quickly.
+	PollIntervalMS = 100
+)
+
+// Fixture is the Traffic Ops data of a single CDN, served by Serve. The CRConfig is the snapshot, from which the monitor config's servers, monitors, and delivery services are created, exactly as Traffic Ops does. The MonitorConfig provides the rest: profiles, cachegroups, config parameters, and delivery service thresholds.
+type Fixture struct {
+	CDN           string
+	CRConfig      crconfig.CRConfig
+	MonitorConfig to.TrafficMonitorConfigMap
+	Servers       []to.Server
+}
+
+// NewFixture creates a fixture for the given CDN, with the given Traffic Monitor and caches. The monitor is ONLINE, so a monitor started with the same hostname finds its CDN and doesn't poll itself as a peer. The caches are REPORTED, so they're polled, and their availability is determined by their astats.
+func NewFixture(cdn string, monitorHostname string, caches ...*Cache) Fixture {
+	monitorIP := "127.0.0.1"
+	monitorPort := 80
+	monitorStatus := crconfig.Status(enum.CacheStatusOnline.String())
+	monitorProfile := MonitorProfile
+	monitorFQDN := monitorHostname + ".fake"
+	monitorLocation := CacheGroup
+	monitorIP6 := ""
+
+	httpProtocol := enum.DSTypeHTTP.String()
+	f := Fixture{
+		CDN: cdn,
+		CRConfig: crconfig.CRConfig{
+			ContentServers: map[string]crconfig.Server{},
+			DeliveryServices: map[string]crconfig.DeliveryService{
+				DeliveryService: crconfig.DeliveryService{
+					MatchSets: []crconfig.MatchSet{{Protocol: httpProtocol, MatchList: []crconfig.MatchType{{MatchType: "HOST", Regex: `.*\.` + DeliveryService + `\..*`}}}},
+				},
+			},
+			Monitors: map[string]crconfig.Monitor{
+				monitorHostname: crconfig.Monitor{
+					FQDN:     &monitorFQDN,
+					IP:       &monitorIP,
+					IP6:      &monitorIP6,
+					Location: &monitorLocation,
+					Port:     &monitorPort,
+					Profile:  &monitorProfile,
+					Status:   &monitorStatus,
+				},
+			},
+		},
+		MonitorConfig: to.TrafficMonitorConfigMap{
+			TrafficServer: map[string]to.TrafficServer{},
+			CacheGroup: map[string]to.TMCacheGroup{
+				CacheGroup: to.TMCacheGroup{Name: CacheGroup},
+			},
+			Config: map[string]interface{}{
+				"peers.polling.interval":  float64(PollIntervalMS),
+				"health.polling.interval": float64(PollIntervalMS),
+				"tm.polling.interval":     float64(PollIntervalMS),
+			},
+			TrafficMonitor: map[string]to.TrafficMonitor{},
+			DeliveryService: map[string]to.TMDeliveryService{
+				DeliveryService: to.TMDeliveryService{XMLID: DeliveryService, Status: enum.CacheStatusReported.String()},
+			},
+			Profile: map[string]to.TMProfile{
+				CacheProfile: to.TMProfile{
+					Name: CacheProfile,
+					Type: enum.CacheTypeEdge.String(),
+					Parameters: to.TMParameters{
+						HealthConnectionTimeout: 2000,
+						HealthPollingURL:        "http://${hostname}/_astats?application=&inf.name=${interface_name}",
+						HistoryCount:            10,
+					},
+				},
+			},
+		},
+		Servers: []to.Server{
+			{HostName: monitorHostname, DomainName: "fake", CDNName: cdn, IPAddress: monitorIP, TCPPort: monitorPort, Profile: MonitorProfile, Status: enum.CacheStatusOnline.String(), Type: "RASCAL", Cachegroup: CacheGroup},
+		},
+	}
+	for _, cache := range caches {
+		f.AddCache(cache, enum.CacheStatusReported)
+	}
+	return f
+}
+
+// AddCache adds the given fake cache to the fixture's CRConfig snapshot, with the given status, assigned to the fake delivery service. The cache's IP is its `host:port`, so the monitor polls the fake astats server.
+func (f *Fixture) AddCache(cache *Cache, status enum.CacheStatus) {
+	name := cache.Name
+	ip := cache.Host()
+	ip6 := ""
+	port := cache.Port()
+	fqdn := name + ".fake"
+	crStatus := crconfig.Status(status.String())
+	profile := CacheProfile
+	cacheGroup := CacheGroup
+	interfaceName := InterfaceName
+	serverType := enum.CacheTypeEdge.String()
+	hashID := name
+	f.CRConfig.ContentServers[name] = crconfig.Server{
+		CacheGroup:       &cacheGroup,
+		DeliveryServices: map[string][]string{DeliveryService: []string{fqdn}},
+		Fqdn:             &fqdn,
+		HashId:           &hashID,
+		InterfaceName:    &interfaceName,
+		Ip:               &ip,
+		Ip6:              &ip6,
+		Port:             &port,
+		Profile:          &profile,
+		Status:           &crStatus,
+		ServerType:       &serverType,
+	}
+	f.Servers = append(f.Servers, to.Server{HostName: name, DomainName: "fake", CDNName: f.CDN, IPAddress: ip, TCPPort: port, InterfaceName: interfaceName, Profile: profile, Status: status.String(), Type: serverType, Cachegroup: cacheGroup})
+}
+
+// SetCacheStatus sets the status of the given cache in the fixture's CRConfig snapshot, e.g. to ADMIN_DOWN or OFFLINE. Does nothing if the cache doesn't exist.
+func (f *Fixture) SetCacheStatus(name string, status enum.CacheStatus) {
+	srv, ok := f.CRConfig.ContentServers[name]
+	if !ok {
+		return
+	}
+	crStatus := crconfig.Status(status.String())
+	srv.Status = &crStatus
+	f.CRConfig.ContentServers[name] = srv
+	for i, server := range f.Servers {
+		if server.HostName == name {
+			f.Servers[i].Status = status.String()
+		}
+	}
+}
+
+// RemoveCache removes the given cache from the fixture's CRConfig snapshot and servers.
+func (f *Fixture) RemoveCache(name string) {
+	delete(f.CRConfig.ContentServers, name)
+	servers := []to.Server{}
+	for _, server := range f.Servers {
+		if server.HostName != name {
+			servers = append(servers, server)
+		}
+	}
+	f.Servers = servers
+}
+
+// Serve sets the fixture's CRConfig snapshot and monitoring config as those of its CDN on the given Traffic Ops, and replaces the CDN's servers with the fixture's. Fixture changes must be served again, and are seen by the monitor on its next Traffic Ops poll.
+func (f Fixture) Serve(tos *totest.Server) error {
+	crConfig, err := json.Marshal(f.CRConfig)
+	if err != nil {
+		return fmt.Errorf("marshalling fixture CRConfig: %v", err)
+	}
+	tos.SetCRConfig(f.CDN, crConfig)
+	tos.SetMonitoringConfig(f.CDN, f.monitoringConfig())
+
+	for _, server := range tos.Servers() {
+		if server.CDNName == f.CDN {
+			tos.RemoveServer(server.HostName)
+		}
+	}
+	for _, server := range f.Servers {
+		tos.AddServer(server)
+	}
+	return nil
+}
+
+// monitoringConfig returns the fixture's monitor config, in the form Traffic Ops serves it.
+func (f Fixture) monitoringConfig() to.TrafficMonitorConfig {
+	cfg := to.TrafficMonitorConfig{Config: f.MonitorConfig.Config}
+	for _, server := range f.MonitorConfig.TrafficServer {
+		cfg.TrafficServers = append(cfg.TrafficServers, server)
+	}
+	for _, cacheGroup := range f.MonitorConfig.CacheGroup {
+		cfg.CacheGroups = append(cfg.CacheGroups, cacheGroup)
+	}
+	for _, monitor := range f.MonitorConfig.TrafficMonitor {
+		cfg.TrafficMonitors = append(cfg.TrafficMonitors, monitor)
+	}
+	for _, ds := range f.MonitorConfig.DeliveryService {
+		cfg.DeliveryServices = append(cfg.DeliveryServices, ds)
+	}
+	for _, profile := range f.MonitorConfig.Profile {
+		cfg.Profiles = append(cfg.Profiles, profile)
+	}
+	return cfg
+}
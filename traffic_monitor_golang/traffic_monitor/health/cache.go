@@ -57,35 +57,35 @@ func GetVitals(newResult *cache.Result, prevResult *cache.Result, mc *to.Traffic
 	}
 
 	// proc.net.dev -- need to compare to prevSample
-	// value looks like
-	// "bond0:8495786321839 31960528603    0    0    0     0          0   2349716 143283576747316 101104535041    0    0    0     0       0          0"
-	// (without the quotes)
-	parts := strings.Split(newResult.Astats.System.ProcNetDev, ":")
-	if len(parts) > 1 {
-		numbers := strings.Fields(parts[1])
-		var err error
-		newResult.Vitals.BytesOut, err = strconv.ParseInt(numbers[8], 10, 64)
-		if err != nil {
-			setErr(newResult, fmt.Errorf("Error converting BytesOut from procnetdev: %v", err))
-			return
-		}
-		newResult.Vitals.BytesIn, err = strconv.ParseInt(numbers[0], 10, 64)
-		if err != nil {
-			setErr(newResult, fmt.Errorf("Error converting BytesIn from procnetdev: %v", err))
-			return
-		}
-		if prevResult != nil && prevResult.Vitals.BytesOut != 0 {
-			elapsedTimeInSecs := float64(newResult.Time.UnixNano()-prevResult.Time.UnixNano()) / 1000000000
-			newResult.Vitals.KbpsOut = int64(float64(((newResult.Vitals.BytesOut - prevResult.Vitals.BytesOut) * 8 / 1000)) / elapsedTimeInSecs)
-		} else {
-			// log.Infoln("prevResult == nil for id " + newResult.Id + ". Hope we're just starting up?")
+	// contains a line for each polled interface, whose bytes are summed.
+	interfaces, err := newResult.Astats.System.Interfaces()
+	if err != nil {
+		setErr(newResult, fmt.Errorf("Error parsing procnetdev: %v", err))
+		return
+	}
+	elapsedTimeInSecs := float64(0)
+	if prevResult != nil {
+		elapsedTimeInSecs = float64(newResult.Time.UnixNano()-prevResult.Time.UnixNano()) / 1000000000
+	}
+	newResult.Vitals.Interfaces = map[string]cache.InterfaceVitals{}
+	for name, iface := range interfaces {
+		ifaceVitals := cache.InterfaceVitals{BytesOut: iface.BytesOut, BytesIn: iface.BytesIn, MaxKbpsOut: int64(iface.Speed) * 1000}
+		if prevResult != nil && elapsedTimeInSecs > 0 {
+			if prevIface, ok := prevResult.Vitals.Interfaces[name]; ok && prevIface.BytesOut != 0 {
+				ifaceVitals.KbpsOut = int64(float64(((ifaceVitals.BytesOut - prevIface.BytesOut) * 8 / 1000)) / elapsedTimeInSecs)
+			}
 		}
+		newResult.Vitals.Interfaces[name] = ifaceVitals
+		newResult.Vitals.BytesOut += ifaceVitals.BytesOut
+		newResult.Vitals.BytesIn += ifaceVitals.BytesIn
+	}
+	if prevResult != nil && prevResult.Vitals.BytesOut != 0 && elapsedTimeInSecs > 0 {
+		newResult.Vitals.KbpsOut = int64(float64(((newResult.Vitals.BytesOut - prevResult.Vitals.BytesOut) * 8 / 1000)) / elapsedTimeInSecs)
 	} else {
-		setErr(newResult, fmt.Errorf("Error parsing procnetdev: no fields found"))
-		return
+		// log.Infoln("prevResult == nil for id " + newResult.Id + ". Hope we're just starting up?")
 	}
 
-	// inf.speed -- value looks like "10000" (without the quotes) so it is in Mbps.
+	// inf.speed -- value looks like "10000" (without the quotes) so it is in Mbps. It's the sum of the speeds of all polled interfaces.
 	// TODO JvD: Should we really be running this code every second for every cache polled????? I don't think so.
 	interfaceBandwidth := newResult.Astats.System.InfSpeed
 	newResult.Vitals.MaxKbpsOut = int64(interfaceBandwidth) * 1000
@@ -136,12 +136,15 @@ func EvalCache(result cache.ResultInfo, resultStats cache.ResultStatValHistory,
 	}
 
 	computedStats := cache.ComputedStats()
+	interfaceStats := cache.InterfaceComputedStats(result.Vitals)
 
 	for stat, threshold := range serverProfile.Parameters.Thresholds {
 		resultStat := interface{}(nil)
 		if computedStatF, ok := computedStats[stat]; ok {
 			dummyCombinedstate := peer.IsAvailable{} // the only stats which use combinedState are things like isAvailable, which don't make sense to ever be thresholds.
 			resultStat = computedStatF(result, serverInfo, serverProfile, dummyCombinedstate)
+		} else if interfaceStat, ok := interfaceStats[stat]; ok {
+			resultStat = interfaceStat
 		} else {
 			if resultStats == nil {
 				continue
@@ -170,9 +173,10 @@ func EvalCache(result cache.ResultInfo, resultStats cache.ResultStatValHistory,
 	return result.Available, eventDesc(status, availability), ""
 }
 
-// CalcAvailability calculates the availability of the cache, from the given result. Availability is stored in `localCacheStatus` and `localStates`, and if the status changed an event is added to `events`. statResultHistory may be nil, for pollers which don't poll stats.
+// CalcAvailability calculates the availability of the cache, from the given result. Availability is stored in `localCacheStatus` and `localStates`, and if the status changed an event is added to `events`. statResultHistory may be nil, for pollers which don't poll stats. cacheGroupMinAvailable is the minimum fraction of a cachegroup's caches which must be available for the cachegroup to be enabled for a delivery service.
+// Results polled over IPv6 only determine whether the cache is healthy over IPv6; the cache's overall availability is determined by its IPv4 results. A cache is only available to IPv6 clients if it's both available, and healthy over IPv6.
 // TODO add enum for poller names?
-func CalcAvailability(results []cache.Result, pollerName string, statResultHistory cache.ResultStatHistory, mc to.TrafficMonitorConfigMap, toData todata.TOData, localCacheStatusThreadsafe threadsafe.CacheAvailableStatus, localStates peer.CRStatesThreadsafe, events ThreadsafeEvents) {
+func CalcAvailability(results []cache.Result, pollerName string, statResultHistory cache.ResultStatHistory, mc to.TrafficMonitorConfigMap, toData todata.TOData, localCacheStatusThreadsafe threadsafe.CacheAvailableStatus, localStates peer.CRStatesThreadsafe, events ThreadsafeEvents, cacheGroupMinAvailable float64) {
 	localCacheStatuses := localCacheStatusThreadsafe.Get().Copy()
 	for _, result := range results {
 		statResults := cache.ResultStatValHistory(nil)
@@ -182,31 +186,56 @@ func CalcAvailability(results []cache.Result, pollerName string, statResultHisto
 
 		isAvailable, whyAvailable, unavailableStat := EvalCache(cache.ToInfo(result), statResults, &mc)
 
+		previousStatus, hasPreviousStatus := localCacheStatuses[result.ID]
+
+		if result.Family == enum.AddressFamilyIPv6 {
+			newStatus := previousStatus
+			newStatus.HealthyIPv6 = isAvailable
+			newStatus.WhyIPv6 = whyAvailable
+			newStatus.AvailableIPv6 = newStatus.Available && isAvailable
+			localCacheStatuses[result.ID] = newStatus
+			setCacheState(result.ID, newStatus, whyAvailable, pollerName+" "+result.Family.String(), result.Error, localStates, toData, events)
+			continue
+		}
+
 		// if the cache is now Available, and was previously unavailable due to a threshold, make sure this poller contains the stat which exceeded the threshold.
-		if previousStatus, hasPreviousStatus := localCacheStatuses[result.ID]; isAvailable && hasPreviousStatus && !previousStatus.Available && previousStatus.UnavailableStat != "" {
+		if isAvailable && hasPreviousStatus && !previousStatus.Available && previousStatus.UnavailableStat != "" {
 			if !result.HasStat(previousStatus.UnavailableStat) {
 				return
 			}
 		}
-		localCacheStatuses[result.ID] = cache.AvailableStatus{
+		healthyIPv6, whyIPv6 := previousStatus.HealthyIPv6, previousStatus.WhyIPv6
+		if mc.TrafficServer[string(result.ID)].IP6 == "" {
+			// the cache no longer has an IPv6 address, so its last IPv6 result is stale.
+			healthyIPv6, whyIPv6 = false, ""
+		}
+		newStatus := cache.AvailableStatus{
 			Available:       isAvailable,
+			AvailableIPv6:   isAvailable && healthyIPv6,
+			HealthyIPv6:     healthyIPv6,
 			Status:          mc.TrafficServer[string(result.ID)].Status,
 			Why:             whyAvailable,
+			WhyIPv6:         whyIPv6,
 			UnavailableStat: unavailableStat,
 			Poller:          pollerName,
 		} // TODO move within localStates?
-
-		if available, ok := localStates.GetCache(result.ID); !ok || available.IsAvailable != isAvailable {
-			log.Infof("Changing state for %s was: %t now: %t because %s poller: %v error: %v", result.ID, available.IsAvailable, isAvailable, whyAvailable, pollerName, result.Error)
-			events.Add(Event{Time: Time(time.Now()), Description: whyAvailable + " (" + pollerName + ")", Name: string(result.ID), Hostname: string(result.ID), Type: toData.ServerTypes[result.ID].String(), Available: isAvailable})
-		}
-
-		localStates.SetCache(result.ID, peer.IsAvailable{IsAvailable: isAvailable})
+		localCacheStatuses[result.ID] = newStatus
+		setCacheState(result.ID, newStatus, whyAvailable, pollerName, result.Error, localStates, toData, events)
 	}
-	calculateDeliveryServiceState(toData.DeliveryServiceServers, localStates, toData)
+	calculateDeliveryServiceState(toData.DeliveryServiceServers, localStates, toData, CacheStatuses(mc), cacheGroupMinAvailable)
 	localCacheStatusThreadsafe.Set(localCacheStatuses)
 }
 
+// setCacheState sets the given cache's availability in localStates from its available status, adding an event if either its IPv4 or IPv6 availability changed.
+func setCacheState(cacheName enum.CacheName, status cache.AvailableStatus, why string, pollerName string, resultErr error, localStates peer.CRStatesThreadsafe, toData todata.TOData, events ThreadsafeEvents) {
+	newState := peer.NewIsAvailable(status.Available, status.AvailableIPv6)
+	if available, ok := localStates.GetCache(cacheName); !ok || available != newState {
+		log.Infof("Changing state for %s was: %t (IPv6 %t) now: %t (IPv6 %t) because %s poller: %v error: %v", cacheName, available.IsAvailable, available.Ipv6Available, newState.IsAvailable, newState.Ipv6Available, why, pollerName, resultErr)
+		events.Add(Event{Time: Time(time.Now()), Description: why + " (" + pollerName + ")", Name: string(cacheName), Hostname: string(cacheName), Type: toData.ServerTypes[cacheName].String(), Available: newState.IsAvailable, IPv6Available: newState.Ipv6Available})
+	}
+	localStates.SetCache(cacheName, newState)
+}
+
 func setErr(newResult *cache.Result, err error) {
 	newResult.Error = err
 	newResult.Available = false
@@ -252,8 +281,17 @@ func eventDesc(status enum.CacheStatus, message string) string {
 	return fmt.Sprintf("%s - %s", status, message)
 }
 
-//calculateDeliveryServiceState calculates the state of delivery services from the new cache state data `cacheState` and the CRConfig data `deliveryServiceServers` and puts the calculated state in the outparam `deliveryServiceStates`
-func calculateDeliveryServiceState(deliveryServiceServers map[enum.DeliveryServiceName][]enum.CacheName, states peer.CRStatesThreadsafe, toData todata.TOData) {
+// CacheStatuses returns the Traffic Ops status of each cache in the given monitor config.
+func CacheStatuses(mc to.TrafficMonitorConfigMap) map[enum.CacheName]enum.CacheStatus {
+	statuses := make(map[enum.CacheName]enum.CacheStatus, len(mc.TrafficServer))
+	for name, server := range mc.TrafficServer {
+		statuses[enum.CacheName(name)] = enum.CacheStatusFromString(server.Status)
+	}
+	return statuses
+}
+
+//calculateDeliveryServiceState calculates the state of delivery services from the new cache state data `cacheState` and the CRConfig data `deliveryServiceServers` and puts the calculated state in the outparam `deliveryServiceStates`. Cachegroups whose fraction of available caches for a delivery service is below `cacheGroupMinAvailable` are disabled for it.
+func calculateDeliveryServiceState(deliveryServiceServers map[enum.DeliveryServiceName][]enum.CacheName, states peer.CRStatesThreadsafe, toData todata.TOData, cacheStatuses map[enum.CacheName]enum.CacheStatus, cacheGroupMinAvailable float64) {
 	cacheStates := states.GetCaches() // map[enum.CacheName]IsAvailable
 
 	deliveryServices := states.GetDeliveryServices()
@@ -262,15 +300,15 @@ func calculateDeliveryServiceState(deliveryServiceServers map[enum.DeliveryServi
 			log.Infof("CRConfig does not have delivery service %s, but traffic monitor poller does; skipping\n", deliveryServiceName)
 			continue
 		}
-		deliveryServiceState.DisabledLocations = getDisabledLocations(deliveryServiceName, toData.DeliveryServiceServers[deliveryServiceName], cacheStates, toData.ServerCachegroups)
+		deliveryServiceState.DisabledLocations = getDisabledLocations(deliveryServiceName, toData.DeliveryServiceServers[deliveryServiceName], cacheStates, cacheStatuses, toData.ServerCachegroups, cacheGroupMinAvailable)
 		states.SetDeliveryService(deliveryServiceName, deliveryServiceState)
 	}
 }
 
-func getDisabledLocations(deliveryService enum.DeliveryServiceName, deliveryServiceServers []enum.CacheName, cacheStates map[enum.CacheName]peer.IsAvailable, serverCacheGroups map[enum.CacheName]enum.CacheGroupName) []enum.CacheGroupName {
+func getDisabledLocations(deliveryService enum.DeliveryServiceName, deliveryServiceServers []enum.CacheName, cacheStates map[enum.CacheName]peer.IsAvailable, cacheStatuses map[enum.CacheName]enum.CacheStatus, serverCacheGroups map[enum.CacheName]enum.CacheGroupName, cacheGroupMinAvailable float64) []enum.CacheGroupName {
 	disabledLocations := []enum.CacheGroupName{} // it's important this isn't nil, so it serialises to the JSON `[]` instead of `null`
 	dsCacheStates := getDeliveryServiceCacheAvailability(cacheStates, deliveryServiceServers)
-	dsCachegroupsAvailable := getDeliveryServiceCachegroupAvailability(dsCacheStates, serverCacheGroups)
+	dsCachegroupsAvailable := getDeliveryServiceCachegroupAvailability(dsCacheStates, cacheStatuses, serverCacheGroups, cacheGroupMinAvailable)
 	for cg, avail := range dsCachegroupsAvailable {
 		if avail {
 			continue
@@ -288,17 +326,43 @@ func getDeliveryServiceCacheAvailability(cacheStates map[enum.CacheName]peer.IsA
 	return dsCacheStates
 }
 
-func getDeliveryServiceCachegroupAvailability(dsCacheStates map[enum.CacheName]peer.IsAvailable, serverCachegroups map[enum.CacheName]enum.CacheGroupName) map[enum.CacheGroupName]bool {
-	cgAvail := map[enum.CacheGroupName]bool{}
-	for cache, available := range dsCacheStates {
+// getDeliveryServiceCachegroupAvailability returns whether each cachegroup is available for the delivery service with the given caches. OFFLINE and ADMIN_DOWN caches aren't counted, because they're deliberately out of service, so they don't lower the fraction of a cachegroup's caches which are available. A cachegroup with no other caches is unavailable.
+func getDeliveryServiceCachegroupAvailability(dsCacheStates map[enum.CacheName]peer.IsAvailable, cacheStatuses map[enum.CacheName]enum.CacheStatus, serverCachegroups map[enum.CacheName]enum.CacheGroupName, cacheGroupMinAvailable float64) map[enum.CacheGroupName]bool {
+	total := map[enum.CacheGroupName]int{}
+	available := map[enum.CacheGroupName]int{}
+	for cache, cacheAvailable := range dsCacheStates {
 		cg, ok := serverCachegroups[cache]
 		if !ok {
-			log.Errorf("cache %v not found in cachegroups!")
+			log.Errorf("cache %v not found in cachegroups!", cache)
 			continue
 		}
-		if _, ok := cgAvail[cg]; !ok || available.IsAvailable {
-			cgAvail[cg] = available.IsAvailable
+		if OutOfService(cacheStatuses[cache]) {
+			if _, ok := total[cg]; !ok {
+				total[cg] = 0 // the cachegroup is still disabled if it has no other caches
+			}
+			continue
+		}
+		total[cg]++
+		if cacheAvailable.IsAvailable {
+			available[cg]++
 		}
 	}
+	cgAvail := map[enum.CacheGroupName]bool{}
+	for cg, n := range total {
+		cgAvail[cg] = CacheGroupAvailable(available[cg], n, cacheGroupMinAvailable)
+	}
 	return cgAvail
 }
+
+// OutOfService returns whether caches with the given Traffic Ops status are deliberately out of service, i.e. OFFLINE or ADMIN_DOWN, so they aren't counted toward their cachegroup's availability.
+func OutOfService(status enum.CacheStatus) bool {
+	return status == enum.CacheStatusOffline || status == enum.CacheStatusAdminDown
+}
+
+// CacheGroupAvailable returns whether a cachegroup with the given number of available caches, out of the given total, should be routed to. A cachegroup is unavailable if none of its caches are available, or if the fraction available is below the given minimum, so its remaining caches aren't overloaded with the traffic of the whole cachegroup.
+func CacheGroupAvailable(available int, total int, minAvailable float64) bool {
+	if available == 0 || total == 0 {
+		return false
+	}
+	return float64(available)/float64(total) >= minAvailable
+}
@@ -0,0 +1,210 @@
+package health
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/log"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/cache"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/threadsafe"
+	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
+	to "github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+)
+
+func TestGetDisabledLocations(t *testing.T) {
+	servers := []enum.CacheName{"edge-a1", "edge-a2", "edge-a3", "edge-a4", "edge-b1", "edge-b2", "edge-c1"}
+	cacheGroups := map[enum.CacheName]enum.CacheGroupName{
+		"edge-a1": "cg-a", "edge-a2": "cg-a", "edge-a3": "cg-a", "edge-a4": "cg-a",
+		"edge-b1": "cg-b", "edge-b2": "cg-b",
+		"edge-c1": "cg-c",
+	}
+	states := map[enum.CacheName]peer.IsAvailable{
+		"edge-a1": {IsAvailable: true},
+		"edge-b1": {IsAvailable: true},
+		"edge-b2": {IsAvailable: true},
+		"edge-c1": {IsAvailable: false},
+	}
+
+	statuses := map[enum.CacheName]enum.CacheStatus{}
+	for _, server := range servers {
+		statuses[server] = enum.CacheStatusReported
+	}
+
+	disabled := func(minAvailable float64) map[enum.CacheGroupName]bool {
+		m := map[enum.CacheGroupName]bool{}
+		for _, cg := range getDisabledLocations("ds-a", servers, states, statuses, cacheGroups, minAvailable) {
+			m[cg] = true
+		}
+		return m
+	}
+
+	if d := disabled(0); len(d) != 1 || !d["cg-c"] {
+		t.Errorf("getDisabledLocations with no minimum expected [cg-c], actual %v", d)
+	}
+	if d := disabled(0.25); len(d) != 1 || !d["cg-c"] {
+		t.Errorf("getDisabledLocations with minimum 0.25 expected [cg-c], actual %v", d)
+	}
+	if d := disabled(0.5); len(d) != 2 || !d["cg-a"] || !d["cg-c"] {
+		t.Errorf("getDisabledLocations with minimum 0.5 expected [cg-a cg-c], actual %v", d)
+	}
+
+	// OFFLINE and ADMIN_DOWN caches don't count against their cachegroup
+	statuses["edge-a2"] = enum.CacheStatusAdminDown
+	statuses["edge-a3"] = enum.CacheStatusOffline
+	if d := disabled(0.5); len(d) != 1 || !d["cg-c"] {
+		t.Errorf("getDisabledLocations with minimum 0.5 and 2 of cg-a down for maintenance expected [cg-c], actual %v", d)
+	}
+	statuses["edge-c1"] = enum.CacheStatusAdminDown
+	if d := disabled(0); len(d) != 1 || !d["cg-c"] {
+		t.Errorf("getDisabledLocations with all of cg-c down for maintenance expected [cg-c], actual %v", d)
+	}
+}
+
+func TestCacheGroupAvailable(t *testing.T) {
+	if CacheGroupAvailable(0, 0, 0) {
+		t.Errorf("CacheGroupAvailable with no caches expected false, actual true")
+	}
+	if CacheGroupAvailable(0, 4, 0) {
+		t.Errorf("CacheGroupAvailable with no available caches expected false, actual true")
+	}
+	if !CacheGroupAvailable(2, 4, 0.5) {
+		t.Errorf("CacheGroupAvailable at minimum expected true, actual false")
+	}
+	if CacheGroupAvailable(1, 4, 0.5) {
+		t.Errorf("CacheGroupAvailable below minimum expected false, actual true")
+	}
+}
+
+func TestCalcAvailabilityIPv6(t *testing.T) {
+	discard := log.NopCloser(ioutil.Discard)
+	log.Init(discard, discard, discard, discard, discard)
+
+	mc := to.TrafficMonitorConfigMap{
+		TrafficServer: map[string]to.TrafficServer{"edge1": {HostName: "edge1", Profile: "EDGE1", Status: string(enum.CacheStatusReported), IP6: "2001:db8::1/64"}},
+		Profile:       map[string]to.TMProfile{"EDGE1": {Name: "EDGE1"}},
+	}
+	toData := *todata.New()
+	statuses := threadsafe.NewCacheAvailableStatus()
+	states := peer.NewCRStatesThreadsafe()
+	states.AddCache("edge1", peer.IsAvailable{})
+	events := NewThreadsafeEvents(10)
+
+	calc := func(family enum.AddressFamily, err error) peer.IsAvailable {
+		result := cache.Result{ID: "edge1", Available: err == nil, Error: err, Family: family}
+		CalcAvailability([]cache.Result{result}, "health", nil, mc, toData, statuses, states, events, 0)
+		available, _ := states.GetCache("edge1")
+		return available
+	}
+
+	if a := calc(enum.AddressFamilyIPv4, nil); !a.IsAvailable || a.Ipv6Available {
+		t.Errorf("CalcAvailability before an IPv6 result expected available only over IPv4, actual %+v", a)
+	}
+	if a := calc(enum.AddressFamilyIPv6, nil); !a.IsAvailable || !a.Ipv6Available {
+		t.Errorf("CalcAvailability with a healthy IPv6 result expected available over IPv4 and IPv6, actual %+v", a)
+	}
+	if a := calc(enum.AddressFamilyIPv6, errors.New("timeout")); !a.IsAvailable || a.Ipv6Available {
+		t.Errorf("CalcAvailability with an unhealthy IPv6 result expected available only over IPv4, actual %+v", a)
+	}
+	calc(enum.AddressFamilyIPv6, nil)
+	if a := calc(enum.AddressFamilyIPv4, errors.New("timeout")); a.IsAvailable || a.Ipv6Available {
+		t.Errorf("CalcAvailability with an unhealthy IPv4 result expected unavailable over IPv4 and IPv6, actual %+v", a)
+	}
+	if a := calc(enum.AddressFamilyIPv4, nil); !a.IsAvailable || !a.Ipv6Available {
+		t.Errorf("CalcAvailability with a healthy IPv4 result after a healthy IPv6 result expected available over IPv4 and IPv6, actual %+v", a)
+	}
+
+	srv := mc.TrafficServer["edge1"]
+	srv.IP6 = ""
+	mc.TrafficServer["edge1"] = srv
+	if a := calc(enum.AddressFamilyIPv4, nil); !a.IsAvailable || a.Ipv6Available {
+		t.Errorf("CalcAvailability after the IPv6 address is removed expected available only over IPv4, actual %+v", a)
+	}
+	if status := statuses.Get()["edge1"]; status.HealthyIPv6 || status.WhyIPv6 != "" {
+		t.Errorf("CalcAvailability after the IPv6 address is removed expected no IPv6 health, actual %+v", status)
+	}
+}
+
+func TestGetVitalsInterfaces(t *testing.T) {
+	mc := to.TrafficMonitorConfigMap{}
+	now := time.Now()
+	prev := cache.Result{ID: "edge1", Time: now.Add(-time.Second)}
+	prev.Astats.System = cache.AstatsSystem{
+		InfSpeed:    20000,
+		InfSpeeds:   "eth0:10000,eth1:10000",
+		ProcLoadavg: "0.20 0.07 0.07 1/967 29536",
+		ProcNetDev:  "eth0:100 2 0 0 0 0 0 0 1000 3 0 0 0 0 0 0\neth1: 200 4 0 0 0 0 0 0 2000 5 0 0 0 0 0 0",
+	}
+	GetVitals(&prev, nil, &mc)
+	if prev.Error != nil {
+		t.Fatalf("GetVitals expected nil error, actual %v", prev.Error)
+	}
+
+	result := cache.Result{ID: "edge1", Time: now}
+	result.Astats.System = prev.Astats.System
+	result.Astats.System.ProcNetDev = "eth0:100 2 0 0 0 0 0 0 126000 3 0 0 0 0 0 0\neth1: 200 4 0 0 0 0 0 0 252000 5 0 0 0 0 0 0"
+	GetVitals(&result, &prev, &mc)
+	if result.Error != nil {
+		t.Fatalf("GetVitals expected nil error, actual %v", result.Error)
+	}
+
+	expected := map[string]cache.InterfaceVitals{
+		"eth0": {BytesIn: 100, BytesOut: 126000, KbpsOut: 1000, MaxKbpsOut: 10000000},
+		"eth1": {BytesIn: 200, BytesOut: 252000, KbpsOut: 2000, MaxKbpsOut: 10000000},
+	}
+	if !reflect.DeepEqual(result.Vitals.Interfaces, expected) {
+		t.Errorf("GetVitals interfaces expected %+v, actual %+v", expected, result.Vitals.Interfaces)
+	}
+	if result.Vitals.BytesIn != 300 || result.Vitals.BytesOut != 378000 || result.Vitals.KbpsOut != 3000 || result.Vitals.MaxKbpsOut != 20000000 {
+		t.Errorf("GetVitals expected summed vitals BytesIn 300 BytesOut 378000 KbpsOut 3000 MaxKbpsOut 20000000, actual %+v", result.Vitals)
+	}
+}
+
+func TestEvalCacheInterfaceThreshold(t *testing.T) {
+	mc := to.TrafficMonitorConfigMap{
+		TrafficServer: map[string]to.TrafficServer{"edge1": {HostName: "edge1", Profile: "EDGE1", Status: string(enum.CacheStatusReported)}},
+		Profile: map[string]to.TMProfile{"EDGE1": {Name: "EDGE1", Parameters: to.TMParameters{Thresholds: map[string]to.HealthThreshold{
+			"system.inf.eth1.availableBandwidthInKbps": {Val: 1500, Comparator: ">"},
+		}}}},
+	}
+	result := cache.Result{ID: "edge1", Available: true}
+	result.Vitals.Interfaces = map[string]cache.InterfaceVitals{
+		"eth0": {KbpsOut: 9000, MaxKbpsOut: 10000},
+		"eth1": {KbpsOut: 1000, MaxKbpsOut: 10000},
+	}
+	if available, why, stat := EvalCache(cache.ToInfo(result), nil, &mc); !available {
+		t.Errorf("EvalCache with eth1 under its threshold expected available, actual unavailable: %v %v", why, stat)
+	}
+
+	result.Vitals.Interfaces["eth1"] = cache.InterfaceVitals{KbpsOut: 9000, MaxKbpsOut: 10000}
+	available, _, stat := EvalCache(cache.ToInfo(result), nil, &mc)
+	if available {
+		t.Errorf("EvalCache with eth1 over its threshold expected unavailable, actual available")
+	}
+	if stat != "system.inf.eth1.availableBandwidthInKbps" {
+		t.Errorf("EvalCache with eth1 over its threshold expected unavailable stat %q, actual %q", "system.inf.eth1.availableBandwidthInKbps", stat)
+	}
+}
@@ -42,6 +42,8 @@ type Event struct {
 	Hostname    string `json:"hostname"`
 	Type        string `json:"type"`
 	Available   bool   `json:"isAvailable"`
+	// IPv6Available is whether the cache is available to IPv6 clients, for cache events.
+	IPv6Available bool `json:"ipv6Available"`
 }
 
 // Events provides safe access for multiple goroutines readers and a single writer to a stored Events slice.
@@ -0,0 +1,140 @@
+package manager
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/log"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/alert"
+	dsdata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/deliveryservicedata"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/threadsafe"
+	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
+	towrap "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopswrapper"
+)
+
+// StartAlertManager starts the goroutine which evaluates the configured alert rules every alert interval, and sends the resulting notifications. If no rules are configured, nothing is started. Returns an error if the alert config is invalid. It stops when the given context is done.
+func StartAlertManager(
+	ctx context.Context,
+	cfg alert.Config,
+	toSession towrap.StatusSession,
+	toData todata.TODataThreadsafe,
+	monitorConfig threadsafe.TrafficMonitorConfigMap,
+	combinedStates peer.CRStatesThreadsafe,
+	localCacheStatus threadsafe.CacheAvailableStatus,
+	peerStates peer.CRStatesPeersThreadsafe,
+	dsStats threadsafe.DSStatsReader,
+	errorCount threadsafe.Uint,
+) error {
+	if len(cfg.Rules) == 0 {
+		return nil
+	}
+	engine, err := alert.NewEngine(cfg)
+	if err != nil {
+		return fmt.Errorf("creating alert engine: %v", err)
+	}
+	notifiers, err := alert.NewNotifiers(cfg.Notifiers)
+	if err != nil {
+		return fmt.Errorf("creating alert notifiers: %v", err)
+	}
+
+	go func() {
+		tick := time.NewTicker(cfg.Interval())
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-tick.C:
+				state := alertState(toSession, toData, monitorConfig, combinedStates, localCacheStatus, peerStates, dsStats)
+				for _, n := range engine.Evaluate(state, now) {
+					sendAlert(ctx, n, notifiers, errorCount)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// sendAlert sends the given notification to its notifiers, or to all notifiers if it doesn't name any. Notifiers are called serially, so a slow receiver delays the next evaluation, rather than piling up concurrent notifications.
+func sendAlert(ctx context.Context, n alert.Notification, notifiers map[string]alert.Notifier, errorCount threadsafe.Uint) {
+	log.Infof("alert %v %v %v: %v\n", n.Alert.Status, n.Alert.Rule, n.Alert.Subject, n.Alert.Description)
+	names := n.Notifiers
+	if len(names) == 0 {
+		for name := range notifiers {
+			names = append(names, name)
+		}
+	}
+	for _, name := range names {
+		if err := notifiers[name].Notify(ctx, n.Alert); err != nil {
+			errorCount.Inc()
+			log.Errorf("alert %v %v notifier %v: %v\n", n.Alert.Rule, n.Alert.Subject, name, err)
+		}
+	}
+}
+
+// alertState returns a snapshot of the monitor's current state, for evaluating alert rules. Caches which haven't been polled yet are omitted, so they don't alert as unavailable on startup or when they're added.
+func alertState(
+	toSession towrap.StatusSession,
+	toData todata.TODataThreadsafe,
+	monitorConfig threadsafe.TrafficMonitorConfigMap,
+	combinedStates peer.CRStatesThreadsafe,
+	localCacheStatus threadsafe.CacheAvailableStatus,
+	peerStates peer.CRStatesPeersThreadsafe,
+	dsStats threadsafe.DSStatsReader,
+) alert.State {
+	mc := monitorConfig.Get()
+	td := toData.Get()
+	crStates := combinedStates.Get()
+	polled := localCacheStatus.Get()
+
+	s := alert.State{
+		Caches:           map[enum.CacheName]alert.CacheState{},
+		DeliveryServices: map[enum.DeliveryServiceName]dsdata.StatCacheStats{},
+		Peers:            map[enum.TrafficMonitorName]alert.PeerState{},
+		TrafficOpsErr:    toSession.Status().LastErr,
+	}
+	for name, server := range mc.TrafficServer {
+		cacheName := enum.CacheName(name)
+		if _, ok := polled[cacheName]; !ok {
+			continue
+		}
+		s.Caches[cacheName] = alert.CacheState{
+			CacheGroup: td.ServerCachegroups[cacheName],
+			Status:     enum.CacheStatusFromString(server.Status),
+			Available:  crStates.Caches[cacheName].IsAvailable,
+		}
+	}
+	stats := dsStats.Get()
+	for name := range mc.DeliveryService {
+		dsName := enum.DeliveryServiceName(name)
+		if stat, ok := stats.Get(dsName); ok {
+			s.DeliveryServices[dsName] = stat.Total()
+		}
+	}
+	for name, online := range peerStates.GetPeersOnline() {
+		s.Peers[name] = alert.PeerState{Online: online, Available: peerStates.GetPeerAvailability(name)}
+	}
+	return s
+}
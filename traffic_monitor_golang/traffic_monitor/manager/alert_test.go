@@ -0,0 +1,54 @@
+package manager
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/cache"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/threadsafe"
+	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
+	towrap "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopswrapper"
+	to "github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+)
+
+func TestAlertStateUnpolledCaches(t *testing.T) {
+	monitorConfig := threadsafe.NewTrafficMonitorConfigMap()
+	monitorConfig.Set(to.TrafficMonitorConfigMap{TrafficServer: map[string]to.TrafficServer{
+		"edge-polled":   {HostName: "edge-polled", Status: string(enum.CacheStatusReported)},
+		"edge-unpolled": {HostName: "edge-unpolled", Status: string(enum.CacheStatusReported)},
+	}})
+	combinedStates := peer.NewCRStatesThreadsafe()
+	combinedStates.AddCache("edge-polled", peer.NewIsAvailable(false, false))
+	combinedStates.AddCache("edge-unpolled", peer.NewIsAvailable(false, false))
+	localCacheStatus := threadsafe.NewCacheAvailableStatus()
+	localCacheStatus.Set(cache.AvailableStatuses{"edge-polled": cache.AvailableStatus{Available: false}})
+
+	dsStats := threadsafe.NewDSStats()
+	s := alertState(towrap.NewStatusSession(nil), todata.NewThreadsafe(), monitorConfig, combinedStates, localCacheStatus, peer.NewCRStatesPeersThreadsafe(), &dsStats)
+	if state, ok := s.Caches["edge-polled"]; !ok || state.Available {
+		t.Errorf("alertState polled cache expected unavailable, actual %+v present %v", state, ok)
+	}
+	if state, ok := s.Caches["edge-unpolled"]; ok {
+		t.Errorf("alertState unpolled cache expected omitted, actual %+v", state)
+	}
+}
@@ -20,6 +20,7 @@ package manager
  */
 
 import (
+	"context"
 	"time"
 
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/log"
@@ -32,12 +33,13 @@ import (
 	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
 )
 
-// StartHealthResultManager starts the goroutine which listens for health results.
+// StartHealthResultManager starts the goroutine which listens for health results, until the given context is done.
 // Note this polls the brief stat endpoint from ATS Astats, not the full stats.
 // This poll should be quicker and less computationally expensive for ATS, but
 // doesn't include all stat data needed for e.g. delivery service calculations.4
 // Returns the last health durations, events, the local cache statuses, and the health result history.
 func StartHealthResultManager(
+	ctx context.Context,
 	cacheHealthChan <-chan cache.Result,
 	toData todata.TODataThreadsafe,
 	localStates peer.CRStatesThreadsafe,
@@ -52,6 +54,7 @@ func StartHealthResultManager(
 	lastHealthDurations := threadsafe.NewDurationMap()
 	healthHistory := threadsafe.NewResultHistory()
 	go healthResultManagerListen(
+		ctx,
 		cacheHealthChan,
 		toData,
 		localStates,
@@ -69,6 +72,7 @@ func StartHealthResultManager(
 }
 
 func healthResultManagerListen(
+	ctx context.Context,
 	cacheHealthChan <-chan cache.Result,
 	toData todata.TODataThreadsafe,
 	localStates peer.CRStatesThreadsafe,
@@ -107,7 +111,15 @@ func healthResultManagerListen(
 
 	for {
 		var results []cache.Result
-		results = append(results, <-cacheHealthChan)
+		select {
+		case <-ctx.Done():
+			if ticker != nil {
+				ticker.Stop()
+			}
+			return
+		case r := <-cacheHealthChan:
+			results = append(results, r)
+		}
 		if ticker != nil {
 			ticker.Stop()
 		}
@@ -175,6 +187,10 @@ func processHealthResult(
 			results[i] = healthResult
 		}
 
+		if healthResult.Family == enum.AddressFamilyIPv6 {
+			continue // IPv6 results only determine IPv6 availability; the history and vitals come from IPv4 polls
+		}
+
 		maxHistory := uint64(monitorConfigCopy.Profile[monitorConfigCopy.TrafficServer[string(healthResult.ID)].Profile].Parameters.HistoryCount)
 		if maxHistory < 1 {
 			log.Infof("processHealthResult got history count %v for %v, setting to 1\n", maxHistory, healthResult.ID)
@@ -184,13 +200,16 @@ func processHealthResult(
 		healthHistoryCopy[healthResult.ID] = pruneHistory(append([]cache.Result{healthResult}, healthHistoryCopy[healthResult.ID]...), maxHistory)
 	}
 
-	health.CalcAvailability(results, "health", nil, monitorConfigCopy, toDataCopy, localCacheStatusThreadsafe, localStates, events)
+	health.CalcAvailability(results, "health", nil, monitorConfigCopy, toDataCopy, localCacheStatusThreadsafe, localStates, events, cfg.CacheGroupMinAvailable)
 
 	healthHistory.Set(healthHistoryCopy)
 	// TODO determine if we should combineCrStates() here
 
 	lastHealthDurations := threadsafe.CopyDurationMap(lastHealthDurationsThreadsafe.Get())
 	for _, healthResult := range results {
+		if healthResult.Family == enum.AddressFamilyIPv6 {
+			continue
+		}
 		if lastHealthStart, ok := lastHealthEndTimes[healthResult.ID]; ok {
 			d := time.Since(lastHealthStart)
 			lastHealthDurations[healthResult.ID] = d
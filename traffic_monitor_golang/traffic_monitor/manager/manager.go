@@ -20,12 +20,15 @@ package manager
  */
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
+	"time"
 
 	"golang.org/x/sys/unix"
 
@@ -37,6 +40,7 @@ import (
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/poller"
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/cache"
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/config"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/health"
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/threadsafe"
@@ -45,10 +49,42 @@ import (
 )
 
 //
-// Start starts the poller and handler goroutines
+// Start starts the poller and handler goroutines, and runs until the process receives SIGTERM, when it shuts down gracefully and returns nil.
 //
 func Start(opsConfigFile string, cfg config.Config, staticAppData config.StaticAppData, trafficMonitorConfigFileName string) error {
-	toSession := towrap.ITrafficOpsSession(towrap.NewTrafficOpsSessionThreadsafe(nil))
+	return StartWithSession(opsConfigFile, cfg, staticAppData, trafficMonitorConfigFileName, towrap.NewTrafficOpsSessionThreadsafe(nil))
+}
+
+// StartWithSession starts the poller and handler goroutines, using the given Traffic Ops session, which is logged in with the credentials in the ops config file. This allows the monitor to be run against a fake Traffic Ops, for testing. Like Start, this does not return unless starting fails, or the process receives SIGTERM, in which case it shuts down gracefully, as described by StartWithContext, and returns nil.
+func StartWithSession(opsConfigFile string, cfg config.Config, staticAppData config.StaticAppData, trafficMonitorConfigFileName string, toSession towrap.ITrafficOpsSession) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, unix.SIGTERM)
+		defer signal.Stop(c)
+		select {
+		case <-c:
+			log.Infof("Received SIGTERM, shutting down\n")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return StartWithContext(ctx, opsConfigFile, cfg, staticAppData, trafficMonitorConfigFileName, toSession)
+}
+
+// StartWithContext starts the poller and handler goroutines, using the given Traffic Ops session, and runs until the given context is done or starting fails.
+// When the context is done, the monitor shuts down gracefully: it stops polling and waits for in-flight polls to finish, continues serving the last known states for the config ShutdownDrain, so Traffic Routers and peers have time to notice it's going away, then stops the HTTP server, waiting for in-flight requests to finish, stops the managers, and returns nil.
+func StartWithContext(ctx context.Context, opsConfigFile string, cfg config.Config, staticAppData config.StaticAppData, trafficMonitorConfigFileName string, toSession towrap.ITrafficOpsSession) error {
+	// The managers aren't stopped by the given context, because they must keep processing the results of in-flight polls, after polling is stopped.
+	managerCtx, stopManagers := context.WithCancel(context.Background())
+	defer stopManagers()
+	pollCtx, stopPolling := context.WithCancel(ctx)
+	defer stopPolling()
+
+	statusSession := towrap.NewStatusSession(toSession) // records Traffic Ops request failures, for alerting
+	toSession = statusSession
+
 	counters := fetcher.Counters{
 		Success: gmx.NewCounter("fetchSuccess"),
 		Fail:    gmx.NewCounter("fetchFail"),
@@ -69,29 +105,39 @@ func Start(opsConfigFile string, cfg config.Config, staticAppData config.StaticA
 
 	cacheHealthHandler := cache.NewHandler()
 	cacheHealthPoller := poller.NewHTTP(cfg.CacheHealthPollingInterval, true, sharedClient, counters, cacheHealthHandler, cfg.HTTPPollNoSleep, staticAppData.UserAgent)
+	cacheHealthHandlerV6 := cacheHealthHandler.WithFamily(enum.AddressFamilyIPv6) // IPv6 health results are sent to the same chan, and processed by the same manager, as IPv4
+	cacheHealthPollerV6 := poller.NewHTTP(cfg.CacheHealthPollingInterval, false, sharedClient, counters, cacheHealthHandlerV6, cfg.HTTPPollNoSleep, staticAppData.UserAgent)
 	cacheStatHandler := cache.NewPrecomputeHandler(toData)
 	cacheStatPoller := poller.NewHTTP(cfg.CacheStatPollingInterval, false, sharedClient, counters, cacheStatHandler, cfg.HTTPPollNoSleep, staticAppData.UserAgent)
 	monitorConfigPoller := poller.NewMonitorConfig(cfg.MonitorConfigPollingInterval)
 	peerHandler := peer.NewHandler()
 	peerPoller := poller.NewHTTP(cfg.PeerPollingInterval, false, sharedClient, counters, peerHandler, cfg.HTTPPollNoSleep, staticAppData.UserAgent)
+	peerPoller.FetcherTemplate.Headers = map[string]string{"Accept": peer.AcceptCrstates} // gzip is requested and decoded by the http.Transport
 
-	go monitorConfigPoller.Poll()
-	go cacheHealthPoller.Poll()
-	go cacheStatPoller.Poll()
-	go peerPoller.Poll()
+	pollers := sync.WaitGroup{}
+	for _, p := range []poller.Poller{monitorConfigPoller, cacheHealthPoller, cacheHealthPollerV6, cacheStatPoller, peerPoller} {
+		pollers.Add(1)
+		go func(p poller.Poller) {
+			defer pollers.Done()
+			p.Poll(pollCtx)
+		}(p)
+	}
 
 	events := health.NewThreadsafeEvents(cfg.MaxEvents)
 
 	cachesChanged := make(chan struct{})
 	peerStates := peer.NewCRStatesPeersThreadsafe() // each peer's last state is saved in this map
+	peerMembership := peer.NewMembershipThreadsafe(enum.TrafficMonitorName(staticAppData.Hostname))
+	toPeers := make(chan poller.HttpPollerConfig)
 
 	monitorConfig := StartMonitorConfigManager(
+		managerCtx,
 		monitorConfigPoller.ConfigChannel,
 		localStates,
-		peerStates,
 		cacheStatPoller.ConfigChannel,
 		cacheHealthPoller.ConfigChannel,
-		peerPoller.ConfigChannel,
+		cacheHealthPollerV6.ConfigChannel,
+		toPeers,
 		monitorConfigPoller.IntervalChan,
 		cachesChanged,
 		cfg,
@@ -100,16 +146,30 @@ func Start(opsConfigFile string, cfg config.Config, staticAppData config.StaticA
 		toData,
 	)
 
-	combinedStates, combineStateFunc := StartStateCombiner(events, peerStates, localStates, toData)
+	combinedStates, combineStateFunc := StartStateCombiner(managerCtx, events, peerStates, localStates, toData)
+
+	StartPeerMembershipManager(
+		managerCtx,
+		toPeers,
+		peerMembership,
+		peerStates,
+		peerPoller.ConfigChannel,
+		cfg,
+		staticAppData,
+		sharedClient,
+	)
 
 	StartPeerManager(
+		managerCtx,
 		peerHandler.ResultChannel,
 		peerStates,
+		peerMembership,
 		events,
 		combineStateFunc,
 	)
 
 	statInfoHistory, statResultHistory, statMaxKbpses, _, lastKbpsStats, dsStats, unpolledCaches, localCacheStatus := StartStatHistoryManager(
+		managerCtx,
 		cacheStatHandler.ResultChan(),
 		localStates,
 		combinedStates,
@@ -123,6 +183,7 @@ func Start(opsConfigFile string, cfg config.Config, staticAppData config.StaticA
 	)
 
 	lastHealthDurations, healthHistory := StartHealthResultManager(
+		managerCtx,
 		cacheHealthHandler.ResultChan(),
 		toData,
 		localStates,
@@ -135,7 +196,7 @@ func Start(opsConfigFile string, cfg config.Config, staticAppData config.StaticA
 		localCacheStatus,
 	)
 
-	StartOpsConfigManager(
+	_, httpServer, err := StartOpsConfigManager(
 		opsConfigFile,
 		toSession,
 		toData,
@@ -143,6 +204,7 @@ func Start(opsConfigFile string, cfg config.Config, staticAppData config.StaticA
 		[]chan<- towrap.ITrafficOpsSession{monitorConfigPoller.SessionChannel},
 		localStates,
 		peerStates,
+		peerMembership,
 		combinedStates,
 		statInfoHistory,
 		statResultHistory,
@@ -162,19 +224,47 @@ func Start(opsConfigFile string, cfg config.Config, staticAppData config.StaticA
 		monitorConfig,
 		cfg,
 	)
+	if err != nil {
+		return fmt.Errorf("starting ops config manager: %v", err)
+	}
 
 	if err := startMonitorConfigFilePoller(trafficMonitorConfigFileName); err != nil {
+		httpServer.Shutdown(context.Background())
 		return fmt.Errorf("starting monitor config file poller: %v", err)
 	}
 
-	healthTickListener(cacheHealthPoller.TickChan, healthIteration)
+	go healthTickListener(managerCtx, cacheHealthPoller.TickChan, healthIteration)
+
+	if err := StartAlertManager(managerCtx, cfg.Alerts, statusSession, toData, monitorConfig, combinedStates, localCacheStatus, peerStates, dsStats, errorCount); err != nil {
+		httpServer.Shutdown(context.Background())
+		return fmt.Errorf("starting alert manager: %v", err)
+	}
+
+	<-ctx.Done()
+	log.Infof("Shutdown: stopping polling\n")
+	pollers.Wait()
+	log.Infof("Shutdown: serving last known states for %v\n", cfg.ShutdownDrain)
+	time.Sleep(cfg.ShutdownDrain)
+
+	// No request takes longer than the write timeout, so waiting longer would only wait for idle clients.
+	serveCtx, cancelServe := context.WithTimeout(context.Background(), cfg.ServeWriteTimeout)
+	defer cancelServe()
+	if err := httpServer.Shutdown(serveCtx); err != nil {
+		log.Errorf("Shutdown: stopping HTTP server: %v\n", err)
+	}
+	log.Infof("Shutdown: complete\n")
 	return nil
 }
 
-// healthTickListener listens for health ticks, and writes to the health iteration variable. Does not return.
-func healthTickListener(cacheHealthTick <-chan uint64, healthIteration threadsafe.Uint) {
-	for i := range cacheHealthTick {
-		healthIteration.Set(i)
+// healthTickListener listens for health ticks, and writes to the health iteration variable, until the given context is done.
+func healthTickListener(ctx context.Context, cacheHealthTick <-chan uint64, healthIteration threadsafe.Uint) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case i := <-cacheHealthTick:
+			healthIteration.Set(i)
+		}
 	}
 }
 
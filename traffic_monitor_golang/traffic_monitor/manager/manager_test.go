@@ -0,0 +1,270 @@
+package manager
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/handler"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/log"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/config"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/fake"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	towrap "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopswrapper"
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client/totest"
+
+	"golang.org/x/sys/unix"
+)
+
+const testTimeout = 10 * time.Second
+
+// freeAddress returns a local address with a port which is currently unused.
+func freeAddress(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("getting free port: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func writeJSONFile(t *testing.T, dir string, name string, v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshalling %v: %v", name, err)
+	}
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, b, 0644); err != nil {
+		t.Fatalf("writing %v: %v", name, err)
+	}
+	return path
+}
+
+// getCRStates returns the monitor's combined CRStates, or an error if the monitor isn't serving yet.
+func getCRStates(addr string) (peer.Crstates, error) {
+	resp, err := http.Get("http://" + addr + "/api/v2/crstates")
+	if err != nil {
+		return peer.Crstates{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return peer.Crstates{}, fmt.Errorf("bad status: %v", resp.StatusCode)
+	}
+	crStates := struct {
+		Response peer.Crstates `json:"response"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&crStates); err != nil {
+		return peer.Crstates{}, err
+	}
+	return crStates.Response, nil
+}
+
+// waitForCRStates polls the monitor's CRStates until the given func returns true, failing the test after testTimeout.
+func waitForCRStates(t *testing.T, addr string, desc string, f func(peer.Crstates) bool) {
+	deadline := time.Now().Add(testTimeout)
+	crStates, err := peer.Crstates{}, error(nil)
+	for time.Now().Before(deadline) {
+		if crStates, err = getCRStates(addr); err == nil && f(crStates) {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %v, last CRStates %+v error %v", desc, crStates, err)
+}
+
+func isAvailable(name enum.CacheName, available bool) func(peer.Crstates) bool {
+	return func(crStates peer.Crstates) bool {
+		state, ok := crStates.Caches[name]
+		return ok && state.IsAvailable == available
+	}
+}
+
+const testCDN = "fake-cdn"
+const testMonitorHostname = "fake-monitor"
+
+// testMonitor is the configuration of a Traffic Monitor under test, monitoring two fake caches from a fake Traffic Ops.
+type testMonitor struct {
+	dir           string
+	addr          string
+	opsConfigFile string
+	tmConfigFile  string
+	cfg           config.Config
+	staticAppData config.StaticAppData
+	tos           *totest.Server
+	fixture       fake.Fixture
+	cacheA        *fake.Cache
+	cacheB        *fake.Cache
+}
+
+// newTestMonitor creates the config files and fakes of a Traffic Monitor under test. Callers must call Close.
+func newTestMonitor(t *testing.T) testMonitor {
+	discard := log.NopCloser(ioutil.Discard)
+	log.Init(discard, discard, discard, discard, discard)
+
+	dir, err := ioutil.TempDir("", "tm-manager-test")
+	if err != nil {
+		t.Fatalf("creating temp dir: %v", err)
+	}
+
+	m := testMonitor{dir: dir, cacheA: fake.NewCache("fake-edge-a"), cacheB: fake.NewCache("fake-edge-b")}
+	m.tos = totest.NewServer()
+	m.tos.AddUser("user", "pass")
+	m.fixture = fake.NewFixture(testCDN, testMonitorHostname, m.cacheA, m.cacheB)
+	if err := m.fixture.Serve(m.tos); err != nil {
+		t.Fatalf("serving fixture: %v", err)
+	}
+
+	m.addr = freeAddress(t)
+	m.opsConfigFile = writeJSONFile(t, dir, "traffic_ops.cfg", handler.OpsConfig{Username: "user", Password: "pass", Url: m.tos.URL, CdnName: testCDN, HttpListener: m.addr})
+	m.tmConfigFile = writeJSONFile(t, dir, "traffic_monitor.cfg", map[string]string{
+		"log_location_error":   config.LogLocationNull,
+		"log_location_warning": config.LogLocationNull,
+		"log_location_info":    config.LogLocationNull,
+		"log_location_debug":   config.LogLocationNull,
+		"log_location_event":   config.LogLocationNull,
+	})
+
+	m.cfg = config.DefaultConfig
+	m.cfg.CacheHealthPollingInterval = fake.PollIntervalMS * time.Millisecond
+	m.cfg.CacheStatPollingInterval = fake.PollIntervalMS * time.Millisecond
+	m.cfg.MonitorConfigPollingInterval = fake.PollIntervalMS * time.Millisecond
+	m.cfg.PeerPollingInterval = fake.PollIntervalMS * time.Millisecond
+	m.cfg.StaticFileDir = "../static"
+	m.cfg.ShutdownDrain = time.Second
+	m.staticAppData = config.StaticAppData{StartTime: time.Now(), Name: "traffic_monitor", Hostname: testMonitorHostname, UserAgent: "traffic_monitor/test"}
+	return m
+}
+
+// update calls the given func to mutate the fixture, and serves the result, which the monitor sees on its next Traffic Ops poll.
+func (m *testMonitor) update(t *testing.T, f func(fixture *fake.Fixture)) {
+	f(&m.fixture)
+	if err := m.fixture.Serve(m.tos); err != nil {
+		t.Fatalf("serving fixture: %v", err)
+	}
+}
+
+func (m testMonitor) Close() {
+	m.tos.Close()
+	m.cacheA.Close()
+	m.cacheB.Close()
+	os.RemoveAll(m.dir)
+}
+
+// TestStartWithSession tests the compatibility entry point, which runs until the process receives SIGTERM.
+func TestStartWithSession(t *testing.T) {
+	m := newTestMonitor(t)
+	defer m.Close()
+	m.cfg.ShutdownDrain = 0
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		if err := StartWithSession(m.opsConfigFile, m.cfg, m.staticAppData, m.tmConfigFile, towrap.NewTrafficOpsSessionThreadsafe(nil)); err != nil {
+			t.Errorf("StartWithSession expected nil error, actual %v", err)
+		}
+	}()
+
+	waitForCRStates(t, m.addr, "both caches available", func(crStates peer.Crstates) bool {
+		return isAvailable(enum.CacheName(m.cacheA.Name), true)(crStates) && isAvailable(enum.CacheName(m.cacheB.Name), true)(crStates)
+	})
+
+	// the monitor is serving, so StartWithSession has already subscribed to SIGTERM, and the signal won't kill the test.
+	if err := unix.Kill(os.Getpid(), unix.SIGTERM); err != nil {
+		t.Fatalf("sending SIGTERM: %v", err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(testTimeout):
+		t.Fatalf("timed out waiting for StartWithSession to return after SIGTERM")
+	}
+}
+
+func TestStartWithContext(t *testing.T) {
+	m := newTestMonitor(t)
+	defer m.Close()
+	cacheA, cacheB, addr := m.cacheA, m.cacheB, m.addr
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		if err := StartWithContext(ctx, m.opsConfigFile, m.cfg, m.staticAppData, m.tmConfigFile, towrap.NewTrafficOpsSessionThreadsafe(nil)); err != nil {
+			t.Errorf("StartWithContext expected nil error, actual %v", err)
+		}
+	}()
+
+	nameA := enum.CacheName(cacheA.Name)
+	nameB := enum.CacheName(cacheB.Name)
+
+	waitForCRStates(t, addr, "both caches available", func(crStates peer.Crstates) bool {
+		return isAvailable(nameA, true)(crStates) && isAvailable(nameB, true)(crStates)
+	})
+
+	cacheA.SetNotAvailable(true)
+	waitForCRStates(t, addr, "notAvailable cache unavailable", isAvailable(nameA, false))
+
+	cacheA.SetNotAvailable(false)
+	waitForCRStates(t, addr, "recovered cache available", isAvailable(nameA, true))
+
+	cacheB.SetStatusCode(http.StatusInternalServerError)
+	waitForCRStates(t, addr, "erroring cache unavailable", isAvailable(nameB, false))
+	cacheB.SetStatusCode(http.StatusOK)
+	waitForCRStates(t, addr, "recovered erroring cache available", isAvailable(nameB, true))
+
+	m.update(t, func(f *fake.Fixture) { f.RemoveCache(cacheB.Name) })
+	waitForCRStates(t, addr, "removed cache gone", func(crStates peer.Crstates) bool {
+		_, ok := crStates.Caches[nameB]
+		return !ok && isAvailable(nameA, true)(crStates)
+	})
+
+	m.update(t, func(f *fake.Fixture) { f.SetCacheStatus(cacheA.Name, enum.CacheStatusAdminDown) })
+	waitForCRStates(t, addr, "ADMIN_DOWN cache unavailable", isAvailable(nameA, false))
+
+	// After the context is done, polling stops, but the last known states are served until the drain period ends.
+	cancel()
+	time.Sleep(2 * fake.PollIntervalMS * time.Millisecond)
+	m.update(t, func(f *fake.Fixture) { f.SetCacheStatus(cacheA.Name, enum.CacheStatusReported) })
+	time.Sleep(3 * fake.PollIntervalMS * time.Millisecond)
+	if crStates, err := getCRStates(addr); err != nil {
+		t.Errorf("getting CRStates while draining expected nil error, actual %v", err)
+	} else if !isAvailable(nameA, false)(crStates) {
+		t.Errorf("CRStates while draining expected last known state unavailable, actual %+v", crStates.Caches[nameA])
+	}
+
+	select {
+	case <-stopped:
+	case <-time.After(testTimeout):
+		t.Fatalf("timed out waiting for StartWithContext to return after the context was done")
+	}
+	if _, err := getCRStates(addr); err == nil {
+		t.Errorf("getting CRStates after shutdown expected error, actual nil")
+	}
+}
@@ -20,7 +20,9 @@ package manager
  */
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"os"
 	"strings"
 	"time"
@@ -103,13 +105,14 @@ func getIntervals(monitorConfig to.TrafficMonitorConfigMap, cfg config.Config, l
 	return intervals, nil
 }
 
-// StartMonitorConfigManager runs the monitor config manager goroutine, and returns the threadsafe data which it sets.
+// StartMonitorConfigManager runs the monitor config manager goroutine, and returns the threadsafe data which it sets. The peers ONLINE in Traffic Ops are sent to peerURLSubscriber, by their base URLs. The manager stops when the given context is done.
 func StartMonitorConfigManager(
+	ctx context.Context,
 	monitorConfigPollChan <-chan poller.MonitorCfg,
 	localStates peer.CRStatesThreadsafe,
-	peerStates peer.CRStatesPeersThreadsafe,
 	statURLSubscriber chan<- poller.HttpPollerConfig,
 	healthURLSubscriber chan<- poller.HttpPollerConfig,
+	healthURLv6Subscriber chan<- poller.HttpPollerConfig,
 	peerURLSubscriber chan<- poller.HttpPollerConfig,
 	toIntervalSubscriber chan<- time.Duration,
 	cachesChangeSubscriber chan<- struct{},
@@ -119,12 +122,13 @@ func StartMonitorConfigManager(
 	toData todata.TODataThreadsafe,
 ) threadsafe.TrafficMonitorConfigMap {
 	monitorConfig := threadsafe.NewTrafficMonitorConfigMap()
-	go monitorConfigListen(monitorConfig,
+	go monitorConfigListen(ctx,
+		monitorConfig,
 		monitorConfigPollChan,
 		localStates,
-		peerStates,
 		statURLSubscriber,
 		healthURLSubscriber,
+		healthURLv6Subscriber,
 		peerURLSubscriber,
 		toIntervalSubscriber,
 		cachesChangeSubscriber,
@@ -166,18 +170,51 @@ func trafficOpsTOPollIntervalToDuration(t int) time.Duration {
 	return time.Duration(t) * time.Millisecond
 }
 
+// healthURL returns the health polling URL for the given Traffic Ops URL template, with the given host, which may be an IPv4 address or a bracketed IPv6 address.
+func healthURL(urlTemplate string, host string, interfaceName string) string {
+	r := strings.NewReplacer(
+		"${hostname}", host,
+		"${interface_name}", interfaceName,
+		"application=plugin.remap", "application=system",
+		"application=", "application=system",
+	)
+	return r.Replace(urlTemplate)
+}
+
+// pollInterfaceName returns the interface name to request from the given server's astats. If the server's profile has a list of interfaces, they are requested as a comma-delimited list, whose bandwidth astats will return for each interface.
+func pollInterfaceName(srv to.TrafficServer, profile to.TMProfile) string {
+	if len(profile.Parameters.Interfaces) == 0 {
+		return srv.InterfaceName
+	}
+	return strings.Join(profile.Parameters.Interfaces, ",")
+}
+
+// ip6Host takes an IPv6 address from Traffic Ops, which may include a prefix length, e.g. `2001:db8::2/64`, and returns the address bracketed for use as a URL host, e.g. `[2001:db8::2]`.
+func ip6Host(ip6 string) (string, error) {
+	addr := ip6
+	if i := strings.Index(addr, "/"); i != -1 {
+		addr = addr[:i]
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil || ip.To4() != nil {
+		return "", fmt.Errorf("'%v' is not an IPv6 address", ip6)
+	}
+	return "[" + ip.String() + "]", nil
+}
+
 // PollIntervalRatio is the ratio of the configuration interval to poll. The configured intervals are 'target' times, so we actually poll at some small fraction less, in attempt to make the actual poll marginally less than the target.
 const PollIntervalRatio = float64(0.97) // TODO make config?
 
 // TODO timing, and determine if the case, or its internal `for`, should be put in a goroutine
 // TODO determine if subscribers take action on change, and change to mutexed objects if not.
 func monitorConfigListen(
+	ctx context.Context,
 	monitorConfigTS threadsafe.TrafficMonitorConfigMap,
 	monitorConfigPollChan <-chan poller.MonitorCfg,
 	localStates peer.CRStatesThreadsafe,
-	peerStates peer.CRStatesPeersThreadsafe,
 	statURLSubscriber chan<- poller.HttpPollerConfig,
 	healthURLSubscriber chan<- poller.HttpPollerConfig,
+	healthURLv6Subscriber chan<- poller.HttpPollerConfig,
 	peerURLSubscriber chan<- poller.HttpPollerConfig,
 	toIntervalSubscriber chan<- time.Duration,
 	cachesChangeSubscriber chan<- struct{},
@@ -187,7 +224,12 @@ func monitorConfigListen(
 	toData todata.TODataThreadsafe,
 ) {
 	defer func() {
-		if err := recover(); err != nil {
+		err := recover()
+		if err == nil && ctx.Err() != nil {
+			log.Infof("MonitorConfigManager: stopped\n")
+			return
+		}
+		if err != nil {
 			log.Errorf("MonitorConfigManager panic: %v\n", err)
 		} else {
 			log.Errorf("MonitorConfigManager failed without panic\n")
@@ -197,13 +239,20 @@ func monitorConfigListen(
 
 	logMissingIntervalParams := true
 
-	for pollerMonitorCfg := range monitorConfigPollChan {
+	for {
+		var pollerMonitorCfg poller.MonitorCfg
+		select {
+		case <-ctx.Done():
+			return
+		case pollerMonitorCfg = <-monitorConfigPollChan:
+		}
 		monitorConfig := pollerMonitorCfg.Cfg
 		cdn := pollerMonitorCfg.CDN
 		monitorConfigTS.Set(monitorConfig)
 		toData.Update(toSession, cdn)
 
 		healthURLs := map[string]poller.PollConfig{}
+		healthURLsV6 := map[string]poller.PollConfig{}
 		statURLs := map[string]poller.PollConfig{}
 		peerURLs := map[string]poller.PollConfig{}
 		caches := map[string]string{}
@@ -222,7 +271,7 @@ func monitorConfigListen(
 
 			srvStatus := enum.CacheStatusFromString(srv.Status)
 			if srvStatus == enum.CacheStatusOnline {
-				localStates.AddCache(cacheName, peer.IsAvailable{IsAvailable: true})
+				localStates.AddCache(cacheName, peer.NewIsAvailable(true, srv.IP6 != ""))
 				continue
 			}
 			if srvStatus == enum.CacheStatusOffline {
@@ -230,30 +279,31 @@ func monitorConfigListen(
 			}
 			// seed states with available = false until our polling cycle picks up a result
 			if _, exists := localStates.GetCache(cacheName); !exists {
-				localStates.AddCache(cacheName, peer.IsAvailable{IsAvailable: false})
+				localStates.AddCache(cacheName, peer.NewIsAvailable(false, false))
 			}
 
-			url := monitorConfig.Profile[srv.Profile].Parameters.HealthPollingURL
-			if url == "" {
+			urlTemplate := monitorConfig.Profile[srv.Profile].Parameters.HealthPollingURL
+			if urlTemplate == "" {
 				log.Errorf("monitor config server %v profile %v has no polling URL; can't poll", srv.HostName, srv.Profile)
 				continue
 			}
-			r := strings.NewReplacer(
-				"${hostname}", srv.IP,
-				"${interface_name}", srv.InterfaceName,
-				"application=plugin.remap", "application=system",
-				"application=", "application=system",
-			)
-			url = r.Replace(url)
+			interfaceName := pollInterfaceName(srv, monitorConfig.Profile[srv.Profile])
+			url := healthURL(urlTemplate, srv.IP, interfaceName)
 
 			connTimeout := trafficOpsHealthConnectionTimeoutToDuration(monitorConfig.Profile[srv.Profile].Parameters.HealthConnectionTimeout)
 			healthURLs[srv.HostName] = poller.PollConfig{URL: url, Host: srv.FQDN, Timeout: connTimeout}
-			r = strings.NewReplacer("application=system", "application=")
+			if srv.IP6 != "" {
+				if ip6, err := ip6Host(srv.IP6); err != nil {
+					log.Errorf("monitor config server %v has invalid IPv6 address, not polling over IPv6: %v", srv.HostName, err)
+				} else {
+					healthURLsV6[srv.HostName] = poller.PollConfig{URL: healthURL(urlTemplate, ip6, interfaceName), Host: srv.FQDN, Timeout: connTimeout}
+				}
+			}
+			r := strings.NewReplacer("application=system", "application=")
 			statURL := r.Replace(url)
 			statURLs[srv.HostName] = poller.PollConfig{URL: statURL, Host: srv.FQDN, Timeout: connTimeout}
 		}
 
-		peerSet := map[enum.TrafficMonitorName]struct{}{}
 		for _, srv := range monitorConfig.TrafficMonitor {
 			if srv.HostName == staticAppData.Hostname {
 				continue
@@ -262,17 +312,15 @@ func monitorConfigListen(
 				continue
 			}
 			// TODO: the URL should be config driven. -jse
-			url := fmt.Sprintf("http://%s:%d/publish/CrStates?raw", srv.IP, srv.Port)
+			url := fmt.Sprintf("http://%s:%d", srv.IP, srv.Port)
 			peerURLs[srv.HostName] = poller.PollConfig{URL: url, Host: srv.FQDN} // TODO determine timeout.
-			peerSet[enum.TrafficMonitorName(srv.HostName)] = struct{}{}
 		}
 
 		statURLSubscriber <- poller.HttpPollerConfig{Urls: statURLs, Interval: intervals.Stat}
 		healthURLSubscriber <- poller.HttpPollerConfig{Urls: healthURLs, Interval: intervals.Health}
+		healthURLv6Subscriber <- poller.HttpPollerConfig{Urls: healthURLsV6, Interval: intervals.Health}
 		peerURLSubscriber <- poller.HttpPollerConfig{Urls: peerURLs, Interval: intervals.Peer}
 		toIntervalSubscriber <- intervals.TO
-		peerStates.SetTimeout((intervals.Peer + cfg.HTTPTimeout) * 2)
-		peerStates.SetPeers(peerSet)
 
 		for cacheName := range localStates.GetCaches() {
 			if _, exists := monitorConfig.TrafficServer[string(cacheName)]; !exists {
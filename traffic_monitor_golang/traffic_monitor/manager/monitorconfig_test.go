@@ -0,0 +1,59 @@
+package manager
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"testing"
+
+	to "github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+)
+
+func TestIP6Host(t *testing.T) {
+	valid := map[string]string{
+		"2001:db8::2":              "[2001:db8::2]",
+		"2001:db8::2/64":           "[2001:db8::2]",
+		"2001:0db8:0:0:0:0:0:2/64": "[2001:db8::2]",
+	}
+	for ip6, expected := range valid {
+		if actual, err := ip6Host(ip6); err != nil {
+			t.Errorf("ip6Host(%q) expected %q, actual error %v", ip6, expected, err)
+		} else if actual != expected {
+			t.Errorf("ip6Host(%q) expected %q, actual %q", ip6, expected, actual)
+		}
+	}
+
+	invalid := []string{"", "192.0.2.1", "192.0.2.1/24", "::ffff:192.0.2.1", "edge1.example.net"}
+	for _, ip6 := range invalid {
+		if actual, err := ip6Host(ip6); err == nil {
+			t.Errorf("ip6Host(%q) expected error, actual %q", ip6, actual)
+		}
+	}
+}
+
+func TestPollInterfaceName(t *testing.T) {
+	srv := to.TrafficServer{HostName: "edge1", InterfaceName: "bond0"}
+	if actual := pollInterfaceName(srv, to.TMProfile{}); actual != "bond0" {
+		t.Errorf("pollInterfaceName without health.polling.interfaces expected %q, actual %q", "bond0", actual)
+	}
+	profile := to.TMProfile{Parameters: to.TMParameters{Interfaces: []string{"eth0", "eth1"}}}
+	if actual := pollInterfaceName(srv, profile); actual != "eth0,eth1" {
+		t.Errorf("pollInterfaceName with health.polling.interfaces expected %q, actual %q", "eth0,eth1", actual)
+	}
+}
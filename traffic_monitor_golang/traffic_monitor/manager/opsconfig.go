@@ -37,10 +37,9 @@ import (
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/threadsafe"
 	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
 	towrap "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopswrapper"
-	to "github.com/apache/incubator-trafficcontrol/traffic_ops/client"
 )
 
-// StartOpsConfigManager starts the ops config manager goroutine, returning the (threadsafe) variables which it sets.
+// StartOpsConfigManager starts the ops config manager goroutine, returning the (threadsafe) variables which it sets, and the HTTP server, which the caller must Shutdown to stop serving.
 // Note the OpsConfigManager is in charge of the httpServer, because ops config changes trigger server changes. If other things needed to trigger server restarts, the server could be put in its own goroutine with signal channels
 func StartOpsConfigManager(
 	opsConfigFile string,
@@ -50,6 +49,7 @@ func StartOpsConfigManager(
 	toChangeSubscribers []chan<- towrap.ITrafficOpsSession,
 	localStates peer.CRStatesThreadsafe,
 	peerStates peer.CRStatesPeersThreadsafe,
+	peerMembership peer.MembershipThreadsafe,
 	combinedStates peer.CRStatesThreadsafe,
 	statInfoHistory threadsafe.ResultInfoHistory,
 	statResultHistory threadsafe.ResultStatHistory,
@@ -68,15 +68,18 @@ func StartOpsConfigManager(
 	unpolledCaches threadsafe.UnpolledCaches,
 	monitorConfig threadsafe.TrafficMonitorConfigMap,
 	cfg config.Config,
-) (threadsafe.OpsConfig, error) {
+) (threadsafe.OpsConfig, *srvhttp.Server, error) {
 
 	handleErr := func(err error) {
 		errorCount.Inc()
 		log.Errorf("OpsConfigManager: %v\n", err)
 	}
 
-	httpServer := srvhttp.Server{}
 	opsConfig := threadsafe.NewOpsConfig()
+	httpServer, err := newHTTPServer(cfg)
+	if err != nil {
+		return opsConfig, nil, fmt.Errorf("creating HTTP server: %v", err)
+	}
 
 	// TODO remove change subscribers, give Threadsafes directly to the things that need them. If they only set vars, and don't actually do work on change.
 	onChange := func(bytes []byte, err error) {
@@ -122,6 +125,9 @@ func StartOpsConfigManager(
 			lastStats,
 			unpolledCaches,
 			monitorConfig,
+			cfg.CacheGroupMinAvailable,
+			peerMembership,
+			cfg.PeerGossip,
 		)
 		err = httpServer.Run(endpoints, listenAddress, cfg.ServeReadTimeout, cfg.ServeWriteTimeout, cfg.StaticFileDir)
 		if err != nil {
@@ -129,18 +135,14 @@ func StartOpsConfigManager(
 			return
 		}
 
-		// TODO config? parameter?
-		useCache := false
 		trafficOpsRequestTimeout := time.Second * time.Duration(10)
 
-		realToSession, err := to.LoginWithAgent(newOpsConfig.Url, newOpsConfig.Username, newOpsConfig.Password, newOpsConfig.Insecure, staticAppData.UserAgent, useCache, trafficOpsRequestTimeout)
-		if err != nil {
+		if err := toSession.Login(newOpsConfig.Url, newOpsConfig.Username, newOpsConfig.Password, newOpsConfig.Insecure, staticAppData.UserAgent, cfg.TrafficOpsCache, trafficOpsRequestTimeout); err != nil {
 			handleErr(fmt.Errorf("MonitorConfigPoller: error instantiating Session with traffic_ops: %s\n", err))
 			return
 		}
-		toSession.Set(realToSession)
 
-		if cdn, err := getMonitorCDN(realToSession, staticAppData.Hostname); err != nil {
+		if cdn, err := getMonitorCDN(toSession, staticAppData.Hostname); err != nil {
 			handleErr(fmt.Errorf("getting CDN name from Traffic Ops, using config CDN '%s': %s\n", newOpsConfig.CdnName, err))
 		} else {
 			if newOpsConfig.CdnName != "" && newOpsConfig.CdnName != cdn {
@@ -166,18 +168,63 @@ func StartOpsConfigManager(
 
 	bytes, err := ioutil.ReadFile(opsConfigFile)
 	if err != nil {
-		return opsConfig, err
+		return opsConfig, nil, err
 	}
 	onChange(bytes, err)
 
 	startSignalFileReloader(opsConfigFile, unix.SIGHUP, onChange)
 
-	return opsConfig, nil
+	return opsConfig, httpServer, nil
+}
+
+// newHTTPServer returns an HTTP server with the authentication, rate limiting, and access log in the given config. Returns an error if a configured scope is invalid.
+func newHTTPServer(cfg config.Config) (*srvhttp.Server, error) {
+	auth := srvhttp.Auth{
+		Tokens:      map[string]srvhttp.Scope{},
+		Users:       map[string]srvhttp.User{},
+		AdminPaths:  map[string]struct{}{},
+		PublicPaths: map[string]struct{}{},
+		PeerPaths:   map[string]struct{}{},
+		PeerSecret:  cfg.PeerGossipSecret,
+	}
+	for token, scopeStr := range cfg.APITokens {
+		scope := srvhttp.ScopeFromString(scopeStr)
+		if scope == srvhttp.ScopeInvalid {
+			return nil, fmt.Errorf("API token has invalid scope '%v'", scopeStr)
+		}
+		auth.Tokens[token] = scope
+	}
+	for name, user := range cfg.APIUsers {
+		scope := srvhttp.ScopeFromString(user.Scope)
+		if scope == srvhttp.ScopeInvalid {
+			return nil, fmt.Errorf("API user '%v' has invalid scope '%v'", name, user.Scope)
+		}
+		auth.Users[name] = srvhttp.User{Password: user.Password, Scope: scope}
+	}
+	for _, path := range datareq.AdminPaths {
+		auth.AdminPaths[path] = struct{}{}
+	}
+	for _, path := range datareq.PublicPaths {
+		auth.PublicPaths[path] = struct{}{}
+	}
+	for _, path := range datareq.PeerPaths {
+		auth.PeerPaths[path] = struct{}{}
+	}
+
+	accessLog, err := config.GetAccessLogWriter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &srvhttp.Server{
+		Auth:      auth,
+		RateLimit: srvhttp.RateLimit{RequestsPerSecond: cfg.APIRateLimitPerSecond, Burst: cfg.APIRateLimitBurst},
+		AccessLog: accessLog,
+	}, nil
 }
 
 // getMonitorCDN returns the CDN of a given Traffic Monitor.
 // TODO change to get by name, when Traffic Ops supports querying a single server.
-func getMonitorCDN(toc *to.Session, monitorHostname string) (string, error) {
+func getMonitorCDN(toc towrap.ITrafficOpsSession, monitorHostname string) (string, error) {
 	servers, err := toc.Servers()
 	if err != nil {
 		return "", fmt.Errorf("getting monitor %s CDN: %v", monitorHostname, err)
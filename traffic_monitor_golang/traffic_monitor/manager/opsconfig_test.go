@@ -0,0 +1,79 @@
+package manager
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/log"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/config"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/datareq"
+)
+
+func TestNewHTTPServerPeerAnnounce(t *testing.T) {
+	discard := log.NopCloser(ioutil.Discard)
+	log.Init(discard, discard, discard, discard, discard)
+
+	cfg := config.DefaultConfig
+	cfg.PeerGossip = true
+	cfg.PeerAdvertiseURL = "http://tm-self.example.net"
+	cfg.PeerGossipSecret = "peer-secret"
+	server, err := newHTTPServer(cfg)
+	if err != nil {
+		t.Fatalf("newHTTPServer expected nil error, actual %v", err)
+	}
+	defer server.Shutdown(context.Background())
+
+	addr := freeAddress(t)
+	endpoints := map[string]http.HandlerFunc{
+		datareq.PeerAnnouncePath: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) },
+	}
+	if err := server.Run(endpoints, addr, testTimeout, testTimeout, "../static"); err != nil {
+		t.Fatalf("Run expected nil error, actual %v", err)
+	}
+
+	announce := func(secret string) int {
+		req, err := http.NewRequest(http.MethodPost, "http://"+addr+datareq.PeerAnnouncePath, nil)
+		if err != nil {
+			t.Fatalf("creating request: %v", err)
+		}
+		if secret != "" {
+			req.Header.Set("Authorization", "Bearer "+secret)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("posting announcement: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if code := announce(""); code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated announce expected %v, actual %v", http.StatusUnauthorized, code)
+	}
+	if code := announce("wrong-secret"); code != http.StatusUnauthorized {
+		t.Errorf("announce with the wrong secret expected %v, actual %v", http.StatusUnauthorized, code)
+	}
+	if code := announce(cfg.PeerGossipSecret); code != http.StatusNoContent {
+		t.Errorf("announce with the gossip secret expected %v, actual %v", http.StatusNoContent, code)
+	}
+}
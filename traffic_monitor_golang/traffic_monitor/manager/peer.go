@@ -20,22 +20,51 @@ package manager
  */
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/log"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/poller"
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/util"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/config"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/datareq"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/health"
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
 )
 
-// StartPeerManager listens for peer results, and when it gets one, it adds it to the peerStates list, and optimistically combines the good results into combinedStates
+// PeerCrStatesPath is the path polled on each peer's base URL.
+const PeerCrStatesPath = "/publish/CrStates?raw"
+
+// StartPeerManager listens for peer results, and when it gets one, it adds it to the peerStates list, marks the peer seen in the membership if it's available, and optimistically combines the good results into combinedStates. It stops when the given context is done.
 func StartPeerManager(
+	ctx context.Context,
 	peerChan <-chan peer.Result,
 	peerStates peer.CRStatesPeersThreadsafe,
+	membership peer.MembershipThreadsafe,
 	events health.ThreadsafeEvents,
 	combineState func(),
 ) {
 	go func() {
-		for peerResult := range peerChan {
+		for {
+			var peerResult peer.Result
+			select {
+			case <-ctx.Done():
+				return
+			case peerResult = <-peerChan:
+			}
 			comparePeerState(events, peerResult, peerStates)
 			peerStates.Set(peerResult)
+			if peerResult.Available {
+				membership.Seen(peerResult.ID, peerResult.Time)
+			}
 			combineState()
 			peerResult.PollFinished <- peerResult.PollID
 		}
@@ -55,3 +84,125 @@ func comparePeerState(events health.ThreadsafeEvents, result peer.Result, peerSt
 		events.Add(health.Event{Time: health.Time(result.Time), Description: description, Name: result.ID.String(), Hostname: result.ID.String(), Type: "Peer", Available: result.Available})
 	}
 }
+
+// StartPeerMembershipManager starts the goroutine which maintains the set of peers to poll, from the static peers in the config, the peers ONLINE in Traffic Ops received on toPeerChan, and if gossip is enabled, peers announcing themselves. Whenever the peer set changes, it's sent to peerURLSubscriber, and set in peerStates.
+// If gossip is enabled, every peer poll interval this monitor announces itself and the peers it's recently seen to every peer, and peers which haven't been announced within the gossip expiry are removed. It stops when the given context is done.
+func StartPeerMembershipManager(
+	ctx context.Context,
+	toPeerChan <-chan poller.HttpPollerConfig,
+	membership peer.MembershipThreadsafe,
+	peerStates peer.CRStatesPeersThreadsafe,
+	peerURLSubscriber chan<- poller.HttpPollerConfig,
+	cfg config.Config,
+	staticAppData config.StaticAppData,
+	client *http.Client,
+) {
+	staticPeers := map[enum.TrafficMonitorName]peer.Address{}
+	for _, p := range cfg.StaticPeers {
+		staticPeers[enum.TrafficMonitorName(p.Name)] = peer.Address{URL: strings.TrimSuffix(p.URL, "/")}
+	}
+	membership.Set(peer.SourceStatic, staticPeers)
+
+	go func() {
+		interval := cfg.PeerPollingInterval
+		tick := time.NewTicker(interval)
+		defer func() { tick.Stop() }()
+
+		// setPeers sends the current peer set to the poller. Returns false if the context is done.
+		setPeers := func() bool {
+			select {
+			case <-membership.Changed(): // the set about to be sent includes any pending change
+			default:
+			}
+			urls := map[string]poller.PollConfig{}
+			names := map[enum.TrafficMonitorName]struct{}{}
+			for name, m := range membership.Get() {
+				urls[string(name)] = poller.PollConfig{URL: m.URL + PeerCrStatesPath, Host: m.Host}
+				names[name] = struct{}{}
+			}
+			peerStates.SetTimeout((interval + cfg.HTTPTimeout) * 2)
+			peerStates.SetPeers(names)
+			select {
+			case peerURLSubscriber <- poller.HttpPollerConfig{Urls: urls, Interval: interval}:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		if !setPeers() {
+			return
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case toPeers := <-toPeerChan:
+				addrs := map[enum.TrafficMonitorName]peer.Address{}
+				for name, pollCfg := range toPeers.Urls {
+					addrs[enum.TrafficMonitorName(name)] = peer.Address{URL: pollCfg.URL, Host: pollCfg.Host}
+				}
+				membership.Set(peer.SourceTrafficOps, addrs)
+				if toPeers.Interval > 0 && toPeers.Interval != interval {
+					interval = toPeers.Interval
+					tick.Stop()
+					tick = time.NewTicker(interval)
+				}
+				if !setPeers() {
+					return
+				}
+			case <-membership.Changed():
+				if !setPeers() {
+					return
+				}
+			case now := <-tick.C:
+				if !cfg.PeerGossip {
+					continue
+				}
+				membership.Expire(now, cfg.PeerGossipExpiry)
+				announcePeers(ctx, client, membership, cfg, staticAppData, now)
+			}
+		}
+	}()
+}
+
+// announcePeers sends this monitor's announcement to every peer, concurrently. Failures are logged, but otherwise ignored; a peer which can't be reached will learn of this monitor from the next announcement, or from another peer.
+func announcePeers(ctx context.Context, client *http.Client, membership peer.MembershipThreadsafe, cfg config.Config, staticAppData config.StaticAppData, now time.Time) {
+	body, err := json.Marshal(membership.Announcement(cfg.PeerAdvertiseURL, now, cfg.PeerGossipExpiry))
+	if err != nil {
+		log.Errorf("peer gossip: marshalling announcement: %v\n", err)
+		return
+	}
+	for name, m := range membership.Get() {
+		go func(name enum.TrafficMonitorName, m peer.Member) {
+			if err := announcePeer(ctx, client, m, body, cfg.PeerGossipSecret, staticAppData.UserAgent); err != nil {
+				log.Warnf("peer gossip: announcing to %v: %v\n", name, err)
+			}
+		}(name, m)
+	}
+}
+
+// announcePeer posts the given announcement body to the given peer, authenticated with the given gossip secret.
+func announcePeer(ctx context.Context, client *http.Client, m peer.Member, body []byte, secret string, userAgent string) error {
+	req, err := http.NewRequest(http.MethodPost, m.URL+datareq.PeerAnnouncePath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	if m.Host != "" {
+		req.Host = m.Host
+	}
+	req.Header.Set("Content-Type", datareq.ContentTypeJSON)
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Authorization", "Bearer "+secret)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%v returned %v", m.URL, resp.Status)
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ package manager
  */
 
 import (
+	"context"
 	"time"
 
 	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/log"
@@ -56,8 +57,10 @@ func getNewCaches(localStates peer.CRStatesThreadsafe, monitorConfigTS threadsaf
 
 // StartStatHistoryManager fetches the full statistics data from ATS Astats. This includes everything needed for all calculations, such as Delivery Services. This is expensive, though, and may be hard on ATS, so it should poll less often.
 // For a fast 'is it alive' poll, use the Health Result Manager poll.
+// The manager stops when the given context is done.
 // Returns the stat history, the duration between the stat poll for each cache, the last Kbps data, the calculated Delivery Service stats, and the unpolled caches list.
 func StartStatHistoryManager(
+	ctx context.Context,
 	cacheStatChan <-chan cache.Result,
 	localStates peer.CRStatesThreadsafe,
 	combinedStates peer.CRStatesThreadsafe,
@@ -85,17 +88,29 @@ func StartStatHistoryManager(
 	overrideMap := map[enum.CacheName]bool{}
 
 	process := func(results []cache.Result) {
-		processStatResults(results, statInfoHistory, statResultHistory, statMaxKbpses, combinedStates, lastStats, toData.Get(), errorCount, dsStats, lastStatEndTimes, lastStatDurations, unpolledCaches, monitorConfig.Get(), precomputedData, lastResults, localStates, events, localCacheStatus, overrideMap, combineState)
+		processStatResults(results, statInfoHistory, statResultHistory, statMaxKbpses, combinedStates, lastStats, toData.Get(), errorCount, dsStats, lastStatEndTimes, lastStatDurations, unpolledCaches, monitorConfig.Get(), precomputedData, lastResults, localStates, events, localCacheStatus, overrideMap, combineState, cfg.CacheGroupMinAvailable)
 	}
 
 	go func() {
 		var ticker *time.Ticker
-		<-cachesChanged // wait for the signal that localStates have been set
+		select {
+		case <-ctx.Done():
+			return
+		case <-cachesChanged: // wait for the signal that localStates have been set
+		}
 		unpolledCaches.SetNewCaches(getNewCaches(localStates, monitorConfig))
 
 		for {
 			var results []cache.Result
-			results = append(results, <-cacheStatChan)
+			select {
+			case <-ctx.Done():
+				if ticker != nil {
+					ticker.Stop()
+				}
+				return
+			case r := <-cacheStatChan:
+				results = append(results, r)
+			}
 			if ticker != nil {
 				ticker.Stop()
 			}
@@ -146,6 +161,7 @@ func processStatResults(
 	localCacheStatusThreadsafe threadsafe.CacheAvailableStatus,
 	overrideMap map[enum.CacheName]bool,
 	combineState func(),
+	cacheGroupMinAvailable float64,
 ) {
 	if len(results) == 0 {
 		return
@@ -207,7 +223,7 @@ func processStatResults(
 		lastStats.Set(newLastStats)
 	}
 
-	health.CalcAvailability(results, "stat", statResultHistory, mc, toData, localCacheStatusThreadsafe, localStates, events)
+	health.CalcAvailability(results, "stat", statResultHistory, mc, toData, localCacheStatusThreadsafe, localStates, events, cacheGroupMinAvailable)
 	combineState()
 
 	endTime := time.Now()
@@ -20,6 +20,7 @@ package manager
  */
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -32,8 +33,8 @@ import (
 	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
 )
 
-// StartStateCombiner starts the State Combiner goroutine, and returns the threadsafe CombinedStates, and a func to signal to combine states.
-func StartStateCombiner(events health.ThreadsafeEvents, peerStates peer.CRStatesPeersThreadsafe, localStates peer.CRStatesThreadsafe, toData todata.TODataThreadsafe) (peer.CRStatesThreadsafe, func()) {
+// StartStateCombiner starts the State Combiner goroutine, and returns the threadsafe CombinedStates, and a func to signal to combine states. The combiner stops when the given context is done.
+func StartStateCombiner(ctx context.Context, events health.ThreadsafeEvents, peerStates peer.CRStatesPeersThreadsafe, localStates peer.CRStatesThreadsafe, toData todata.TODataThreadsafe) (peer.CRStatesThreadsafe, func()) {
 	combinedStates := peer.NewCRStatesThreadsafe()
 
 	// the chan buffer just reduces the number of goroutines on our infinite buffer hack in combineState(), no real writer will block, since combineState() writes in a goroutine.
@@ -56,7 +57,12 @@ func StartStateCombiner(events health.ThreadsafeEvents, peerStates peer.CRStates
 
 	go func() {
 		overrideMap := map[enum.CacheName]bool{}
-		for range combineStateChan {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-combineStateChan:
+			}
 			drain(combineStateChan)
 			combineCrStates(events, true, peerStates, localStates.Get(), combinedStates, overrideMap, toData.Get())
 		}
@@ -114,7 +120,17 @@ func combineCacheState(cacheName enum.CacheName, localCacheState peer.IsAvailabl
 		events.Add(health.Event{Time: health.Time(time.Now()), Description: fmt.Sprintf("Health protocol override condition %s", overrideCondition), Name: cacheName.String(), Hostname: cacheName.String(), Type: toData.ServerTypes[cacheName].String(), Available: available})
 	}
 
-	combinedStates.AddCache(cacheName, peer.IsAvailable{IsAvailable: available})
+	availableIPv6 := localCacheState.Ipv6Available
+	if !availableIPv6 && peerOptimistic {
+		for peer, peerCrStates := range peerStates.GetCrstates() {
+			if peerStates.GetPeerAvailability(peer) && peerCrStates.Caches[cacheName].Ipv6Available {
+				availableIPv6 = true
+				break
+			}
+		}
+	}
+
+	combinedStates.AddCache(cacheName, peer.NewIsAvailable(available, available && availableIPv6))
 }
 
 func combineDSState(
@@ -0,0 +1,72 @@
+package manager
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/log"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/health"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	todata "github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/trafficopsdata"
+)
+
+func TestCombineCacheStateIPv6(t *testing.T) {
+	discard := log.NopCloser(ioutil.Discard)
+	log.Init(discard, discard, discard, discard, discard)
+
+	peerStates := peer.NewCRStatesPeersThreadsafe()
+	peerCRStates := peer.NewCrstates()
+	peerCRStates.Caches["edge1"] = peer.NewIsAvailable(true, true)
+	peerStates.Set(peer.Result{ID: "tm-peer", Available: true, PeerStates: peerCRStates, Time: time.Now()})
+	peerStates.SetPeers(map[enum.TrafficMonitorName]struct{}{"tm-peer": struct{}{}})
+
+	type testCase struct {
+		local          peer.IsAvailable
+		peerOptimistic bool
+		expected       peer.IsAvailable
+	}
+	testCases := []testCase{
+		{local: peer.NewIsAvailable(true, true), peerOptimistic: false, expected: peer.NewIsAvailable(true, true)},
+		{local: peer.NewIsAvailable(true, false), peerOptimistic: false, expected: peer.NewIsAvailable(true, false)},
+		{local: peer.NewIsAvailable(true, false), peerOptimistic: true, expected: peer.NewIsAvailable(true, true)},
+		{local: peer.NewIsAvailable(false, false), peerOptimistic: true, expected: peer.NewIsAvailable(true, true)},
+		{local: peer.NewIsAvailable(false, false), peerOptimistic: false, expected: peer.NewIsAvailable(false, false)},
+	}
+	for _, tc := range testCases {
+		combinedStates := peer.NewCRStatesThreadsafe()
+		combineCacheState("edge1", tc.local, health.NewThreadsafeEvents(10), tc.peerOptimistic, peerStates, peer.NewCrstates(), combinedStates, map[enum.CacheName]bool{}, *todata.New())
+		if actual, _ := combinedStates.GetCache("edge1"); actual != tc.expected {
+			t.Errorf("combineCacheState local %+v peer optimistic %v expected %+v, actual %+v", tc.local, tc.peerOptimistic, tc.expected, actual)
+		}
+	}
+
+	// a peer which only sees the cache as healthy over IPv6 must not make it available to IPv6 clients while it's unavailable.
+	peerCRStates.Caches["edge1"] = peer.IsAvailable{IsAvailable: false, Ipv6Available: true}
+	peerStates.Set(peer.Result{ID: "tm-peer", Available: true, PeerStates: peerCRStates, Time: time.Now()})
+	combinedStates := peer.NewCRStatesThreadsafe()
+	combineCacheState("edge1", peer.NewIsAvailable(false, false), health.NewThreadsafeEvents(10), true, peerStates, peer.NewCrstates(), combinedStates, map[enum.CacheName]bool{}, *todata.New())
+	if actual, _ := combinedStates.GetCache("edge1"); actual.IsAvailable || actual.Ipv6Available {
+		t.Errorf("combineCacheState with a peer healthy only over IPv6 expected unavailable, actual %+v", actual)
+	}
+}
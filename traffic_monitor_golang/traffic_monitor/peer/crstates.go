@@ -73,8 +73,16 @@ func (a Crstates) CopyCaches() map[enum.CacheName]IsAvailable {
 }
 
 // IsAvailable contains whether the given cache or delivery service is available. It is designed for JSON serialization, namely in the Traffic Monitor 1.0 API.
+// IsAvailable is the legacy availability, and is always equal to Ipv4Available. Ipv6Available is only ever true for caches with an IPv6 address, which are healthy when polled over IPv6.
 type IsAvailable struct {
-	IsAvailable bool `json:"isAvailable"`
+	IsAvailable   bool `json:"isAvailable"`
+	Ipv4Available bool `json:"ipv4Available"`
+	Ipv6Available bool `json:"ipv6Available"`
+}
+
+// NewIsAvailable returns an IsAvailable with the given IPv4 and IPv6 availability.
+func NewIsAvailable(ipv4Available bool, ipv6Available bool) IsAvailable {
+	return IsAvailable{IsAvailable: ipv4Available, Ipv4Available: ipv4Available, Ipv6Available: ipv6Available}
 }
 
 // Deliveryservice contains data about the availability of a particular delivery service, and which caches in that delivery service have been marked as unavailable.
@@ -213,6 +221,7 @@ func (t *CRStatesPeersThreadsafe) SetTimeout(timeout time.Duration) {
 	*t.timeout = timeout
 }
 
+// SetPeers sets the peers in the peer set. Peers not in the given set, which have previously been polled, are marked as not online.
 func (t *CRStatesPeersThreadsafe) SetPeers(newPeers map[enum.TrafficMonitorName]struct{}) {
 	t.m.Lock()
 	defer t.m.Unlock()
@@ -220,6 +229,9 @@ func (t *CRStatesPeersThreadsafe) SetPeers(newPeers map[enum.TrafficMonitorName]
 		_, ok := newPeers[peer]
 		t.peerOnline[peer] = ok
 	}
+	for peer := range newPeers {
+		t.peerOnline[peer] = true
+	}
 }
 
 // GetCrstates returns the internal Traffic Monitor peer Crstates data. This MUST NOT be modified.
@@ -257,7 +269,7 @@ func (t *CRStatesPeersThreadsafe) GetPeerAvailability(peer enum.TrafficMonitorNa
 	return availability
 }
 
-// GetPeersOnline return a map of peers which are marked ONLINE in the latest CRConfig from Traffic Ops. This is NOT guaranteed to actually _contain_ all OFFLINE monitors returned by other functions, such as `GetPeerAvailability` and `GetQueryTimes`, but bool defaults to false, so the value of any key is guaranteed to be correct.
+// GetPeersOnline return a map of peers which are in the peer set, i.e. ONLINE in the latest CRConfig from Traffic Ops, in the static peer list, or discovered by gossip. This is NOT guaranteed to actually _contain_ all OFFLINE monitors returned by other functions, such as `GetPeerAvailability` and `GetQueryTimes`, but bool defaults to false, so the value of any key is guaranteed to be correct.
 func (t *CRStatesPeersThreadsafe) GetPeersOnline() map[enum.TrafficMonitorName]bool {
 	t.m.RLock()
 	defer t.m.RUnlock()
@@ -0,0 +1,211 @@
+package peer
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+)
+
+// ContentTypeCrstatesBinary is the media type of the compact binary Crstates encoding, which peers request in the Accept header.
+const ContentTypeCrstatesBinary = "application/vnd.trafficmonitor.crstates"
+
+// AcceptCrstates is the Accept header peers request CrStates with. Monitors which don't support the binary encoding ignore it, and serve JSON.
+const AcceptCrstates = ContentTypeCrstatesBinary + ", application/json;q=0.5"
+
+// crstatesBinaryMagic prefixes the binary encoding, so it can be told apart from JSON without a Content-Type. The last byte is the format version.
+var crstatesBinaryMagic = []byte{'T', 'M', 'C', 'S', 1}
+
+// maxCrstatesBinaryLen is the maximum count or string length accepted when decoding, so a corrupt length can't allocate unbounded memory.
+const maxCrstatesBinaryLen = 1 << 24
+
+const (
+	binaryFlagIsAvailable = 1 << iota
+	binaryFlagIpv4Available
+	binaryFlagIpv6Available
+)
+
+// CrstatesMarshallBinary serializes the given Crstates into the compact binary encoding. The encoding is the magic prefix, followed by the uvarint count of caches, and each cache as a uvarint-length-prefixed name and a byte of availability flags; then the uvarint count of delivery services, and each delivery service as a length-prefixed name, a flags byte, the uvarint count of disabled locations, and each length-prefixed location. Names are sorted, so equal states encode to equal bytes.
+func CrstatesMarshallBinary(states Crstates) ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, len(crstatesBinaryMagic)+len(states.Caches)*32+len(states.Deliveryservice)*32))
+	buf.Write(crstatesBinaryMagic)
+
+	cacheNames := make([]string, 0, len(states.Caches))
+	for name := range states.Caches {
+		cacheNames = append(cacheNames, string(name))
+	}
+	sort.Strings(cacheNames)
+	writeUvarint(buf, uint64(len(cacheNames)))
+	for _, name := range cacheNames {
+		available := states.Caches[enum.CacheName(name)]
+		writeString(buf, name)
+		buf.WriteByte(flagsByte(available.IsAvailable, available.Ipv4Available, available.Ipv6Available))
+	}
+
+	dsNames := make([]string, 0, len(states.Deliveryservice))
+	for name := range states.Deliveryservice {
+		dsNames = append(dsNames, string(name))
+	}
+	sort.Strings(dsNames)
+	writeUvarint(buf, uint64(len(dsNames)))
+	for _, name := range dsNames {
+		ds := states.Deliveryservice[enum.DeliveryServiceName(name)]
+		writeString(buf, name)
+		buf.WriteByte(flagsByte(ds.IsAvailable, false, false))
+		writeUvarint(buf, uint64(len(ds.DisabledLocations)))
+		for _, location := range ds.DisabledLocations {
+			writeString(buf, string(location))
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// CrstatesUnMarshallBinary takes bytes of the binary encoding, and unmarshals them into a Crstates object.
+func CrstatesUnMarshallBinary(body []byte) (Crstates, error) {
+	if !bytes.HasPrefix(body, crstatesBinaryMagic) {
+		return Crstates{}, errors.New("not binary crstates: missing magic prefix")
+	}
+	r := bytes.NewReader(body[len(crstatesBinaryMagic):])
+	states := NewCrstates()
+
+	numCaches, err := readLen(r)
+	if err != nil {
+		return Crstates{}, fmt.Errorf("reading cache count: %v", err)
+	}
+	for i := 0; i < numCaches; i++ {
+		name, err := readString(r)
+		if err != nil {
+			return Crstates{}, fmt.Errorf("reading cache name: %v", err)
+		}
+		flags, err := r.ReadByte()
+		if err != nil {
+			return Crstates{}, fmt.Errorf("reading cache %v flags: %v", name, err)
+		}
+		states.Caches[enum.CacheName(name)] = IsAvailable{
+			IsAvailable:   flags&binaryFlagIsAvailable != 0,
+			Ipv4Available: flags&binaryFlagIpv4Available != 0,
+			Ipv6Available: flags&binaryFlagIpv6Available != 0,
+		}
+	}
+
+	numDSes, err := readLen(r)
+	if err != nil {
+		return Crstates{}, fmt.Errorf("reading delivery service count: %v", err)
+	}
+	for i := 0; i < numDSes; i++ {
+		name, err := readString(r)
+		if err != nil {
+			return Crstates{}, fmt.Errorf("reading delivery service name: %v", err)
+		}
+		flags, err := r.ReadByte()
+		if err != nil {
+			return Crstates{}, fmt.Errorf("reading delivery service %v flags: %v", name, err)
+		}
+		numLocations, err := readLen(r)
+		if err != nil {
+			return Crstates{}, fmt.Errorf("reading delivery service %v disabled location count: %v", name, err)
+		}
+		ds := Deliveryservice{IsAvailable: flags&binaryFlagIsAvailable != 0, DisabledLocations: make([]enum.CacheGroupName, 0, numLocations)}
+		for j := 0; j < numLocations; j++ {
+			location, err := readString(r)
+			if err != nil {
+				return Crstates{}, fmt.Errorf("reading delivery service %v disabled location: %v", name, err)
+			}
+			ds.DisabledLocations = append(ds.DisabledLocations, enum.CacheGroupName(location))
+		}
+		states.Deliveryservice[enum.DeliveryServiceName(name)] = ds
+	}
+
+	if r.Len() != 0 {
+		return Crstates{}, fmt.Errorf("%v trailing bytes", r.Len())
+	}
+	return states, nil
+}
+
+// CrstatesDecode reads a Crstates object in either the binary or JSON encoding from the given reader, detecting the binary encoding by its magic prefix.
+func CrstatesDecode(r io.Reader) (Crstates, error) {
+	br := bufio.NewReader(r)
+	if prefix, err := br.Peek(len(crstatesBinaryMagic)); err == nil && bytes.Equal(prefix, crstatesBinaryMagic) {
+		body, err := ioutil.ReadAll(br)
+		if err != nil {
+			return Crstates{}, err
+		}
+		return CrstatesUnMarshallBinary(body)
+	}
+	crStates := Crstates{}
+	err := json.NewDecoder(br).Decode(&crStates)
+	return crStates, err
+}
+
+func flagsByte(isAvailable, ipv4Available, ipv6Available bool) byte {
+	flags := byte(0)
+	if isAvailable {
+		flags |= binaryFlagIsAvailable
+	}
+	if ipv4Available {
+		flags |= binaryFlagIpv4Available
+	}
+	if ipv6Available {
+		flags |= binaryFlagIpv6Available
+	}
+	return flags
+}
+
+func writeUvarint(buf *bytes.Buffer, v uint64) {
+	b := [binary.MaxVarintLen64]byte{}
+	buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	writeUvarint(buf, uint64(len(s)))
+	buf.WriteString(s)
+}
+
+func readLen(r *bytes.Reader) (int, error) {
+	v, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, err
+	}
+	if v > maxCrstatesBinaryLen || v > uint64(r.Len()) { // every counted item is at least 1 byte
+		return 0, fmt.Errorf("length %v exceeds remaining %v bytes", v, r.Len())
+	}
+	return int(v), nil
+}
+
+func readString(r *bytes.Reader) (string, error) {
+	l, err := readLen(r)
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, l)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
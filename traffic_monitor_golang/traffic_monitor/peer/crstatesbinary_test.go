@@ -0,0 +1,88 @@
+package peer
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+)
+
+func testCrstates() Crstates {
+	states := NewCrstates()
+	states.Caches["edge-a"] = NewIsAvailable(true, true)
+	states.Caches["edge-b"] = NewIsAvailable(true, false)
+	states.Caches["edge-c"] = NewIsAvailable(false, false)
+	states.Deliveryservice["ds-a"] = Deliveryservice{IsAvailable: true, DisabledLocations: []enum.CacheGroupName{}}
+	states.Deliveryservice["ds-b"] = Deliveryservice{IsAvailable: false, DisabledLocations: []enum.CacheGroupName{"cg-a", "cg-b"}}
+	return states
+}
+
+func TestCrstatesBinaryRoundTrip(t *testing.T) {
+	states := testCrstates()
+	body, err := CrstatesMarshallBinary(states)
+	if err != nil {
+		t.Fatalf("CrstatesMarshallBinary expected nil error, actual %v", err)
+	}
+	decoded, err := CrstatesUnMarshallBinary(body)
+	if err != nil {
+		t.Fatalf("CrstatesUnMarshallBinary expected nil error, actual %v", err)
+	}
+	if !reflect.DeepEqual(states, decoded) {
+		t.Errorf("binary round trip expected %+v, actual %+v", states, decoded)
+	}
+
+	again, _ := CrstatesMarshallBinary(decoded)
+	if !bytes.Equal(body, again) {
+		t.Errorf("CrstatesMarshallBinary of equal states expected equal bytes, actual different")
+	}
+	jsonBody, _ := CrstatesMarshall(states)
+	if len(body) >= len(jsonBody) {
+		t.Errorf("binary encoding expected smaller than JSON %v bytes, actual %v bytes", len(jsonBody), len(body))
+	}
+
+	for i := len(crstatesBinaryMagic); i < len(body); i++ {
+		if _, err := CrstatesUnMarshallBinary(body[:i]); err == nil {
+			t.Errorf("CrstatesUnMarshallBinary truncated to %v bytes expected error, actual nil", i)
+		}
+	}
+	if _, err := CrstatesUnMarshallBinary(append(body, 0)); err == nil {
+		t.Errorf("CrstatesUnMarshallBinary with trailing bytes expected error, actual nil")
+	}
+}
+
+func TestCrstatesDecode(t *testing.T) {
+	states := testCrstates()
+	binaryBody, _ := CrstatesMarshallBinary(states)
+	jsonBody, _ := CrstatesMarshall(states)
+	for name, body := range map[string][]byte{"binary": binaryBody, "json": jsonBody} {
+		decoded, err := CrstatesDecode(bytes.NewReader(body))
+		if err != nil {
+			t.Errorf("CrstatesDecode %v expected nil error, actual %v", name, err)
+		} else if !reflect.DeepEqual(states, decoded) {
+			t.Errorf("CrstatesDecode %v expected %+v, actual %+v", name, states, decoded)
+		}
+	}
+	if _, err := CrstatesDecode(bytes.NewReader([]byte("TM"))); err == nil {
+		t.Errorf("CrstatesDecode of garbage expected error, actual nil")
+	}
+}
@@ -0,0 +1,253 @@
+package peer
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+)
+
+// Source is where a peer Traffic Monitor was learned from.
+type Source string
+
+const (
+	// SourceTrafficOps is a peer which is ONLINE in the Traffic Ops monitoring config.
+	SourceTrafficOps = Source("traffic_ops")
+	// SourceStatic is a peer in the static peer list of this monitor's config file.
+	SourceStatic = Source("static")
+	// SourceGossip is a peer which announced itself to this monitor, or which was announced by another peer.
+	SourceGossip = Source("gossip")
+)
+
+// sourcePriority is the order in which sources' addresses are used, if a peer was learned from multiple sources.
+var sourcePriority = []Source{SourceTrafficOps, SourceStatic, SourceGossip}
+
+// Address is how to reach a peer Traffic Monitor. URL is the base URL of the peer, without a path, e.g. `http://192.0.2.1:80`. Host, if not empty, is the Host header to request it with.
+type Address struct {
+	URL  string `json:"url"`
+	Host string `json:"-"`
+}
+
+// Member is a peer Traffic Monitor in the peer set. LastSeen is the last time this monitor heard from the peer, by polling it successfully or receiving its announcement, or zero if it never has.
+type Member struct {
+	Address
+	Sources  []Source  `json:"sources"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// Announcement is the message a monitor gossips to its peers, announcing itself and the peers it has recently seen, by name and URL.
+type Announcement struct {
+	Name  enum.TrafficMonitorName            `json:"name"`
+	URL   string                             `json:"url"`
+	Peers map[enum.TrafficMonitorName]string `json:"peers"`
+}
+
+// member is the internal state of a peer. gossiped is the last time the peer was announced, directly or by another peer.
+type member struct {
+	addresses map[Source]Address
+	lastSeen  time.Time
+	gossiped  time.Time
+}
+
+func (m *member) member() Member {
+	mem := Member{LastSeen: m.lastSeen, Sources: []Source{}}
+	for _, source := range sourcePriority {
+		if addr, ok := m.addresses[source]; ok {
+			if mem.URL == "" {
+				mem.Address = addr
+			}
+			mem.Sources = append(mem.Sources, source)
+		}
+	}
+	return mem
+}
+
+// MembershipThreadsafe is the set of peer Traffic Monitors, from all sources, safe for multiple goroutines. Peers may come from Traffic Ops, a static list, and gossip, so monitors can join the peer set while Traffic Ops is unreachable. This monitor itself is never a member.
+type MembershipThreadsafe struct {
+	self    enum.TrafficMonitorName
+	members map[enum.TrafficMonitorName]*member
+	changed chan struct{}
+	m       *sync.RWMutex
+}
+
+// NewMembershipThreadsafe returns a new empty peer set, for the monitor with the given name.
+func NewMembershipThreadsafe(self enum.TrafficMonitorName) MembershipThreadsafe {
+	return MembershipThreadsafe{
+		self:    self,
+		members: map[enum.TrafficMonitorName]*member{},
+		changed: make(chan struct{}, 1),
+		m:       &sync.RWMutex{},
+	}
+}
+
+// Changed returns a channel which receives when a peer joins or leaves the set, or a peer's address changes. Changes which happen before the last is received are coalesced.
+func (t MembershipThreadsafe) Changed() <-chan struct{} {
+	return t.changed
+}
+
+func (t MembershipThreadsafe) notify() {
+	select {
+	case t.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Set replaces the peers from the given source. Peers no longer in the source are removed from it, and leave the set if they have no other source. This MUST NOT be used for SourceGossip, see Announce.
+func (t MembershipThreadsafe) Set(source Source, peers map[enum.TrafficMonitorName]Address) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	changed := false
+	for name, m := range t.members {
+		if _, ok := peers[name]; ok {
+			continue
+		}
+		if _, ok := m.addresses[source]; ok {
+			delete(m.addresses, source)
+			changed = true
+		}
+		if len(m.addresses) == 0 {
+			delete(t.members, name)
+		}
+	}
+	for name, addr := range peers {
+		if name == t.self {
+			continue
+		}
+		if t.setAddress(name, source, addr) {
+			changed = true
+		}
+	}
+	if changed {
+		t.notify()
+	}
+}
+
+// setAddress sets the address of the given peer from the given source, adding the peer if it doesn't exist. Returns whether the peer's address changed. The lock MUST be held.
+func (t MembershipThreadsafe) setAddress(name enum.TrafficMonitorName, source Source, addr Address) bool {
+	m, ok := t.members[name]
+	if !ok {
+		m = &member{addresses: map[Source]Address{}}
+		t.members[name] = m
+	}
+	old := m.member().Address
+	m.addresses[source] = addr
+	return !ok || m.member().Address != old
+}
+
+// Announce adds the peers in the given announcement, received at the given time, from SourceGossip. The announcing peer is marked as seen.
+// Addresses relayed for other peers are ignored if the peer is known from Traffic Ops or the static list, so an announcer can't redirect a trusted peer.
+func (t MembershipThreadsafe) Announce(a Announcement, now time.Time) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	changed := false
+	announce := func(name enum.TrafficMonitorName, url string) {
+		if name == t.self || name == "" || url == "" {
+			return
+		}
+		if name != a.Name && t.trusted(name) {
+			return
+		}
+		if t.setAddress(name, SourceGossip, Address{URL: strings.TrimSuffix(url, "/")}) {
+			changed = true
+		}
+		t.members[name].gossiped = now
+	}
+	for name, url := range a.Peers {
+		announce(name, url)
+	}
+	announce(a.Name, a.URL)
+	if m, ok := t.members[a.Name]; ok {
+		m.lastSeen = now
+	}
+	if changed {
+		t.notify()
+	}
+}
+
+// trusted returns whether the given peer is known from a source other than gossip. The lock MUST be held.
+func (t MembershipThreadsafe) trusted(name enum.TrafficMonitorName) bool {
+	m, ok := t.members[name]
+	if !ok {
+		return false
+	}
+	_, fromTO := m.addresses[SourceTrafficOps]
+	_, fromStatic := m.addresses[SourceStatic]
+	return fromTO || fromStatic
+}
+
+// Seen marks the given peer as seen at the given time, if it's a member.
+func (t MembershipThreadsafe) Seen(name enum.TrafficMonitorName, seen time.Time) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	if m, ok := t.members[name]; ok && seen.After(m.lastSeen) {
+		m.lastSeen = seen
+	}
+}
+
+// Expire removes peers from SourceGossip which haven't been announced within the given age. Peers with no other source leave the set.
+func (t MembershipThreadsafe) Expire(now time.Time, maxAge time.Duration) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	changed := false
+	for name, m := range t.members {
+		if _, ok := m.addresses[SourceGossip]; !ok || now.Sub(m.gossiped) < maxAge {
+			continue
+		}
+		old := m.member().Address
+		delete(m.addresses, SourceGossip)
+		if len(m.addresses) == 0 {
+			delete(t.members, name)
+			changed = true
+		} else if m.member().Address != old {
+			changed = true
+		}
+	}
+	if changed {
+		t.notify()
+	}
+}
+
+// Get returns the members of the peer set.
+func (t MembershipThreadsafe) Get() map[enum.TrafficMonitorName]Member {
+	t.m.RLock()
+	defer t.m.RUnlock()
+	members := make(map[enum.TrafficMonitorName]Member, len(t.members))
+	for name, m := range t.members {
+		members[name] = m.member()
+	}
+	return members
+}
+
+// Announcement returns the announcement for this monitor, reachable at the given URL, to gossip to its peers. Only peers seen within the given age are included, so peers which have gone away stop being gossiped, and eventually expire.
+func (t MembershipThreadsafe) Announcement(url string, now time.Time, maxAge time.Duration) Announcement {
+	t.m.RLock()
+	defer t.m.RUnlock()
+	a := Announcement{Name: t.self, URL: url, Peers: map[enum.TrafficMonitorName]string{}}
+	for name, m := range t.members {
+		if m.lastSeen.IsZero() || now.Sub(m.lastSeen) >= maxAge {
+			continue
+		}
+		a.Peers[name] = m.member().URL
+	}
+	return a
+}
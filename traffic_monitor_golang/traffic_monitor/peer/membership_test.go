@@ -0,0 +1,125 @@
+package peer
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+)
+
+func changed(m MembershipThreadsafe) bool {
+	select {
+	case <-m.Changed():
+		return true
+	default:
+		return false
+	}
+}
+
+func TestMembershipSources(t *testing.T) {
+	m := NewMembershipThreadsafe("tm-self")
+	m.Set(SourceStatic, map[enum.TrafficMonitorName]Address{
+		"tm-a":    {URL: "http://tm-a.example.net"},
+		"tm-self": {URL: "http://tm-self.example.net"},
+	})
+	if !changed(m) {
+		t.Errorf("Set new peer expected changed, actual unchanged")
+	}
+	m.Set(SourceTrafficOps, map[enum.TrafficMonitorName]Address{"tm-a": {URL: "http://192.0.2.1:80", Host: "tm-a.example.net"}})
+	if !changed(m) {
+		t.Errorf("Set new address expected changed, actual unchanged")
+	}
+
+	members := m.Get()
+	if _, ok := members["tm-self"]; ok {
+		t.Errorf("Get expected self excluded, actual %+v", members)
+	}
+	a := members["tm-a"]
+	if a.URL != "http://192.0.2.1:80" || a.Host != "tm-a.example.net" || len(a.Sources) != 2 || a.Sources[0] != SourceTrafficOps || a.Sources[1] != SourceStatic {
+		t.Errorf("Get expected Traffic Ops address with traffic_ops and static sources, actual %+v", a)
+	}
+
+	m.Set(SourceTrafficOps, map[enum.TrafficMonitorName]Address{})
+	if a := m.Get()["tm-a"]; a.URL != "http://tm-a.example.net" || len(a.Sources) != 1 {
+		t.Errorf("Get after Traffic Ops removal expected static address, actual %+v", a)
+	}
+	m.Set(SourceStatic, map[enum.TrafficMonitorName]Address{})
+	if members := m.Get(); len(members) != 0 {
+		t.Errorf("Get after all sources removed expected empty, actual %+v", members)
+	}
+}
+
+func TestMembershipGossip(t *testing.T) {
+	m := NewMembershipThreadsafe("tm-self")
+	now := time.Now()
+	m.Announce(Announcement{Name: "tm-a", URL: "http://tm-a.example.net/", Peers: map[enum.TrafficMonitorName]string{"tm-b": "http://tm-b.example.net", "tm-self": "http://tm-self.example.net"}}, now)
+	if !changed(m) {
+		t.Errorf("Announce new peers expected changed, actual unchanged")
+	}
+	members := m.Get()
+	if len(members) != 2 || members["tm-a"].URL != "http://tm-a.example.net" || !members["tm-a"].LastSeen.Equal(now) || !members["tm-b"].LastSeen.IsZero() {
+		t.Fatalf("Announce expected tm-a seen and tm-b unseen, actual %+v", members)
+	}
+
+	if a := m.Announcement("http://tm-self.example.net", now, time.Minute); len(a.Peers) != 1 || a.Peers["tm-a"] != "http://tm-a.example.net" {
+		t.Errorf("Announcement expected only seen peer tm-a, actual %+v", a)
+	}
+
+	m.Announce(Announcement{Name: "tm-a", URL: "http://tm-a.example.net"}, now.Add(time.Minute))
+	m.Expire(now.Add(90*time.Second), time.Minute)
+	if members := m.Get(); len(members) != 1 || members["tm-a"].URL == "" {
+		t.Errorf("Expire expected tm-b removed, actual %+v", members)
+	}
+	if !changed(m) {
+		t.Errorf("Expire expected changed, actual unchanged")
+	}
+
+	m.Set(SourceStatic, map[enum.TrafficMonitorName]Address{"tm-a": {URL: "http://tm-a.example.net"}})
+	m.Expire(now.Add(time.Hour), time.Minute)
+	if a := m.Get()["tm-a"]; len(a.Sources) != 1 || a.Sources[0] != SourceStatic {
+		t.Errorf("Expire of static peer expected static source kept, actual %+v", a)
+	}
+}
+
+func TestMembershipGossipTrustedPeers(t *testing.T) {
+	m := NewMembershipThreadsafe("tm-self")
+	now := time.Now()
+	m.Set(SourceTrafficOps, map[enum.TrafficMonitorName]Address{"tm-a": {URL: "http://tm-a.example.net"}})
+	m.Set(SourceStatic, map[enum.TrafficMonitorName]Address{"tm-b": {URL: "http://tm-b.example.net"}})
+	changed(m)
+
+	m.Announce(Announcement{Name: "tm-c", URL: "http://tm-c.example.net", Peers: map[enum.TrafficMonitorName]string{"tm-a": "http://evil.example.net", "tm-b": "http://evil.example.net"}}, now)
+	members := m.Get()
+	for _, name := range []enum.TrafficMonitorName{"tm-a", "tm-b"} {
+		if a := members[name]; len(a.Sources) != 1 || a.Sources[0] == SourceGossip {
+			t.Errorf("Announce relaying trusted peer %v expected relayed address ignored, actual %+v", name, a)
+		}
+	}
+	if a := members["tm-c"]; a.URL != "http://tm-c.example.net" {
+		t.Errorf("Announce expected announcer tm-c added, actual %+v", a)
+	}
+
+	m.Set(SourceTrafficOps, map[enum.TrafficMonitorName]Address{})
+	if _, ok := m.Get()["tm-a"]; ok {
+		t.Errorf("Get after Traffic Ops removal expected relayed address not kept, actual %+v", m.Get()["tm-a"])
+	}
+}
@@ -20,7 +20,6 @@ package peer
  */
 
 import (
-	"encoding/json"
 	"io"
 	"time"
 
@@ -49,7 +48,7 @@ type Result struct {
 	Time         time.Time
 }
 
-// Handle handles a response from a polled Traffic Monitor peer, parsing the data and forwarding it to the ResultChannel.
+// Handle handles a response from a polled Traffic Monitor peer, parsing the data in either the JSON or binary encoding, and forwarding it to the ResultChannel.
 func (handler Handler) Handle(id string, r io.Reader, reqTime time.Duration, reqEnd time.Time, err error, pollID uint64, pollFinished chan<- uint64) {
 	result := Result{
 		ID:           enum.TrafficMonitorName(id),
@@ -65,9 +64,7 @@ func (handler Handler) Handle(id string, r io.Reader, reqTime time.Duration, req
 	}
 
 	if r != nil {
-		dec := json.NewDecoder(r)
-		err = dec.Decode(&result.PeerStates)
-
+		result.PeerStates, err = CrstatesDecode(r)
 		if err == nil {
 			result.Available = true
 		} else {
@@ -0,0 +1,81 @@
+package srvhttp
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AccessLogTimeFormat is the time format of access log entries, as used by the Common Log Format.
+const AccessLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// accessLogWriter wraps a ResponseWriter, recording the response code and size, and the authenticated user, for the access log.
+type accessLogWriter struct {
+	http.ResponseWriter
+	code  int
+	bytes int
+	user  string
+}
+
+func (w *accessLogWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *accessLogWriter) Write(b []byte) (int, error) {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// wrapAccessLog wraps the given handler, writing an entry for each request to the given writer, in the Common Log Format followed by the user agent and the time taken to serve the request, e.g.
+// 192.0.2.1 - admin [10/Oct/2017:13:55:36 -0700] "GET /publish/CacheStats HTTP/1.1" 200 2326 "curl/7.29.0" 0.0042
+// If the writer is nil, requests aren't logged.
+func wrapAccessLog(w io.Writer, h http.Handler) http.Handler {
+	if w == nil {
+		return h
+	}
+	m := sync.Mutex{}
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		lw := &accessLogWriter{ResponseWriter: rw}
+		h.ServeHTTP(lw, r)
+		if lw.code == 0 {
+			lw.code = http.StatusOK
+		}
+		user := lw.user
+		if user == "" {
+			user = "-"
+		}
+		line := fmt.Sprintf("%s - %s [%s] \"%s %s %s\" %d %d %q %.4f\n", clientIP(r), user, start.Format(AccessLogTimeFormat), r.Method, r.URL.RequestURI(), r.Proto, lw.code, lw.bytes, r.UserAgent(), time.Since(start).Seconds())
+		m.Lock()
+		defer m.Unlock()
+		io.WriteString(w, line)
+	})
+}
@@ -0,0 +1,179 @@
+package srvhttp
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// Scope is the access granted to an authenticated API client.
+type Scope string
+
+const (
+	// ScopeRead allows requesting all endpoints except admin endpoints.
+	ScopeRead = Scope("read")
+	// ScopeAdmin allows requesting all endpoints, including admin endpoints, which expose configuration.
+	ScopeAdmin = Scope("admin")
+	// ScopePeer is required to request peer endpoints, which change the peer set. It's granted by the admin scope, or by the peer secret, and can't be given to API clients.
+	ScopePeer = Scope("peer")
+	// ScopeInvalid represents an invalid scope enumeration.
+	ScopeInvalid = Scope("")
+)
+
+// ScopeFromString returns a scope from its string representation, or ScopeInvalid if the string is not a valid scope.
+func ScopeFromString(s string) Scope {
+	switch strings.ToLower(s) {
+	case "read":
+		return ScopeRead
+	case "admin":
+		return ScopeAdmin
+	default:
+		return ScopeInvalid
+	}
+}
+
+// Allows returns whether this scope grants the given scope. Admin grants read.
+func (s Scope) Allows(required Scope) bool {
+	return s == ScopeAdmin || (s == ScopeRead && required == ScopeRead)
+}
+
+// User is a client allowed to authenticate with HTTP basic authentication.
+type User struct {
+	Password string
+	Scope    Scope
+}
+
+// AuthRealm is the realm returned in the WWW-Authenticate header of unauthorized responses.
+const AuthRealm = "traffic_monitor"
+
+// Auth is the authentication required to request the server's endpoints. Clients authenticate with a bearer token, as `Authorization: Bearer <token>`, or with HTTP basic authentication. If no Tokens or Users exist, authentication is disabled, and all requests are allowed.
+// AdminPaths require the admin scope. PublicPaths don't require authentication; these are the health protocol endpoints polled by Traffic Router and peer Traffic Monitors, which don't authenticate. Paths are matched without trailing slashes.
+// PeerPaths are requested by peer Traffic Monitors with the PeerSecret as a bearer token, or by admin clients. Unlike other paths, they always require authentication, even if no Tokens or Users exist, and are forbidden to everyone if there's no PeerSecret and authentication is disabled.
+type Auth struct {
+	Tokens      map[string]Scope
+	Users       map[string]User
+	AdminPaths  map[string]struct{}
+	PublicPaths map[string]struct{}
+	PeerPaths   map[string]struct{}
+	PeerSecret  string
+}
+
+// Enabled returns whether any credentials exist, and thus whether authentication is required.
+func (a Auth) Enabled() bool {
+	return len(a.Tokens) > 0 || len(a.Users) > 0
+}
+
+// trimPath returns the given path without a trailing slash, as paths are matched.
+func trimPath(path string) string {
+	if path == "/" {
+		return path
+	}
+	return strings.TrimSuffix(path, "/")
+}
+
+// isPublic returns whether the given path is one of the PublicPaths. Public paths are neither authenticated nor rate limited, so Traffic Router and peers can always poll health.
+func (a Auth) isPublic(path string) bool {
+	_, ok := a.PublicPaths[trimPath(path)]
+	return ok
+}
+
+// requiredScope returns the scope required to request the given path, and false if the path is public.
+func (a Auth) requiredScope(path string) (Scope, bool) {
+	if a.isPublic(path) {
+		return ScopeInvalid, false
+	}
+	if _, ok := a.PeerPaths[trimPath(path)]; ok {
+		return ScopePeer, true
+	}
+	if _, ok := a.AdminPaths[trimPath(path)]; ok {
+		return ScopeAdmin, true
+	}
+	return ScopeRead, true
+}
+
+// authenticate returns the name of the client making the given request and its scope, or false if the request has no valid credentials. Token clients are named `token`, so tokens aren't logged. Secrets are compared in constant time.
+func (a Auth) authenticate(r *http.Request) (string, Scope, bool) {
+	authorization := r.Header.Get("Authorization")
+	if strings.HasPrefix(authorization, "Bearer ") {
+		token := []byte(strings.TrimSpace(strings.TrimPrefix(authorization, "Bearer ")))
+		for validToken, scope := range a.Tokens {
+			if subtle.ConstantTimeCompare(token, []byte(validToken)) == 1 {
+				return "token", scope, true
+			}
+		}
+		return "", ScopeInvalid, false
+	}
+	name, pass, ok := r.BasicAuth()
+	if !ok {
+		return "", ScopeInvalid, false
+	}
+	user, ok := a.Users[name]
+	if !ok || subtle.ConstantTimeCompare([]byte(pass), []byte(user.Password)) != 1 {
+		return "", ScopeInvalid, false
+	}
+	return name, user.Scope, true
+}
+
+// isPeer returns whether the given request has the peer secret as its bearer token. The secret is compared in constant time.
+func (a Auth) isPeer(r *http.Request) bool {
+	authorization := r.Header.Get("Authorization")
+	if a.PeerSecret == "" || !strings.HasPrefix(authorization, "Bearer ") {
+		return false
+	}
+	token := strings.TrimSpace(strings.TrimPrefix(authorization, "Bearer "))
+	return subtle.ConstantTimeCompare([]byte(token), []byte(a.PeerSecret)) == 1
+}
+
+// wrapAuth wraps the given handler, returning 401 Unauthorized for requests without valid credentials, and 403 Forbidden for requests whose credentials don't grant the scope the path requires. The authenticated client name is set in the access log entry, if any.
+func (a Auth) wrapAuth(h http.Handler) http.Handler {
+	if !a.Enabled() && len(a.PeerPaths) == 0 {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		required, ok := a.requiredScope(r.URL.Path)
+		if !ok || (!a.Enabled() && required != ScopePeer) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if required == ScopePeer && a.isPeer(r) {
+			if lw, ok := w.(*accessLogWriter); ok {
+				lw.user = "peer"
+			}
+			h.ServeHTTP(w, r)
+			return
+		}
+		name, scope, ok := a.authenticate(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Basic realm="`+AuthRealm+`"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		if lw, ok := w.(*accessLogWriter); ok {
+			lw.user = name
+		}
+		if !scope.Allows(required) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,152 @@
+package srvhttp
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func testAuth() Auth {
+	return Auth{
+		Tokens:      map[string]Scope{"read-token": ScopeRead, "admin-token": ScopeAdmin},
+		Users:       map[string]User{"reader": {Password: "pw", Scope: ScopeRead}},
+		AdminPaths:  map[string]struct{}{"/publish/ConfigDoc": {}},
+		PublicPaths: map[string]struct{}{"/publish/CrStates": {}},
+	}
+}
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok")) })
+
+func TestWrapAuth(t *testing.T) {
+	h := testAuth().wrapAuth(okHandler)
+	tests := []struct {
+		path     string
+		token    string
+		user     string
+		pass     string
+		expected int
+	}{
+		{"/publish/CacheStats", "", "", "", http.StatusUnauthorized},
+		{"/publish/CacheStats", "wrong-token", "", "", http.StatusUnauthorized},
+		{"/publish/CacheStats", "read-token", "", "", http.StatusOK},
+		{"/publish/CacheStats", "", "reader", "pw", http.StatusOK},
+		{"/publish/CacheStats", "", "reader", "wrong", http.StatusUnauthorized},
+		{"/publish/ConfigDoc", "read-token", "", "", http.StatusForbidden},
+		{"/publish/ConfigDoc/", "", "reader", "pw", http.StatusForbidden},
+		{"/publish/ConfigDoc", "admin-token", "", "", http.StatusOK},
+		{"/publish/CrStates", "", "", "", http.StatusOK},
+		{"/publish/CrStates/", "", "", "", http.StatusOK},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", test.path, nil)
+		if test.token != "" {
+			r.Header.Set("Authorization", "Bearer "+test.token)
+		}
+		if test.user != "" {
+			r.SetBasicAuth(test.user, test.pass)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != test.expected {
+			t.Errorf("wrapAuth %v token '%v' user '%v' expected %v, actual %v", test.path, test.token, test.user, test.expected, w.Code)
+		}
+		if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("wrapAuth %v unauthorized expected WWW-Authenticate header, actual none", test.path)
+		}
+	}
+}
+
+func TestWrapAuthDisabled(t *testing.T) {
+	w := httptest.NewRecorder()
+	Auth{AdminPaths: map[string]struct{}{"/publish/ConfigDoc": {}}}.wrapAuth(okHandler).ServeHTTP(w, httptest.NewRequest("GET", "/publish/ConfigDoc", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("wrapAuth with no credentials configured expected %v, actual %v", http.StatusOK, w.Code)
+	}
+}
+
+func TestWrapAuthPeerPaths(t *testing.T) {
+	auth := testAuth()
+	auth.PeerPaths = map[string]struct{}{"/api/peers/announce": {}}
+	auth.PeerSecret = "peer-secret"
+	noCreds := auth
+	noCreds.Tokens = nil
+	noCreds.Users = nil
+	noSecret := noCreds
+	noSecret.PeerSecret = ""
+
+	tests := []struct {
+		name     string
+		auth     Auth
+		path     string
+		token    string
+		expected int
+	}{
+		{"auth", auth, "/api/peers/announce", "", http.StatusUnauthorized},
+		{"auth", auth, "/api/peers/announce", "wrong-secret", http.StatusUnauthorized},
+		{"auth", auth, "/api/peers/announce/", "peer-secret", http.StatusOK},
+		{"auth", auth, "/api/peers/announce", "admin-token", http.StatusOK},
+		{"auth", auth, "/api/peers/announce", "read-token", http.StatusForbidden},
+		{"auth", auth, "/publish/CacheStats", "peer-secret", http.StatusUnauthorized},
+		{"no credentials", noCreds, "/api/peers/announce", "", http.StatusUnauthorized},
+		{"no credentials", noCreds, "/api/peers/announce", "peer-secret", http.StatusOK},
+		{"no credentials", noCreds, "/publish/CacheStats", "", http.StatusOK},
+		{"no secret", noSecret, "/api/peers/announce", "", http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("POST", test.path, nil)
+		if test.token != "" {
+			r.Header.Set("Authorization", "Bearer "+test.token)
+		}
+		w := httptest.NewRecorder()
+		test.auth.wrapAuth(okHandler).ServeHTTP(w, r)
+		if w.Code != test.expected {
+			t.Errorf("wrapAuth with %v %v token '%v' expected %v, actual %v", test.name, test.path, test.token, test.expected, w.Code)
+		}
+	}
+}
+
+func TestWrapAccessLog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	h := wrapAccessLog(buf, testAuth().wrapAuth(okHandler))
+
+	r := httptest.NewRequest("GET", "/publish/CacheStats?hc=1", nil)
+	r.SetBasicAuth("reader", "pw")
+	r.Header.Set("User-Agent", "test-agent")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/publish/CacheStats", nil))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("wrapAccessLog expected 2 lines, actual %v: %v", len(lines), buf.String())
+	}
+	if expected := ` - reader [`; !strings.Contains(lines[0], expected) {
+		t.Errorf("wrapAccessLog expected user '%v', actual '%v'", expected, lines[0])
+	}
+	if expected := `"GET /publish/CacheStats?hc=1 HTTP/1.1" 200 2 "test-agent"`; !strings.Contains(lines[0], expected) {
+		t.Errorf("wrapAccessLog expected '%v', actual '%v'", expected, lines[0])
+	}
+	if expected := ` - - [`; !strings.Contains(lines[1], expected) || !strings.Contains(lines[1], `" 401 `) {
+		t.Errorf("wrapAccessLog unauthenticated request expected no user and 401, actual '%v'", lines[1])
+	}
+}
@@ -0,0 +1,124 @@
+package srvhttp
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit is the number of requests each client may make. Each client may make Burst requests at once, after which requests are allowed at RequestsPerSecond. Clients are identified by IP address. If RequestsPerSecond is 0, requests aren't limited.
+type RateLimit struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+// rateLimitPruneInterval is how often clients which haven't made requests recently are removed from the rate limiter.
+const rateLimitPruneInterval = time.Minute
+
+// rateLimitBucket is the token bucket of a single client. Tokens is the number of requests the client may make, as of Time.
+type rateLimitBucket struct {
+	tokens float64
+	time   time.Time
+}
+
+// rateLimiter limits the requests of each client with a token bucket. It's safe for multiple goroutines.
+type rateLimiter struct {
+	limit     RateLimit
+	m         sync.Mutex
+	buckets   map[string]rateLimitBucket
+	lastPrune time.Time
+}
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &rateLimiter{limit: limit, buckets: map[string]rateLimitBucket{}, lastPrune: time.Now()}
+}
+
+// allow returns whether the given client may make a request at the given time, and if not, how long until it may.
+func (l *rateLimiter) allow(client string, now time.Time) (bool, time.Duration) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.prune(now)
+
+	burst := float64(l.limit.Burst)
+	b, ok := l.buckets[client]
+	if !ok {
+		b = rateLimitBucket{tokens: burst, time: now}
+	}
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.time).Seconds()*l.limit.RequestsPerSecond)
+	b.time = now
+	if b.tokens < 1 {
+		l.buckets[client] = b
+		return false, time.Duration((1 - b.tokens) / l.limit.RequestsPerSecond * float64(time.Second))
+	}
+	b.tokens--
+	l.buckets[client] = b
+	return true, 0
+}
+
+// prune removes the buckets of clients which would have refilled to the burst, so clients which stop making requests don't consume memory. Callers must hold the lock.
+func (l *rateLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < rateLimitPruneInterval {
+		return
+	}
+	l.lastPrune = now
+	refill := time.Duration(float64(l.limit.Burst) / l.limit.RequestsPerSecond * float64(time.Second))
+	for client, b := range l.buckets {
+		if now.Sub(b.time) > refill {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+// clientIP returns the IP of the client making the given request. Forwarding headers aren't used, because clients can forge them to evade the limit.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// wrapRateLimit wraps the given handler, returning 429 Too Many Requests with a Retry-After header to clients which exceed the limit. Requests for paths which are exempt aren't limited, and don't count against the client's limit.
+func (limit RateLimit) wrapRateLimit(h http.Handler, exempt func(path string) bool) http.Handler {
+	if limit.RequestsPerSecond <= 0 {
+		return h
+	}
+	limiter := newRateLimiter(limit)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if exempt(r.URL.Path) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if ok, retryAfter := limiter.allow(clientIP(r), time.Now()); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,108 @@
+package srvhttp
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	l := newRateLimiter(RateLimit{RequestsPerSecond: 2, Burst: 3})
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		if ok, _ := l.allow("a", now); !ok {
+			t.Fatalf("allow burst request %v expected true, actual false", i)
+		}
+	}
+	ok, retryAfter := l.allow("a", now)
+	if ok || retryAfter != 500*time.Millisecond {
+		t.Errorf("allow after burst expected false with 500ms retry, actual %v %v", ok, retryAfter)
+	}
+	if ok, _ := l.allow("b", now); !ok {
+		t.Errorf("allow other client expected true, actual false")
+	}
+	if ok, _ := l.allow("a", now.Add(500*time.Millisecond)); !ok {
+		t.Errorf("allow after refill expected true, actual false")
+	}
+
+	l.allow("c", now.Add(rateLimitPruneInterval*2))
+	if _, ok := l.buckets["a"]; ok {
+		t.Errorf("allow after prune interval expected idle client removed, actual %+v", l.buckets)
+	}
+}
+
+func TestWrapRateLimit(t *testing.T) {
+	h := RateLimit{RequestsPerSecond: 1, Burst: 1}.wrapRateLimit(okHandler, Auth{}.isPublic)
+	r := httptest.NewRequest("GET", "/publish/CacheStats", nil)
+	r.RemoteAddr = "192.0.2.1:1234"
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Errorf("wrapRateLimit first request expected %v, actual %v", http.StatusOK, w.Code)
+	}
+
+	r.RemoteAddr = "192.0.2.1:5678" // same client, different connection
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "1" {
+		t.Errorf("wrapRateLimit second request expected %v with Retry-After 1, actual %v %v", http.StatusTooManyRequests, w.Code, w.Header().Get("Retry-After"))
+	}
+
+	w = httptest.NewRecorder()
+	RateLimit{}.wrapRateLimit(okHandler, Auth{}.isPublic).ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Errorf("wrapRateLimit with no limit expected %v, actual %v", http.StatusOK, w.Code)
+	}
+}
+
+func TestWrapRateLimitPublicPaths(t *testing.T) {
+	auth := Auth{PublicPaths: map[string]struct{}{"/publish/CrStates": struct{}{}, "/publish/CrConfig": struct{}{}}}
+	h := RateLimit{RequestsPerSecond: 1, Burst: 1}.wrapRateLimit(okHandler, auth.isPublic)
+
+	for i := 0; i < 5; i++ {
+		for _, path := range []string{"/publish/CrStates", "/publish/CrStates/", "/publish/CrConfig"} {
+			r := httptest.NewRequest("GET", path, nil)
+			r.RemoteAddr = "192.0.2.1:1234"
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != http.StatusOK {
+				t.Errorf("wrapRateLimit public path %v request %v expected %v, actual %v", path, i, http.StatusOK, w.Code)
+			}
+		}
+	}
+
+	// public requests don't count against the client's limit
+	r := httptest.NewRequest("GET", "/publish/CacheStats", nil)
+	r.RemoteAddr = "192.0.2.1:1234"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Errorf("wrapRateLimit first non-public request after public requests expected %v, actual %v", http.StatusOK, w.Code)
+	}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("wrapRateLimit second non-public request expected %v, actual %v", http.StatusTooManyRequests, w.Code)
+	}
+}
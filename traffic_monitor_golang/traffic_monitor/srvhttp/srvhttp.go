@@ -20,7 +20,10 @@ package srvhttp
  */
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -49,11 +52,24 @@ type CommonAPIData struct {
 // Server is a re-runnable HTTP server. Server.Run() may be called repeatedly, and
 // each time the previous running server will be stopped, and the server will be
 // restarted with the new port address and data request channel.
+//
+// Auth, RateLimit, and AccessLog are optional; the zero values disable authentication, rate limiting, and access logging. They must be set before Run is called.
+//
+// Shutdown stops the server gracefully, after which Run returns an error.
 type Server struct {
+	Auth                       Auth
+	RateLimit                  RateLimit
+	AccessLog                  io.Writer
+	m                          sync.Mutex // m guards Run and Shutdown, which may be called from different goroutines, e.g. the ops config reloader and the shutdown handler.
+	server                     *http.Server
+	shutdown                   bool
 	stoppableListener          *stoppableListener.StoppableListener
 	stoppableListenerWaitGroup sync.WaitGroup
 }
 
+// ErrServerShutdown is returned by Run after the server has been shut down.
+var ErrServerShutdown = errors.New("server has been shut down")
+
 func (s *Server) registerEndpoints(sm *http.ServeMux, endpoints map[string]http.HandlerFunc, staticFileDir string) error {
 	handleRoot, err := s.handleRootFunc(staticFileDir)
 	if err != nil {
@@ -76,8 +92,12 @@ func (s *Server) registerEndpoints(sm *http.ServeMux, endpoints map[string]http.
 
 // Run runs a new HTTP service at the given addr, making data requests to the given c.
 // Run may be called repeatedly, and each time, will shut down any existing service first.
-// Run is NOT threadsafe, and MUST NOT be called concurrently by multiple goroutines.
 func (s *Server) Run(endpoints map[string]http.HandlerFunc, addr string, readTimeout time.Duration, writeTimeout time.Duration, staticFileDir string) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if s.shutdown {
+		return ErrServerShutdown
+	}
 	if s.stoppableListener != nil {
 		log.Infof("Stopping Web Server\n")
 		s.stoppableListener.Stop()
@@ -99,32 +119,49 @@ func (s *Server) Run(endpoints map[string]http.HandlerFunc, addr string, readTim
 	if err != nil {
 		return err
 	}
+	// Requests are rate limited before authenticating, so clients can't guess credentials without limit. Rejected requests are still logged. Public health paths are never limited, so Traffic Router always gets cache states.
+	handler := wrapAccessLog(s.AccessLog, s.RateLimit.wrapRateLimit(s.Auth.wrapAuth(sm), s.Auth.isPublic))
 	server := &http.Server{
 		Addr:           addr,
-		Handler:        sm,
+		Handler:        handler,
 		ReadTimeout:    readTimeout,
 		WriteTimeout:   writeTimeout,
 		MaxHeaderBytes: 1 << 20,
 	}
 
+	s.server = server
 	s.stoppableListenerWaitGroup = sync.WaitGroup{}
 	s.stoppableListenerWaitGroup.Add(1)
-	go func() {
+	go func(l net.Listener) {
 		defer s.stoppableListenerWaitGroup.Done()
-		err := server.Serve(s.stoppableListener)
+		err := server.Serve(l)
 		if err != nil {
-			if err != stoppableListener.StoppedError {
+			if err != stoppableListener.StoppedError && err != http.ErrServerClosed {
 				log.Warnf("HTTP server stopped with error: %v\n", err)
 			} else {
 				log.Infof("Web server stopped on %s", addr)
 			}
 		}
-	}()
+	}(s.stoppableListener)
 
 	log.Infof("Web server listening on %s", addr)
 	return nil
 }
 
+// Shutdown stops the server from accepting new connections, and waits for in-flight requests to finish, or for the given context to be done, whichever is first. After Shutdown, Run may not be called again.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.shutdown = true
+	if s.server == nil {
+		return nil
+	}
+	log.Infof("Shutting down Web Server\n")
+	err := s.server.Shutdown(ctx)
+	s.stoppableListenerWaitGroup.Wait()
+	return err
+}
+
 // ParametersStr takes the URL query parameters, and returns a string as used by the Traffic Monitor 1.0 endpoints "pp" key.
 func ParametersStr(params url.Values) string {
 	pp := ""
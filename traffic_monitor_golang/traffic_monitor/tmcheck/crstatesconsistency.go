@@ -0,0 +1,275 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package tmcheck
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+	to "github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+)
+
+// InconsistencyType is the type of object whose availability is inconsistent across monitors.
+type InconsistencyType string
+
+const (
+	InconsistencyTypeCache           = InconsistencyType("cache")
+	InconsistencyTypeDeliveryService = InconsistencyType("deliveryservice")
+)
+
+// Inconsistency is a cache or delivery service whose availability disagrees across the Traffic Monitors of a CDN. Missing contains monitors whose CRStates don't contain the object at all.
+type Inconsistency struct {
+	Type        InconsistencyType
+	Name        string
+	CDN         enum.CDNName
+	Available   []enum.TrafficMonitorName
+	Unavailable []enum.TrafficMonitorName
+	Missing     []enum.TrafficMonitorName
+}
+
+func (i Inconsistency) key() string {
+	return string(i.CDN) + "/" + string(i.Type) + "/" + i.Name
+}
+
+func (i Inconsistency) String() string {
+	return fmt.Sprintf("%v %v available on %v, unavailable on %v, missing on %v", i.Type, i.Name, i.Available, i.Unavailable, i.Missing)
+}
+
+// Minority returns the monitors which disagree with the majority. If there is no majority, all monitors involved are returned.
+func (i Inconsistency) Minority() []enum.TrafficMonitorName {
+	groups := [][]enum.TrafficMonitorName{i.Available, i.Unavailable, i.Missing}
+	largest := 0
+	largestCount := 0
+	for _, group := range groups {
+		if len(group) > largest {
+			largest = len(group)
+			largestCount = 1
+		} else if len(group) == largest {
+			largestCount++
+		}
+	}
+
+	minority := []enum.TrafficMonitorName{}
+	for _, group := range groups {
+		if largestCount == 1 && len(group) == largest {
+			continue
+		}
+		minority = append(minority, group...)
+	}
+	return minority
+}
+
+// CRStatesInconsistencies returns all caches and delivery services whose availability disagrees across the given CRStates. All CRStates must be from monitors of the same CDN.
+func CRStatesInconsistencies(cdn enum.CDNName, crStates map[enum.TrafficMonitorName]peer.Crstates) []Inconsistency {
+	monitors := []enum.TrafficMonitorName{}
+	cacheNames := map[string]struct{}{}
+	dsNames := map[string]struct{}{}
+	for monitor, states := range crStates {
+		monitors = append(monitors, monitor)
+		for cache, _ := range states.Caches {
+			cacheNames[string(cache)] = struct{}{}
+		}
+		for ds, _ := range states.Deliveryservice {
+			dsNames[string(ds)] = struct{}{}
+		}
+	}
+	sort.Sort(trafficMonitorNames(monitors))
+
+	cacheAvailable := func(states peer.Crstates, name string) (bool, bool) {
+		available, ok := states.Caches[enum.CacheName(name)]
+		return available.IsAvailable, ok
+	}
+	dsAvailable := func(states peer.Crstates, name string) (bool, bool) {
+		available, ok := states.Deliveryservice[enum.DeliveryServiceName(name)]
+		return available.IsAvailable, ok
+	}
+
+	inconsistencies := []Inconsistency{}
+	check := func(typ InconsistencyType, names map[string]struct{}, getAvailable func(peer.Crstates, string) (bool, bool)) {
+		sortedNames := []string{}
+		for name, _ := range names {
+			sortedNames = append(sortedNames, name)
+		}
+		sort.Strings(sortedNames)
+		for _, name := range sortedNames {
+			inconsistency := Inconsistency{Type: typ, Name: name, CDN: cdn}
+			for _, monitor := range monitors {
+				available, ok := getAvailable(crStates[monitor], name)
+				switch {
+				case !ok:
+					inconsistency.Missing = append(inconsistency.Missing, monitor)
+				case available:
+					inconsistency.Available = append(inconsistency.Available, monitor)
+				default:
+					inconsistency.Unavailable = append(inconsistency.Unavailable, monitor)
+				}
+			}
+			if len(inconsistency.Available) == len(monitors) || len(inconsistency.Unavailable) == len(monitors) {
+				continue
+			}
+			inconsistencies = append(inconsistencies, inconsistency)
+		}
+	}
+	check(InconsistencyTypeCache, cacheNames, cacheAvailable)
+	check(InconsistencyTypeDeliveryService, dsNames, dsAvailable)
+	return inconsistencies
+}
+
+type trafficMonitorNames []enum.TrafficMonitorName
+
+func (t trafficMonitorNames) Len() int           { return len(t) }
+func (t trafficMonitorNames) Less(i, j int) bool { return t[i] < t[j] }
+func (t trafficMonitorNames) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+
+// InconsistencyTracker tracks how long inconsistencies have persisted across successive checks. It is not safe for multiple goroutines.
+type InconsistencyTracker struct {
+	firstSeen map[string]time.Time
+}
+
+// NewInconsistencyTracker creates a new InconsistencyTracker.
+func NewInconsistencyTracker() InconsistencyTracker {
+	return InconsistencyTracker{firstSeen: map[string]time.Time{}}
+}
+
+// Update records the given current inconsistencies as of `now`, forgets any previous inconsistencies which no longer exist, and returns the inconsistencies which have persisted longer than `grace`.
+func (t InconsistencyTracker) Update(inconsistencies []Inconsistency, now time.Time, grace time.Duration) []Inconsistency {
+	current := map[string]struct{}{}
+	persistent := []Inconsistency{}
+	for _, inconsistency := range inconsistencies {
+		key := inconsistency.key()
+		current[key] = struct{}{}
+		firstSeen, ok := t.firstSeen[key]
+		if !ok {
+			firstSeen = now
+			t.firstSeen[key] = now
+		}
+		if now.Sub(firstSeen) >= grace {
+			persistent = append(persistent, inconsistency)
+		}
+	}
+	for key, _ := range t.firstSeen {
+		if _, ok := current[key]; !ok {
+			delete(t.firstSeen, key)
+		}
+	}
+	return persistent
+}
+
+// GetAllCRStates gets the CRStates of all monitors in the given Traffic Ops, grouped by CDN. Monitors whose CRStates couldn't be fetched are returned in the error map.
+func GetAllCRStates(toClient *to.Session, includeOffline bool) (map[enum.CDNName]map[enum.TrafficMonitorName]peer.Crstates, map[enum.TrafficMonitorName]error, error) {
+	servers, err := GetMonitors(toClient, includeOffline)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cdnStates := map[enum.CDNName]map[enum.TrafficMonitorName]peer.Crstates{}
+	errs := map[enum.TrafficMonitorName]error{}
+	for _, server := range servers {
+		uri := fmt.Sprintf("http://%s.%s", server.HostName, server.DomainName)
+		crStates, err := GetCRStates(uri + TrafficMonitorCRStatesPath)
+		if err != nil {
+			errs[enum.TrafficMonitorName(server.HostName)] = fmt.Errorf("getting CRStates: %v", err)
+			continue
+		}
+		cdn := enum.CDNName(server.CDNName)
+		if _, ok := cdnStates[cdn]; !ok {
+			cdnStates[cdn] = map[enum.TrafficMonitorName]peer.Crstates{}
+		}
+		cdnStates[cdn][enum.TrafficMonitorName(server.HostName)] = *crStates
+	}
+	return cdnStates, errs, nil
+}
+
+// GetAllCRStatesInconsistencies gets the CRStates of all monitors in the given Traffic Ops, and returns all inconsistencies between monitors of the same CDN, as well as errors for monitors whose CRStates couldn't be fetched.
+func GetAllCRStatesInconsistencies(toClient *to.Session, includeOffline bool) ([]Inconsistency, map[enum.TrafficMonitorName]error, error) {
+	cdnStates, errs, err := GetAllCRStates(toClient, includeOffline)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	inconsistencies := []Inconsistency{}
+	for cdn, crStates := range cdnStates {
+		inconsistencies = append(inconsistencies, CRStatesInconsistencies(cdn, crStates)...)
+	}
+	return inconsistencies, errs, nil
+}
+
+// InconsistencyErrors attributes the given inconsistencies to the monitors in the minority for each inconsistency, returning an error for every monitor in the given CDN states. Monitors with no inconsistencies have a nil error.
+func InconsistencyErrors(inconsistencies []Inconsistency, monitorErrs map[enum.TrafficMonitorName]error, monitors []enum.TrafficMonitorName) map[enum.TrafficMonitorName]error {
+	msgs := map[enum.TrafficMonitorName][]string{}
+	for _, inconsistency := range inconsistencies {
+		for _, monitor := range inconsistency.Minority() {
+			msgs[monitor] = append(msgs[monitor], inconsistency.String())
+		}
+	}
+
+	errs := map[enum.TrafficMonitorName]error{}
+	for _, monitor := range monitors {
+		errs[monitor] = nil
+		if monitorMsgs, ok := msgs[monitor]; ok {
+			errs[monitor] = fmt.Errorf("CRStates disagree with other monitors: %v", strings.Join(monitorMsgs, "; "))
+		}
+	}
+	for monitor, err := range monitorErrs {
+		errs[monitor] = err
+	}
+	return errs
+}
+
+// ValidateAllMonitorsCRStatesConsistency validates, for all monitors in the given Traffic Ops, that no cache or delivery service availability has disagreed across monitors of the same CDN for longer than `grace`, as tracked by the given tracker across successive calls.
+func ValidateAllMonitorsCRStatesConsistency(toClient *to.Session, includeOffline bool, tracker InconsistencyTracker, grace time.Duration) (map[enum.TrafficMonitorName]error, error) {
+	cdnStates, monitorErrs, err := GetAllCRStates(toClient, includeOffline)
+	if err != nil {
+		return nil, err
+	}
+
+	monitors := []enum.TrafficMonitorName{}
+	inconsistencies := []Inconsistency{}
+	for cdn, crStates := range cdnStates {
+		for monitor, _ := range crStates {
+			monitors = append(monitors, monitor)
+		}
+		inconsistencies = append(inconsistencies, CRStatesInconsistencies(cdn, crStates)...)
+	}
+
+	persistent := tracker.Update(inconsistencies, time.Now(), grace)
+	return InconsistencyErrors(persistent, monitorErrs, monitors), nil
+}
+
+// AllMonitorsCRStatesConsistencyValidator is designed to be run as a goroutine, and does not return. It continously validates every `interval`, and calls `onErr` on failure, `onResumeSuccess` when a failure ceases, and `onCheck` on every poll. Note the grace period applies to each inconsistent cache or delivery service, not to each monitor; monitors are reported as soon as any inconsistency they're in the minority of exceeds the grace period. Note the error passed to `onErr` may be a general validation error not associated with any monitor, in which case the passed `enum.TrafficMonitorName` will be empty.
+func AllMonitorsCRStatesConsistencyValidator(
+	toClient *to.Session,
+	interval time.Duration,
+	includeOffline bool,
+	grace time.Duration,
+	onErr func(enum.TrafficMonitorName, error),
+	onResumeSuccess func(enum.TrafficMonitorName),
+	onCheck func(enum.TrafficMonitorName, error),
+) {
+	tracker := NewInconsistencyTracker()
+	validator := func(toClient *to.Session, includeOffline bool) (map[enum.TrafficMonitorName]error, error) {
+		return ValidateAllMonitorsCRStatesConsistency(toClient, includeOffline, tracker, grace)
+	}
+	AllValidator(toClient, interval, includeOffline, 0, onErr, onResumeSuccess, onCheck, validator)
+}
@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package tmcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/peer"
+)
+
+func TestCRStatesInconsistencies(t *testing.T) {
+	newStates := func(cacheAvailable bool, dsAvailable bool) peer.Crstates {
+		states := peer.NewCrstates()
+		states.Caches["cache0"] = peer.IsAvailable{IsAvailable: true}
+		states.Caches["cache1"] = peer.IsAvailable{IsAvailable: cacheAvailable}
+		states.Deliveryservice["ds0"] = peer.Deliveryservice{IsAvailable: dsAvailable}
+		return states
+	}
+
+	crStates := map[enum.TrafficMonitorName]peer.Crstates{
+		"tm0": newStates(true, true),
+		"tm1": newStates(true, true),
+		"tm2": newStates(false, true),
+	}
+	inconsistencies := CRStatesInconsistencies("cdn", crStates)
+	if len(inconsistencies) != 1 {
+		t.Fatalf("expected 1 inconsistency, actual %v", inconsistencies)
+	}
+	if inconsistencies[0].Type != InconsistencyTypeCache || inconsistencies[0].Name != "cache1" {
+		t.Errorf("expected inconsistent cache1, actual %v", inconsistencies[0])
+	}
+	if minority := inconsistencies[0].Minority(); len(minority) != 1 || minority[0] != "tm2" {
+		t.Errorf("expected minority tm2, actual %v", minority)
+	}
+
+	delete(crStates["tm0"].Deliveryservice, "ds0")
+	inconsistencies = CRStatesInconsistencies("cdn", crStates)
+	if len(inconsistencies) != 2 {
+		t.Fatalf("expected 2 inconsistencies, actual %v", inconsistencies)
+	}
+	if ds := inconsistencies[1]; ds.Type != InconsistencyTypeDeliveryService || len(ds.Missing) != 1 || ds.Missing[0] != "tm0" {
+		t.Errorf("expected ds0 missing on tm0, actual %v", ds)
+	}
+}
+
+func TestInconsistencyTracker(t *testing.T) {
+	tracker := NewInconsistencyTracker()
+	grace := time.Minute
+	start := time.Now()
+	inconsistencies := []Inconsistency{{Type: InconsistencyTypeCache, Name: "cache0", CDN: "cdn"}}
+
+	if persistent := tracker.Update(inconsistencies, start, grace); len(persistent) != 0 {
+		t.Errorf("expected no persistent inconsistencies within grace, actual %v", persistent)
+	}
+	if persistent := tracker.Update(inconsistencies, start.Add(grace), grace); len(persistent) != 1 {
+		t.Errorf("expected persistent inconsistency after grace, actual %v", persistent)
+	}
+	if persistent := tracker.Update(nil, start.Add(grace), grace); len(persistent) != 0 {
+		t.Errorf("expected no persistent inconsistencies after resolution, actual %v", persistent)
+	}
+	if persistent := tracker.Update(inconsistencies, start.Add(2*grace), grace); len(persistent) != 0 {
+		t.Errorf("expected recurring inconsistency to restart grace, actual %v", persistent)
+	}
+}
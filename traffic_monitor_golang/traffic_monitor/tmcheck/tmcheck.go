@@ -21,6 +21,7 @@
 package tmcheck
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -195,7 +196,7 @@ type CRConfigOrError struct {
 func GetMonitors(toClient *to.Session, includeOffline bool) ([]to.Server, error) {
 	trafficMonitorType := "RASCAL"
 	monitorTypeQuery := map[string][]string{"type": []string{trafficMonitorType}}
-	servers, err := toClient.ServersByType(monitorTypeQuery)
+	servers, err := toClient.ServersByType(context.Background(), monitorTypeQuery)
 	if err != nil {
 		return nil, fmt.Errorf("getting monitors from Traffic Ops: %v", err)
 	}
@@ -281,7 +282,7 @@ func GetCDNs(servers []to.Server) map[enum.CDNName]struct{} {
 func GetCRConfigs(cdns map[enum.CDNName]struct{}, toClient *to.Session) map[enum.CDNName]CRConfigOrError {
 	crConfigs := map[enum.CDNName]CRConfigOrError{}
 	for cdn, _ := range cdns {
-		crConfigBytes, err := toClient.CRConfigRaw(string(cdn))
+		crConfigBytes, err := toClient.CRConfigRaw(context.Background(), string(cdn))
 		if err != nil {
 			crConfigs[cdn] = CRConfigOrError{Err: fmt.Errorf("getting CRConfig: %v", err)}
 			continue
@@ -0,0 +1,86 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/common/nagios"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/tmcheck"
+	to "github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+	"time"
+)
+
+const UserAgent = "tm-consistency-validator/0.1"
+
+func main() {
+	toURI := flag.String("to", "", "The Traffic Ops URI, whose monitors to validate")
+	toUser := flag.String("touser", "", "The Traffic Ops user")
+	toPass := flag.String("topass", "", "The Traffic Ops password")
+	includeOffline := flag.Bool("includeOffline", false, "Whether to include Offline Monitors")
+	grace := flag.Duration("grace", time.Duration(0), "How long a cache or delivery service must disagree across monitors before it's reported. The check will poll for up to this long")
+	interval := flag.Duration("interval", time.Second*time.Duration(5), "The interval to re-poll monitors within the grace period")
+	deadline := flag.Duration("deadline", time.Second*time.Duration(50), "How long the check may run in total, before it exits Unknown. Inconsistencies which keep clearing and reappearing never exceed the grace period, so this keeps the check within the Nagios plugin timeout")
+	help := flag.Bool("help", false, "Usage info")
+	helpBrief := flag.Bool("h", false, "Usage info")
+	flag.Parse()
+	end := time.Now().Add(*deadline)
+	if *help || *helpBrief || *toURI == "" {
+		fmt.Printf("Usage: ./nagios-validate-consistency -to https://traffic-ops.example.net -touser bill -topass thelizard -includeOffline true -grace 30s -interval 5s -deadline 50s\n")
+		return
+	}
+
+	toClient, err := to.LoginWithAgent(*toURI, *toUser, *toPass, true, UserAgent, false, tmcheck.RequestTimeout)
+	if err != nil {
+		fmt.Printf("Error logging in to Traffic Ops: %v\n", err)
+		return
+	}
+
+	tracker := tmcheck.NewInconsistencyTracker()
+	for {
+		inconsistencies, monitorErrs, err := tmcheck.GetAllCRStatesInconsistencies(toClient, *includeOffline)
+		if err != nil {
+			nagios.Exit(nagios.Critical, fmt.Sprintf("Error validating monitor consistency: %v", err))
+		}
+
+		errStr := ""
+		for monitor, err := range monitorErrs {
+			errStr += fmt.Sprintf("error validating consistency for monitor %v : %v\n", monitor, err.Error())
+		}
+
+		persistent := tracker.Update(inconsistencies, time.Now(), *grace)
+		for _, inconsistency := range persistent {
+			errStr += fmt.Sprintf("inconsistent %v\n", inconsistency.String())
+		}
+
+		if errStr != "" {
+			nagios.Exit(nagios.Critical, errStr)
+		}
+
+		if len(inconsistencies) == 0 {
+			nagios.Exit(nagios.Ok, "")
+		}
+
+		if time.Now().Add(*interval).After(end) {
+			nagios.Exit(nagios.Unknown, fmt.Sprintf("%v inconsistencies still present after the %v deadline, none persisting longer than the %v grace period", len(inconsistencies), *deadline, *grace))
+		}
+		time.Sleep(*interval)
+	}
+}
@@ -17,33 +17,58 @@
  * under the License.
  */
 
-// validate-offline is a utility HTTP service which polls the given Traffic Monitor and validates that no OFFLINE or ADMIN_DOWN caches in the Traffic Ops CRConfig are marked Available in Traffic Monitor's CRstates endpoint.
+// validator-service is a long-running utility HTTP service which continuously runs all tmcheck validators against every Traffic Monitor in Traffic Ops, keeps a history of their results, and serves them as HTML, JSON, and Prometheus metrics.
+//
+// Endpoints:
+//   /                      HTML status page
+//   /api/validators        JSON status of every validator, for every monitor
+//   /api/monitors          JSON list of all known monitors
+//   /api/monitor/{name}    JSON drilldown of every validator's status and history for a single monitor
+//   /metrics               Prometheus text exposition of validator status
 
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
-	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
-	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/tmcheck"
-	to "github.com/apache/incubator-trafficcontrol/traffic_ops/client"
 	"io"
 	"net/http"
 	"sort"
+	"strings"
 	"sync"
 	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/enum"
+	"github.com/apache/incubator-trafficcontrol/traffic_monitor_golang/traffic_monitor/tmcheck"
+	to "github.com/apache/incubator-trafficcontrol/traffic_ops/client"
 )
 
-const UserAgent = "tm-offline-validator/0.1"
+const UserAgent = "tm-validator-service/0.2"
+
+const DefaultLogLimit = 10
+
+const DefaultHistoryLimit = 100
 
-const LogLimit = 10
+const MonitorPathPrefix = "/api/monitor/"
+
+// CheckResult is the result of a single validator check, for a single monitor.
+type CheckResult struct {
+	Time  time.Time `json:"time"`
+	Valid bool      `json:"valid"`
+	Error string    `json:"error,omitempty"`
+}
 
 type Log struct {
-	log       *[]string
-	limit     int
-	errored   *bool
-	lastCheck *time.Time
-	m         *sync.RWMutex
+	log          *[]string
+	history      *[]CheckResult
+	limit        int
+	historyLimit int
+	errored      *bool
+	lastCheck    *time.Time
+	checks       *uint64
+	failures     *uint64
+	m            *sync.RWMutex
 }
 
 func (l *Log) Add(msg string) {
@@ -61,12 +86,28 @@ func (l *Log) Get() []string {
 	return *l.log
 }
 
+// GetHistory returns a copy of the check history, newest first.
+func (l *Log) GetHistory() []CheckResult {
+	l.m.RLock()
+	defer l.m.RUnlock()
+	history := make([]CheckResult, len(*l.history))
+	copy(history, *l.history)
+	return history
+}
+
 func (l *Log) GetErrored() (bool, time.Time) {
 	l.m.RLock()
 	defer l.m.RUnlock()
 	return *l.errored, *l.lastCheck
 }
 
+// GetCounts returns the total number of checks, and the number of those which failed.
+func (l *Log) GetCounts() (uint64, uint64) {
+	l.m.RLock()
+	defer l.m.RUnlock()
+	return *l.checks, *l.failures
+}
+
 func (l *Log) SetErrored(e bool) {
 	l.m.Lock()
 	defer l.m.Unlock()
@@ -74,32 +115,63 @@ func (l *Log) SetErrored(e bool) {
 	*l.lastCheck = time.Now()
 }
 
-func NewLog() Log {
-	log := make([]string, 0, LogLimit+1)
+// AddCheck records the result of a check, setting the errored state and appending to the history.
+func (l *Log) AddCheck(err error) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	now := time.Now()
+	result := CheckResult{Time: now, Valid: err == nil}
+	if err != nil {
+		result.Error = strings.TrimRight(err.Error(), "\n")
+		*l.failures++
+	}
+	*l.checks++
+	*l.errored = err != nil
+	*l.lastCheck = now
+	*l.history = append([]CheckResult{result}, *l.history...)
+	if len(*l.history) > l.historyLimit {
+		*l.history = (*l.history)[:l.historyLimit]
+	}
+}
+
+func NewLog(limit int, historyLimit int) Log {
+	log := make([]string, 0, limit+1)
+	history := make([]CheckResult, 0, historyLimit+1)
 	errored := false
-	limit := LogLimit
 	lastCheck := time.Time{}
-	return Log{log: &log, errored: &errored, m: &sync.RWMutex{}, limit: limit, lastCheck: &lastCheck}
+	checks := uint64(0)
+	failures := uint64(0)
+	return Log{log: &log, history: &history, errored: &errored, m: &sync.RWMutex{}, limit: limit, historyLimit: historyLimit, lastCheck: &lastCheck, checks: &checks, failures: &failures}
 }
 
 type Logs struct {
-	logs map[enum.TrafficMonitorName]Log
-	m    *sync.RWMutex
+	logs         map[enum.TrafficMonitorName]Log
+	limit        int
+	historyLimit int
+	m            *sync.RWMutex
 }
 
-func NewLogs() Logs {
-	return Logs{logs: map[enum.TrafficMonitorName]Log{}, m: &sync.RWMutex{}}
+func NewLogs(limit int, historyLimit int) Logs {
+	return Logs{logs: map[enum.TrafficMonitorName]Log{}, limit: limit, historyLimit: historyLimit, m: &sync.RWMutex{}}
 }
 
 func (l Logs) Get(name enum.TrafficMonitorName) Log {
 	l.m.Lock()
 	defer l.m.Unlock()
 	if _, ok := l.logs[name]; !ok {
-		l.logs[name] = NewLog()
+		l.logs[name] = NewLog(l.limit, l.historyLimit)
 	}
 	return l.logs[name]
 }
 
+// Has returns whether the given monitor has been validated by this validator.
+func (l Logs) Has(name enum.TrafficMonitorName) bool {
+	l.m.RLock()
+	defer l.m.RUnlock()
+	_, ok := l.logs[name]
+	return ok
+}
+
 func (l Logs) GetMonitors() []string {
 	l.m.RLock()
 	defer l.m.RUnlock()
@@ -110,8 +182,18 @@ func (l Logs) GetMonitors() []string {
 	return monitors
 }
 
-func startValidator(validator tmcheck.AllValidatorFunc, toClient *to.Session, interval time.Duration, includeOffline bool, grace time.Duration) Logs {
-	logs := NewLogs()
+// Validator is a tmcheck validator, run on its own schedule, with the logs of its results.
+type Validator struct {
+	Name        string
+	Title       string
+	Description string
+	Interval    time.Duration
+	Func        tmcheck.AllValidatorFunc
+	Logs        Logs
+}
+
+func startValidator(validator tmcheck.AllValidatorFunc, toClient *to.Session, interval time.Duration, includeOffline bool, grace time.Duration, logLimit int, historyLimit int) Logs {
+	logs := NewLogs(logLimit, historyLimit)
 
 	onErr := func(name enum.TrafficMonitorName, err error) {
 		log := logs.Get(name)
@@ -127,25 +209,41 @@ func startValidator(validator tmcheck.AllValidatorFunc, toClient *to.Session, in
 
 	onCheck := func(name enum.TrafficMonitorName, err error) {
 		log := logs.Get(name)
-		log.SetErrored(err != nil)
+		log.AddCheck(err)
 	}
 
 	go validator(toClient, interval, includeOffline, grace, onErr, onResumeSuccess, onCheck)
 	return logs
 }
 
+// intervalOr returns the given interval if it's nonzero, else the default.
+func intervalOr(interval time.Duration, def time.Duration) time.Duration {
+	if interval == 0 {
+		return def
+	}
+	return interval
+}
+
 func main() {
 	toURI := flag.String("to", "", "The Traffic Ops URI, whose CRConfig to validate")
 	toUser := flag.String("touser", "", "The Traffic Ops user")
 	toPass := flag.String("topass", "", "The Traffic Ops password")
-	interval := flag.Duration("interval", time.Second*time.Duration(5), "The interval to validate")
+	interval := flag.Duration("interval", time.Second*time.Duration(5), "The default interval to validate")
+	offlineInterval := flag.Duration("offlineInterval", 0, "The interval to validate CRStates Offline. Defaults to -interval")
+	peerPollerInterval := flag.Duration("peerPollerInterval", 0, "The interval to validate Peer Pollers. Defaults to -interval")
+	dsStatsInterval := flag.Duration("dsStatsInterval", 0, "The interval to validate Delivery Service Stats. Defaults to -interval")
+	queryIntervalInterval := flag.Duration("queryIntervalInterval", 0, "The interval to validate Query Intervals. Defaults to -interval")
+	consistencyInterval := flag.Duration("consistencyInterval", 0, "The interval to validate CRStates Consistency across monitors. Defaults to -interval")
 	grace := flag.Duration("grace", time.Second*time.Duration(30), "The grace period before invalid states are reported")
 	includeOffline := flag.Bool("includeOffline", false, "Whether to include Offline Monitors")
+	logLimit := flag.Int("logLimit", DefaultLogLimit, "The number of error and resume messages to keep, per validator per monitor")
+	historyLimit := flag.Int("historyLimit", DefaultHistoryLimit, "The number of check results to keep, per validator per monitor")
+	port := flag.Int("port", 80, "The port to serve on")
 	help := flag.Bool("help", false, "Usage info")
 	helpBrief := flag.Bool("h", false, "Usage info")
 	flag.Parse()
 	if *help || *helpBrief {
-		fmt.Printf("Usage: go run validate-offline -to https://traffic-ops.example.net -touser bill -topass thelizard -tm http://traffic-monitor.example.net -interval 5s -grace 30s -includeOffline true\n")
+		fmt.Printf("Usage: go run validator-service.go -to https://traffic-ops.example.net -touser bill -topass thelizard -interval 5s -dsStatsInterval 30s -grace 30s -includeOffline true -historyLimit 100 -port 80\n")
 		return
 	}
 
@@ -155,12 +253,49 @@ func main() {
 		return
 	}
 
-	crStatesOfflineLogs := startValidator(tmcheck.AllMonitorsCRStatesOfflineValidator, toClient, *interval, *includeOffline, *grace)
-	peerPollerLogs := startValidator(tmcheck.PeerPollersAllValidator, toClient, *interval, *includeOffline, *grace)
-	dsStatsLogs := startValidator(tmcheck.AllMonitorsDSStatsValidator, toClient, *interval, *includeOffline, *grace)
-	queryIntervalLogs := startValidator(tmcheck.AllMonitorsQueryIntervalValidator, toClient, *interval, *includeOffline, *grace)
+	validators := []*Validator{
+		&Validator{
+			Name:        "offline",
+			Title:       "CRStates Offline",
+			Description: "validates all OFFLINE and ADMIN_DOWN caches in the CRConfig are Unavailable",
+			Interval:    intervalOr(*offlineInterval, *interval),
+			Func:        tmcheck.AllMonitorsCRStatesOfflineValidator,
+		},
+		&Validator{
+			Name:        "peerpoller",
+			Title:       "Peer Poller",
+			Description: fmt.Sprintf("validates all peers in the CRConfig have been polled within the last %v", tmcheck.PeerPollMax),
+			Interval:    intervalOr(*peerPollerInterval, *interval),
+			Func:        tmcheck.PeerPollersAllValidator,
+		},
+		&Validator{
+			Name:        "deliveryservices",
+			Title:       "Delivery Services",
+			Description: "validates all Delivery Services in the CRConfig exist in DsStats",
+			Interval:    intervalOr(*dsStatsInterval, *interval),
+			Func:        tmcheck.AllMonitorsDSStatsValidator,
+		},
+		&Validator{
+			Name:        "queryinterval",
+			Title:       "Query Interval",
+			Description: fmt.Sprintf("validates all Monitors' Query Interval (95th percentile) is less than %v", tmcheck.QueryIntervalMax),
+			Interval:    intervalOr(*queryIntervalInterval, *interval),
+			Func:        tmcheck.AllMonitorsQueryIntervalValidator,
+		},
+		&Validator{
+			Name:        "consistency",
+			Title:       "CRStates Consistency",
+			Description: "validates no cache or Delivery Service availability disagrees across monitors of the same CDN for longer than the grace period",
+			Interval:    intervalOr(*consistencyInterval, *interval),
+			Func:        tmcheck.AllMonitorsCRStatesConsistencyValidator,
+		},
+	}
+
+	for _, validator := range validators {
+		validator.Logs = startValidator(validator.Func, toClient, validator.Interval, *includeOffline, *grace, *logLimit, *historyLimit)
+	}
 
-	if err := serve(*toURI, crStatesOfflineLogs, peerPollerLogs, dsStatsLogs, queryIntervalLogs); err != nil {
+	if err := serve(*toURI, *port, validators); err != nil {
 		fmt.Printf("Serve error: %v\n", err)
 	}
 }
@@ -175,7 +310,7 @@ func printLogs(logs Logs, w io.Writer) {
 
 		log := logs.Get(enum.TrafficMonitorName(monitor))
 
-		fmt.Fprintf(w, `<td><span>%s</span></td>`, monitor)
+		fmt.Fprintf(w, `<td><span><a href="%s%s">%s</a></span></td>`, MonitorPathPrefix, monitor, monitor)
 		errored, lastCheck := log.GetErrored()
 		if errored {
 			fmt.Fprintf(w, `<td><span style="color:red">Invalid</span></td>`)
@@ -200,7 +335,126 @@ func printLogs(logs Logs, w io.Writer) {
 	fmt.Fprintf(w, `</table>`)
 }
 
-func serve(toURI string, crStatesOfflineLogs Logs, peerPollerLogs Logs, dsStatsLogs Logs, queryIntervalLogs Logs) error {
+// MonitorStatus is the JSON status of a single validator for a single monitor.
+type MonitorStatus struct {
+	Valid     bool          `json:"valid"`
+	LastCheck time.Time     `json:"lastCheck"`
+	Checks    uint64        `json:"checks"`
+	Failures  uint64        `json:"failures"`
+	Messages  []string      `json:"messages"`
+	History   []CheckResult `json:"history,omitempty"`
+}
+
+func getMonitorStatus(log Log, includeHistory bool) MonitorStatus {
+	errored, lastCheck := log.GetErrored()
+	checks, failures := log.GetCounts()
+	status := MonitorStatus{Valid: !errored, LastCheck: lastCheck, Checks: checks, Failures: failures, Messages: log.Get()}
+	if includeHistory {
+		status.History = log.GetHistory()
+	}
+	return status
+}
+
+// ValidatorStatus is the JSON status of a single validator, for all monitors.
+type ValidatorStatus struct {
+	Name        string                                    `json:"name"`
+	Description string                                    `json:"description"`
+	IntervalMs  int64                                     `json:"intervalMs"`
+	Monitors    map[enum.TrafficMonitorName]MonitorStatus `json:"monitors"`
+}
+
+func getValidatorStatuses(validators []*Validator) []ValidatorStatus {
+	statuses := []ValidatorStatus{}
+	for _, validator := range validators {
+		status := ValidatorStatus{
+			Name:        validator.Name,
+			Description: validator.Description,
+			IntervalMs:  int64(validator.Interval / time.Millisecond),
+			Monitors:    map[enum.TrafficMonitorName]MonitorStatus{},
+		}
+		for _, monitor := range validator.Logs.GetMonitors() {
+			name := enum.TrafficMonitorName(monitor)
+			status.Monitors[name] = getMonitorStatus(validator.Logs.Get(name), false)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// getMonitors returns the sorted names of all monitors known to any validator.
+func getMonitors(validators []*Validator) []string {
+	monitorSet := map[string]struct{}{}
+	for _, validator := range validators {
+		for _, monitor := range validator.Logs.GetMonitors() {
+			monitorSet[monitor] = struct{}{}
+		}
+	}
+	monitors := []string{}
+	for monitor, _ := range monitorSet {
+		monitors = append(monitors, monitor)
+	}
+	sort.Strings(monitors)
+	return monitors
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(bytes)
+}
+
+// promEscape escapes the given string for use as a Prometheus label value.
+func promEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func printMetrics(validators []*Validator, w io.Writer) {
+	type metric struct {
+		name string
+		help string
+		typ  string
+		val  func(log Log) float64
+	}
+	metrics := []metric{
+		{"tmcheck_valid", "Whether the monitor passed the validator's last check.", "gauge", func(log Log) float64 {
+			if errored, _ := log.GetErrored(); errored {
+				return 0
+			}
+			return 1
+		}},
+		{"tmcheck_last_check_timestamp_seconds", "The Unix time of the validator's last check of the monitor.", "gauge", func(log Log) float64 {
+			_, lastCheck := log.GetErrored()
+			return float64(lastCheck.UnixNano()) / float64(time.Second)
+		}},
+		{"tmcheck_checks_total", "The number of times the validator has checked the monitor.", "counter", func(log Log) float64 {
+			checks, _ := log.GetCounts()
+			return float64(checks)
+		}},
+		{"tmcheck_failures_total", "The number of times the monitor has failed the validator's check.", "counter", func(log Log) float64 {
+			_, failures := log.GetCounts()
+			return float64(failures)
+		}},
+	}
+
+	for _, m := range metrics {
+		fmt.Fprintf(w, "# HELP %s %s\n", m.name, m.help)
+		fmt.Fprintf(w, "# TYPE %s %s\n", m.name, m.typ)
+		for _, validator := range validators {
+			monitors := validator.Logs.GetMonitors()
+			sort.Strings(monitors)
+			for _, monitor := range monitors {
+				log := validator.Logs.Get(enum.TrafficMonitorName(monitor))
+				fmt.Fprintf(w, "%s{validator=\"%s\",monitor=\"%s\"} %v\n", m.name, promEscape(validator.Name), promEscape(monitor), m.val(log))
+			}
+		}
+	}
+}
+
+func serve(toURI string, port int, validators []*Validator) error {
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Content-Type", "text/html")
@@ -216,21 +470,44 @@ func serve(toURI string, crStatesOfflineLogs Logs, peerPollerLogs Logs, dsStatsL
 		fmt.Fprintf(w, `<p>%s`, toURI)
 		fmt.Fprintf(w, `<p>%s`, time.Now())
 
-		fmt.Fprintf(w, `<h2>CRStates Offline</h2>`)
-		fmt.Fprintf(w, `<h3>validates all OFFLINE and ADMIN_DOWN caches in the CRConfig are Unavailable</h3>`)
-		printLogs(crStatesOfflineLogs, w)
+		for _, validator := range validators {
+			fmt.Fprintf(w, `<h2>%s</h2>`, validator.Title)
+			fmt.Fprintf(w, `<h3>%s, every %v</h3>`, validator.Description, validator.Interval)
+			printLogs(validator.Logs, w)
+		}
+	})
 
-		fmt.Fprintf(w, `<h2>Peer Poller</h2>`)
-		fmt.Fprintf(w, `<h3>validates all peers in the CRConfig have been polled within the last %v</h3>`, tmcheck.PeerPollMax)
-		printLogs(peerPollerLogs, w)
+	http.HandleFunc("/api/validators", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		writeJSON(w, getValidatorStatuses(validators))
+	})
 
-		fmt.Fprintf(w, `<h2>Delivery Services</h2>`)
-		fmt.Fprintf(w, `<h3>validates all Delivery Services in the CRConfig exist in DsStats</h3>`)
-		printLogs(dsStatsLogs, w)
+	http.HandleFunc("/api/monitors", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		writeJSON(w, getMonitors(validators))
+	})
+
+	http.HandleFunc(MonitorPathPrefix, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		name := enum.TrafficMonitorName(strings.TrimPrefix(r.URL.Path, MonitorPathPrefix))
+		statuses := map[string]MonitorStatus{}
+		for _, validator := range validators {
+			if !validator.Logs.Has(name) {
+				continue
+			}
+			statuses[validator.Name] = getMonitorStatus(validator.Logs.Get(name), true)
+		}
+		if len(statuses) == 0 {
+			http.Error(w, "Not Found", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, statuses)
+	})
 
-		fmt.Fprintf(w, `<h2>Query Interval</h2>`)
-		fmt.Fprintf(w, `<h3>validates all Monitors' Query Interval (95th percentile) is less than %v</h3>`, tmcheck.QueryIntervalMax)
-		printLogs(queryIntervalLogs, w)
+	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		printMetrics(validators, w)
 	})
-	return http.ListenAndServe(":80", nil)
+
+	return http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
 }
@@ -0,0 +1,84 @@
+package trafficopswrapper
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"sync"
+	"time"
+
+	to "github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+)
+
+// FetchStatus is the result of the most recent requests to Traffic Ops. FailingSince is the time of the first failure since the last success, and LastErr the most recent error; both are zero if the last request succeeded.
+type FetchStatus struct {
+	LastSuccess  time.Time
+	FailingSince time.Time
+	LastErr      error
+}
+
+// StatusSession wraps an ITrafficOpsSession, recording the FetchStatus of logging in, and of the requests the monitor polls Traffic Ops with, CRConfigRaw and TrafficMonitorConfigMap. This fulfills the ITrafficOpsSession interface, and is safe for multiple goroutines.
+type StatusSession struct {
+	ITrafficOpsSession
+	status *FetchStatus
+	m      *sync.RWMutex
+}
+
+// NewStatusSession returns a new StatusSession wrapping the given session.
+func NewStatusSession(s ITrafficOpsSession) StatusSession {
+	return StatusSession{ITrafficOpsSession: s, status: &FetchStatus{}, m: &sync.RWMutex{}}
+}
+
+// Status returns the status of the most recent request to Traffic Ops.
+func (s StatusSession) Status() FetchStatus {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	return *s.status
+}
+
+func (s StatusSession) record(err error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if err == nil {
+		*s.status = FetchStatus{LastSuccess: time.Now()}
+		return
+	}
+	if s.status.FailingSince.IsZero() {
+		s.status.FailingSince = time.Now()
+	}
+	s.status.LastErr = err
+}
+
+func (s StatusSession) Login(url, user, pass string, insecure bool, userAgent string, useCache bool, timeout time.Duration) error {
+	err := s.ITrafficOpsSession.Login(url, user, pass, insecure, userAgent, useCache, timeout)
+	s.record(err)
+	return err
+}
+
+func (s StatusSession) CRConfigRaw(cdn string) ([]byte, error) {
+	b, err := s.ITrafficOpsSession.CRConfigRaw(cdn)
+	s.record(err)
+	return b, err
+}
+
+func (s StatusSession) TrafficMonitorConfigMap(cdn string) (*to.TrafficMonitorConfigMap, error) {
+	mc, err := s.ITrafficOpsSession.TrafficMonitorConfigMap(cdn)
+	s.record(err)
+	return mc, err
+}
@@ -20,6 +20,7 @@ package trafficopswrapper
  */
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"sync"
@@ -36,6 +37,7 @@ type ITrafficOpsSession interface {
 	LastCRConfig(cdn string) ([]byte, time.Time, error)
 	TrafficMonitorConfigMap(cdn string) (*to.TrafficMonitorConfigMap, error)
 	Set(session *to.Session)
+	Login(url, user, pass string, insecure bool, userAgent string, useCache bool, timeout time.Duration) error
 	URL() (string, error)
 	User() (string, error)
 	Servers() ([]to.Server, error)
@@ -96,6 +98,16 @@ func (s TrafficOpsSessionThreadsafe) Set(session *to.Session) {
 	*s.session = session
 }
 
+// Login logs in to the given Traffic Ops, and sets the internal Traffic Ops session to the new session. If login fails, the existing session is unchanged.
+func (s TrafficOpsSessionThreadsafe) Login(url, user, pass string, insecure bool, userAgent string, useCache bool, timeout time.Duration) error {
+	session, err := to.LoginWithAgent(url, user, pass, insecure, userAgent, useCache, timeout)
+	if err != nil {
+		return err
+	}
+	s.Set(session)
+	return nil
+}
+
 // getThreadsafeSession is used internally to get a copy of the session pointer, or nil if it doesn't exist. This should not be used outside TrafficOpsSessionThreadsafe, and never stored, because part of the purpose of TrafficOpsSessionThreadsafe is to store a pointer to the Session pointer, so it can be updated by one goroutine and immediately used by another. This should only be called immediately before using the session, since someone else may update it concurrently.
 func (s TrafficOpsSessionThreadsafe) get() *to.Session {
 	s.m.Lock()
@@ -128,7 +140,7 @@ func (s TrafficOpsSessionThreadsafe) CRConfigRaw(cdn string) ([]byte, error) {
 	if ss == nil {
 		return nil, ErrNilSession
 	}
-	b, _, err := ss.GetCRConfig(cdn)
+	b, _, err := ss.GetCRConfig(context.Background(), cdn)
 	if err == nil {
 		s.lastCRConfig.Set(cdn, b)
 	}
@@ -151,7 +163,7 @@ func (s TrafficOpsSessionThreadsafe) trafficMonitorConfigMapRaw(cdn string) (*to
 	if ss == nil {
 		return nil, ErrNilSession
 	}
-	return ss.TrafficMonitorConfigMap(cdn)
+	return ss.TrafficMonitorConfigMap(context.Background(), cdn)
 }
 
 // TrafficMonitorConfigMap returns the Traffic Monitor config map from the Traffic Ops. This is safe for multiple goroutines.
@@ -307,7 +319,7 @@ func (s TrafficOpsSessionThreadsafe) Servers() ([]to.Server, error) {
 	if ss == nil {
 		return nil, ErrNilSession
 	}
-	return ss.Servers()
+	return ss.Servers(context.Background())
 }
 
 func (s TrafficOpsSessionThreadsafe) Profiles() ([]to.Profile, error) {
@@ -315,7 +327,7 @@ func (s TrafficOpsSessionThreadsafe) Profiles() ([]to.Profile, error) {
 	if ss == nil {
 		return nil, ErrNilSession
 	}
-	return ss.Profiles()
+	return ss.Profiles(context.Background())
 }
 
 func (s TrafficOpsSessionThreadsafe) Parameters(profileName string) ([]to.Parameter, error) {
@@ -323,7 +335,7 @@ func (s TrafficOpsSessionThreadsafe) Parameters(profileName string) ([]to.Parame
 	if ss == nil {
 		return nil, ErrNilSession
 	}
-	return ss.Parameters(profileName)
+	return ss.Parameters(context.Background(), profileName)
 }
 
 func (s TrafficOpsSessionThreadsafe) DeliveryServices() ([]to.DeliveryService, error) {
@@ -331,7 +343,7 @@ func (s TrafficOpsSessionThreadsafe) DeliveryServices() ([]to.DeliveryService, e
 	if ss == nil {
 		return nil, ErrNilSession
 	}
-	return ss.DeliveryServices()
+	return ss.DeliveryServices(context.Background())
 }
 
 func (s TrafficOpsSessionThreadsafe) CacheGroups() ([]to.CacheGroup, error) {
@@ -339,5 +351,5 @@ func (s TrafficOpsSessionThreadsafe) CacheGroups() ([]to.CacheGroup, error) {
 	if ss == nil {
 		return nil, ErrNilSession
 	}
-	return ss.CacheGroups()
+	return ss.CacheGroups(context.Background())
 }
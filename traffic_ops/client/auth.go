@@ -0,0 +1,303 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// AuthMethod is how a Session authenticates to Traffic Ops.
+type AuthMethod string
+
+const (
+	// AuthCookie logs in with the user and password to /api/1.2/user/login, which sets a session cookie. It is the default.
+	AuthCookie = AuthMethod("cookie")
+	// AuthToken logs in with the user and password to the Session's TokenLoginPath, which returns a JWT, sent as a bearer token. The Session logs in again before the token expires.
+	AuthToken = AuthMethod("token")
+	// AuthAPIKey sends the Session's APIKey as a bearer token, and never logs in. It is for service accounts.
+	AuthAPIKey = AuthMethod("apikey")
+)
+
+// DefaultTokenLoginPath is the path AuthToken Sessions log in to, by default. It is the login of the Traffic Ops 2.0 API.
+const DefaultTokenLoginPath = "/api/2.0/login"
+
+// DefaultTokenRefreshBefore is how long before its token expires an AuthToken Session logs in again, by default.
+const DefaultTokenRefreshBefore = 5 * time.Minute
+
+// Environment variables read by AuthConfigFromEnv.
+const (
+	EnvURL          = "TRAFFIC_OPS_URL"
+	EnvUser         = "TRAFFIC_OPS_USER"
+	EnvPassword     = "TRAFFIC_OPS_PASSWORD"
+	EnvPasswordFile = "TRAFFIC_OPS_PASSWORD_FILE"
+	EnvAPIKey       = "TRAFFIC_OPS_API_KEY"
+	EnvAuth         = "TRAFFIC_OPS_AUTH"
+)
+
+// AuthConfig is the URL of a Traffic Ops and the credentials to authenticate with, so consumers can read credentials from the environment or a file, rather than each requiring a password in its own config. PasswordFile is the path of a file containing only the password, e.g. a container secret, which is used if Password is empty. If Auth is empty, AuthAPIKey is used if there's an APIKey, and AuthCookie otherwise.
+type AuthConfig struct {
+	URL          string     `json:"url"`
+	User         string     `json:"user"`
+	Password     string     `json:"password"`
+	PasswordFile string     `json:"passwordFile"`
+	APIKey       string     `json:"apiKey"`
+	Auth         AuthMethod `json:"auth"`
+}
+
+// AuthConfigFromEnv returns the AuthConfig in the TRAFFIC_OPS_ environment variables. Unset variables are empty.
+func AuthConfigFromEnv() AuthConfig {
+	return AuthConfig{
+		URL:          os.Getenv(EnvURL),
+		User:         os.Getenv(EnvUser),
+		Password:     os.Getenv(EnvPassword),
+		PasswordFile: os.Getenv(EnvPasswordFile),
+		APIKey:       os.Getenv(EnvAPIKey),
+		Auth:         AuthMethod(os.Getenv(EnvAuth)),
+	}
+}
+
+// ReadCredentialsFile reads the file at the given path, which contains credentials, returning an error if the file is readable or writable by group or others. A missing file is an error for which os.IsNotExist is true.
+func ReadCredentialsFile(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("credentials file %v has permissions %v, must not be accessible by group or others", path, info.Mode().Perm())
+	}
+	return ioutil.ReadFile(path)
+}
+
+// LoadAuthConfig reads the AuthConfig JSON file at the given path. Because it contains credentials, the file must not be readable or writable by group or others. A relative PasswordFile is relative to the file's directory.
+func LoadAuthConfig(path string) (AuthConfig, error) {
+	cfg := AuthConfig{}
+	b, err := ReadCredentialsFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("parsing credentials file %v: %v", path, err)
+	}
+	if cfg.PasswordFile != "" && !filepath.IsAbs(cfg.PasswordFile) {
+		cfg.PasswordFile = filepath.Join(filepath.Dir(path), cfg.PasswordFile)
+	}
+	return cfg, nil
+}
+
+// Override returns the config with the fields which are set in o replacing its own. A Password in o also replaces a PasswordFile, and vice versa.
+func (c AuthConfig) Override(o AuthConfig) AuthConfig {
+	if o.URL != "" {
+		c.URL = o.URL
+	}
+	if o.User != "" {
+		c.User = o.User
+	}
+	if o.Password != "" {
+		c.Password = o.Password
+		c.PasswordFile = ""
+	}
+	if o.PasswordFile != "" {
+		c.PasswordFile = o.PasswordFile
+		c.Password = ""
+	}
+	if o.APIKey != "" {
+		c.APIKey = o.APIKey
+	}
+	if o.Auth != "" {
+		c.Auth = o.Auth
+	}
+	return c
+}
+
+// method returns the AuthMethod of the config.
+func (c AuthConfig) method() (AuthMethod, error) {
+	switch c.Auth {
+	case AuthCookie, AuthToken, AuthAPIKey:
+		return c.Auth, nil
+	case "":
+		if c.APIKey != "" {
+			return AuthAPIKey, nil
+		}
+		return AuthCookie, nil
+	}
+	return "", fmt.Errorf("unknown auth method '%v', must be %v, %v or %v", c.Auth, AuthCookie, AuthToken, AuthAPIKey)
+}
+
+// password returns the config's Password, or the contents of its PasswordFile, without trailing newlines.
+func (c AuthConfig) password() (string, error) {
+	if c.Password != "" || c.PasswordFile == "" {
+		return c.Password, nil
+	}
+	b, err := ioutil.ReadFile(c.PasswordFile)
+	if err != nil {
+		return "", fmt.Errorf("reading password file: %v", err)
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// LoginWithAuthConfig returns a Session authenticated with the given config. AuthCookie and AuthToken Sessions log in; AuthAPIKey Sessions make no request until they're used.
+func LoginWithAuthConfig(ctx context.Context, cfg AuthConfig, insecure bool, userAgent string, useCache bool, requestTimeout time.Duration) (*Session, error) {
+	method, err := cfg.method()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("no Traffic Ops URL")
+	}
+	password, err := cfg.password()
+	if err != nil {
+		return nil, err
+	}
+	if method == AuthAPIKey && cfg.APIKey == "" {
+		return nil, fmt.Errorf("no API key")
+	}
+	if method != AuthAPIKey && cfg.User == "" {
+		return nil, fmt.Errorf("no Traffic Ops user")
+	}
+
+	client, err := newHTTPClient(insecure, requestTimeout)
+	if err != nil {
+		return nil, err
+	}
+	to := NewSession(cfg.User, password, cfg.URL, userAgent, client, useCache)
+	to.Auth = method
+	to.APIKey = cfg.APIKey
+	if method == AuthAPIKey {
+		return to, nil
+	}
+	if err := to.login(ctx); err != nil {
+		return nil, err
+	}
+	return to, nil
+}
+
+// LoginWithToken returns a Session which logs in with the given user and password for a bearer token, see AuthToken.
+func LoginWithToken(ctx context.Context, toURL string, toUser string, toPasswd string, insecure bool, userAgent string, useCache bool, requestTimeout time.Duration) (*Session, error) {
+	return LoginWithAuthConfig(ctx, AuthConfig{URL: toURL, User: toUser, Password: toPasswd, Auth: AuthToken}, insecure, userAgent, useCache, requestTimeout)
+}
+
+// NewSessionWithAPIKey returns a Session which authenticates with the given API key, see AuthAPIKey.
+func NewSessionWithAPIKey(toURL string, apiKey string, insecure bool, userAgent string, useCache bool, requestTimeout time.Duration) (*Session, error) {
+	return LoginWithAuthConfig(context.Background(), AuthConfig{URL: toURL, APIKey: apiKey, Auth: AuthAPIKey}, insecure, userAgent, useCache, requestTimeout)
+}
+
+// bearerToken is a token from a token login, and when it expires. Expires is zero if the token doesn't say.
+type bearerToken struct {
+	Value   string
+	Expires time.Time
+}
+
+// tokenLoginResponse is the response of a token login. Traffic Ops 2.0 returns `{"Token": "..."}`.
+type tokenLoginResponse struct {
+	Token string `json:"token"`
+}
+
+// isLoginPath returns whether the path is one the Session logs in to, whose Unauthorized responses mean the credentials are wrong, not that the session expired.
+func (to *Session) isLoginPath(path string) bool {
+	return path == loginPath || path == to.tokenLoginPath()
+}
+
+func (to *Session) tokenLoginPath() string {
+	if to.TokenLoginPath == "" {
+		return DefaultTokenLoginPath
+	}
+	return to.TokenLoginPath
+}
+
+// loginToken logs in with the Session's user and password for a bearer token.
+func (to *Session) loginToken(ctx context.Context) error {
+	credentials, err := loginCreds(to.UserName, to.Password)
+	if err != nil {
+		return err
+	}
+	resp, err := to.send(ctx, http.MethodPost, to.tokenLoginPath(), credentials, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data := tokenLoginResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return fmt.Errorf("decoding token login response: %v", err)
+	}
+	if data.Token == "" {
+		return fmt.Errorf("Login failed, no token in response")
+	}
+	to.token.Store(bearerToken{Value: data.Token, Expires: tokenExpiry(data.Token)})
+	return nil
+}
+
+// currentToken returns the Session's bearer token, which is empty if it hasn't logged in.
+func (to *Session) currentToken() bearerToken {
+	t, _ := to.token.Load().(bearerToken)
+	return t
+}
+
+// refreshToken logs in again if the Session's token expires within its TokenRefreshBefore. If logging in fails and the token hasn't expired yet, the token is still used, and the error is only returned once it has.
+func (to *Session) refreshToken(ctx context.Context) error {
+	generation := atomic.LoadUint64(to.loginGeneration)
+	t := to.currentToken()
+	if t.Expires.IsZero() || time.Now().Add(to.TokenRefreshBefore).Before(t.Expires) {
+		return nil
+	}
+	if err := to.relogin(ctx, generation); err != nil && !time.Now().Before(t.Expires) {
+		return fmt.Errorf("refreshing expired token: %v", err)
+	}
+	return nil
+}
+
+// authorization returns the Authorization header of the Session's requests, which is empty for AuthCookie.
+func (to *Session) authorization() string {
+	switch to.Auth {
+	case AuthAPIKey:
+		return "Bearer " + to.APIKey
+	case AuthToken:
+		if t := to.currentToken(); t.Value != "" {
+			return "Bearer " + t.Value
+		}
+	}
+	return ""
+}
+
+// tokenExpiry returns the expiration of the given JWT, from its `exp` claim, or zero if it isn't a JWT or has no expiration. The token isn't verified: Traffic Ops does that, and the Session only needs to know when to refresh it.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+	claims := struct {
+		Exp float64 `json:"exp"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(claims.Exp), 0)
+}
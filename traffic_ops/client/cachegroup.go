@@ -15,7 +15,11 @@
 
 package client
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
 
 // CacheGroupResponse ...
 type CacheGroupResponse struct {
@@ -23,30 +27,101 @@ type CacheGroupResponse struct {
 }
 
 // CacheGroup contains information about a given Cachegroup in Traffic Ops.
+// When creating or updating, the parents and type are given by ID, in ParentID, SecondaryParentID and TypeID; the names are ignored.
 type CacheGroup struct {
-	Name        string  `json:"name"`
-	ShortName   string  `json:"shortName"`
-	Latitude    float64 `json:"latitude"`
-	Longitude   float64 `json:"longitude"`
-	ParentName  string  `json:"parentCachegroupName,omitempty"`
-	Type        string  `json:"typeName,omitempty"`
-	LastUpdated string  `json:"lastUpdated,omitempty"`
+	ID                  int     `json:"id,omitempty"`
+	Name                string  `json:"name"`
+	ShortName           string  `json:"shortName"`
+	Latitude            float64 `json:"latitude"`
+	Longitude           float64 `json:"longitude"`
+	ParentID            int     `json:"parentCachegroupId,omitempty"`
+	ParentName          string  `json:"parentCachegroupName,omitempty"`
+	SecondaryParentID   int     `json:"secondaryParentCachegroupId,omitempty"`
+	SecondaryParentName string  `json:"secondaryParentCachegroupName,omitempty"`
+	TypeID              int     `json:"typeId,omitempty"`
+	Type                string  `json:"typeName,omitempty"`
+	LastUpdated         string  `json:"lastUpdated,omitempty"`
+}
+
+// CreateCacheGroupResponse is the JSON object returned when a CacheGroup is created
+type CreateCacheGroupResponse struct {
+	Response CacheGroup `json:"response"`
+	Alerts   []Alert    `json:"alerts"`
+}
+
+// UpdateCacheGroupResponse is the JSON object returned when a CacheGroup is updated
+type UpdateCacheGroupResponse struct {
+	Response CacheGroup `json:"response"`
+	Alerts   []Alert    `json:"alerts"`
+}
+
+// DeleteCacheGroupResponse is the JSON object returned when a CacheGroup is deleted
+type DeleteCacheGroupResponse struct {
+	Alerts []Alert `json:"alerts"`
 }
 
 // CacheGroups gets the CacheGroups in an array of CacheGroup structs
 // (note CacheGroup used to be called location)
-func (to *Session) CacheGroups() ([]CacheGroup, error) {
-	url := "/api/1.2/cachegroups.json"
-	resp, err := to.request("GET", url, nil)
+func (to *Session) CacheGroups(ctx context.Context) ([]CacheGroup, error) {
+	return to.ListCacheGroups(ctx, ListOptions{})
+}
+
+// ListCacheGroups gets an array of the CacheGroups matching the given options
+func (to *Session) ListCacheGroups(ctx context.Context, opts ListOptions) ([]CacheGroup, error) {
+	var data CacheGroupResponse
+	err := get(ctx, to, opts.endpoint(cacheGroupsEp()+".json"), &data)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var data CacheGroupResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+	return data.Response, nil
+}
+
+// CreateCacheGroup creates the CacheGroup it's passed
+func (to *Session) CreateCacheGroup(ctx context.Context, cg *CacheGroup) (*CreateCacheGroupResponse, error) {
+	var data CreateCacheGroupResponse
+	jsonReq, err := json.Marshal(cg)
+	if err != nil {
+		return nil, err
+	}
+	err = post(ctx, to, cacheGroupsEp(), jsonReq, &data)
+	if err != nil {
 		return nil, err
 	}
 
-	return data.Response, nil
+	return &data, nil
+}
+
+// UpdateCacheGroup updates the CacheGroup matching the ID it's passed with the CacheGroup it is passed
+func (to *Session) UpdateCacheGroup(ctx context.Context, id int, cg *CacheGroup) (*UpdateCacheGroupResponse, error) {
+	var data UpdateCacheGroupResponse
+	jsonReq, err := json.Marshal(cg)
+	if err != nil {
+		return nil, err
+	}
+	err = put(ctx, to, cacheGroupEp(id), jsonReq, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// DeleteCacheGroup deletes the CacheGroup matching the ID it's passed. Traffic Ops refuses to delete a CacheGroup which has servers, or is the parent of another CacheGroup.
+func (to *Session) DeleteCacheGroup(ctx context.Context, id int) (*DeleteCacheGroupResponse, error) {
+	var data DeleteCacheGroupResponse
+	err := del(ctx, to, cacheGroupEp(id), &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func cacheGroupsEp() string {
+	return apiBase + "/cachegroups"
+}
+
+func cacheGroupEp(id int) string {
+	return cacheGroupsEp() + "/" + strconv.Itoa(id)
 }
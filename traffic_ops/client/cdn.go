@@ -16,8 +16,10 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 )
 
 // CDNResponse ...
@@ -27,31 +29,50 @@ type CDNResponse struct {
 
 // CDN ...
 type CDN struct {
-	ID          int    `json:"id"`
-	Name        string `json:"name"`
-	LastUpdated string `json:"lastUpdated"`
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	DomainName    string `json:"domainName,omitempty"`
+	DNSSECEnabled bool   `json:"dnssecEnabled"`
+	LastUpdated   string `json:"lastUpdated"`
+}
+
+// CreateCDNResponse is the JSON object returned when a CDN is created
+type CreateCDNResponse struct {
+	Response CDN     `json:"response"`
+	Alerts   []Alert `json:"alerts"`
+}
+
+// UpdateCDNResponse is the JSON object returned when a CDN is updated
+type UpdateCDNResponse struct {
+	Response CDN     `json:"response"`
+	Alerts   []Alert `json:"alerts"`
+}
+
+// DeleteCDNResponse is the JSON object returned when a CDN is deleted
+type DeleteCDNResponse struct {
+	Alerts []Alert `json:"alerts"`
 }
 
 // CDNs gets an array of CDNs
-func (to *Session) CDNs() ([]CDN, error) {
-	url := "/api/1.2/cdns.json"
-	resp, err := to.request("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+func (to *Session) CDNs(ctx context.Context) ([]CDN, error) {
+	return to.ListCDNs(ctx, ListOptions{})
+}
 
+// ListCDNs gets an array of the CDNs matching the given options
+func (to *Session) ListCDNs(ctx context.Context, opts ListOptions) ([]CDN, error) {
 	var data CDNResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+	err := get(ctx, to, opts.endpoint(cdnsEp()+".json"), &data)
+	if err != nil {
 		return nil, err
 	}
+
 	return data.Response, nil
 }
 
 // CDNName gets an array of CDNs
-func (to *Session) CDNName(name string) ([]CDN, error) {
+func (to *Session) CDNName(ctx context.Context, name string) ([]CDN, error) {
 	url := fmt.Sprintf("/api/1.2/cdns/name/%s.json", name)
-	resp, err := to.request("GET", url, nil)
+	resp, err := to.request(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -64,3 +85,52 @@ func (to *Session) CDNName(name string) ([]CDN, error) {
 
 	return data.Response, nil
 }
+
+// CreateCDN creates the CDN it's passed. Traffic Ops requires the Name and DomainName.
+func (to *Session) CreateCDN(ctx context.Context, cdn *CDN) (*CreateCDNResponse, error) {
+	var data CreateCDNResponse
+	jsonReq, err := json.Marshal(cdn)
+	if err != nil {
+		return nil, err
+	}
+	err = post(ctx, to, cdnsEp(), jsonReq, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// UpdateCDN updates the CDN matching the ID it's passed with the CDN it is passed
+func (to *Session) UpdateCDN(ctx context.Context, id int, cdn *CDN) (*UpdateCDNResponse, error) {
+	var data UpdateCDNResponse
+	jsonReq, err := json.Marshal(cdn)
+	if err != nil {
+		return nil, err
+	}
+	err = put(ctx, to, cdnEp(id), jsonReq, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// DeleteCDN deletes the CDN matching the ID it's passed. Traffic Ops refuses to delete a CDN which has servers or delivery services.
+func (to *Session) DeleteCDN(ctx context.Context, id int) (*DeleteCDNResponse, error) {
+	var data DeleteCDNResponse
+	err := del(ctx, to, cdnEp(id), &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func cdnsEp() string {
+	return apiBase + "/cdns"
+}
+
+func cdnEp(id int) string {
+	return cdnsEp() + "/" + strconv.Itoa(id)
+}
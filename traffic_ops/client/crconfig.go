@@ -15,16 +15,26 @@
 
 package client
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // CRConfigRaw Deprecated: use GetCRConfig instead
-func (to *Session) CRConfigRaw(cdn string) ([]byte, error) {
-	bytes, _, err := to.GetCRConfig(cdn)
+func (to *Session) CRConfigRaw(ctx context.Context, cdn string) ([]byte, error) {
+	bytes, _, err := to.GetCRConfig(ctx, cdn)
 	return bytes, err
 }
 
-// GetCRConfig returns the raw JSON bytes of the CRConfig from Traffic Ops, and whether the bytes were from the client's internal cache.
-func (to *Session) GetCRConfig(cdn string) ([]byte, CacheHitStatus, error) {
+// GetCRConfig returns the raw JSON bytes of the CRConfig from Traffic Ops, and whether the bytes were from the client's internal cache. The CRConfig is requested from the 2.0 API if the Session's API is 2.0.
+func (to *Session) GetCRConfig(ctx context.Context, cdn string) ([]byte, CacheHitStatus, error) {
+	version, err := to.apiVersion(ctx)
+	if err != nil {
+		return nil, CacheHitStatusInvalid, err
+	}
 	url := fmt.Sprintf("/CRConfig-Snapshots/%s/CRConfig.json", cdn)
-	return to.getBytesWithTTL(url, tmPollingInterval)
+	if version == APIVersion20 {
+		url = fmt.Sprintf(apiBase20+"/config/cr/%s/CRConfig.json", cdn)
+	}
+	return to.getBytesWithTTL(ctx, url, to.CRConfigCacheDefaultTTL)
 }
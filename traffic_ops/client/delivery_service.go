@@ -15,12 +15,21 @@
 
 package client
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
 
 // DeliveryServices gets an array of DeliveryServices
-func (to *Session) DeliveryServices() ([]DeliveryService, error) {
+func (to *Session) DeliveryServices(ctx context.Context) ([]DeliveryService, error) {
+	return to.ListDeliveryServices(ctx, ListOptions{})
+}
+
+// ListDeliveryServices gets an array of the DeliveryServices matching the given options
+func (to *Session) ListDeliveryServices(ctx context.Context, opts ListOptions) ([]DeliveryService, error) {
 	var data GetDeliveryServiceResponse
-	err := get(to, deliveryServicesEp(), &data)
+	err := get(ctx, to, opts.endpoint(deliveryServicesEp()), &data)
 	if err != nil {
 		return nil, err
 	}
@@ -29,9 +38,9 @@ func (to *Session) DeliveryServices() ([]DeliveryService, error) {
 }
 
 // DeliveryService gets the DeliveryService for the ID it's passed
-func (to *Session) DeliveryService(id string) (*DeliveryService, error) {
+func (to *Session) DeliveryService(ctx context.Context, id string) (*DeliveryService, error) {
 	var data GetDeliveryServiceResponse
-	err := get(to, deliveryServiceEp(id), &data)
+	err := get(ctx, to, deliveryServiceEp(id), &data)
 	if err != nil {
 		return nil, err
 	}
@@ -40,13 +49,19 @@ func (to *Session) DeliveryService(id string) (*DeliveryService, error) {
 }
 
 // CreateDeliveryService creates the DeliveryService it's passed
-func (to *Session) CreateDeliveryService(ds *DeliveryService) (*CreateDeliveryServiceResponse, error) {
+func (to *Session) CreateDeliveryService(ctx context.Context, ds *DeliveryService) (*CreateDeliveryServiceResponse, error) {
+	if err := to.validateDeliveryService(ds); err != nil {
+		return nil, err
+	}
+	if to.DryRun {
+		return &CreateDeliveryServiceResponse{Response: []DeliveryService{*ds}, Alerts: dryRunAlerts("created")}, nil
+	}
 	var data CreateDeliveryServiceResponse
 	jsonReq, err := json.Marshal(ds)
 	if err != nil {
 		return nil, err
 	}
-	err = post(to, deliveryServicesEp(), jsonReq, &data)
+	err = post(ctx, to, deliveryServicesEp(), jsonReq, &data)
 	if err != nil {
 		return nil, err
 	}
@@ -56,13 +71,19 @@ func (to *Session) CreateDeliveryService(ds *DeliveryService) (*CreateDeliverySe
 
 // UpdateDeliveryService updates the DeliveryService matching the ID it's passed with
 // the DeliveryService it is passed
-func (to *Session) UpdateDeliveryService(id string, ds *DeliveryService) (*UpdateDeliveryServiceResponse, error) {
+func (to *Session) UpdateDeliveryService(ctx context.Context, id string, ds *DeliveryService) (*UpdateDeliveryServiceResponse, error) {
+	if err := to.validateDeliveryService(ds); err != nil {
+		return nil, err
+	}
+	if to.DryRun {
+		return &UpdateDeliveryServiceResponse{Response: []DeliveryService{*ds}, Alerts: dryRunAlerts("updated")}, nil
+	}
 	var data UpdateDeliveryServiceResponse
 	jsonReq, err := json.Marshal(ds)
 	if err != nil {
 		return nil, err
 	}
-	err = put(to, deliveryServiceEp(id), jsonReq, &data)
+	err = put(ctx, to, deliveryServiceEp(id), jsonReq, &data)
 	if err != nil {
 		return nil, err
 	}
@@ -71,9 +92,12 @@ func (to *Session) UpdateDeliveryService(id string, ds *DeliveryService) (*Updat
 }
 
 // DeleteDeliveryService deletes the DeliveryService matching the ID it's passed
-func (to *Session) DeleteDeliveryService(id string) (*DeleteDeliveryServiceResponse, error) {
+func (to *Session) DeleteDeliveryService(ctx context.Context, id string) (*DeleteDeliveryServiceResponse, error) {
+	if to.DryRun {
+		return &DeleteDeliveryServiceResponse{Alerts: dryRunAlerts("deleted")}, nil
+	}
 	var data DeleteDeliveryServiceResponse
-	err := del(to, deliveryServiceEp(id), &data)
+	err := del(ctx, to, deliveryServiceEp(id), &data)
 	if err != nil {
 		return nil, err
 	}
@@ -82,9 +106,9 @@ func (to *Session) DeleteDeliveryService(id string) (*DeleteDeliveryServiceRespo
 }
 
 // DeliveryServiceState gets the DeliveryServiceState for the ID it's passed
-func (to *Session) DeliveryServiceState(id string) (*DeliveryServiceState, error) {
+func (to *Session) DeliveryServiceState(ctx context.Context, id string) (*DeliveryServiceState, error) {
 	var data DeliveryServiceStateResponse
-	err := get(to, deliveryServiceStateEp(id), &data)
+	err := get(ctx, to, deliveryServiceStateEp(id), &data)
 	if err != nil {
 		return nil, err
 	}
@@ -93,9 +117,9 @@ func (to *Session) DeliveryServiceState(id string) (*DeliveryServiceState, error
 }
 
 // DeliveryServiceHealth gets the DeliveryServiceHealth for the ID it's passed
-func (to *Session) DeliveryServiceHealth(id string) (*DeliveryServiceHealth, error) {
+func (to *Session) DeliveryServiceHealth(ctx context.Context, id string) (*DeliveryServiceHealth, error) {
 	var data DeliveryServiceHealthResponse
-	err := get(to, deliveryServiceHealthEp(id), &data)
+	err := get(ctx, to, deliveryServiceHealthEp(id), &data)
 	if err != nil {
 		return nil, err
 	}
@@ -104,9 +128,9 @@ func (to *Session) DeliveryServiceHealth(id string) (*DeliveryServiceHealth, err
 }
 
 // DeliveryServiceCapacity gets the DeliveryServiceCapacity for the ID it's passed
-func (to *Session) DeliveryServiceCapacity(id string) (*DeliveryServiceCapacity, error) {
+func (to *Session) DeliveryServiceCapacity(ctx context.Context, id string) (*DeliveryServiceCapacity, error) {
 	var data DeliveryServiceCapacityResponse
-	err := get(to, deliveryServiceCapacityEp(id), &data)
+	err := get(ctx, to, deliveryServiceCapacityEp(id), &data)
 	if err != nil {
 		return nil, err
 	}
@@ -115,9 +139,9 @@ func (to *Session) DeliveryServiceCapacity(id string) (*DeliveryServiceCapacity,
 }
 
 // DeliveryServiceRouting gets the DeliveryServiceRouting for the ID it's passed
-func (to *Session) DeliveryServiceRouting(id string) (*DeliveryServiceRouting, error) {
+func (to *Session) DeliveryServiceRouting(ctx context.Context, id string) (*DeliveryServiceRouting, error) {
 	var data DeliveryServiceRoutingResponse
-	err := get(to, deliveryServiceRoutingEp(id), &data)
+	err := get(ctx, to, deliveryServiceRoutingEp(id), &data)
 	if err != nil {
 		return nil, err
 	}
@@ -126,9 +150,20 @@ func (to *Session) DeliveryServiceRouting(id string) (*DeliveryServiceRouting, e
 }
 
 // DeliveryServiceServer gets the DeliveryServiceServer
-func (to *Session) DeliveryServiceServer(page, limit string) ([]DeliveryServiceServer, error) {
+func (to *Session) DeliveryServiceServer(ctx context.Context, page, limit string) ([]DeliveryServiceServer, error) {
+	var data DeliveryServiceServerResponse
+	err := get(ctx, to, deliveryServiceServerEp(page, limit), &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Response, nil
+}
+
+// ListDeliveryServiceServers gets the DeliveryServiceServers matching the given options. Traffic Ops returns 20 per page by default.
+func (to *Session) ListDeliveryServiceServers(ctx context.Context, opts ListOptions) ([]DeliveryServiceServer, error) {
 	var data DeliveryServiceServerResponse
-	err := get(to, deliveryServiceServerEp(page, limit), &data)
+	err := get(ctx, to, opts.endpoint(deliveryServiceServerListEp()), &data)
 	if err != nil {
 		return nil, err
 	}
@@ -136,10 +171,58 @@ func (to *Session) DeliveryServiceServer(page, limit string) ([]DeliveryServiceS
 	return data.Response, nil
 }
 
+// DeliveryServiceServerIterator pages through DeliveryServiceServers. Call Next to advance to each DeliveryServiceServer, and Err after Next returns false.
+type DeliveryServiceServerIterator struct {
+	pager
+	page []DeliveryServiceServer
+}
+
+// IterateDeliveryServiceServers returns an iterator over the DeliveryServiceServers matching the given options, which fetches them a page at a time.
+func (to *Session) IterateDeliveryServiceServers(ctx context.Context, opts ListOptions) *DeliveryServiceServerIterator {
+	it := &DeliveryServiceServerIterator{}
+	it.pager = newPager(ctx, opts, func(ctx context.Context, opts ListOptions) (int, string, error) {
+		dss, err := to.ListDeliveryServiceServers(ctx, opts)
+		if err != nil || len(dss) == 0 {
+			return 0, "", err
+		}
+		it.page = dss
+		return len(dss), fmt.Sprintf("%d/%d", dss[0].DeliveryService, dss[0].Server), nil
+	})
+	return it
+}
+
+// Next advances to the next DeliveryServiceServer, and returns whether there is one.
+func (it *DeliveryServiceServerIterator) Next() bool {
+	return it.next()
+}
+
+// DeliveryServiceServer returns the current DeliveryServiceServer. It must only be called after Next returns true.
+func (it *DeliveryServiceServerIterator) DeliveryServiceServer() DeliveryServiceServer {
+	return it.page[it.i]
+}
+
+// AssignDeliveryServiceServers assigns the servers with the given host names to the DeliveryService with the XMLID it's passed. The servers replace any already assigned to the DeliveryService.
+func (to *Session) AssignDeliveryServiceServers(ctx context.Context, xmlID string, serverNames []string) (*AssignDeliveryServiceServersResponse, error) {
+	if to.DryRun {
+		return &AssignDeliveryServiceServersResponse{Response: DeliveryServiceServers{XMLID: xmlID, ServerNames: serverNames}, Alerts: []Alert{{Level: "info", Text: dryRunText("assigned servers")}}}, nil
+	}
+	var data AssignDeliveryServiceServersResponse
+	jsonReq, err := json.Marshal(DeliveryServiceServers{ServerNames: serverNames})
+	if err != nil {
+		return nil, err
+	}
+	err = post(ctx, to, deliveryServiceServersEp(xmlID), jsonReq, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
 // DeliveryServiceSSLKeysByID gets the DeliveryServiceSSLKeys by ID
-func (to *Session) DeliveryServiceSSLKeysByID(id string) (*DeliveryServiceSSLKeys, error) {
+func (to *Session) DeliveryServiceSSLKeysByID(ctx context.Context, id string) (*DeliveryServiceSSLKeys, error) {
 	var data DeliveryServiceSSLKeysResponse
-	err := get(to, deliveryServiceSSLKeysByIDEp(id), &data)
+	err := get(ctx, to, deliveryServiceSSLKeysByIDEp(id), &data)
 	if err != nil {
 		return nil, err
 	}
@@ -148,9 +231,9 @@ func (to *Session) DeliveryServiceSSLKeysByID(id string) (*DeliveryServiceSSLKey
 }
 
 // DeliveryServiceSSLKeysByHostname gets the DeliveryServiceSSLKeys by Hostname
-func (to *Session) DeliveryServiceSSLKeysByHostname(hostname string) (*DeliveryServiceSSLKeys, error) {
+func (to *Session) DeliveryServiceSSLKeysByHostname(ctx context.Context, hostname string) (*DeliveryServiceSSLKeys, error) {
 	var data DeliveryServiceSSLKeysResponse
-	err := get(to, deliveryServiceSSLKeysByHostnameEp(hostname), &data)
+	err := get(ctx, to, deliveryServiceSSLKeysByHostnameEp(hostname), &data)
 	if err != nil {
 		return nil, err
 	}
@@ -158,24 +241,41 @@ func (to *Session) DeliveryServiceSSLKeysByHostname(hostname string) (*DeliveryS
 	return &data.Response, nil
 }
 
-func get(to *Session, endpoint string, respStruct interface{}) error {
-	return makeReq(to, "GET", endpoint, nil, respStruct)
+// validateDeliveryService returns ds.Validate() if the Session validates delivery services or is a dry run, and nil otherwise.
+func (to *Session) validateDeliveryService(ds *DeliveryService) error {
+	if !to.ValidateDeliveryServices && !to.DryRun {
+		return nil
+	}
+	return ds.Validate()
+}
+
+// dryRunAlerts returns the alerts of a dry run response, which say the delivery service wasn't changed.
+func dryRunAlerts(action string) []DeliveryServiceAlert {
+	return []DeliveryServiceAlert{{Level: "info", Text: dryRunText(action)}}
+}
+
+func dryRunText(action string) string {
+	return "Dry run: the delivery service was not " + action + "."
+}
+
+func get(ctx context.Context, to *Session, endpoint string, respStruct interface{}) error {
+	return makeReq(ctx, to, "GET", endpoint, nil, respStruct)
 }
 
-func post(to *Session, endpoint string, body []byte, respStruct interface{}) error {
-	return makeReq(to, "POST", endpoint, body, respStruct)
+func post(ctx context.Context, to *Session, endpoint string, body []byte, respStruct interface{}) error {
+	return makeReq(ctx, to, "POST", endpoint, body, respStruct)
 }
 
-func put(to *Session, endpoint string, body []byte, respStruct interface{}) error {
-	return makeReq(to, "PUT", endpoint, body, respStruct)
+func put(ctx context.Context, to *Session, endpoint string, body []byte, respStruct interface{}) error {
+	return makeReq(ctx, to, "PUT", endpoint, body, respStruct)
 }
 
-func del(to *Session, endpoint string, respStruct interface{}) error {
-	return makeReq(to, "DELETE", endpoint, nil, respStruct)
+func del(ctx context.Context, to *Session, endpoint string, respStruct interface{}) error {
+	return makeReq(ctx, to, "DELETE", endpoint, nil, respStruct)
 }
 
-func makeReq(to *Session, method, endpoint string, body []byte, respStruct interface{}) error {
-	resp, err := to.request(method, endpoint, body)
+func makeReq(ctx context.Context, to *Session, method, endpoint string, body []byte, respStruct interface{}) error {
+	resp, err := to.request(ctx, method, endpoint, body)
 	if err != nil {
 		return err
 	}
@@ -46,8 +46,16 @@ func deliveryServiceRoutingEp(id string) string {
 	return deliveryServiceBaseEp(id) + "/routing.json"
 }
 
+func deliveryServiceServerListEp() string {
+	return apiBase + "/deliveryserviceserver.json"
+}
+
 func deliveryServiceServerEp(page, limit string) string {
-	return apiBase + "/deliveryserviceserver.json?page=" + page + "&limit=" + limit
+	return deliveryServiceServerListEp() + "?page=" + page + "&limit=" + limit
+}
+
+func deliveryServiceServersEp(xmlID string) string {
+	return deliveryServiceBaseEp(xmlID) + "/servers"
 }
 
 func deliveryServiceSSLKeysByIDEp(id string) string {
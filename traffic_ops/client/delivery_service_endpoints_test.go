@@ -105,6 +105,18 @@ func TestDeliveryServiceServerEp(t *testing.T) {
 	}
 }
 
+func TestDeliveryServiceServersEp(t *testing.T) {
+	testHelper.Context(t, "Given the need to test that AssignDeliveryServiceServers uses the correct URL")
+
+	ep := deliveryServiceServersEp("ds-test")
+	expected := "/api/1.2/deliveryservices/ds-test/servers"
+	if ep != expected {
+		testHelper.Error(t, "Should get back %s for \"deliveryServiceServersEp\", got: %s", expected, ep)
+	} else {
+		testHelper.Success(t, "Should be able to get the correct delivery service servers endpoint")
+	}
+}
+
 func TestDeliveryServiceSSLKeysByIDEp(t *testing.T) {
 	testHelper.Context(t, "Given the need to test that DeliveryServiceSSLKeysByID uses the correct URL")
 
@@ -193,6 +193,18 @@ type DeliveryServiceServerResponse struct {
 	Limit    int                     `json:"limit"`
 }
 
+// AssignDeliveryServiceServersResponse is the JSON object returned when servers are assigned to a delivery service
+type AssignDeliveryServiceServersResponse struct {
+	Response DeliveryServiceServers `json:"response"`
+	Alerts   []Alert                `json:"alerts"`
+}
+
+// DeliveryServiceServers is the set of servers, by host name, assigned to the delivery service with the given XMLID
+type DeliveryServiceServers struct {
+	XMLID       string   `json:"xmlId,omitempty"`
+	ServerNames []string `json:"serverNames"`
+}
+
 // DeliveryServiceServer ...
 type DeliveryServiceServer struct {
 	LastUpdated     string `json:"lastUpdated"`
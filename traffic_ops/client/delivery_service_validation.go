@@ -0,0 +1,303 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DSType is the name of a delivery service type.
+type DSType string
+
+const (
+	DSTypeHTTP           = DSType("HTTP")
+	DSTypeHTTPNoCache    = DSType("HTTP_NO_CACHE")
+	DSTypeHTTPLive       = DSType("HTTP_LIVE")
+	DSTypeHTTPLiveNatnl  = DSType("HTTP_LIVE_NATNL")
+	DSTypeDNS            = DSType("DNS")
+	DSTypeDNSLive        = DSType("DNS_LIVE")
+	DSTypeDNSLiveNatnl   = DSType("DNS_LIVE_NATNL")
+	DSTypeAnyMap         = DSType("ANY_MAP")
+	DSTypeSteering       = DSType("STEERING")
+	DSTypeClientSteering = DSType("CLIENT_STEERING")
+)
+
+// IsKnown returns whether t is one of the delivery service types Traffic Ops has.
+func (t DSType) IsKnown() bool {
+	switch t {
+	case DSTypeHTTP, DSTypeHTTPNoCache, DSTypeHTTPLive, DSTypeHTTPLiveNatnl, DSTypeDNS, DSTypeDNSLive, DSTypeDNSLiveNatnl, DSTypeAnyMap, DSTypeSteering, DSTypeClientSteering:
+		return true
+	}
+	return false
+}
+
+// IsHTTP returns whether delivery services of type t are routed by Traffic Router with HTTP redirects, including steering delivery services.
+func (t DSType) IsHTTP() bool {
+	return strings.HasPrefix(string(t), "HTTP") || t.IsSteering()
+}
+
+// IsDNS returns whether delivery services of type t are routed by Traffic Router with DNS.
+func (t DSType) IsDNS() bool {
+	return strings.HasPrefix(string(t), "DNS")
+}
+
+// IsSteering returns whether delivery services of type t steer clients to other delivery services.
+func (t DSType) IsSteering() bool {
+	return t == DSTypeSteering || t == DSTypeClientSteering
+}
+
+// The delivery service match list types.
+const (
+	DSMatchTypeHost   = "HOST_REGEXP"
+	DSMatchTypePath   = "PATH_REGEXP"
+	DSMatchTypeHeader = "HEADER_REGEXP"
+)
+
+// DSMaxNameLen is the longest a delivery service xmlId or displayName may be.
+const DSMaxNameLen = 48
+
+// FieldError is a problem with a field of an object sent to Traffic Ops. Field is the field's JSON name, as in Traffic Ops' alerts.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// ValidationError is returned when a delivery service is invalid. Errors are all of its problems, not just the first.
+type ValidationError struct {
+	XMLID  string
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("invalid delivery service '%s': %s", e.XMLID, strings.Join(msgs, "; "))
+}
+
+// Validate checks ds against the rules Traffic Ops has for creating and updating delivery services, so an invalid delivery service can be rejected without a request. It returns nil if ds is valid, or a *ValidationError with every problem found. Like the Traffic Ops 1.2 API, the typeId, cdnId, displayName and orgServerFqdn are required for every type, and the type and CDN names aren't used instead of their IDs. Rules which need Traffic Ops' database, such as whether the type ID exists or a host regex conflicts with another delivery service, aren't checked. If ds has a TypeID but no Type name, the rules which depend on the type aren't checked either.
+func (ds *DeliveryService) Validate() error {
+	v := validator{}
+
+	if ds.XMLID == "" {
+		v.add("xmlId", "is required")
+	} else if strings.IndexFunc(ds.XMLID, unicode.IsSpace) >= 0 {
+		v.add("xmlId", "cannot contain whitespace")
+	} else if len(ds.XMLID) > DSMaxNameLen {
+		v.addf("xmlId", "is too long, the maximum is %d characters", DSMaxNameLen)
+	}
+
+	typ := DSType(ds.Type)
+	if ds.TypeID == 0 {
+		v.add("typeId", "is required")
+	}
+	if typ != "" && !typ.IsKnown() {
+		v.addf("type", "'%s' is not a delivery service type", typ)
+	}
+
+	if ds.DisplayName == "" {
+		v.add("displayName", "is required")
+	} else if len(ds.DisplayName) > DSMaxNameLen {
+		v.addf("displayName", "is too long, the maximum is %d characters", DSMaxNameLen)
+	}
+	if ds.CDNID == 0 {
+		v.add("cdnId", "is required")
+	}
+
+	if ds.OrgServerFQDN == "" {
+		v.add("orgServerFqdn", "is required")
+	} else if err := validateOrigin(ds.OrgServerFQDN); err != "" {
+		v.add("orgServerFqdn", err)
+	}
+
+	if ds.HTTPBypassFQDN != "" {
+		if typ.IsDNS() {
+			v.addf("httpBypassFqdn", "is only valid for HTTP delivery services, not %s", typ)
+		} else if !isHostname(ds.HTTPBypassFQDN) {
+			v.addf("httpBypassFqdn", "'%s' should be a FQDN only, not a URL, e.g. host.overflowcdn.com", ds.HTTPBypassFQDN)
+		}
+	}
+	for _, bypass := range []struct{ field, val string }{{"dnsBypassIp", ds.DNSBypassIP}, {"dnsBypassIp6", ds.DNSBypassIP6}, {"dnsBypassCname", ds.DNSBypassCname}} {
+		if bypass.val != "" && typ.IsHTTP() {
+			v.addf(bypass.field, "is only valid for DNS delivery services, not %s", typ)
+		}
+	}
+	if ds.DNSBypassIP != "" {
+		if ip := net.ParseIP(ds.DNSBypassIP); ip == nil || ip.To4() == nil {
+			v.addf("dnsBypassIp", "'%s' is not a valid IPv4 address", ds.DNSBypassIP)
+		}
+	}
+	if ds.DNSBypassIP6 != "" {
+		if ip := net.ParseIP(ds.DNSBypassIP6); ip == nil || !strings.Contains(ds.DNSBypassIP6, ":") {
+			v.addf("dnsBypassIp6", "'%s' is not a valid IPv6 address", ds.DNSBypassIP6)
+		}
+	}
+	if ds.DNSBypassCname != "" && !isHostname(ds.DNSBypassCname) {
+		v.addf("dnsBypassCname", "'%s' should be a FQDN only, not a URL, e.g. host.bypass.com", ds.DNSBypassCname)
+	}
+	if (ds.DNSBypassIP != "" || ds.DNSBypassIP6 != "" || ds.DNSBypassCname != "") && ds.DNSBypassTTL <= 0 {
+		v.add("dnsBypassTtl", "is required when a DNS bypass is set")
+	}
+	if ds.TRResponseHeaders != "" && typ.IsDNS() {
+		v.add("trResponseHeaders", "are only valid for HTTP delivery services")
+	}
+
+	v.between("dscp", ds.DSCP, 0, 63)
+	v.between("protocol", ds.Protocol, 0, 3)
+	v.between("qstringIgnore", ds.QStringIgnore, 0, 2)
+	v.between("geoLimit", ds.GeoLimit, 0, 2)
+	v.between("geoProvider", ds.GeoProvider, 0, 1)
+	v.between("rangeRequestHandling", ds.RangeRequestHandling, 0, 2)
+	v.nonNegative("dnsBypassTtl", ds.DNSBypassTTL)
+	v.nonNegative("ccrDnsTtl", ds.CCRDNSTTL)
+	v.nonNegative("globalMaxMbps", ds.GlobalMaxMBPS)
+	v.nonNegative("globalMaxTps", ds.GlobalMaxTPS)
+	v.nonNegative("maxDnsAnswers", ds.MaxDNSAnswers)
+	v.nonNegative("initialDispersion", ds.InitialDispersion)
+	if ds.MissLat < -90 || ds.MissLat > 90 {
+		v.add("missLat", "is invalid, it may not exceed +- 90.0")
+	}
+	if ds.MissLong < -180 || ds.MissLong > 180 {
+		v.add("missLong", "is invalid, it may not exceed +- 180.0")
+	}
+
+	if ds.QStringIgnore == 2 && ds.RegexRemap != "" {
+		v.add("regexRemap", "can not be used when qstringIgnore is 2")
+	}
+	if ds.InfoURL != "" {
+		if u, err := url.Parse(ds.InfoURL); err != nil || u.Scheme == "" || u.Host == "" {
+			v.addf("infoUrl", "'%s' is not a valid URL", ds.InfoURL)
+		}
+	}
+
+	validateMatchList(&v, typ, ds.MatchList)
+	return v.err(ds.XMLID)
+}
+
+// validateMatchList adds the problems with the given match list to v. An empty match list is valid, because Traffic Ops creates a host regex from the xmlId when none is given.
+func validateMatchList(v *validator, typ DSType, matches []DeliveryServiceMatch) {
+	if len(matches) == 0 {
+		return
+	}
+	hasHost := false
+	seen := map[string]struct{}{}
+	for _, match := range matches {
+		switch match.Type {
+		case DSMatchTypeHost:
+			if match.SetNumber == 0 {
+				hasHost = true
+			}
+		case DSMatchTypePath, DSMatchTypeHeader:
+			if typ.IsDNS() {
+				v.addf("matchList", "%s is only valid for HTTP delivery services, not %s", match.Type, typ)
+			}
+		default:
+			v.addf("matchList", "'%s' is not a valid regexp type", match.Type)
+		}
+		key := match.Type + "/" + strconv.Itoa(match.SetNumber)
+		if _, ok := seen[key]; ok {
+			v.addf("matchList", "has more than one %s with set number %d", match.Type, match.SetNumber)
+		}
+		seen[key] = struct{}{}
+		if match.SetNumber < 0 {
+			v.addf("matchList", "set number %d is not a valid order number", match.SetNumber)
+		}
+		if match.Pattern == "" {
+			v.add("matchList", "regular expression cannot be empty")
+		} else if _, err := regexp.Compile(match.Pattern); err != nil {
+			v.addf("matchList", "regular expression '%s' is invalid: %v", match.Pattern, err)
+		}
+	}
+	if !hasHost {
+		v.add("matchList", "needs a HOST_REGEXP with set number 0")
+	}
+}
+
+// validateOrigin returns why the given orgServerFqdn is invalid, or the empty string if it's valid. Like Traffic Ops, it must be a http or https URL of a host name and optional port, e.g. http://origin.example.net:8080.
+func validateOrigin(origin string) string {
+	lower := strings.ToLower(origin)
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+		return "must start with http:// or https://"
+	}
+	hostPort := origin[strings.Index(origin, "://")+len("://"):]
+	host, port := hostPort, "80"
+	if i := strings.Index(hostPort, ":"); i >= 0 {
+		host, port = hostPort[:i], hostPort[i+1:]
+	}
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 || !isHostname(host) {
+		return fmt.Sprintf("'%s' is not a valid origin host name (rfc1123) and port", hostPort)
+	}
+	return ""
+}
+
+var hostnameLabelRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// isHostname returns whether s is a valid rfc1123 host name.
+func isHostname(s string) bool {
+	if s == "" || len(s) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(s, "."), ".") {
+		if !hostnameLabelRegex.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// validator collects FieldErrors.
+type validator struct {
+	errs []FieldError
+}
+
+func (v *validator) add(field, msg string) {
+	v.errs = append(v.errs, FieldError{Field: field, Message: msg})
+}
+
+func (v *validator) addf(field, format string, args ...interface{}) {
+	v.add(field, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) between(field string, val, min, max int) {
+	if val < min || val > max {
+		v.addf(field, "%d is invalid, it must be between %d and %d", val, min, max)
+	}
+}
+
+func (v *validator) nonNegative(field string, val int) {
+	if val < 0 {
+		v.addf(field, "%d is invalid, it may not be negative", val)
+	}
+}
+
+// err returns a *ValidationError of the collected errors, or nil if there are none.
+func (v *validator) err(xmlID string) error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return &ValidationError{XMLID: xmlID, Errors: v.errs}
+}
@@ -0,0 +1,94 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/jheitz200/test_helper"
+)
+
+func TestServerEp(t *testing.T) {
+	testHelper.Context(t, "Given the need to test that UpdateServer and DeleteServer use the correct URL")
+
+	ep := serverEp(1)
+	expected := "/api/1.2/servers/1"
+	if ep != expected {
+		testHelper.Error(t, "Should get back %s for \"serverEp\", got: %s", expected, ep)
+	} else {
+		testHelper.Success(t, "Should be able to get the correct server endpoint")
+	}
+}
+
+func TestCacheGroupEp(t *testing.T) {
+	testHelper.Context(t, "Given the need to test that UpdateCacheGroup and DeleteCacheGroup use the correct URL")
+
+	ep := cacheGroupEp(1)
+	expected := "/api/1.2/cachegroups/1"
+	if ep != expected {
+		testHelper.Error(t, "Should get back %s for \"cacheGroupEp\", got: %s", expected, ep)
+	} else {
+		testHelper.Success(t, "Should be able to get the correct cachegroup endpoint")
+	}
+}
+
+func TestProfileEp(t *testing.T) {
+	testHelper.Context(t, "Given the need to test that UpdateProfile and DeleteProfile use the correct URL")
+
+	ep := profileEp(1)
+	expected := "/api/1.2/profiles/1"
+	if ep != expected {
+		testHelper.Error(t, "Should get back %s for \"profileEp\", got: %s", expected, ep)
+	} else {
+		testHelper.Success(t, "Should be able to get the correct profile endpoint")
+	}
+}
+
+func TestParameterEp(t *testing.T) {
+	testHelper.Context(t, "Given the need to test that UpdateParameter and DeleteParameter use the correct URL")
+
+	ep := parameterEp(1)
+	expected := "/api/1.2/parameters/1"
+	if ep != expected {
+		testHelper.Error(t, "Should get back %s for \"parameterEp\", got: %s", expected, ep)
+	} else {
+		testHelper.Success(t, "Should be able to get the correct parameter endpoint")
+	}
+}
+
+func TestProfileParameterEp(t *testing.T) {
+	testHelper.Context(t, "Given the need to test that DeleteProfileParameter uses the correct URL")
+
+	ep := profileParameterEp(1, 7)
+	expected := "/api/1.2/profileparameters/1/7"
+	if ep != expected {
+		testHelper.Error(t, "Should get back %s for \"profileParameterEp\", got: %s", expected, ep)
+	} else {
+		testHelper.Success(t, "Should be able to get the correct profile parameter endpoint")
+	}
+}
+
+func TestCDNEp(t *testing.T) {
+	testHelper.Context(t, "Given the need to test that UpdateCDN and DeleteCDN use the correct URL")
+
+	ep := cdnEp(1)
+	expected := "/api/1.2/cdns/1"
+	if ep != expected {
+		testHelper.Error(t, "Should get back %s for \"cdnEp\", got: %s", expected, ep)
+	} else {
+		testHelper.Success(t, "Should be able to get the correct CDN endpoint")
+	}
+}
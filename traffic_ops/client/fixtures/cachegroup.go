@@ -33,3 +33,26 @@ func Cachegroups() *client.CacheGroupResponse {
 		},
 	}
 }
+
+// CreateCacheGroup returns a default CreateCacheGroupResponse to be used for testing.
+func CreateCacheGroup() *client.CreateCacheGroupResponse {
+	return &client.CreateCacheGroupResponse{
+		Response: Cachegroups().Response[0],
+		Alerts:   successAlerts("Cachegroup creation was successful."),
+	}
+}
+
+// UpdateCacheGroup returns a default UpdateCacheGroupResponse to be used for testing.
+func UpdateCacheGroup() *client.UpdateCacheGroupResponse {
+	return &client.UpdateCacheGroupResponse{
+		Response: Cachegroups().Response[0],
+		Alerts:   successAlerts("Cachegroup update was successful."),
+	}
+}
+
+// DeleteCacheGroup returns a default DeleteCacheGroupResponse to be used for testing.
+func DeleteCacheGroup() *client.DeleteCacheGroupResponse {
+	return &client.DeleteCacheGroupResponse{
+		Alerts: successAlerts("Cachegroup deleted."),
+	}
+}
@@ -29,3 +29,26 @@ func CDNs() *client.CDNResponse {
 		},
 	}
 }
+
+// CreateCDN returns a default CreateCDNResponse to be used for testing.
+func CreateCDN() *client.CreateCDNResponse {
+	return &client.CreateCDNResponse{
+		Response: CDNs().Response[0],
+		Alerts:   successAlerts("cdn was created."),
+	}
+}
+
+// UpdateCDN returns a default UpdateCDNResponse to be used for testing.
+func UpdateCDN() *client.UpdateCDNResponse {
+	return &client.UpdateCDNResponse{
+		Response: CDNs().Response[0],
+		Alerts:   successAlerts("CDN update was successful."),
+	}
+}
+
+// DeleteCDN returns a default DeleteCDNResponse to be used for testing.
+func DeleteCDN() *client.DeleteCDNResponse {
+	return &client.DeleteCDNResponse{
+		Alerts: successAlerts("cdn was deleted."),
+	}
+}
@@ -208,3 +208,13 @@ func DeliveryServiceSSLKeys() *client.DeliveryServiceSSLKeysResponse {
 		Response: sslKeys,
 	}
 }
+
+// AssignDeliveryServiceServers returns a default AssignDeliveryServiceServersResponse to be used for testing.
+func AssignDeliveryServiceServers() *client.AssignDeliveryServiceServersResponse {
+	return &client.AssignDeliveryServiceServersResponse{
+		Response: client.DeliveryServiceServers{
+			XMLID:       "ds-test",
+			ServerNames: []string{"edge-alb-01", "edge-alb-02"},
+		},
+	}
+}
@@ -30,3 +30,26 @@ func Parameters() *client.ParamResponse {
 		},
 	}
 }
+
+// CreateParameters returns a default CreateParametersResponse to be used for testing.
+func CreateParameters() *client.CreateParametersResponse {
+	return &client.CreateParametersResponse{
+		Response: Parameters().Response,
+		Alerts:   successAlerts("Create 1 parameters successfully."),
+	}
+}
+
+// UpdateParameter returns a default UpdateParameterResponse to be used for testing.
+func UpdateParameter() *client.UpdateParameterResponse {
+	return &client.UpdateParameterResponse{
+		Response: Parameters().Response[0],
+		Alerts:   successAlerts("Parameter was successfully edited."),
+	}
+}
+
+// DeleteParameter returns a default DeleteParameterResponse to be used for testing.
+func DeleteParameter() *client.DeleteParameterResponse {
+	return &client.DeleteParameterResponse{
+		Alerts: successAlerts("Parameter was successfully deleted."),
+	}
+}
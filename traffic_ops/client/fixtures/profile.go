@@ -30,3 +30,26 @@ func Profiles() *client.ProfileResponse {
 		},
 	}
 }
+
+// CreateProfile returns a default CreateProfileResponse to be used for testing.
+func CreateProfile() *client.CreateProfileResponse {
+	return &client.CreateProfileResponse{
+		Response: Profiles().Response[0],
+		Alerts:   successAlerts("Profile was created."),
+	}
+}
+
+// UpdateProfile returns a default UpdateProfileResponse to be used for testing.
+func UpdateProfile() *client.UpdateProfileResponse {
+	return &client.UpdateProfileResponse{
+		Response: Profiles().Response[0],
+		Alerts:   successAlerts("Profile was updated: 1"),
+	}
+}
+
+// DeleteProfile returns a default DeleteProfileResponse to be used for testing.
+func DeleteProfile() *client.DeleteProfileResponse {
+	return &client.DeleteProfileResponse{
+		Alerts: successAlerts("Profile was deleted."),
+	}
+}
@@ -188,3 +188,26 @@ func Server() *client.ServerDetailResponse {
 		},
 	}
 }
+
+// CreateServer returns a default CreateServerResponse to be used for testing.
+func CreateServer() *client.CreateServerResponse {
+	return &client.CreateServerResponse{
+		Response: Servers().Response[:1],
+		Alerts:   successAlerts("Server creation was successful."),
+	}
+}
+
+// UpdateServer returns a default UpdateServerResponse to be used for testing.
+func UpdateServer() *client.UpdateServerResponse {
+	return &client.UpdateServerResponse{
+		Response: Servers().Response[:1],
+		Alerts:   successAlerts("Server update was successful."),
+	}
+}
+
+// DeleteServer returns a default DeleteServerResponse to be used for testing.
+func DeleteServer() *client.DeleteServerResponse {
+	return &client.DeleteServerResponse{
+		Alerts: successAlerts("Server was deleted: edge-alb-01"),
+	}
+}
@@ -15,7 +15,10 @@
 
 package client
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+)
 import "fmt"
 
 // HardwareResponse ...
@@ -34,12 +37,12 @@ type Hardware struct {
 }
 
 // Hardware gets an array of Hardware
-func (to *Session) Hardware(limit int) ([]Hardware, error) {
+func (to *Session) Hardware(ctx context.Context, limit int) ([]Hardware, error) {
 	url := "/api/1.2/hwinfo.json"
 	if limit > 0 {
 		url += fmt.Sprintf("?limit=%v", limit)
 	}
-	resp, err := to.request("GET", url, nil)
+	resp, err := to.request(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,327 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheEntries is the number of responses the memory cache of a Session created with useCache holds.
+const DefaultCacheEntries = 1000
+
+// DefaultCRConfigCacheTTL is how long a cached CRConfig or Traffic Router config is fresh, if Traffic Ops doesn't say. Traffic Ops 1.2 never says, and these are large, so they aren't requested again more often than this.
+const DefaultCRConfigCacheTTL = 60 * time.Second
+
+// DefaultCacheStaleIfError is how long after a cached response expires it may still be used, if Traffic Ops can't be reached.
+const DefaultCacheStaleIfError = 5 * time.Minute
+
+// CachedResponse is the body of a successful GET response, with the validators and freshness Traffic Ops sent with it.
+type CachedResponse struct {
+	Body           []byte    `json:"body"`
+	ETag           string    `json:"etag,omitempty"`
+	LastModified   string    `json:"lastModified,omitempty"`
+	Expires        time.Time `json:"expires"`
+	MustRevalidate bool      `json:"mustRevalidate,omitempty"`
+}
+
+// CacheEntry is a response in the cache of Sessions before CacheStore.
+// Deprecated: the Session's Cache stores CachedResponses. CacheEntry is unused, and kept so code referring to it still compiles.
+type CacheEntry struct {
+	Entered int64
+	Bytes   []byte
+}
+
+// CacheStore stores cached responses by key. Implementations must be safe for multiple goroutines. Caching is best-effort, so a store may drop entries at any time.
+type CacheStore interface {
+	// Get returns the response stored at the given key, and whether one was.
+	Get(key string) (CachedResponse, bool)
+	// Set stores the response at the given key, replacing any response already there.
+	Set(key string, r CachedResponse) error
+}
+
+// MemoryCacheStore is a CacheStore in memory, which holds up to a maximum number of responses, evicting the least recently used.
+type MemoryCacheStore struct {
+	max     int
+	entries map[string]*list.Element
+	lru     *list.List
+	m       sync.Mutex
+}
+
+type memoryCacheEntry struct {
+	key string
+	r   CachedResponse
+}
+
+// NewMemoryCacheStore returns a new empty MemoryCacheStore, which holds up to maxEntries responses.
+func NewMemoryCacheStore(maxEntries int) *MemoryCacheStore {
+	return &MemoryCacheStore{max: maxEntries, entries: map[string]*list.Element{}, lru: list.New()}
+}
+
+// Get implements CacheStore.
+func (s *MemoryCacheStore) Get(key string) (CachedResponse, bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return CachedResponse{}, false
+	}
+	s.lru.MoveToFront(e)
+	return e.Value.(*memoryCacheEntry).r, true
+}
+
+// Set implements CacheStore.
+func (s *MemoryCacheStore) Set(key string, r CachedResponse) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if e, ok := s.entries[key]; ok {
+		e.Value.(*memoryCacheEntry).r = r
+		s.lru.MoveToFront(e)
+		return nil
+	}
+	s.entries[key] = s.lru.PushFront(&memoryCacheEntry{key: key, r: r})
+	for s.max > 0 && s.lru.Len() > s.max {
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+	return nil
+}
+
+// DiskCacheStore is a CacheStore which stores each response as a file in a directory, so cached responses survive restarts. It doesn't limit its size; the number of files is the number of distinct URLs requested.
+type DiskCacheStore struct {
+	dir string
+}
+
+// diskCacheFile is the file a DiskCacheStore stores a response in. The key is stored so a hash collision is a miss, rather than the wrong response.
+type diskCacheFile struct {
+	Key      string         `json:"key"`
+	Response CachedResponse `json:"response"`
+}
+
+// NewDiskCacheStore returns a DiskCacheStore in the given directory, creating it if it doesn't exist.
+func NewDiskCacheStore(dir string) (*DiskCacheStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &DiskCacheStore{dir: dir}, nil
+}
+
+func (s *DiskCacheStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get implements CacheStore. A file which can't be read or decoded is a miss.
+func (s *DiskCacheStore) Get(key string) (CachedResponse, bool) {
+	b, err := ioutil.ReadFile(s.path(key))
+	if err != nil {
+		return CachedResponse{}, false
+	}
+	f := diskCacheFile{}
+	if err := json.Unmarshal(b, &f); err != nil || f.Key != key {
+		return CachedResponse{}, false
+	}
+	return f.Response, true
+}
+
+// Set implements CacheStore. The file is written to a temporary file and renamed, so concurrent readers never see a partial response.
+func (s *DiskCacheStore) Set(key string, r CachedResponse) error {
+	b, err := json.Marshal(diskCacheFile{Key: key, Response: r})
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(s.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path(key)); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// cacheKey returns the key of the given path in the Session's cache. The key includes the identity of the Session's credentials, because Traffic Ops responses depend on their permissions, and Sessions with different credentials may share a CacheStore.
+func (to *Session) cacheKey(path string) string {
+	return to.credentialID() + " " + to.URL + path
+}
+
+// credentialID returns the identity of the Session's credentials: the user, or for AuthAPIKey Sessions, which have no user, a hash of the API key, so the key itself isn't stored in cache keys.
+func (to *Session) credentialID() string {
+	if to.Auth == AuthAPIKey {
+		hash := sha256.Sum256([]byte(to.APIKey))
+		return string(AuthAPIKey) + ":" + hex.EncodeToString(hash[:])
+	}
+	return "user:" + to.UserName
+}
+
+// cachedGet gets the given path, using the Session's cache. A response without a max-age or Expires is fresh for the given default TTL. A fresh cached response is returned without a request. A stale one is revalidated with its ETag or Last-Modified, if it has them. If Traffic Ops can't be reached or responds with a server error, a stale response is returned for up to the Session's CacheStaleIfError after it expired, unless Traffic Ops said it must be revalidated.
+func (to *Session) cachedGet(ctx context.Context, path string, defaultTTL time.Duration) ([]byte, CacheHitStatus, error) {
+	key := to.cacheKey(path)
+	now := time.Now()
+	cached, ok := to.Cache.Get(key)
+	if ok && now.Before(cached.Expires) {
+		return cached.Body, CacheHitStatusHit, nil
+	}
+
+	header := http.Header{}
+	if ok && cached.ETag != "" {
+		header.Set("If-None-Match", cached.ETag)
+	}
+	if ok && cached.LastModified != "" {
+		header.Set("If-Modified-Since", cached.LastModified)
+	}
+	resp, err := to.send(ctx, http.MethodGet, path, nil, header)
+	if err != nil {
+		if ok && !cached.MustRevalidate && now.Before(cached.Expires.Add(to.CacheStaleIfError)) && ctx.Err() == nil && serverUnavailable(err) {
+			return cached.Body, CacheHitStatusStale, nil
+		}
+		return nil, CacheHitStatusInvalid, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && !ok {
+		// nothing was cached, so no conditional request was made, and there's no body to return or cache.
+		return nil, CacheHitStatusInvalid, newHTTPError(resp, to.URL+path)
+	}
+	cc := parseCacheControl(resp.Header.Get("Cache-Control"))
+	if resp.StatusCode == http.StatusNotModified {
+		cached.Expires = now.Add(freshness(resp.Header, cc, now, defaultTTL))
+		cached.MustRevalidate = cc.mustRevalidate
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			cached.ETag = etag
+		}
+		to.Cache.Set(key, cached) // caching is best-effort, a failure to store just means the next request isn't cached
+		return cached.Body, CacheHitStatusRevalidated, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, CacheHitStatusInvalid, err
+	}
+	if !cc.noStore {
+		to.Cache.Set(key, CachedResponse{
+			Body:           body,
+			ETag:           resp.Header.Get("ETag"),
+			LastModified:   resp.Header.Get("Last-Modified"),
+			Expires:        now.Add(freshness(resp.Header, cc, now, defaultTTL)),
+			MustRevalidate: cc.mustRevalidate,
+		})
+	}
+	if ok {
+		return body, CacheHitStatusExpired, nil
+	}
+	return body, CacheHitStatusMiss, nil
+}
+
+// serverUnavailable returns whether the given request error means Traffic Ops couldn't be reached or had a server error, as opposed to refusing the request.
+func serverUnavailable(err error) bool {
+	if httpErr, ok := err.(*HTTPError); ok {
+		return httpErr.HTTPStatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// cachedResponse returns a response with the given body, as if it were received from Traffic Ops.
+func cachedResponse(body []byte) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}
+}
+
+type cacheControl struct {
+	noStore        bool
+	noCache        bool
+	mustRevalidate bool
+	maxAge         time.Duration
+	hasMaxAge      bool
+}
+
+// parseCacheControl parses the directives of a Cache-Control header which matter to a private client cache. Unknown directives are ignored.
+func parseCacheControl(header string) cacheControl {
+	cc := cacheControl{}
+	for _, directive := range strings.Split(header, ",") {
+		name, val := strings.TrimSpace(directive), ""
+		if i := strings.Index(name, "="); i >= 0 {
+			name, val = strings.TrimSpace(name[:i]), strings.Trim(strings.TrimSpace(name[i+1:]), `"`)
+		}
+		switch strings.ToLower(name) {
+		case "no-store":
+			cc.noStore = true
+		case "no-cache":
+			cc.noCache = true
+		case "must-revalidate", "proxy-revalidate":
+			cc.mustRevalidate = true
+		case "max-age":
+			if seconds, err := strconv.Atoi(val); err == nil && seconds >= 0 {
+				cc.maxAge = time.Duration(seconds) * time.Second
+				cc.hasMaxAge = true
+			}
+		}
+	}
+	return cc
+}
+
+// freshness returns how long a response with the given headers is fresh: the max-age, or else the time until Expires, or else the given default.
+func freshness(header http.Header, cc cacheControl, now time.Time, defaultTTL time.Duration) time.Duration {
+	if cc.noCache {
+		return 0
+	}
+	if cc.hasMaxAge {
+		return cc.maxAge
+	}
+	if expiresStr := header.Get("Expires"); expiresStr != "" {
+		expires, err := http.ParseTime(expiresStr)
+		if err != nil {
+			return 0 // an invalid Expires means already expired, per RFC 7234
+		}
+		date := now
+		if d, err := http.ParseTime(header.Get("Date")); err == nil {
+			date = d
+		}
+		if ttl := expires.Sub(date); ttl > 0 {
+			return ttl
+		}
+		return 0
+	}
+	return defaultTTL
+}
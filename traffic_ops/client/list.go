@@ -0,0 +1,121 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// DefaultPageLimit is the page size iterators request, if the ListOptions don't set a Limit.
+const DefaultPageLimit = 500
+
+// ListOptions are the query parameters of a list request. Filters are passed as-is, e.g. `type=EDGE` or `cdn=1`; which filters an endpoint supports depends on the endpoint. OrderBy is the field to sort by. Page is the 1-based page of Limit results to return. Zero values aren't sent, so Traffic Ops uses its defaults.
+type ListOptions struct {
+	Filters url.Values
+	OrderBy string
+	Page    int
+	Limit   int
+}
+
+// Values returns the options as URL query values.
+func (o ListOptions) Values() url.Values {
+	v := url.Values{}
+	for key, vals := range o.Filters {
+		v[key] = append([]string(nil), vals...)
+	}
+	if o.OrderBy != "" {
+		v.Set("orderby", o.OrderBy)
+	}
+	if o.Page > 0 {
+		v.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.Limit > 0 {
+		v.Set("limit", strconv.Itoa(o.Limit))
+	}
+	return v
+}
+
+// endpoint returns the given endpoint with the options as its query string.
+func (o ListOptions) endpoint(ep string) string {
+	if q := o.Values().Encode(); q != "" {
+		return ep + "?" + q
+	}
+	return ep
+}
+
+// pageFetcher fetches the page of the given options into its iterator, and returns the number of results and a key identifying the first result.
+type pageFetcher func(ctx context.Context, opts ListOptions) (n int, firstKey string, err error)
+
+// pager is the paging state shared by the typed iterators. The typed iterator holds the current page, and i is the index of the current result in it.
+type pager struct {
+	ctx      context.Context
+	opts     ListOptions
+	fetch    pageFetcher
+	n        int
+	i        int
+	firstKey string
+	done     bool
+	err      error
+}
+
+func newPager(ctx context.Context, opts ListOptions, fetch pageFetcher) pager {
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultPageLimit
+	}
+	if opts.Page <= 0 {
+		opts.Page = 1
+	}
+	opts.Page-- // next increments the page before each fetch
+	return pager{ctx: ctx, opts: opts, fetch: fetch, i: -1}
+}
+
+// next advances to the next result, fetching the next page if the current one is exhausted. Returns false when there are no more results, or an error occurred.
+//
+// A page shorter than the limit is the last. Not every Traffic Ops endpoint pages, and those which don't return every result regardless of the page; so a page longer than the limit is also the last, and a page starting with the same result as the previous page is discarded.
+func (p *pager) next() bool {
+	if p.err != nil {
+		return false
+	}
+	if p.i+1 < p.n {
+		p.i++
+		return true
+	}
+	if p.done {
+		return false
+	}
+	p.opts.Page++
+	n, firstKey, err := p.fetch(p.ctx, p.opts)
+	if err != nil {
+		p.err = err
+		return false
+	}
+	if n > 0 && p.n > 0 && firstKey == p.firstKey {
+		p.done = true
+		return false
+	}
+	p.n, p.i, p.firstKey = n, 0, firstKey
+	if n != p.opts.Limit {
+		p.done = true
+	}
+	return n > 0
+}
+
+// Err returns the error which stopped iteration, if any.
+func (p *pager) Err() error {
+	return p.err
+}
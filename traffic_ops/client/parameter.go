@@ -16,8 +16,10 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 )
 
 // ParamResponse ...
@@ -27,16 +29,51 @@ type ParamResponse struct {
 
 // Parameter ...
 type Parameter struct {
-	Name        string `json:"name"`
-	ConfigFile  string `json:"configFile"`
-	Value       string `json:"Value"`
-	LastUpdated string `json:"lastUpdated"`
+	ID          int             `json:"id,omitempty"`
+	Name        string          `json:"name"`
+	ConfigFile  string          `json:"configFile"`
+	Value       string          `json:"value"`
+	Secure      ParameterSecure `json:"secure"`
+	LastUpdated string          `json:"lastUpdated,omitempty"`
+}
+
+// ParameterSecure is whether a Parameter's value is concealed from users who aren't admins. Traffic Ops returns it as a JSON boolean when reading parameters, but as 0 or 1 when creating or updating them, so it unmarshals from either.
+type ParameterSecure bool
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ParameterSecure) UnmarshalJSON(b []byte) error {
+	switch string(b) {
+	case "true", "1":
+		*s = true
+	case "false", "0", "null":
+		*s = false
+	default:
+		return fmt.Errorf("invalid parameter secure value %s", b)
+	}
+	return nil
+}
+
+// CreateParametersResponse is the JSON object returned when Parameters are created
+type CreateParametersResponse struct {
+	Response []Parameter `json:"response"`
+	Alerts   []Alert     `json:"alerts"`
+}
+
+// UpdateParameterResponse is the JSON object returned when a Parameter is updated
+type UpdateParameterResponse struct {
+	Response Parameter `json:"response"`
+	Alerts   []Alert   `json:"alerts"`
+}
+
+// DeleteParameterResponse is the JSON object returned when a Parameter is deleted
+type DeleteParameterResponse struct {
+	Alerts []Alert `json:"alerts"`
 }
 
 // Parameters gets an array of parameter structs for the profile given
-func (to *Session) Parameters(profileName string) ([]Parameter, error) {
+func (to *Session) Parameters(ctx context.Context, profileName string) ([]Parameter, error) {
 	url := fmt.Sprintf("/api/1.2/parameters/profile/%s.json", profileName)
-	resp, err := to.request("GET", url, nil)
+	resp, err := to.request(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -49,3 +86,52 @@ func (to *Session) Parameters(profileName string) ([]Parameter, error) {
 
 	return data.Response, nil
 }
+
+// CreateParameters creates the Parameters it's passed. Traffic Ops creates them in a single transaction, so if any Parameter is invalid or already exists, none are created.
+func (to *Session) CreateParameters(ctx context.Context, params []Parameter) (*CreateParametersResponse, error) {
+	var data CreateParametersResponse
+	jsonReq, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	err = post(ctx, to, parametersEp(), jsonReq, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// UpdateParameter updates the Parameter matching the ID it's passed with the Parameter it is passed. Empty fields are left unchanged.
+func (to *Session) UpdateParameter(ctx context.Context, id int, param *Parameter) (*UpdateParameterResponse, error) {
+	var data UpdateParameterResponse
+	jsonReq, err := json.Marshal(param)
+	if err != nil {
+		return nil, err
+	}
+	err = put(ctx, to, parameterEp(id), jsonReq, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// DeleteParameter deletes the Parameter matching the ID it's passed. Traffic Ops refuses to delete a Parameter assigned to any Profile.
+func (to *Session) DeleteParameter(ctx context.Context, id int) (*DeleteParameterResponse, error) {
+	var data DeleteParameterResponse
+	err := del(ctx, to, parameterEp(id), &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func parametersEp() string {
+	return apiBase + "/parameters"
+}
+
+func parameterEp(id int) string {
+	return parametersEp() + "/" + strconv.Itoa(id)
+}
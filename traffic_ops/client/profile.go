@@ -15,7 +15,11 @@
 
 package client
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
 
 // ProfileResponse ...
 type ProfileResponse struct {
@@ -28,21 +32,88 @@ type Profile struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	LastUpdated string `json:"lastUpdated"`
+	CDNID       int    `json:"cdn,omitempty"`
+	Type        string `json:"type,omitempty"`
+}
+
+// CreateProfileResponse is the JSON object returned when a Profile is created
+type CreateProfileResponse struct {
+	Response Profile `json:"response"`
+	Alerts   []Alert `json:"alerts"`
+}
+
+// UpdateProfileResponse is the JSON object returned when a Profile is updated
+type UpdateProfileResponse struct {
+	Response Profile `json:"response"`
+	Alerts   []Alert `json:"alerts"`
+}
+
+// DeleteProfileResponse is the JSON object returned when a Profile is deleted
+type DeleteProfileResponse struct {
+	Alerts []Alert `json:"alerts"`
 }
 
 // Profiles gets an array of Profiles
-func (to *Session) Profiles() ([]Profile, error) {
-	url := "/api/1.2/profiles.json"
-	resp, err := to.request("GET", url, nil)
+func (to *Session) Profiles(ctx context.Context) ([]Profile, error) {
+	return to.ListProfiles(ctx, ListOptions{})
+}
+
+// ListProfiles gets an array of the Profiles matching the given options
+func (to *Session) ListProfiles(ctx context.Context, opts ListOptions) ([]Profile, error) {
+	var data ProfileResponse
+	err := get(ctx, to, opts.endpoint(profilesEp()+".json"), &data)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var data ProfileResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+	return data.Response, nil
+}
+
+// CreateProfile creates the Profile it's passed. Traffic Ops requires the Name, Description and Type.
+func (to *Session) CreateProfile(ctx context.Context, profile *Profile) (*CreateProfileResponse, error) {
+	var data CreateProfileResponse
+	jsonReq, err := json.Marshal(profile)
+	if err != nil {
+		return nil, err
+	}
+	err = post(ctx, to, profilesEp(), jsonReq, &data)
+	if err != nil {
 		return nil, err
 	}
 
-	return data.Response, nil
+	return &data, nil
+}
+
+// UpdateProfile updates the Profile matching the ID it's passed with the Profile it is passed
+func (to *Session) UpdateProfile(ctx context.Context, id int, profile *Profile) (*UpdateProfileResponse, error) {
+	var data UpdateProfileResponse
+	jsonReq, err := json.Marshal(profile)
+	if err != nil {
+		return nil, err
+	}
+	err = put(ctx, to, profileEp(id), jsonReq, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// DeleteProfile deletes the Profile matching the ID it's passed. Traffic Ops refuses to delete a Profile used by servers or delivery services.
+func (to *Session) DeleteProfile(ctx context.Context, id int) (*DeleteProfileResponse, error) {
+	var data DeleteProfileResponse
+	err := del(ctx, to, profileEp(id), &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func profilesEp() string {
+	return apiBase + "/profiles"
+}
+
+func profileEp(id int) string {
+	return profilesEp() + "/" + strconv.Itoa(id)
 }
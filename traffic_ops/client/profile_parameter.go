@@ -0,0 +1,73 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+// ProfileParameter is the assignment of a Parameter to a Profile, by ID.
+type ProfileParameter struct {
+	ProfileID   int `json:"profileId"`
+	ParameterID int `json:"parameterId"`
+}
+
+// CreateProfileParametersResponse is the JSON object returned when Parameters are assigned to Profiles
+type CreateProfileParametersResponse struct {
+	Response []ProfileParameter `json:"response"`
+	Alerts   []Alert            `json:"alerts"`
+}
+
+// DeleteProfileParameterResponse is the JSON object returned when a Parameter is removed from a Profile
+type DeleteProfileParameterResponse struct {
+	Alerts []Alert `json:"alerts"`
+}
+
+// CreateProfileParameters assigns Parameters to Profiles. Traffic Ops creates the assignments in a single transaction, so if any Profile or Parameter doesn't exist, or any Parameter is already assigned to the Profile, none are created.
+func (to *Session) CreateProfileParameters(ctx context.Context, pps []ProfileParameter) (*CreateProfileParametersResponse, error) {
+	var data CreateProfileParametersResponse
+	jsonReq, err := json.Marshal(pps)
+	if err != nil {
+		return nil, err
+	}
+	err = post(ctx, to, profileParametersEp(), jsonReq, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// DeleteProfileParameter removes the Parameter matching the parameter ID it's passed from the Profile matching the profile ID. The Parameter itself isn't deleted.
+func (to *Session) DeleteProfileParameter(ctx context.Context, profileID, parameterID int) (*DeleteProfileParameterResponse, error) {
+	var data DeleteProfileParameterResponse
+	err := del(ctx, to, profileParameterEp(profileID, parameterID), &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func profileParametersEp() string {
+	return apiBase + "/profileparameters"
+}
+
+func profileParameterEp(profileID, parameterID int) string {
+	return profileParametersEp() + "/" + strconv.Itoa(profileID) + "/" + strconv.Itoa(parameterID)
+}
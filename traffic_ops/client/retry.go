@@ -0,0 +1,83 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package client
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy is how a Session retries idempotent requests (GET, HEAD, PUT, DELETE, OPTIONS) which fail with a network error, or with a Too Many Requests, Bad Gateway, Service Unavailable or Gateway Timeout status. MaxAttempts is the maximum number of requests, including the first; 1 or less never retries.
+// Retries wait a random duration between zero and InitialBackoff doubled for each previous retry, capped at MaxBackoff ("full jitter"), so many clients failing at once don't retry in lockstep. A Retry-After header from Traffic Ops is honored, if it's no longer than MaxBackoff.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy is the RetryPolicy of new Sessions.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+}
+
+// NoRetryPolicy never retries.
+var NoRetryPolicy = RetryPolicy{MaxAttempts: 1}
+
+// Backoff returns how long to wait after the given attempt failed, before the next attempt. Attempts start at 1.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	max := p.InitialBackoff
+	for i := 1; i < attempt && max < p.MaxBackoff; i++ {
+		max *= 2
+	}
+	if max > p.MaxBackoff {
+		max = p.MaxBackoff
+	}
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max) + 1))
+}
+
+// idempotent returns whether requests with the given method may safely be retried.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// retryableStatus returns whether the given response status is likely temporary.
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter returns the duration of a Retry-After header in seconds, or 0 if it's empty or an HTTP date, which aren't supported.
+func parseRetryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
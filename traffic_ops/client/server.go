@@ -16,9 +16,11 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 )
 
@@ -67,29 +69,85 @@ type Server struct {
 	Type           string `json:"type"`
 	XMPPID         string `json:"xmppId"`
 	XMPPPasswd     string `json:"xmppPasswd"`
+
+	CachegroupID   int    `json:"cachegroupId,omitempty"`
+	CDNID          int    `json:"cdnId,omitempty"`
+	PhysLocationID int    `json:"physLocationId,omitempty"`
+	ProfileID      int    `json:"profileId,omitempty"`
+	StatusID       int    `json:"statusId,omitempty"`
+	TypeID         int    `json:"typeId,omitempty"`
+	HTTPSPort      int    `json:"httpsPort,omitempty"`
+	OfflineReason  string `json:"offlineReason,omitempty"`
+	UpdPending     *bool  `json:"updPending,omitempty"`
+}
+
+// CreateServerResponse is the JSON object returned when a server is created
+type CreateServerResponse struct {
+	Response []Server `json:"response"`
+	Alerts   []Alert  `json:"alerts"`
+}
+
+// UpdateServerResponse is the JSON object returned when a server is updated
+type UpdateServerResponse struct {
+	Response []Server `json:"response"`
+	Alerts   []Alert  `json:"alerts"`
+}
+
+// DeleteServerResponse is the JSON object returned when a server is deleted
+type DeleteServerResponse struct {
+	Alerts []Alert `json:"alerts"`
 }
 
 // Servers gets an array of servers
-func (to *Session) Servers() ([]Server, error) {
-	url := "/api/1.2/servers.json"
-	resp, err := to.request("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+func (to *Session) Servers(ctx context.Context) ([]Server, error) {
+	return to.ListServers(ctx, ListOptions{})
+}
 
+// ListServers gets an array of the servers matching the given options. Traffic Ops filters servers by one of `dsId`, `type`, `profileId`, `cdn`, `cachegroup` or `physLocation`, and `status`.
+func (to *Session) ListServers(ctx context.Context, opts ListOptions) ([]Server, error) {
 	var data ServerResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+	err := get(ctx, to, opts.endpoint(serversEp()+".json"), &data)
+	if err != nil {
 		return nil, err
 	}
 
 	return data.Response, nil
 }
 
+// ServerIterator pages through servers. Call Next to advance to each server, and Err after Next returns false.
+type ServerIterator struct {
+	pager
+	page []Server
+}
+
+// IterateServers returns an iterator over the servers matching the given options, which fetches them a page at a time.
+func (to *Session) IterateServers(ctx context.Context, opts ListOptions) *ServerIterator {
+	it := &ServerIterator{}
+	it.pager = newPager(ctx, opts, func(ctx context.Context, opts ListOptions) (int, string, error) {
+		servers, err := to.ListServers(ctx, opts)
+		if err != nil || len(servers) == 0 {
+			return 0, "", err
+		}
+		it.page = servers
+		return len(servers), strconv.Itoa(servers[0].ID), nil
+	})
+	return it
+}
+
+// Next advances to the next server, and returns whether there is one.
+func (it *ServerIterator) Next() bool {
+	return it.next()
+}
+
+// Server returns the current server. It must only be called after Next returns true.
+func (it *ServerIterator) Server() Server {
+	return it.page[it.i]
+}
+
 // Server gets a server by hostname
-func (to *Session) Server(name string) (*Server, error) {
+func (to *Session) Server(ctx context.Context, name string) (*Server, error) {
 	url := fmt.Sprintf("/api/1.2/servers/hostname/%s/details", name)
-	resp, err := to.request("GET", url, nil)
+	resp, err := to.request(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -104,26 +162,55 @@ func (to *Session) Server(name string) (*Server, error) {
 }
 
 // ServersByType gets an array of serves of a specified type.
-func (to *Session) ServersByType(qparams url.Values) ([]Server, error) {
-	url := fmt.Sprintf("/api/1.2/servers.json?%s", qparams.Encode())
-	resp, err := to.request("GET", url, nil)
+func (to *Session) ServersByType(ctx context.Context, qparams url.Values) ([]Server, error) {
+	return to.ListServers(ctx, ListOptions{Filters: qparams})
+}
+
+// CreateServer creates the server it's passed. Traffic Ops requires the cachegroup, CDN, physical location, profile, status and type by ID, in CachegroupID, CDNID, PhysLocationID, ProfileID, StatusID and TypeID; the names are ignored.
+func (to *Session) CreateServer(ctx context.Context, server *Server) (*CreateServerResponse, error) {
+	var data CreateServerResponse
+	jsonReq, err := json.Marshal(server)
+	if err != nil {
+		return nil, err
+	}
+	err = post(ctx, to, serversEp(), jsonReq, &data)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var data ServerResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+	return &data, nil
+}
+
+// UpdateServer updates the server matching the ID it's passed with the server it is passed
+func (to *Session) UpdateServer(ctx context.Context, id int, server *Server) (*UpdateServerResponse, error) {
+	var data UpdateServerResponse
+	jsonReq, err := json.Marshal(server)
+	if err != nil {
+		return nil, err
+	}
+	err = put(ctx, to, serverEp(id), jsonReq, &data)
+	if err != nil {
 		return nil, err
 	}
 
-	return data.Response, nil
+	return &data, nil
+}
+
+// DeleteServer deletes the server matching the ID it's passed
+func (to *Session) DeleteServer(ctx context.Context, id int) (*DeleteServerResponse, error) {
+	var data DeleteServerResponse
+	err := del(ctx, to, serverEp(id), &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
 }
 
 // ServersFqdn returns a the full domain name for the server short name passed in.
-func (to *Session) ServersFqdn(n string) (string, error) {
+func (to *Session) ServersFqdn(ctx context.Context, n string) (string, error) {
 	fdn := ""
-	servers, err := to.Servers()
+	servers, err := to.Servers(ctx)
 	if err != nil {
 		return "Error", err
 	}
@@ -140,9 +227,9 @@ func (to *Session) ServersFqdn(n string) (string, error) {
 }
 
 // ServersShortNameSearch returns a slice of short server names that match a greedy match.
-func (to *Session) ServersShortNameSearch(shortname string) ([]string, error) {
+func (to *Session) ServersShortNameSearch(ctx context.Context, shortname string) ([]string, error) {
 	var serverlst []string
-	servers, err := to.Servers()
+	servers, err := to.Servers(ctx)
 	if err != nil {
 		serverlst = append(serverlst, "N/A")
 		return serverlst, err
@@ -158,3 +245,11 @@ func (to *Session) ServersShortNameSearch(shortname string) ([]string, error) {
 	}
 	return serverlst, nil
 }
+
+func serversEp() string {
+	return apiBase + "/servers"
+}
+
+func serverEp(id int) string {
+	return serversEp() + "/" + strconv.Itoa(id)
+}
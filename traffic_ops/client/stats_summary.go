@@ -13,6 +13,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -42,7 +43,7 @@ type LastUpdated struct {
 }
 
 // SummaryStats ...
-func (to *Session) SummaryStats(cdn string, deliveryService string, statName string) ([]StatsSummary, error) {
+func (to *Session) SummaryStats(ctx context.Context, cdn string, deliveryService string, statName string) ([]StatsSummary, error) {
 	var queryParams []string
 	if len(cdn) > 0 {
 		queryParams = append(queryParams, fmt.Sprintf("cdnName=%s", cdn))
@@ -66,7 +67,7 @@ func (to *Session) SummaryStats(cdn string, deliveryService string, statName str
 		queryURL += queryParamString
 	}
 
-	resp, err := to.request("GET", queryURL, nil)
+	resp, err := to.request(ctx, "GET", queryURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -81,13 +82,13 @@ func (to *Session) SummaryStats(cdn string, deliveryService string, statName str
 }
 
 // SummaryStatsLastUpdated ...
-func (to *Session) SummaryStatsLastUpdated(statName string) (string, error) {
+func (to *Session) SummaryStatsLastUpdated(ctx context.Context, statName string) (string, error) {
 	queryURL := "/api/1.2/stats_summary.json?lastSummaryDate=true"
 	if len(statName) > 0 {
 		queryURL += fmt.Sprintf("?statName=%s", statName)
 	}
 
-	resp, err := to.request("GET", queryURL, nil)
+	resp, err := to.request(ctx, "GET", queryURL, nil)
 	if err != nil {
 		return "", err
 	}
@@ -106,14 +107,14 @@ func (to *Session) SummaryStatsLastUpdated(statName string) (string, error) {
 }
 
 // AddSummaryStats ...
-func (to *Session) AddSummaryStats(statsSummary StatsSummary) error {
+func (to *Session) AddSummaryStats(ctx context.Context, statsSummary StatsSummary) error {
 	reqBody, err := json.Marshal(statsSummary)
 	if err != nil {
 		return err
 	}
 
 	url := "/api/1.2/stats_summary/create"
-	resp, err := to.request("POST", url, reqBody)
+	resp, err := to.request(ctx, "POST", url, reqBody)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,153 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// tableResponse is the response of the 2.0 API. Errors are returned as alerts with the level "error", with an OK status.
+type tableResponse struct {
+	Response json.RawMessage `json:"response"`
+	Error    string          `json:"error"`
+	Version  float64         `json:"version"`
+	Alerts   []Alert         `json:"alerts"`
+}
+
+// AlertsError is returned when the 2.0 API responds with error alerts.
+type AlertsError struct {
+	URL    string
+	Alerts []Alert
+}
+
+func (e *AlertsError) Error() string {
+	texts := []string{}
+	for _, alert := range e.Alerts {
+		if alert.Level == "error" {
+			texts = append(texts, alert.Text)
+		}
+	}
+	return fmt.Sprintf("%v: %v", e.URL, strings.Join(texts, "; "))
+}
+
+// GetTable gets the rows of the given table of the 2.0 API, e.g. "cdn" or "deliveryservice", decoding them into rows, which should be a pointer to a slice of structs or maps. The 2.0 API requires a bearer token, so the Session should use AuthToken or AuthAPIKey.
+func (to *Session) GetTable(ctx context.Context, table string, rows interface{}) error {
+	_, err := to.tableRequest(ctx, http.MethodGet, tableEp(table, ""), nil, rows)
+	return err
+}
+
+// GetTableRow gets the row of the given table with the given key, decoding it into rows, which should be a pointer to a slice like GetTable's, because the 2.0 API returns single rows as a list. The key is the rest of the row's path, e.g. "cdn1" for the cdn table, or "deliveryservice/ds1/server/edge1" for deliveryservice_server.
+func (to *Session) GetTableRow(ctx context.Context, table string, key string, rows interface{}) error {
+	_, err := to.tableRequest(ctx, http.MethodGet, tableEp(table, key), nil, rows)
+	return err
+}
+
+// CreateTableRow creates a row in the given table of the 2.0 API, from row's JSON, and returns the alerts of the response.
+func (to *Session) CreateTableRow(ctx context.Context, table string, row interface{}) ([]Alert, error) {
+	body, err := json.Marshal(row)
+	if err != nil {
+		return nil, err
+	}
+	return to.tableRequest(ctx, http.MethodPost, tableEp(table, ""), body, nil)
+}
+
+// UpdateTableRow updates the row of the given table with the given key, see GetTableRow, from row's JSON, and returns the alerts of the response.
+func (to *Session) UpdateTableRow(ctx context.Context, table string, key string, row interface{}) ([]Alert, error) {
+	body, err := json.Marshal(row)
+	if err != nil {
+		return nil, err
+	}
+	return to.tableRequest(ctx, http.MethodPut, tableEp(table, key), body, nil)
+}
+
+// DeleteTableRow deletes the row of the given table with the given key, see GetTableRow, and returns the alerts of the response.
+func (to *Session) DeleteTableRow(ctx context.Context, table string, key string) ([]Alert, error) {
+	return to.tableRequest(ctx, http.MethodDelete, tableEp(table, key), nil, nil)
+}
+
+// TableOperation is one operation of a bulk request to the 2.0 API. Method is http.MethodPost to create a row, http.MethodPut to update one, or http.MethodDelete to delete one. Path is the table, or the table and key of the row, e.g. "cdn/cdn1". Row is the row to create or update.
+type TableOperation struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Row    interface{} `json:"body,omitempty"`
+}
+
+// TableOperationResult is the result of the bulk operation at Index. Status is "ok", "failed" with the Error, "rolled back" if a later operation failed, or "skipped" if an earlier one did.
+type TableOperationResult struct {
+	Index        int    `json:"index"`
+	Method       string `json:"method"`
+	Path         string `json:"path"`
+	Status       string `json:"status"`
+	RowsAffected int64  `json:"rowsAffected"`
+	Error        string `json:"error,omitempty"`
+}
+
+// BulkTable applies the given operations to the tables of the 2.0 API in order, in one transaction, and returns the result of each. If any operation fails, none are applied, and an *AlertsError is returned with the results, which say which operation failed.
+func (to *Session) BulkTable(ctx context.Context, ops []TableOperation) ([]TableOperationResult, error) {
+	body, err := json.Marshal(ops)
+	if err != nil {
+		return nil, err
+	}
+	results := []TableOperationResult{}
+	_, err = to.tableRequest(ctx, http.MethodPost, apiBase20+"/bulk", body, &results)
+	return results, err
+}
+
+// tableRequest makes a request to the 2.0 API, decoding the response into resp if it isn't nil, and returns the alerts of the response. If the response has error alerts, an *AlertsError is returned, after decoding any response. The 2.0 API responds Bad Request to failed requests with the usual response, e.g. the results of a failed bulk request, so those are decoded too.
+func (to *Session) tableRequest(ctx context.Context, method string, path string, body []byte, resp interface{}) ([]Alert, error) {
+	data := tableResponse{}
+	httpResp, err := to.request(ctx, method, path, body)
+	if httpErr, ok := err.(*HTTPError); ok && httpErr.HTTPStatusCode == http.StatusBadRequest {
+		if json.Unmarshal(httpErr.body, &data) != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	} else {
+		defer httpResp.Body.Close()
+		if err := json.NewDecoder(httpResp.Body).Decode(&data); err != nil {
+			return nil, err
+		}
+	}
+	if data.Error != "" {
+		data.Alerts = append(data.Alerts, Alert{Level: "error", Text: data.Error})
+	}
+	var decodeErr error
+	if resp != nil && len(data.Response) > 0 {
+		decodeErr = json.Unmarshal(data.Response, resp)
+	}
+	for _, alert := range data.Alerts {
+		if alert.Level == "error" {
+			return data.Alerts, &AlertsError{URL: to.URL + path, Alerts: data.Alerts}
+		}
+	}
+	if err != nil {
+		return data.Alerts, err
+	}
+	return data.Alerts, decodeErr
+}
+
+func tableEp(table string, key string) string {
+	ep := apiBase20 + "/" + table
+	if key != "" {
+		ep += "/" + key
+	}
+	return ep
+}
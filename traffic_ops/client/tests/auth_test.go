@@ -0,0 +1,224 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client/totest"
+	"github.com/jheitz200/test_helper"
+)
+
+func authServer() *totest.Server {
+	tos := totest.NewServer()
+	tos.AddUser("admin", "password")
+	tos.AddServer(client.Server{HostName: "edge1"})
+	return tos
+}
+
+func TestTokenLogin(t *testing.T) {
+	tos := authServer()
+	defer tos.Close()
+
+	testHelper.Context(t, "Given the need to test logging in for a bearer token")
+
+	if _, err := client.LoginWithToken(context.Background(), tos.URL, "admin", "wrong", true, "test", false, client.DefaultTimeout); err == nil {
+		testHelper.Error(t, "Should not be able to log in with the wrong password")
+	}
+
+	to, err := client.LoginWithToken(context.Background(), tos.URL, "admin", "password", true, "test", false, client.DefaultTimeout)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to log in, got: %v", err)
+	}
+	to.Client.Jar = nil // so only the token authenticates
+	if servers, err := to.Servers(context.Background()); err != nil || len(servers) != 1 {
+		testHelper.Error(t, "Should be able to get servers with the token, got: %v %v", servers, err)
+	} else {
+		testHelper.Success(t, "Should be able to get servers with the token")
+	}
+
+	tos.ExpireSessions()
+	if _, err := to.Servers(context.Background()); err != nil {
+		testHelper.Error(t, "Should log in again when the token is rejected, got: %v", err)
+	} else if tos.Logins() != 2 {
+		testHelper.Error(t, "Should log in again once, logged in %v times", tos.Logins())
+	} else {
+		testHelper.Success(t, "Should log in again when the token is rejected")
+	}
+}
+
+func TestTokenRefresh(t *testing.T) {
+	tos := authServer()
+	defer tos.Close()
+
+	testHelper.Context(t, "Given the need to test that tokens are refreshed before they expire")
+
+	to, err := client.LoginWithToken(context.Background(), tos.URL, "admin", "password", true, "test", false, client.DefaultTimeout)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to log in, got: %v", err)
+	}
+
+	if _, err := to.Servers(context.Background()); err != nil || tos.Logins() != 1 {
+		testHelper.Error(t, "Should not refresh a token which doesn't expire soon, logged in %v times: %v", tos.Logins(), err)
+	}
+
+	to.TokenRefreshBefore = 2 * totest.DefaultTokenTTL
+	if _, err := to.Servers(context.Background()); err != nil {
+		testHelper.Error(t, "Should be able to get servers, got: %v", err)
+	} else if tos.Logins() != 2 {
+		testHelper.Error(t, "Should refresh a token which expires soon, logged in %v times", tos.Logins())
+	} else {
+		testHelper.Success(t, "Should refresh a token which expires soon")
+	}
+
+	tos.SetUnavailable(true)
+	to.RetryPolicy = client.NoRetryPolicy
+	_, err = to.Servers(context.Background())
+	if httpErr, ok := err.(*client.HTTPError); !ok || httpErr.HTTPStatusCode != http.StatusServiceUnavailable {
+		testHelper.Error(t, "Should use the unexpired token when refreshing fails, got: %v", err)
+	} else {
+		testHelper.Success(t, "Should use the unexpired token when refreshing fails")
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	tos := authServer()
+	defer tos.Close()
+	tos.AddAPIKey("key1")
+
+	testHelper.Context(t, "Given the need to test authenticating with an API key")
+
+	to, err := client.NewSessionWithAPIKey(tos.URL, "key1", true, "test", false, client.DefaultTimeout)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to create a session, got: %v", err)
+	}
+	if servers, err := to.Servers(context.Background()); err != nil || len(servers) != 1 {
+		testHelper.Error(t, "Should be able to get servers with the API key, got: %v %v", servers, err)
+	} else {
+		testHelper.Success(t, "Should be able to get servers with the API key")
+	}
+
+	to, err = client.NewSessionWithAPIKey(tos.URL, "key2", true, "test", false, client.DefaultTimeout)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to create a session, got: %v", err)
+	}
+	_, err = to.Servers(context.Background())
+	if httpErr, ok := err.(*client.HTTPError); !ok || httpErr.HTTPStatusCode != http.StatusUnauthorized {
+		testHelper.Error(t, "Should get Unauthorized with an invalid API key, got: %v", err)
+	} else if tos.Logins() != 0 {
+		testHelper.Error(t, "Should not log in with an API key, logged in %v times", tos.Logins())
+	} else {
+		testHelper.Success(t, "Should get Unauthorized with an invalid API key")
+	}
+}
+
+func TestAuthConfigFromEnv(t *testing.T) {
+	tos := authServer()
+	defer tos.Close()
+
+	testHelper.Context(t, "Given the need to test reading credentials from the environment")
+
+	env := map[string]string{client.EnvURL: tos.URL, client.EnvUser: "admin", client.EnvPassword: "password", client.EnvAuth: string(client.AuthToken)}
+	for name, val := range env {
+		os.Setenv(name, val)
+		defer os.Unsetenv(name)
+	}
+
+	cfg := client.AuthConfigFromEnv()
+	if cfg.URL != tos.URL || cfg.User != "admin" || cfg.Password != "password" || cfg.Auth != client.AuthToken {
+		testHelper.Fatal(t, "Should read the environment, got: %+v", cfg)
+	}
+	to, err := client.LoginWithAuthConfig(context.Background(), cfg, true, "test", false, client.DefaultTimeout)
+	if err != nil || to.Auth != client.AuthToken {
+		testHelper.Error(t, "Should log in for a token, got: %v", err)
+	} else {
+		testHelper.Success(t, "Should log in with credentials from the environment")
+	}
+}
+
+func TestLoadAuthConfig(t *testing.T) {
+	tos := authServer()
+	defer tos.Close()
+
+	dir, err := ioutil.TempDir("", "toauth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "password"), []byte("password\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "credentials.json")
+	if err := ioutil.WriteFile(path, []byte(`{"url": "`+tos.URL+`", "user": "admin", "passwordFile": "password"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testHelper.Context(t, "Given the need to test reading credentials from a file")
+
+	cfg, err := client.LoadAuthConfig(path)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to load the file, got: %v", err)
+	}
+	if _, err := client.LoginWithAuthConfig(context.Background(), cfg, true, "test", false, client.DefaultTimeout); err != nil {
+		testHelper.Error(t, "Should log in with the password file, got: %v", err)
+	} else {
+		testHelper.Success(t, "Should log in with the password file")
+	}
+
+	override := cfg.Override(client.AuthConfig{Password: "wrong"})
+	if override.PasswordFile != "" || override.URL != tos.URL {
+		testHelper.Error(t, "Should override the password file with a password, got: %+v", override)
+	}
+	if _, err := client.LoginWithAuthConfig(context.Background(), override, true, "test", false, client.DefaultTimeout); err == nil {
+		testHelper.Error(t, "Should not log in with the overriding wrong password")
+	}
+
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.LoadAuthConfig(path); err == nil {
+		testHelper.Error(t, "Should not load a credentials file readable by others")
+	} else {
+		testHelper.Success(t, "Should not load a credentials file readable by others")
+	}
+}
+
+func TestAuthConfigInvalid(t *testing.T) {
+	testHelper.Context(t, "Given the need to test invalid credentials configs")
+
+	invalid := map[string]client.AuthConfig{
+		"unknown auth": {URL: "http://to.example.net", User: "admin", Auth: "kerberos"},
+		"no URL":       {User: "admin", Password: "password"},
+		"no user":      {URL: "http://to.example.net", Password: "password"},
+		"no API key":   {URL: "http://to.example.net", Auth: client.AuthAPIKey},
+	}
+	for name, cfg := range invalid {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		if _, err := client.LoginWithAuthConfig(ctx, cfg, true, "test", false, client.DefaultTimeout); err == nil {
+			testHelper.Error(t, "Should get an error for %v", name)
+		} else {
+			testHelper.Success(t, "Should get an error for %v: %v", name, err)
+		}
+		cancel()
+	}
+}
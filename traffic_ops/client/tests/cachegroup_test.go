@@ -16,6 +16,7 @@
 package test
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
@@ -33,7 +34,7 @@ func TestCacheGroup(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for CacheGroups")
 
-	cacheGroups, err := to.CacheGroups()
+	cacheGroups, err := to.CacheGroups(context.Background())
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -81,7 +82,127 @@ func TestCacheGroupsUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for CacheGroups")
 
-	_, err := to.CacheGroups()
+	_, err := to.CacheGroups(context.Background())
+	if err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+}
+
+func TestCreateCacheGroup(t *testing.T) {
+	resp := fixtures.CreateCacheGroup()
+	server := endpointServer(t, http.MethodPost, "/api/1.2/cachegroups", resp)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to create a CacheGroup")
+
+	result, err := to.CreateCacheGroup(context.Background(), &client.CacheGroup{Name: "edge-philadelphia", ShortName: "phila", TypeID: 1})
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
+	}
+
+	actual := result.Response.Name
+	if actual != "edge-philadelphia" {
+		testHelper.Error(t, "Should get back \"edge-philadelphia\" for \"Response.Name\", got: %s", actual)
+	} else {
+		testHelper.Success(t, "Should get back \"edge-philadelphia\" for \"Response.Name\"")
+	}
+}
+
+func TestCreateCacheGroupUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to create a CacheGroup")
+
+	_, err := to.CreateCacheGroup(context.Background(), &client.CacheGroup{Name: "edge-philadelphia", ShortName: "phila", TypeID: 1})
+	if err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+}
+
+func TestUpdateCacheGroup(t *testing.T) {
+	resp := fixtures.UpdateCacheGroup()
+	server := endpointServer(t, http.MethodPut, "/api/1.2/cachegroups/1", resp)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to update a CacheGroup")
+
+	result, err := to.UpdateCacheGroup(context.Background(), 1, &client.CacheGroup{Name: "edge-philadelphia", ShortName: "phila", TypeID: 1})
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
+	}
+
+	actual := result.Response.ShortName
+	if actual != "phila" {
+		testHelper.Error(t, "Should get back \"phila\" for \"Response.ShortName\", got: %s", actual)
+	} else {
+		testHelper.Success(t, "Should get back \"phila\" for \"Response.ShortName\"")
+	}
+}
+
+func TestUpdateCacheGroupUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to update a CacheGroup")
+
+	_, err := to.UpdateCacheGroup(context.Background(), 1, &client.CacheGroup{Name: "edge-philadelphia", ShortName: "phila", TypeID: 1})
+	if err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+}
+
+func TestDeleteCacheGroup(t *testing.T) {
+	resp := fixtures.DeleteCacheGroup()
+	server := endpointServer(t, http.MethodDelete, "/api/1.2/cachegroups/1", resp)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to delete a CacheGroup")
+
+	result, err := to.DeleteCacheGroup(context.Background(), 1)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
+	}
+
+	actual := result.Alerts[0].Level
+	if actual != "success" {
+		testHelper.Error(t, "Should get back \"success\" for \"Alerts[0].Level\", got: %s", actual)
+	} else {
+		testHelper.Success(t, "Should get back \"success\" for \"Alerts[0].Level\"")
+	}
+}
+
+func TestDeleteCacheGroupUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to delete a CacheGroup")
+
+	_, err := to.DeleteCacheGroup(context.Background(), 1)
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -16,6 +16,7 @@
 package test
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
@@ -33,7 +34,7 @@ func TestCDNs(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for CDNs")
 
-	cdns, err := to.CDNs()
+	cdns, err := to.CDNs(context.Background())
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -68,7 +69,7 @@ func TestCDNsUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for CDNs")
 
-	_, err := to.CDNs()
+	_, err := to.CDNs(context.Background())
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -86,7 +87,7 @@ func TestCDNName(t *testing.T) {
 	cdn := "CDN-1"
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for CDN: \"%s\"", cdn)
 
-	cdns, err := to.CDNName(cdn)
+	cdns, err := to.CDNName(context.Background(), cdn)
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -117,7 +118,127 @@ func TestCDNNameUnauthorized(t *testing.T) {
 	cdn := "CDN-1"
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for CDN: \"%s\"", cdn)
 
-	_, err := to.CDNName(cdn)
+	_, err := to.CDNName(context.Background(), cdn)
+	if err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+}
+
+func TestCreateCDN(t *testing.T) {
+	resp := fixtures.CreateCDN()
+	server := endpointServer(t, http.MethodPost, "/api/1.2/cdns", resp)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to create a CDN")
+
+	result, err := to.CreateCDN(context.Background(), &client.CDN{Name: "CDN-1", DomainName: "kabletown.com"})
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
+	}
+
+	actual := result.Response.Name
+	if actual != "CDN-1" {
+		testHelper.Error(t, "Should get back \"CDN-1\" for \"Response.Name\", got: %s", actual)
+	} else {
+		testHelper.Success(t, "Should get back \"CDN-1\" for \"Response.Name\"")
+	}
+}
+
+func TestCreateCDNUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to create a CDN")
+
+	_, err := to.CreateCDN(context.Background(), &client.CDN{Name: "CDN-1", DomainName: "kabletown.com"})
+	if err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+}
+
+func TestUpdateCDN(t *testing.T) {
+	resp := fixtures.UpdateCDN()
+	server := endpointServer(t, http.MethodPut, "/api/1.2/cdns/1", resp)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to update a CDN")
+
+	result, err := to.UpdateCDN(context.Background(), 1, &client.CDN{Name: "CDN-1", DomainName: "kabletown.com"})
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
+	}
+
+	actual := result.Response.ID
+	if actual != 1 {
+		testHelper.Error(t, "Should get back \"1\" for \"Response.ID\", got: %d", actual)
+	} else {
+		testHelper.Success(t, "Should get back \"1\" for \"Response.ID\"")
+	}
+}
+
+func TestUpdateCDNUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to update a CDN")
+
+	_, err := to.UpdateCDN(context.Background(), 1, &client.CDN{Name: "CDN-1", DomainName: "kabletown.com"})
+	if err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+}
+
+func TestDeleteCDN(t *testing.T) {
+	resp := fixtures.DeleteCDN()
+	server := endpointServer(t, http.MethodDelete, "/api/1.2/cdns/1", resp)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to delete a CDN")
+
+	result, err := to.DeleteCDN(context.Background(), 1)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
+	}
+
+	actual := result.Alerts[0].Level
+	if actual != "success" {
+		testHelper.Error(t, "Should get back \"success\" for \"Alerts[0].Level\", got: %s", actual)
+	} else {
+		testHelper.Success(t, "Should get back \"success\" for \"Alerts[0].Level\"")
+	}
+}
+
+func TestDeleteCDNUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to delete a CDN")
+
+	_, err := to.DeleteCDN(context.Background(), 1)
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -16,24 +16,25 @@
 package test
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
 	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
 	"github.com/apache/incubator-trafficcontrol/traffic_ops/client/fixtures"
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client/totest"
 	"github.com/jheitz200/test_helper"
 )
 
 func TestDeliveryServices(t *testing.T) {
-	resp := fixtures.DeliveryServices()
-	server := testHelper.ValidHTTPServer(resp)
-	defer server.Close()
-
-	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	tos := totest.NewServer()
+	defer tos.Close()
+	tos.AddDeliveryService(fixtures.DeliveryServices().Response[0])
+	to := totestSession(t, tos)
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for DeliveryServices")
 
-	ds, err := to.DeliveryServices()
+	ds, err := to.DeliveryServices(context.Background())
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -69,7 +70,7 @@ func TestDeliveryServicesUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for DeliveryServices")
 
-	_, err := to.DeliveryServices()
+	_, err := to.DeliveryServices(context.Background())
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -78,15 +79,14 @@ func TestDeliveryServicesUnauthorized(t *testing.T) {
 }
 
 func TestDeliveryService(t *testing.T) {
-	resp := fixtures.DeliveryServices()
-	server := testHelper.ValidHTTPServer(resp)
-	defer server.Close()
-
-	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	tos := totest.NewServer()
+	defer tos.Close()
+	tos.AddDeliveryService(fixtures.DeliveryServices().Response[0])
+	to := totestSession(t, tos)
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for a DeliveryService")
 
-	ds, err := to.DeliveryService("123")
+	ds, err := to.DeliveryService(context.Background(), "1")
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -114,7 +114,7 @@ func TestDeliveryServiceUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for a DeliveryService")
 
-	_, err := to.DeliveryService("123")
+	_, err := to.DeliveryService(context.Background(), "123")
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -123,15 +123,13 @@ func TestDeliveryServiceUnauthorized(t *testing.T) {
 }
 
 func TestCreateDeliveryService(t *testing.T) {
-	resp := fixtures.CreateDeliveryService()
-	server := testHelper.ValidHTTPServer(resp)
-	defer server.Close()
-
-	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	tos := totest.NewServer()
+	defer tos.Close()
+	to := totestSession(t, tos)
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to create a DeliveryService")
 
-	ds, err := to.CreateDeliveryService(&client.DeliveryService{})
+	ds, err := to.CreateDeliveryService(context.Background(), &client.DeliveryService{XMLID: "ds-test"})
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -154,7 +152,7 @@ func TestCreateDeliveryServiceUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to create a DeliveryService")
 
-	_, err := to.CreateDeliveryService(&client.DeliveryService{})
+	_, err := to.CreateDeliveryService(context.Background(), &client.DeliveryService{})
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -163,15 +161,14 @@ func TestCreateDeliveryServiceUnauthorized(t *testing.T) {
 }
 
 func TestUpdateDeliveryService(t *testing.T) {
-	resp := fixtures.UpdateDeliveryService()
-	server := testHelper.ValidHTTPServer(resp)
-	defer server.Close()
-
-	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	tos := totest.NewServer()
+	defer tos.Close()
+	tos.AddDeliveryService(fixtures.DeliveryServices().Response[0])
+	to := totestSession(t, tos)
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to update a DeliveryService")
 
-	ds, err := to.UpdateDeliveryService("123", &client.DeliveryService{})
+	ds, err := to.UpdateDeliveryService(context.Background(), "1", &client.DeliveryService{XMLID: "ds-test"})
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -194,7 +191,7 @@ func TestUpdateDeliveryServiceUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to update a DeliveryService")
 
-	_, err := to.UpdateDeliveryService("123", &client.DeliveryService{})
+	_, err := to.UpdateDeliveryService(context.Background(), "123", &client.DeliveryService{})
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -203,26 +200,28 @@ func TestUpdateDeliveryServiceUnauthorized(t *testing.T) {
 }
 
 func TestDeleteDeliveryService(t *testing.T) {
-	resp := fixtures.DeleteDeliveryService()
-	server := testHelper.ValidHTTPServer(resp)
-	defer server.Close()
-
-	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	tos := totest.NewServer()
+	defer tos.Close()
+	tos.AddDeliveryService(fixtures.DeliveryServices().Response[0])
+	to := totestSession(t, tos)
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to delete a DeliveryService")
 
-	ds, err := to.DeleteDeliveryService("123")
+	ds, err := to.DeleteDeliveryService(context.Background(), "1")
 	if err != nil {
-		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
 	} else {
 		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
 	}
 
-	actual := ds.Alerts[0].Text
-	if actual != "text" {
-		testHelper.Error(t, "Should get back \"text\" for \"Alerts[0].Text\", got: %s", actual)
+	actual := ds.Alerts[0].Level
+	if actual != "success" {
+		testHelper.Error(t, "Should get back \"success\" for \"Alerts[0].Level\", got: %s", actual)
 	} else {
-		testHelper.Success(t, "Should get back \"text\" for \"Alerts[0].Text\"")
+		testHelper.Success(t, "Should get back \"success\" for \"Alerts[0].Level\"")
+	}
+	if dses := tos.DeliveryServices(); len(dses) != 0 {
+		testHelper.Error(t, "Should delete the delivery service, got: %+v", dses)
 	}
 }
 
@@ -234,7 +233,7 @@ func TestDeleteDeliveryServiceUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to delete a DeliveryService")
 
-	_, err := to.DeleteDeliveryService("123")
+	_, err := to.DeleteDeliveryService(context.Background(), "123")
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -251,7 +250,7 @@ func TestDeliveryServiceState(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for a DeliveryServiceState")
 
-	state, err := to.DeliveryServiceState("123")
+	state, err := to.DeliveryServiceState(context.Background(), "123")
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -279,7 +278,7 @@ func TestDeliveryServiceStateUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for a DeliveryServiceState")
 
-	_, err := to.DeliveryServiceState("123")
+	_, err := to.DeliveryServiceState(context.Background(), "123")
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -296,7 +295,7 @@ func TestDeliveryServiceHealth(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for a DeliveryServiceHealth")
 
-	health, err := to.DeliveryServiceHealth("123")
+	health, err := to.DeliveryServiceHealth(context.Background(), "123")
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -330,7 +329,7 @@ func TestDeliveryServiceHealthUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for a DeliveryServiceHealth")
 
-	_, err := to.DeliveryServiceHealth("123")
+	_, err := to.DeliveryServiceHealth(context.Background(), "123")
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -347,7 +346,7 @@ func TestDeliveryServiceCapacity(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for a DeliveryServiceCapacity")
 
-	capacity, err := to.DeliveryServiceCapacity("123")
+	capacity, err := to.DeliveryServiceCapacity(context.Background(), "123")
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -381,7 +380,7 @@ func TestDeliveryServiceCapacityUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for a DeliveryServiceCapacity")
 
-	_, err := to.DeliveryServiceCapacity("123")
+	_, err := to.DeliveryServiceCapacity(context.Background(), "123")
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -398,7 +397,7 @@ func TestDeliveryServiceRouting(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for a DeliveryServiceRouting")
 
-	routing, err := to.DeliveryServiceRouting("123")
+	routing, err := to.DeliveryServiceRouting(context.Background(), "123")
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -420,7 +419,7 @@ func TestDeliveryServiceRoutingUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for a DeliveryServiceRouting")
 
-	_, err := to.DeliveryServiceRouting("123")
+	_, err := to.DeliveryServiceRouting(context.Background(), "123")
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -437,7 +436,7 @@ func TestDeliveryServiceServer(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for a DeliveryServiceServer")
 
-	s, err := to.DeliveryServiceServer("1", "1")
+	s, err := to.DeliveryServiceServer(context.Background(), "1", "1")
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -471,7 +470,7 @@ func TestDeliveryServiceServerUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for a DeliveryServiceServer")
 
-	_, err := to.DeliveryServiceServer("1", "1")
+	_, err := to.DeliveryServiceServer(context.Background(), "1", "1")
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -488,7 +487,7 @@ func TestDeliveryServiceSSLKeysByID(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for a DeliveryServiceSSLKeysByID")
 
-	ssl, err := to.DeliveryServiceSSLKeysByID("123")
+	ssl, err := to.DeliveryServiceSSLKeysByID(context.Background(), "123")
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -516,7 +515,7 @@ func TestDeliveryServiceSSLKeysByIDUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for a DeliveryServiceSSLKeysByID")
 
-	_, err := to.DeliveryServiceSSLKeysByID("123")
+	_, err := to.DeliveryServiceSSLKeysByID(context.Background(), "123")
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -533,7 +532,7 @@ func TestDeliveryServiceSSLKeysByHostname(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for a DeliveryServiceSSLKeysByHostname")
 
-	ssl, err := to.DeliveryServiceSSLKeysByHostname("hostname")
+	ssl, err := to.DeliveryServiceSSLKeysByHostname(context.Background(), "hostname")
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -561,7 +560,47 @@ func TestDeliveryServiceSSLKeysByHostnameUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for a DeliveryServiceSSLKeysByHostname")
 
-	_, err := to.DeliveryServiceSSLKeysByHostname("hostname")
+	_, err := to.DeliveryServiceSSLKeysByHostname(context.Background(), "hostname")
+	if err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+}
+
+func TestAssignDeliveryServiceServers(t *testing.T) {
+	resp := fixtures.AssignDeliveryServiceServers()
+	server := endpointServer(t, http.MethodPost, "/api/1.2/deliveryservices/ds-test/servers", resp)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to assign Servers to a DeliveryService")
+
+	result, err := to.AssignDeliveryServiceServers(context.Background(), "ds-test", []string{"edge-alb-01", "edge-alb-02"})
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
+	}
+
+	actual := result.Response.XMLID
+	if actual != "ds-test" {
+		testHelper.Error(t, "Should get back \"ds-test\" for \"Response.XMLID\", got: %s", actual)
+	} else {
+		testHelper.Success(t, "Should get back \"ds-test\" for \"Response.XMLID\"")
+	}
+}
+
+func TestAssignDeliveryServiceServersUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to assign Servers to a DeliveryService")
+
+	_, err := to.AssignDeliveryServiceServers(context.Background(), "ds-test", []string{"edge-alb-01", "edge-alb-02"})
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
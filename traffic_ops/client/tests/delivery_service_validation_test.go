@@ -0,0 +1,181 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+	"github.com/jheitz200/test_helper"
+)
+
+func validDeliveryService() client.DeliveryService {
+	return client.DeliveryService{
+		XMLID:         "ds1",
+		DisplayName:   "DS 1",
+		Type:          string(client.DSTypeHTTP),
+		TypeID:        1,
+		CDNID:         1,
+		OrgServerFQDN: "http://origin.example.net:8080",
+		MissLat:       41.881944,
+		MissLong:      -87.627778,
+		MatchList:     []client.DeliveryServiceMatch{{Type: client.DSMatchTypeHost, SetNumber: 0, Pattern: `.*\.ds1\..*`}},
+	}
+}
+
+func TestValidateDeliveryService(t *testing.T) {
+	testHelper.Context(t, "Given the need to test validating delivery services")
+
+	ds := validDeliveryService()
+	if err := ds.Validate(); err != nil {
+		testHelper.Error(t, "Should validate a valid delivery service, got: %v", err)
+	} else {
+		testHelper.Success(t, "Should validate a valid delivery service")
+	}
+
+	anyMap := client.DeliveryService{XMLID: "any1", Type: string(client.DSTypeAnyMap), CDNName: "cdn1"}
+	if err := anyMap.Validate(); err == nil {
+		testHelper.Error(t, "Should not validate an ANY_MAP delivery service without a typeId, cdnId, displayName or origin")
+	} else if validationErr, ok := err.(*client.ValidationError); !ok || len(validationErr.Errors) != 4 {
+		testHelper.Error(t, "Should get errors for the typeId, cdnId, displayName and origin of an ANY_MAP delivery service, got: %v", err)
+	} else {
+		testHelper.Success(t, "Should require the typeId, cdnId, displayName and origin of every delivery service type")
+	}
+
+	steering := validDeliveryService()
+	steering.Type = string(client.DSTypeSteering)
+	steering.OrgServerFQDN = ""
+	if err := steering.Validate(); err == nil {
+		testHelper.Error(t, "Should not validate a STEERING delivery service without an origin")
+	}
+
+	ds.Type = string(client.DSTypeDNS)
+	ds.XMLID = "ds 1"
+	ds.HTTPBypassFQDN = "bypass.example.net"
+	ds.OrgServerFQDN = ""
+	ds.DNSBypassIP = "10.0.0.1"
+	ds.MatchList = append(ds.MatchList, client.DeliveryServiceMatch{Type: client.DSMatchTypePath, SetNumber: 1, Pattern: "(unclosed"})
+	err := ds.Validate()
+	validationErr, ok := err.(*client.ValidationError)
+	if !ok {
+		testHelper.Fatal(t, "Should get a ValidationError, got: %v", err)
+	}
+
+	expected := map[string]bool{"xmlId": false, "httpBypassFqdn": false, "orgServerFqdn": false, "dnsBypassTtl": false, "matchList": false}
+	for _, fieldErr := range validationErr.Errors {
+		if _, ok := expected[fieldErr.Field]; !ok {
+			testHelper.Error(t, "Should not get an error for %v, got: %v", fieldErr.Field, fieldErr)
+		}
+		expected[fieldErr.Field] = true
+	}
+	for field, found := range expected {
+		if !found {
+			testHelper.Error(t, "Should get an error for %v, got: %v", field, err)
+		}
+	}
+	if matchErrs := strings.Count(err.Error(), "matchList"); matchErrs != 2 {
+		testHelper.Error(t, "Should get errors for the PATH_REGEXP of a DNS delivery service and the invalid regex, got: %v", err)
+	} else {
+		testHelper.Success(t, "Should get every error of an invalid delivery service: %v", err)
+	}
+
+	for _, origin := range []string{"origin.example.net", "http://origin.example.net:0", "https://origin_example.net", "http://origin.example.net/path"} {
+		ds := validDeliveryService()
+		ds.OrgServerFQDN = origin
+		if err := ds.Validate(); err == nil {
+			testHelper.Error(t, "Should not validate an origin of %v", origin)
+		}
+	}
+}
+
+func TestDeliveryServiceDryRun(t *testing.T) {
+	requests := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"response":[],"alerts":[]}`))
+	}))
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	to.DryRun = true
+	ctx := context.Background()
+
+	testHelper.Context(t, "Given the need to test changing delivery services in a dry run")
+
+	ds := validDeliveryService()
+	resp, err := to.CreateDeliveryService(ctx, &ds)
+	if err != nil || len(resp.Response) != 1 || resp.Response[0].XMLID != "ds1" || len(resp.Alerts) != 1 {
+		testHelper.Error(t, "Should get the delivery service and an alert from a dry run create, got: %+v %v", resp, err)
+	}
+	if _, err := to.UpdateDeliveryService(ctx, "1", &ds); err != nil {
+		testHelper.Error(t, "Should be able to update in a dry run, got: %v", err)
+	}
+	if _, err := to.DeleteDeliveryService(ctx, "1"); err != nil {
+		testHelper.Error(t, "Should be able to delete in a dry run, got: %v", err)
+	}
+	if _, err := to.AssignDeliveryServiceServers(ctx, "ds1", []string{"edge1"}); err != nil {
+		testHelper.Error(t, "Should be able to assign servers in a dry run, got: %v", err)
+	}
+
+	ds.OrgServerFQDN = ""
+	if _, err := to.CreateDeliveryService(ctx, &ds); err == nil {
+		testHelper.Error(t, "Should not be able to create an invalid delivery service in a dry run")
+	}
+
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		testHelper.Error(t, "Should not send any requests in a dry run, sent %v", n)
+	} else {
+		testHelper.Success(t, "Should not send any requests in a dry run")
+	}
+}
+
+func TestValidateDeliveryServicesSession(t *testing.T) {
+	requests := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"response":[],"alerts":[]}`))
+	}))
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	to.ValidateDeliveryServices = true
+	ctx := context.Background()
+
+	testHelper.Context(t, "Given the need to test that a validating Session doesn't send invalid delivery services")
+
+	ds := validDeliveryService()
+	ds.XMLID = ""
+	if _, err := to.UpdateDeliveryService(ctx, "1", &ds); err == nil {
+		testHelper.Error(t, "Should not be able to update an invalid delivery service")
+	}
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		testHelper.Error(t, "Should not send an invalid delivery service, sent %v requests", n)
+	}
+
+	ds = validDeliveryService()
+	if _, err := to.CreateDeliveryService(ctx, &ds); err != nil {
+		testHelper.Error(t, "Should be able to create a valid delivery service, got: %v", err)
+	} else if n := atomic.LoadInt32(&requests); n != 1 {
+		testHelper.Error(t, "Should send a valid delivery service, sent %v requests", n)
+	} else {
+		testHelper.Success(t, "Should send only valid delivery services")
+	}
+}
@@ -16,6 +16,7 @@
 package test
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"testing"
@@ -34,7 +35,7 @@ func TestHardware(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for Hardware")
 
-	hardware, err := to.Hardware(0)
+	hardware, err := to.Hardware(context.Background(), 0)
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -76,7 +77,7 @@ func TestHardwareUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for Hardware")
 
-	_, err := to.Hardware(0)
+	_, err := to.Hardware(context.Background(), 0)
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
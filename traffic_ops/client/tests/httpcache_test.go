@@ -0,0 +1,320 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client/fixtures"
+	"github.com/jheitz200/test_helper"
+)
+
+// cachingServer returns a server which responds with the CDNs fixture and the given Cache-Control and ETag headers, or Not Modified if the request has the ETag in If-None-Match; or with the status from the given function, if it returns one other than OK. It also returns the count of requests received.
+func cachingServer(cacheControl, etag string, status func() int) (*httptest.Server, *int32) {
+	requests := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if code := status(); code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+		if cacheControl != "" {
+			w.Header().Set("Cache-Control", cacheControl)
+		}
+		if etag != "" {
+			w.Header().Set("ETag", etag)
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		json.NewEncoder(w).Encode(fixtures.CDNs())
+	}))
+	return server, &requests
+}
+
+func alwaysOK() int { return http.StatusOK }
+
+func cachingSession(url string) *client.Session {
+	to := client.NewSession("", "", url, "", &http.Client{}, true)
+	to.RetryPolicy = client.NoRetryPolicy
+	return to
+}
+
+func TestCacheFresh(t *testing.T) {
+	server, requests := cachingServer("max-age=60", "", alwaysOK)
+	defer server.Close()
+
+	to := cachingSession(server.URL)
+
+	testHelper.Context(t, "Given the need to test that a fresh cached response is used without a request")
+
+	for i := 0; i < 2; i++ {
+		if cdns, err := to.CDNs(context.Background()); err != nil || len(cdns) != 1 {
+			testHelper.Fatal(t, "Should be able to get CDNs, got %v %v", cdns, err)
+		}
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		testHelper.Error(t, "Should make 1 request, made %d", n)
+	} else {
+		testHelper.Success(t, "Should make 1 request")
+	}
+}
+
+func TestCacheRevalidate(t *testing.T) {
+	server, requests := cachingServer("", `"v1"`, alwaysOK)
+	defer server.Close()
+
+	to := cachingSession(server.URL)
+	to.CRConfigCacheDefaultTTL = 0
+
+	testHelper.Context(t, "Given the need to test that an expired cached response is revalidated with its ETag")
+
+	if _, status, err := to.GetCRConfig(context.Background(), "cdn"); err != nil || status != client.CacheHitStatusMiss {
+		testHelper.Fatal(t, "Should get a cache miss, got %v %v", status, err)
+	}
+	body, status, err := to.GetCRConfig(context.Background(), "cdn")
+	if err != nil || status != client.CacheHitStatusRevalidated {
+		testHelper.Error(t, "Should get a revalidated response, got %v %v", status, err)
+	} else if len(body) == 0 {
+		testHelper.Error(t, "Should get back the cached body for a Not Modified response")
+	} else {
+		testHelper.Success(t, "Should get back the cached body for a Not Modified response")
+	}
+	if n := atomic.LoadInt32(requests); n != 2 {
+		testHelper.Error(t, "Should make 2 requests, made %d", n)
+	} else {
+		testHelper.Success(t, "Should make 2 requests")
+	}
+}
+
+func TestCacheCRConfigDefaultTTL(t *testing.T) {
+	server, requests := cachingServer("", "", alwaysOK)
+	defer server.Close()
+
+	to := cachingSession(server.URL)
+
+	testHelper.Context(t, "Given the need to test that a CRConfig without cache headers isn't downloaded again within its default TTL")
+
+	if _, status, err := to.GetCRConfig(context.Background(), "cdn"); err != nil || status != client.CacheHitStatusMiss {
+		testHelper.Fatal(t, "Should get a cache miss, got %v %v", status, err)
+	}
+	if _, status, err := to.GetCRConfig(context.Background(), "cdn"); err != nil || status != client.CacheHitStatusHit {
+		testHelper.Error(t, "Should get a cache hit, got %v %v", status, err)
+	} else {
+		testHelper.Success(t, "Should get a cache hit")
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		testHelper.Error(t, "Should make 1 request, made %d", n)
+	} else {
+		testHelper.Success(t, "Should make 1 request")
+	}
+}
+
+func TestCacheNotModifiedUncached(t *testing.T) {
+	server, requests := cachingServer("max-age=60", "", func() int { return http.StatusNotModified })
+	defer server.Close()
+
+	to := cachingSession(server.URL)
+
+	testHelper.Context(t, "Given the need to test that a Not Modified response to an unconditional request isn't cached")
+
+	for i := 0; i < 2; i++ {
+		_, err := to.CDNs(context.Background())
+		if httpErr, ok := err.(*client.HTTPError); !ok || httpErr.HTTPStatusCode != http.StatusNotModified {
+			testHelper.Error(t, "Should get a Not Modified HTTPError, got %v", err)
+		} else {
+			testHelper.Success(t, "Should get a Not Modified HTTPError")
+		}
+	}
+	if n := atomic.LoadInt32(requests); n != 2 {
+		testHelper.Error(t, "Should not cache the empty response, made %d requests", n)
+	} else {
+		testHelper.Success(t, "Should not cache the empty response")
+	}
+}
+
+func TestCacheNoStore(t *testing.T) {
+	server, requests := cachingServer("no-store", `"v1"`, alwaysOK)
+	defer server.Close()
+
+	to := cachingSession(server.URL)
+
+	testHelper.Context(t, "Given the need to test that a no-store response isn't cached")
+
+	for i := 0; i < 2; i++ {
+		if _, status, err := to.GetCRConfig(context.Background(), "cdn"); err != nil || status != client.CacheHitStatusMiss {
+			testHelper.Error(t, "Should get a cache miss, got %v %v", status, err)
+		}
+	}
+	if n := atomic.LoadInt32(requests); n != 2 {
+		testHelper.Error(t, "Should make 2 requests, made %d", n)
+	} else {
+		testHelper.Success(t, "Should make 2 requests")
+	}
+}
+
+func TestCacheStaleIfError(t *testing.T) {
+	code := int32(http.StatusOK)
+	server, _ := cachingServer("", "", func() int { return int(atomic.LoadInt32(&code)) })
+	defer server.Close()
+
+	to := cachingSession(server.URL)
+
+	testHelper.Context(t, "Given the need to test that an expired cached response is used while Traffic Ops is unavailable")
+
+	if _, err := to.CDNs(context.Background()); err != nil {
+		testHelper.Fatal(t, "Should be able to get CDNs, got %v", err)
+	}
+
+	atomic.StoreInt32(&code, http.StatusServiceUnavailable)
+	_, status, err := to.GetCRConfig(context.Background(), "cdn") // a different path, which was never cached
+	if err == nil {
+		testHelper.Error(t, "Should get an error for an uncached path, got %v", status)
+	}
+	if cdns, err := to.CDNs(context.Background()); err != nil || len(cdns) != 1 {
+		testHelper.Error(t, "Should get back the stale CDNs, got %v %v", cdns, err)
+	} else {
+		testHelper.Success(t, "Should get back the stale CDNs")
+	}
+
+	atomic.StoreInt32(&code, http.StatusForbidden)
+	if _, err := to.CDNs(context.Background()); err == nil {
+		testHelper.Error(t, "Should not get back stale CDNs when Traffic Ops refuses the request")
+	} else {
+		testHelper.Success(t, "Should not get back stale CDNs when Traffic Ops refuses the request")
+	}
+
+	to.CacheStaleIfError = 0
+	atomic.StoreInt32(&code, http.StatusServiceUnavailable)
+	if _, err := to.CDNs(context.Background()); err == nil {
+		testHelper.Error(t, "Should not get back stale CDNs older than CacheStaleIfError")
+	} else {
+		testHelper.Success(t, "Should not get back stale CDNs older than CacheStaleIfError")
+	}
+}
+
+func TestCacheMustRevalidate(t *testing.T) {
+	code := int32(http.StatusOK)
+	server, _ := cachingServer("must-revalidate", "", func() int { return int(atomic.LoadInt32(&code)) })
+	defer server.Close()
+
+	to := cachingSession(server.URL)
+
+	testHelper.Context(t, "Given the need to test that a must-revalidate response isn't used while Traffic Ops is unavailable")
+
+	if _, err := to.CDNs(context.Background()); err != nil {
+		testHelper.Fatal(t, "Should be able to get CDNs, got %v", err)
+	}
+	atomic.StoreInt32(&code, http.StatusServiceUnavailable)
+	if _, err := to.CDNs(context.Background()); err == nil {
+		testHelper.Error(t, "Should not get back stale CDNs")
+	} else {
+		testHelper.Success(t, "Should not get back stale CDNs")
+	}
+}
+
+func TestCacheSharedStoreAPIKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		json.NewEncoder(w).Encode(client.CDNResponse{Response: []client.CDN{{Name: r.Header.Get("Authorization")}}})
+	}))
+	defer server.Close()
+
+	testHelper.Context(t, "Given the need to test that Sessions with different API keys sharing a cache don't get each other's responses")
+
+	store := client.NewMemoryCacheStore(client.DefaultCacheEntries)
+	for _, key := range []string{"key1", "key2"} {
+		to, err := client.NewSessionWithAPIKey(server.URL, key, true, "test", true, client.DefaultTimeout)
+		if err != nil {
+			testHelper.Fatal(t, "Should be able to create a session, got: %v", err)
+		}
+		to.Cache = store
+		cdns, err := to.CDNs(context.Background())
+		if err != nil || len(cdns) != 1 {
+			testHelper.Fatal(t, "Should be able to get CDNs, got %v %v", cdns, err)
+		}
+		if expected := "Bearer " + key; cdns[0].Name != expected {
+			testHelper.Error(t, "Should get the response for %q, got %q", expected, cdns[0].Name)
+		} else {
+			testHelper.Success(t, "Should get the response for %q", expected)
+		}
+	}
+}
+
+func TestMemoryCacheStore(t *testing.T) {
+	testHelper.Context(t, "Given the need to test that the memory cache evicts the least recently used response")
+
+	store := client.NewMemoryCacheStore(2)
+	store.Set("a", client.CachedResponse{Body: []byte("a")})
+	store.Set("b", client.CachedResponse{Body: []byte("b")})
+	store.Get("a")
+	store.Set("c", client.CachedResponse{Body: []byte("c")})
+
+	if _, ok := store.Get("b"); ok {
+		testHelper.Error(t, "Should evict \"b\"")
+	} else {
+		testHelper.Success(t, "Should evict \"b\"")
+	}
+	if r, ok := store.Get("a"); !ok || string(r.Body) != "a" {
+		testHelper.Error(t, "Should keep \"a\", got %v %v", r, ok)
+	} else {
+		testHelper.Success(t, "Should keep \"a\"")
+	}
+}
+
+func TestDiskCacheStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "to-client-cache")
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to create a temporary directory, got %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	testHelper.Context(t, "Given the need to test that the disk cache stores responses across instances")
+
+	store, err := client.NewDiskCacheStore(dir)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to create a disk cache, got %v", err)
+	}
+	expires := time.Now().Add(time.Minute).Round(time.Second)
+	if err := store.Set("key", client.CachedResponse{Body: []byte("body"), ETag: `"v1"`, Expires: expires}); err != nil {
+		testHelper.Fatal(t, "Should be able to store a response, got %v", err)
+	}
+
+	reopened, err := client.NewDiskCacheStore(dir)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to reopen the disk cache, got %v", err)
+	}
+	if r, ok := reopened.Get("key"); !ok || string(r.Body) != "body" || r.ETag != `"v1"` || !r.Expires.Equal(expires) {
+		testHelper.Error(t, "Should get back the stored response, got %v %v", r, ok)
+	} else {
+		testHelper.Success(t, "Should get back the stored response")
+	}
+	if _, ok := reopened.Get("other"); ok {
+		testHelper.Error(t, "Should not get back a response for a key which wasn't stored")
+	} else {
+		testHelper.Success(t, "Should not get back a response for a key which wasn't stored")
+	}
+}
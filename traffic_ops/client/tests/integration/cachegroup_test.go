@@ -16,6 +16,7 @@
 package integration
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -37,7 +38,7 @@ func TestCachegroups(t *testing.T) {
 	}
 	apiCgs := apiCgRes.Response
 
-	clientCgs, err := to.CacheGroups()
+	clientCgs, err := to.CacheGroups(context.Background())
 	if err != nil {
 		t.Errorf("Could not get Cachegroups from client.  Error is: %v\n", err)
 	}
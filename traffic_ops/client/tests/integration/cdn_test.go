@@ -16,6 +16,7 @@
 package integration
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -38,7 +39,7 @@ func TestCDNs(t *testing.T) {
 	apiCDNs := apiCDNRes.Response
 
 	//get CDNs data from client
-	clientCDNs, err := to.CDNs()
+	clientCDNs, err := to.CDNs(context.Background())
 	if err != nil {
 		t.Errorf("Could not get CDNs from client.  Error is: %v\n", err)
 	}
@@ -87,7 +88,7 @@ func TestCDNName(t *testing.T) {
 	apiLastUpdated := apiCDNs[0].LastUpdated
 
 	//get CDNs data from client
-	clientCDN, err := to.CDNName(apiName)
+	clientCDN, err := to.CDNName(context.Background(), apiName)
 
 	if len(clientCDN) != 1 {
 		t.Errorf("The length of the client CDN response %v is greater than 1!\n", len(apiCDNs))
@@ -15,7 +15,10 @@
 
 package integration
 
-import "testing"
+import (
+	"context"
+	"testing"
+)
 
 //TestCachegroupResults compares the results of the Cachegroup api and Cachegroup client
 func TestGetCrConfig(t *testing.T) {
@@ -25,7 +28,7 @@ func TestGetCrConfig(t *testing.T) {
 		t.Errorf("TestGetCrConfig -- Could not get CDNs from TO...%v\n", err)
 	}
 
-	crConfig, cacheHitStatus, err := to.GetCRConfig(cdn.Name)
+	crConfig, cacheHitStatus, err := to.GetCRConfig(context.Background(), cdn.Name)
 	if err != nil {
 		t.Errorf("Could not get CrConfig for %s.  Error is...%v\n", cdn.Name, err)
 	}
@@ -16,6 +16,7 @@
 package integration
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -131,7 +132,7 @@ func TestDeliveryServices(t *testing.T) {
 	}
 	apiDss := apiDsRes.Response
 
-	clientDss, err := to.DeliveryServices()
+	clientDss, err := to.DeliveryServices(context.Background())
 	if err != nil {
 		t.Errorf("Could not get Deliveryservices from client.  Error is: %v\n", err)
 	}
@@ -166,7 +167,7 @@ func TestDeliveryServices(t *testing.T) {
 
 func TestCreateDs(t *testing.T) {
 	//create a DS and validate response
-	res, err := to.CreateDeliveryService(&testDs)
+	res, err := to.CreateDeliveryService(context.Background(), &testDs)
 	if err != nil {
 		t.Error("Failed to create deliveryservice!  Error is: ", err)
 		t.FailNow()
@@ -190,7 +191,7 @@ func TestUpdateDs(t *testing.T) {
 	testDs.LongDesc1 += "-- Update"
 	testDs.LongDesc2 += "-- Update"
 	testDs.EdgeHeaderRewrite += "-- Update"
-	res, err := to.UpdateDeliveryService(testDsID, &testDs)
+	res, err := to.UpdateDeliveryService(context.Background(), testDsID, &testDs)
 	if err != nil {
 		t.Error("Failed to update deliveryservice!  Error is: ", err)
 	} else {
@@ -215,7 +216,7 @@ func TestDeliveryService(t *testing.T) {
 		t.Errorf("Could not decode Deliveryservice json.  Error is: %v\n", err)
 	}
 
-	clientDss, err := to.DeliveryService(testDsID)
+	clientDss, err := to.DeliveryService(context.Background(), testDsID)
 	if err != nil {
 		t.Errorf("Could not get Deliveryservice from client.  Error is: %v\n", err)
 	}
@@ -229,7 +230,7 @@ func TestDeleteDeliveryService(t *testing.T) {
 		t.Error("testDsID is not defined")
 		t.FailNow()
 	}
-	res, err := to.DeleteDeliveryService(testDsID)
+	res, err := to.DeleteDeliveryService(context.Background(), testDsID)
 	if err != nil {
 		t.Errorf("Could not delete Deliveryserivce %s reponse was: %v\n", testDsID, err)
 	}
@@ -256,7 +257,7 @@ func TestDeliveryServiceState(t *testing.T) {
 
 	apiDsState := apiDsStateRes.Response
 
-	clientDsState, err := to.DeliveryServiceState(existingTestDSID)
+	clientDsState, err := to.DeliveryServiceState(context.Background(), existingTestDSID)
 	if err != nil {
 		t.Errorf("Could not get DS State from client for %s reponse was: %v\n", existingTestDSID, err)
 	}
@@ -296,7 +297,7 @@ func TestDeliveryServiceHealth(t *testing.T) {
 
 	apiDsHealth := apiDsHealthRes.Response
 
-	clientDsHealth, err := to.DeliveryServiceHealth(existingTestDSID)
+	clientDsHealth, err := to.DeliveryServiceHealth(context.Background(), existingTestDSID)
 	if err != nil {
 		t.Errorf("Could not ge Deliveryserivce Health for %s reponse was: %v\n", existingTestDSID, err)
 	}
@@ -348,7 +349,7 @@ func TestDeliveryServiceCapacity(t *testing.T) {
 
 	apiDsCapacity := apiDsCapacityRes.Response
 
-	clientDsCapacity, err := to.DeliveryServiceCapacity(existingTestDSID)
+	clientDsCapacity, err := to.DeliveryServiceCapacity(context.Background(), existingTestDSID)
 	if err != nil {
 		t.Errorf("Could not ge Deliveryserivce Capacity for %s reponse was: %v\n", existingTestDSID, err)
 	}
@@ -394,7 +395,7 @@ func TestDeliveryServiceRouting(t *testing.T) {
 
 	apiDsRouting := apiDsRoutingRes.Response
 
-	clientDsRouting, err := to.DeliveryServiceRouting(existingTestDSID)
+	clientDsRouting, err := to.DeliveryServiceRouting(context.Background(), existingTestDSID)
 	if err != nil {
 		t.Errorf("Could not ge Deliveryserivce Routing for %s reponse was: %v\n", existingTestDSID, err)
 	}
@@ -444,7 +445,7 @@ func TestDeliveryServiceServer(t *testing.T) {
 		t.Errorf("Could not decode DeliveryserviceServer reponse.  Error is: %v\n", err)
 	}
 
-	clientDsServerRes, err := to.DeliveryServiceServer("1", "1")
+	clientDsServerRes, err := to.DeliveryServiceServer(context.Background(), "1", "1")
 
 	if err != nil {
 		t.Errorf("Could not get DeliveryserviceServer, reponse was: %v\n", err)
@@ -485,7 +486,7 @@ func TestDeliveryServiceSSLKeysByID(t *testing.T) {
 			t.Errorf("Could not decode DeliveryServiceSSLKeysResponse reponse.  Error is: %v\n", err)
 		}
 
-		clientSslRes, err := to.DeliveryServiceSSLKeysByID(sslDs.XMLID)
+		clientSslRes, err := to.DeliveryServiceSSLKeysByID(context.Background(), sslDs.XMLID)
 
 		if err != nil {
 			t.Errorf("Could not get DeliveryserviceSSLKeys, reponse was: %v\n", err)
@@ -526,7 +527,7 @@ func TestDeliveryServiceSSLKeysByHostname(t *testing.T) {
 			t.Errorf("Could not decode DeliveryServiceSSLKeysResponse reponse.  Error is: %v\n", err)
 		}
 
-		clientSslRes, err := to.DeliveryServiceSSLKeysByHostname(hostname)
+		clientSslRes, err := to.DeliveryServiceSSLKeysByHostname(context.Background(), hostname)
 
 		if err != nil {
 			t.Errorf("Could not get DeliveryserviceSSLKeys, reponse was: %v\n", err)
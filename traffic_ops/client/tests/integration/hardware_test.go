@@ -16,6 +16,7 @@
 package integration
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -37,7 +38,7 @@ func TestHardware(t *testing.T) {
 	}
 	apiHardware := apiHwRes.Response
 
-	clientHardware, err := to.Hardware(0)
+	clientHardware, err := to.Hardware(context.Background(), 0)
 	if err != nil {
 		t.Errorf("Could not get Hardware from client.  Error is: %v\n", err)
 		t.FailNow()
@@ -76,7 +77,7 @@ func TestHardwareWithLimit(t *testing.T) {
 	}
 	apiHardware := apiHwRes.Response
 
-	clientHardware, err := to.Hardware(10)
+	clientHardware, err := to.Hardware(context.Background(), 10)
 	if err != nil {
 		t.Errorf("Could not get Hardware from client.  Error is: %v\n", err)
 		t.FailNow()
@@ -17,6 +17,7 @@ package integration
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
@@ -47,7 +48,7 @@ func init() {
 
 //GetCdn returns a Cdn struct
 func GetCdn() (traffic_ops.CDN, error) {
-	cdns, err := to.CDNs()
+	cdns, err := to.CDNs(context.Background())
 	if err != nil {
 		return *new(traffic_ops.CDN), err
 	}
@@ -60,7 +61,7 @@ func GetCdn() (traffic_ops.CDN, error) {
 
 //GetProfile returns a Profile Struct
 func GetProfile() (traffic_ops.Profile, error) {
-	profiles, err := to.Profiles()
+	profiles, err := to.Profiles(context.Background())
 	if err != nil {
 		return *new(traffic_ops.Profile), err
 	}
@@ -69,7 +70,7 @@ func GetProfile() (traffic_ops.Profile, error) {
 
 //GetType returns a Type Struct
 func GetType(useInTable string) (traffic_ops.Type, error) {
-	types, err := to.Types()
+	types, err := to.Types(context.Background())
 	if err != nil {
 		return *new(traffic_ops.Type), err
 	}
@@ -84,7 +85,7 @@ func GetType(useInTable string) (traffic_ops.Type, error) {
 
 //GetDeliveryService returns a DeliveryService Struct
 func GetDeliveryService(cdn string) (traffic_ops.DeliveryService, error) {
-	dss, err := to.DeliveryServices()
+	dss, err := to.DeliveryServices(context.Background())
 	if err != nil {
 		return *new(traffic_ops.DeliveryService), err
 	}
@@ -16,6 +16,7 @@
 package integration
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -44,7 +45,7 @@ func TestParameters(t *testing.T) {
 	}
 	apiParams := apiParamRes.Response
 
-	clientParams, err := to.Parameters(profile.Name)
+	clientParams, err := to.Parameters(context.Background(), profile.Name)
 	if err != nil {
 		t.Errorf("Could not get parameters from client.  Error is: %v\n", err)
 		t.FailNow()
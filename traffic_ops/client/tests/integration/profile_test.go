@@ -16,6 +16,7 @@
 package integration
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -40,7 +41,7 @@ func TestProfiles(t *testing.T) {
 	}
 	apiProfiles := apiProfileRes.Response
 
-	clientProfiles, err := to.Profiles()
+	clientProfiles, err := to.Profiles(context.Background())
 	if err != nil {
 		t.Errorf("Could not get profiles from client.  Error is: %v\n", err)
 		t.FailNow()
@@ -16,6 +16,7 @@
 package integration
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -41,7 +42,7 @@ func TestServers(t *testing.T) {
 	}
 	apiServers := apiServerRes.Response
 
-	clientServers, err := to.Servers()
+	clientServers, err := to.Servers(context.Background())
 	if err != nil {
 		t.Errorf("Could not get servers from client.  Error is: %v\n", err)
 		t.FailNow()
@@ -88,7 +89,7 @@ func TestServersByType(t *testing.T) {
 
 	params := make(url.Values)
 	params.Add("type", serverType.Name)
-	clientServers, err := to.ServersByType(params)
+	clientServers, err := to.ServersByType(context.Background(), params)
 	if err != nil {
 		t.Errorf("Could not get servers from client.  Error is: %v\n", err)
 		t.FailNow()
@@ -122,7 +123,7 @@ func TestServersFQDN(t *testing.T) {
 
 	params := make(url.Values)
 	params.Add("type", serverType.Name)
-	servers, err := to.ServersByType(params)
+	servers, err := to.ServersByType(context.Background(), params)
 	if err != nil {
 		t.Errorf("Could not get servers from client.  Error is: %v\n", err)
 		t.FailNow()
@@ -130,7 +131,7 @@ func TestServersFQDN(t *testing.T) {
 
 	serverFQDN := fmt.Sprintf("%s.%s", servers[0].HostName, servers[0].DomainName)
 
-	clientFQDN, err := to.ServersFqdn(servers[0].HostName)
+	clientFQDN, err := to.ServersFqdn(context.Background(), servers[0].HostName)
 	if err != nil {
 		t.Errorf("Servers FQDN failed...err: %v\n", err)
 	}
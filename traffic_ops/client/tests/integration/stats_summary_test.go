@@ -16,6 +16,7 @@
 package integration
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -41,7 +42,7 @@ func TestStatsSummaryAll(t *testing.T) {
 	}
 	apiStatsSummary := apiStatsSummaryRes.Response
 
-	clientStatsSummary, err := to.SummaryStats("", "", "")
+	clientStatsSummary, err := to.SummaryStats(context.Background(), "", "", "")
 	if err != nil {
 		t.Errorf("Could not get stats summary from client.  Error is: %v\n", err)
 		t.FailNow()
@@ -85,7 +86,7 @@ func TestStatsSummarybyCDN(t *testing.T) {
 	}
 	apiStatsSummary := apiStatsSummaryRes.Response
 
-	clientStatsSummary, err := to.SummaryStats(cdn.Name, "", "")
+	clientStatsSummary, err := to.SummaryStats(context.Background(), cdn.Name, "", "")
 	if err != nil {
 		t.Errorf("Could not get stats summary from client.  Error is: %v\n", err)
 		t.FailNow()
@@ -129,7 +130,7 @@ func TestStatsSummaryByDs(t *testing.T) {
 	}
 	apiStatsSummary := apiStatsSummaryRes.Response
 
-	clientStatsSummary, err := to.SummaryStats("", ds.XMLID, "")
+	clientStatsSummary, err := to.SummaryStats(context.Background(), "", ds.XMLID, "")
 	if err != nil {
 		t.Errorf("Could not get stats summary from client.  Error is: %v\n", err)
 		t.FailNow()
@@ -168,7 +169,7 @@ func TestStatsSummaryByStatName(t *testing.T) {
 	}
 	apiStatsSummary := apiStatsSummaryRes.Response
 
-	clientStatsSummary, err := to.SummaryStats("", "", "daily_bytesserved")
+	clientStatsSummary, err := to.SummaryStats(context.Background(), "", "", "daily_bytesserved")
 	if err != nil {
 		t.Errorf("Could not get stats summary from client.  Error is: %v\n", err)
 		t.FailNow()
@@ -214,13 +215,13 @@ func TestAddSummaryStats(t *testing.T) {
 	testStatsSummay.StatValue = "1234"
 	testStatsSummay.SummaryTime = summaryTime
 
-	err = to.AddSummaryStats(*testStatsSummay)
+	err = to.AddSummaryStats(context.Background(), *testStatsSummay)
 	if err != nil {
 		t.Errorf("Could not add Summary Stats, response was %v\n", err)
 		t.FailNow()
 	}
 
-	ssRes, err := to.SummaryStats(testStatsSummay.CDNName, testStatsSummay.DeliveryService, testStatsSummay.StatName)
+	ssRes, err := to.SummaryStats(context.Background(), testStatsSummay.CDNName, testStatsSummay.DeliveryService, testStatsSummay.StatName)
 	if err != nil {
 		t.Errorf("Could not get a SummaryStats, error was: %v\n", err)
 		t.FailNow()
@@ -16,6 +16,7 @@
 package integration
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -43,7 +44,7 @@ func TestTrafficMonitorConfig(t *testing.T) {
 	}
 	apiTMConfig := apiTMConfigRes.Response
 
-	clientTMConfig, err := to.TrafficMonitorConfig(cdn.Name)
+	clientTMConfig, err := to.TrafficMonitorConfig(context.Background(), cdn.Name)
 	if err != nil {
 		t.Errorf("Could not get Traffic Monitor Config from client.  Error is: %v\n", err)
 		t.FailNow()
@@ -16,6 +16,7 @@
 package integration
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -43,7 +44,7 @@ func TestTrafficRouterConfig(t *testing.T) {
 	}
 	apiTRConfig := apiTRConfigRes.Response
 
-	clientTRConfig, err := to.TrafficRouterConfig(cdn.Name)
+	clientTRConfig, err := to.TrafficRouterConfig(context.Background(), cdn.Name)
 	if err != nil {
 		t.Errorf("Could not get Traffic Router Config from client.  Error is: %v\n", err)
 		t.FailNow()
@@ -16,6 +16,7 @@
 package integration
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -40,7 +41,7 @@ func TestTypes(t *testing.T) {
 	}
 	apiTypes := apiTypeRes.Response
 
-	clientTypes, err := to.Types()
+	clientTypes, err := to.Types(context.Background())
 	if err != nil {
 		t.Errorf("Could not get types from client.  Error is: %v\n", err)
 		t.FailNow()
@@ -16,6 +16,7 @@
 package integration
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -40,7 +41,7 @@ func TestUsers(t *testing.T) {
 	}
 	apiUsers := apiUserRes.Response
 
-	clientUsers, err := to.Users()
+	clientUsers, err := to.Users(context.Background())
 	if err != nil {
 		t.Errorf("Could not get users from client.  Error is: %v\n", err)
 		t.FailNow()
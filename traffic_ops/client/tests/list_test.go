@@ -0,0 +1,200 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+	"github.com/jheitz200/test_helper"
+)
+
+// pagingServer returns a server which serves the given number of DeliveryServiceServers, paged by the `page` and `limit` query parameters, and the queries it received.
+func pagingServer(total int) (*httptest.Server, *[]url.Values) {
+	queries := []url.Values{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		resp := client.DeliveryServiceServerResponse{Response: []client.DeliveryServiceServer{}, Limit: limit}
+		for i := (page - 1) * limit; i < page*limit && i < total; i++ {
+			resp.Response = append(resp.Response, client.DeliveryServiceServer{DeliveryService: 1, Server: i})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	return server, &queries
+}
+
+// unpagedServer returns a server which serves the given number of servers regardless of the query, and the count of requests it received.
+func unpagedServer(total int) (*httptest.Server, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		resp := client.ServerResponse{Response: []client.Server{}}
+		for i := 0; i < total; i++ {
+			resp.Response = append(resp.Response, client.Server{ID: i, HostName: "edge-" + strconv.Itoa(i)})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	return server, &requests
+}
+
+func TestListOptionsValues(t *testing.T) {
+	testHelper.Context(t, "Given the need to test that ListOptions are sent as query parameters")
+
+	opts := client.ListOptions{Filters: url.Values{"type": {"EDGE"}}, OrderBy: "hostName", Page: 2, Limit: 10}
+	expected := "limit=10&orderby=hostName&page=2&type=EDGE"
+	if actual := opts.Values().Encode(); actual != expected {
+		testHelper.Error(t, "Should get back %s for the query, got: %s", expected, actual)
+	} else {
+		testHelper.Success(t, "Should get back %s for the query", expected)
+	}
+
+	if actual := (client.ListOptions{}).Values().Encode(); actual != "" {
+		testHelper.Error(t, "Should get back an empty query for empty ListOptions, got: %s", actual)
+	} else {
+		testHelper.Success(t, "Should get back an empty query for empty ListOptions")
+	}
+}
+
+func TestListServers(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		json.NewEncoder(w).Encode(client.ServerResponse{Response: []client.Server{}})
+	}))
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test listing Servers with filters")
+
+	if _, err := to.ListServers(context.Background(), client.ListOptions{Filters: url.Values{"cdn": {"1"}, "status": {"ONLINE"}}, OrderBy: "hostName"}); err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops, got %v", err)
+	}
+	if query.Get("cdn") != "1" || query.Get("status") != "ONLINE" || query.Get("orderby") != "hostName" {
+		testHelper.Error(t, "Should send the filters and orderby as query parameters, got: %v", query)
+	} else {
+		testHelper.Success(t, "Should send the filters and orderby as query parameters")
+	}
+}
+
+func TestIterateDeliveryServiceServers(t *testing.T) {
+	server, queries := pagingServer(5)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test iterating through paged DeliveryServiceServers")
+
+	it := to.IterateDeliveryServiceServers(context.Background(), client.ListOptions{Limit: 2})
+	servers := []int{}
+	for it.Next() {
+		servers = append(servers, it.DeliveryServiceServer().Server)
+	}
+	if err := it.Err(); err != nil {
+		testHelper.Error(t, "Should be able to iterate without an error, got %v", err)
+	}
+	if len(servers) != 5 || servers[0] != 0 || servers[4] != 4 {
+		testHelper.Error(t, "Should get back 5 DeliveryServiceServers in order, got: %v", servers)
+	} else {
+		testHelper.Success(t, "Should get back 5 DeliveryServiceServers in order")
+	}
+	if len(*queries) != 3 {
+		testHelper.Error(t, "Should request 3 pages, requested %d", len(*queries))
+	} else if page := (*queries)[2].Get("page"); page != "3" {
+		testHelper.Error(t, "Should request page 3 last, requested %s", page)
+	} else {
+		testHelper.Success(t, "Should request 3 pages")
+	}
+}
+
+func TestIterateExactPages(t *testing.T) {
+	server, queries := pagingServer(4)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test iterating through results which exactly fill the last page")
+
+	it := to.IterateDeliveryServiceServers(context.Background(), client.ListOptions{Limit: 2})
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if n != 4 || it.Err() != nil {
+		testHelper.Error(t, "Should get back 4 DeliveryServiceServers, got: %d %v", n, it.Err())
+	} else {
+		testHelper.Success(t, "Should get back 4 DeliveryServiceServers")
+	}
+	if len(*queries) != 3 {
+		testHelper.Error(t, "Should request 3 pages, the last empty, requested %d", len(*queries))
+	} else {
+		testHelper.Success(t, "Should request 3 pages, the last empty")
+	}
+}
+
+func TestIterateUnpaged(t *testing.T) {
+	for _, total := range []int{3, 2} {
+		server, requests := unpagedServer(total)
+
+		to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+		testHelper.Context(t, "Given the need to test iterating through an endpoint which doesn't page, with %d results and a limit of 2", total)
+
+		it := to.IterateServers(context.Background(), client.ListOptions{Limit: 2})
+		n := 0
+		for it.Next() {
+			n++
+		}
+		if n != total || it.Err() != nil {
+			testHelper.Error(t, "Should get back each of the %d Servers once, got: %d %v", total, n, it.Err())
+		} else {
+			testHelper.Success(t, "Should get back each of the %d Servers once", total)
+		}
+		if *requests > 2 {
+			testHelper.Error(t, "Should stop requesting pages, requested %d", *requests)
+		} else {
+			testHelper.Success(t, "Should stop requesting pages")
+		}
+		server.Close()
+	}
+}
+
+func TestIterateUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request while iterating")
+
+	it := to.IterateServers(context.Background(), client.ListOptions{})
+	if it.Next() {
+		testHelper.Error(t, "Should not get back a Server")
+	}
+	if it.Err() == nil {
+		testHelper.Error(t, "Should get back an error")
+	} else {
+		testHelper.Success(t, "Should get back an error")
+	}
+}
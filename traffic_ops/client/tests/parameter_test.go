@@ -16,6 +16,8 @@
 package test
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"testing"
 
@@ -33,7 +35,7 @@ func TestParameters(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for Parameters")
 
-	parameters, err := to.Parameters("test")
+	parameters, err := to.Parameters(context.Background(), "test")
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -75,10 +77,151 @@ func TestParametersUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for Parameters")
 
-	_, err := to.Parameters("test")
+	_, err := to.Parameters(context.Background(), "test")
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
 		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
 	}
 }
+
+func TestCreateParameters(t *testing.T) {
+	resp := fixtures.CreateParameters()
+	server := endpointServer(t, http.MethodPost, "/api/1.2/parameters", resp)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to create Parameters")
+
+	result, err := to.CreateParameters(context.Background(), []client.Parameter{{Name: "location", ConfigFile: "parent.config", Value: "/foo/trafficserver/"}})
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
+	}
+
+	actual := result.Response[0].Value
+	if actual != "/foo/trafficserver/" {
+		testHelper.Error(t, "Should get back \"/foo/trafficserver/\" for \"Response[0].Value\", got: %s", actual)
+	} else {
+		testHelper.Success(t, "Should get back \"/foo/trafficserver/\" for \"Response[0].Value\"")
+	}
+}
+
+func TestCreateParametersUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to create Parameters")
+
+	_, err := to.CreateParameters(context.Background(), []client.Parameter{{Name: "location", ConfigFile: "parent.config", Value: "/foo/trafficserver/"}})
+	if err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+}
+
+func TestUpdateParameter(t *testing.T) {
+	resp := fixtures.UpdateParameter()
+	server := endpointServer(t, http.MethodPut, "/api/1.2/parameters/1", resp)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to update a Parameter")
+
+	result, err := to.UpdateParameter(context.Background(), 1, &client.Parameter{Value: "/foo/trafficserver/"})
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
+	}
+
+	actual := result.Response.ConfigFile
+	if actual != "parent.config" {
+		testHelper.Error(t, "Should get back \"parent.config\" for \"Response.ConfigFile\", got: %s", actual)
+	} else {
+		testHelper.Success(t, "Should get back \"parent.config\" for \"Response.ConfigFile\"")
+	}
+}
+
+func TestUpdateParameterUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to update a Parameter")
+
+	_, err := to.UpdateParameter(context.Background(), 1, &client.Parameter{Value: "/foo/trafficserver/"})
+	if err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+}
+
+func TestDeleteParameter(t *testing.T) {
+	resp := fixtures.DeleteParameter()
+	server := endpointServer(t, http.MethodDelete, "/api/1.2/parameters/1", resp)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to delete a Parameter")
+
+	result, err := to.DeleteParameter(context.Background(), 1)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
+	}
+
+	actual := result.Alerts[0].Level
+	if actual != "success" {
+		testHelper.Error(t, "Should get back \"success\" for \"Alerts[0].Level\", got: %s", actual)
+	} else {
+		testHelper.Success(t, "Should get back \"success\" for \"Alerts[0].Level\"")
+	}
+}
+
+func TestDeleteParameterUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to delete a Parameter")
+
+	_, err := to.DeleteParameter(context.Background(), 1)
+	if err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+}
+
+func TestParameterSecure(t *testing.T) {
+	testHelper.Context(t, "Given the need to test that a Parameter's secure flag decodes from both a boolean and an integer")
+
+	for input, expected := range map[string]client.ParameterSecure{`true`: true, `1`: true, `false`: false, `0`: false} {
+		param := client.Parameter{}
+		if err := json.Unmarshal([]byte(`{"secure":`+input+`}`), &param); err != nil {
+			testHelper.Error(t, "Should be able to decode %s for \"Secure\", got: %v", input, err)
+		} else if param.Secure != expected {
+			testHelper.Error(t, "Should get back \"%v\" for \"Secure\" from %s, got: %v", expected, input, param.Secure)
+		} else {
+			testHelper.Success(t, "Should get back \"%v\" for \"Secure\" from %s", expected, input)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`{"secure":2}`), &client.Parameter{}); err == nil {
+		testHelper.Error(t, "Should not be able to decode 2 for \"Secure\"")
+	} else {
+		testHelper.Success(t, "Should not be able to decode 2 for \"Secure\"")
+	}
+}
@@ -0,0 +1,106 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client/fixtures"
+	"github.com/jheitz200/test_helper"
+)
+
+func TestCreateProfileParameters(t *testing.T) {
+	resp := fixtures.CreateProfileParameters()
+	server := endpointServer(t, http.MethodPost, "/api/1.2/profileparameters", resp)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to assign Parameters to Profiles")
+
+	result, err := to.CreateProfileParameters(context.Background(), []client.ProfileParameter{{ProfileID: 1, ParameterID: 7}})
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
+	}
+
+	actual := result.Response[0].ParameterID
+	if actual != 7 {
+		testHelper.Error(t, "Should get back \"7\" for \"Response[0].ParameterID\", got: %d", actual)
+	} else {
+		testHelper.Success(t, "Should get back \"7\" for \"Response[0].ParameterID\"")
+	}
+}
+
+func TestCreateProfileParametersUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to assign Parameters to Profiles")
+
+	_, err := to.CreateProfileParameters(context.Background(), []client.ProfileParameter{{ProfileID: 1, ParameterID: 7}})
+	if err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+}
+
+func TestDeleteProfileParameter(t *testing.T) {
+	resp := fixtures.DeleteProfileParameter()
+	server := endpointServer(t, http.MethodDelete, "/api/1.2/profileparameters/1/7", resp)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to remove a Parameter from a Profile")
+
+	result, err := to.DeleteProfileParameter(context.Background(), 1, 7)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
+	}
+
+	actual := result.Alerts[0].Level
+	if actual != "success" {
+		testHelper.Error(t, "Should get back \"success\" for \"Alerts[0].Level\", got: %s", actual)
+	} else {
+		testHelper.Success(t, "Should get back \"success\" for \"Alerts[0].Level\"")
+	}
+}
+
+func TestDeleteProfileParameterUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to remove a Parameter from a Profile")
+
+	_, err := to.DeleteProfileParameter(context.Background(), 1, 7)
+	if err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+}
@@ -16,6 +16,7 @@
 package test
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
@@ -33,7 +34,7 @@ func TestProfile(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for Profiles")
 
-	profiles, err := to.Profiles()
+	profiles, err := to.Profiles(context.Background())
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -75,7 +76,127 @@ func TestProfilesUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for Profiles")
 
-	_, err := to.Profiles()
+	_, err := to.Profiles(context.Background())
+	if err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+}
+
+func TestCreateProfile(t *testing.T) {
+	resp := fixtures.CreateProfile()
+	server := endpointServer(t, http.MethodPost, "/api/1.2/profiles", resp)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to create a Profile")
+
+	result, err := to.CreateProfile(context.Background(), &client.Profile{Name: "TR_CDN2", Description: "kabletown Content Router", Type: "TR_PROFILE"})
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
+	}
+
+	actual := result.Response.Name
+	if actual != "TR_CDN2" {
+		testHelper.Error(t, "Should get back \"TR_CDN2\" for \"Response.Name\", got: %s", actual)
+	} else {
+		testHelper.Success(t, "Should get back \"TR_CDN2\" for \"Response.Name\"")
+	}
+}
+
+func TestCreateProfileUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to create a Profile")
+
+	_, err := to.CreateProfile(context.Background(), &client.Profile{Name: "TR_CDN2", Description: "kabletown Content Router", Type: "TR_PROFILE"})
+	if err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	resp := fixtures.UpdateProfile()
+	server := endpointServer(t, http.MethodPut, "/api/1.2/profiles/1", resp)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to update a Profile")
+
+	result, err := to.UpdateProfile(context.Background(), 1, &client.Profile{Name: "TR_CDN2", Description: "kabletown Content Router", Type: "TR_PROFILE"})
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
+	}
+
+	actual := result.Response.ID
+	if actual != 1 {
+		testHelper.Error(t, "Should get back \"1\" for \"Response.ID\", got: %d", actual)
+	} else {
+		testHelper.Success(t, "Should get back \"1\" for \"Response.ID\"")
+	}
+}
+
+func TestUpdateProfileUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to update a Profile")
+
+	_, err := to.UpdateProfile(context.Background(), 1, &client.Profile{Name: "TR_CDN2", Description: "kabletown Content Router", Type: "TR_PROFILE"})
+	if err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+}
+
+func TestDeleteProfile(t *testing.T) {
+	resp := fixtures.DeleteProfile()
+	server := endpointServer(t, http.MethodDelete, "/api/1.2/profiles/1", resp)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to delete a Profile")
+
+	result, err := to.DeleteProfile(context.Background(), 1)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
+	}
+
+	actual := result.Alerts[0].Level
+	if actual != "success" {
+		testHelper.Error(t, "Should get back \"success\" for \"Alerts[0].Level\", got: %s", actual)
+	} else {
+		testHelper.Success(t, "Should get back \"success\" for \"Alerts[0].Level\"")
+	}
+}
+
+func TestDeleteProfileUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to delete a Profile")
+
+	_, err := to.DeleteProfile(context.Background(), 1)
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
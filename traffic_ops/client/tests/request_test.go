@@ -0,0 +1,217 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client/fixtures"
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client/totest"
+	"github.com/jheitz200/test_helper"
+)
+
+var testRetryPolicy = client.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+// flakyServer returns a server which responds with the given status to the first `failures` requests, and with the CDNs fixture after, and the count of requests received.
+func flakyServer(status int, failures int32) (*httptest.Server, *int32) {
+	requests := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		json.NewEncoder(w).Encode(fixtures.CDNs())
+	}))
+	return server, &requests
+}
+
+// totestSession returns a Session logged in to the given fake Traffic Ops, as a user added for the test.
+func totestSession(t *testing.T, tos *totest.Server) *client.Session {
+	tos.AddUser("admin", "password")
+	to, err := client.LoginWithAgent(tos.URL, "admin", "password", true, "test", false, client.DefaultTimeout)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to log in to Traffic Ops, got: %v", err)
+	}
+	return to
+}
+
+// endpointServer returns a server which responds with the given response to requests with the given method and path, and fails the test with 404 Not Found for any other request.
+func endpointServer(t *testing.T, method string, path string, resp interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method || r.URL.Path != path {
+			testHelper.Error(t, "Should request %s %s, got: %s %s", method, path, r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestRetry(t *testing.T) {
+	server, requests := flakyServer(http.StatusServiceUnavailable, 2)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	to.RetryPolicy = testRetryPolicy
+
+	testHelper.Context(t, "Given the need to test retrying a temporarily unavailable Traffic Ops")
+
+	if _, err := to.CDNs(context.Background()); err != nil {
+		testHelper.Error(t, "Should be able to make a request to Traffic Ops after retrying, got %v", err)
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops after retrying")
+	}
+	if n := atomic.LoadInt32(requests); n != 3 {
+		testHelper.Error(t, "Should make 3 requests, made %d", n)
+	} else {
+		testHelper.Success(t, "Should make 3 requests")
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	server, requests := flakyServer(http.StatusServiceUnavailable, 10)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	to.RetryPolicy = testRetryPolicy
+
+	testHelper.Context(t, "Given the need to test a Traffic Ops which stays unavailable")
+
+	_, err := to.CDNs(context.Background())
+	if httpErr, ok := err.(*client.HTTPError); !ok || httpErr.HTTPStatusCode != http.StatusServiceUnavailable {
+		testHelper.Error(t, "Should get a Service Unavailable HTTPError, got %v", err)
+	} else {
+		testHelper.Success(t, "Should get a Service Unavailable HTTPError")
+	}
+	if n := atomic.LoadInt32(requests); n != int32(testRetryPolicy.MaxAttempts) {
+		testHelper.Error(t, "Should make %d requests, made %d", testRetryPolicy.MaxAttempts, n)
+	} else {
+		testHelper.Success(t, "Should make %d requests", testRetryPolicy.MaxAttempts)
+	}
+}
+
+func TestNoRetryNonIdempotent(t *testing.T) {
+	server, requests := flakyServer(http.StatusServiceUnavailable, 1)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	to.RetryPolicy = testRetryPolicy
+
+	testHelper.Context(t, "Given the need to test a POST to a temporarily unavailable Traffic Ops isn't retried")
+
+	if err := to.AddSummaryStats(context.Background(), client.StatsSummary{}); err == nil {
+		testHelper.Error(t, "Should not be able to POST to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to POST to Traffic Ops")
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		testHelper.Error(t, "Should make 1 request, made %d", n)
+	} else {
+		testHelper.Success(t, "Should make 1 request")
+	}
+}
+
+func TestRetryContextCanceled(t *testing.T) {
+	server, _ := flakyServer(http.StatusServiceUnavailable, 10)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	to.RetryPolicy = client.RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Minute, MaxBackoff: time.Minute}
+
+	testHelper.Context(t, "Given the need to test a canceled request isn't retried")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := to.CDNs(ctx); err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		testHelper.Error(t, "Should return when the context is done, took %v", elapsed)
+	} else {
+		testHelper.Success(t, "Should return when the context is done")
+	}
+}
+
+func TestRelogin(t *testing.T) {
+	logins := int32(0)
+	token := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/1.2/user/login" {
+			http.SetCookie(w, &http.Cookie{Name: "mojolicious", Value: strconv.Itoa(int(atomic.AddInt32(&logins, 1))), Path: "/"})
+			json.NewEncoder(w).Encode(client.Result{Alerts: []client.Alert{{Level: "success", Text: "Successfully logged in."}}})
+			return
+		}
+		cookie, err := r.Cookie("mojolicious")
+		if err != nil || cookie.Value != strconv.Itoa(int(atomic.LoadInt32(&token))) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(fixtures.CDNs())
+	}))
+	defer server.Close()
+
+	testHelper.Context(t, "Given the need to test logging in again when the Traffic Ops session expires")
+
+	atomic.StoreInt32(&token, 1)
+	to, err := client.LoginWithAgent(server.URL, "test", "password", true, "test", false, client.DefaultTimeout)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to login, got %v", err)
+	}
+
+	atomic.StoreInt32(&token, 2) // expire the session
+	if _, err := to.CDNs(context.Background()); err != nil {
+		testHelper.Error(t, "Should be able to make a request to Traffic Ops after the session expired, got %v", err)
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops after the session expired")
+	}
+	if n := atomic.LoadInt32(&logins); n != 2 {
+		testHelper.Error(t, "Should login 2 times, logged in %d", n)
+	} else {
+		testHelper.Success(t, "Should login 2 times")
+	}
+}
+
+func TestHTTPErrorAlerts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(client.Result{Alerts: []client.Alert{{Level: "error", Text: "xmlId is required"}}})
+	}))
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test Traffic Ops alerts are returned in errors")
+
+	_, err := to.CreateDeliveryService(context.Background(), &client.DeliveryService{})
+	httpErr, ok := err.(*client.HTTPError)
+	if !ok || len(httpErr.Alerts) != 1 || httpErr.Alerts[0].Text != "xmlId is required" {
+		testHelper.Error(t, "Should get an HTTPError with the response alerts, got %v", err)
+	} else {
+		testHelper.Success(t, "Should get an HTTPError with the response alerts")
+	}
+}
@@ -16,25 +16,28 @@
 package test
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"testing"
 
 	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
 	"github.com/apache/incubator-trafficcontrol/traffic_ops/client/fixtures"
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client/totest"
 	"github.com/jheitz200/test_helper"
 )
 
 func TestServers(t *testing.T) {
-	resp := fixtures.Servers()
-	server := testHelper.ValidHTTPServer(resp)
-	defer server.Close()
-
-	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	tos := totest.NewServer()
+	defer tos.Close()
+	for _, server := range fixtures.Servers().Response {
+		tos.AddServer(server)
+	}
+	to := totestSession(t, tos)
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for Servers")
 
-	servers, err := to.Servers()
+	servers, err := to.Servers(context.Background())
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -74,7 +77,7 @@ func TestServersUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for Servers")
 
-	_, err := to.Servers()
+	_, err := to.Servers(context.Background())
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -83,16 +86,17 @@ func TestServersUnauthorized(t *testing.T) {
 }
 
 func TestServerFQDN(t *testing.T) {
-	resp := fixtures.Servers()
-	server := testHelper.ValidHTTPServer(resp)
-	defer server.Close()
-
-	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	tos := totest.NewServer()
+	defer tos.Close()
+	for _, server := range fixtures.Servers().Response {
+		tos.AddServer(server)
+	}
+	to := totestSession(t, tos)
 
 	shortName := "edge-alb-01"
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for the FQDN of Server: \"%s\"", shortName)
 
-	s, err := to.ServersFqdn("edge-alb-01")
+	s, err := to.ServersFqdn(context.Background(), "edge-alb-01")
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -107,16 +111,14 @@ func TestServerFQDN(t *testing.T) {
 }
 
 func TestServerFQDNError(t *testing.T) {
-	var resp client.ServerResponse
-	server := testHelper.ValidHTTPServer(resp)
-	defer server.Close()
-
-	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	tos := totest.NewServer()
+	defer tos.Close()
+	to := totestSession(t, tos)
 
 	shortName := "edge-alb-01"
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for the FQDN of Server: \"%s\"", shortName)
 
-	_, err := to.ServersFqdn(shortName)
+	_, err := to.ServersFqdn(context.Background(), shortName)
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -133,7 +135,7 @@ func TestServerFQDNUnauthorized(t *testing.T) {
 	shortName := "edge-alb-01"
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for the FQDN of Server: \"%s\"", shortName)
 
-	_, err := to.ServersFqdn(shortName)
+	_, err := to.ServersFqdn(context.Background(), shortName)
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -142,16 +144,17 @@ func TestServerFQDNUnauthorized(t *testing.T) {
 }
 
 func TestServerShortName(t *testing.T) {
-	resp := fixtures.Servers()
-	server := testHelper.ValidHTTPServer(resp)
-	defer server.Close()
-
-	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	tos := totest.NewServer()
+	defer tos.Close()
+	for _, server := range fixtures.Servers().Response {
+		tos.AddServer(server)
+	}
+	to := totestSession(t, tos)
 
 	pattern := "edge"
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for servers that match Short Name: \"%s\"", pattern)
 
-	servers, err := to.ServersShortNameSearch(pattern)
+	servers, err := to.ServersShortNameSearch(context.Background(), pattern)
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -172,16 +175,14 @@ func TestServerShortName(t *testing.T) {
 }
 
 func TestServerShortNameError(t *testing.T) {
-	var resp client.ServerResponse
-	server := testHelper.ValidHTTPServer(resp)
-	defer server.Close()
-
-	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	tos := totest.NewServer()
+	defer tos.Close()
+	to := totestSession(t, tos)
 
 	pattern := "edge"
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for servers that match Short Name: \"%s\"", pattern)
 
-	_, err := to.ServersShortNameSearch(pattern)
+	_, err := to.ServersShortNameSearch(context.Background(), pattern)
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -198,7 +199,7 @@ func TestServerShortNameUnauthorized(t *testing.T) {
 	pattern := "edge"
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for servers that match Short Name: \"%s\"", pattern)
 
-	_, err := to.ServersShortNameSearch(pattern)
+	_, err := to.ServersShortNameSearch(context.Background(), pattern)
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -207,18 +208,22 @@ func TestServerShortNameUnauthorized(t *testing.T) {
 }
 
 func TestServerByType(t *testing.T) {
-	resp := fixtures.LogstashServers()
-	server := testHelper.ValidHTTPServer(resp)
-	defer server.Close()
-
-	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	tos := totest.NewServer()
+	defer tos.Close()
+	for _, server := range fixtures.Servers().Response {
+		tos.AddServer(server)
+	}
+	for _, server := range fixtures.LogstashServers().Response {
+		tos.AddServer(server)
+	}
+	to := totestSession(t, tos)
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for \"Logstash\" Servers")
 
 	params := make(url.Values)
-	params.Add("type", "Logstash")
+	params.Add("type", "LOGSTASH")
 
-	servers, err := to.ServersByType(params)
+	servers, err := to.ServersByType(context.Background(), params)
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -261,7 +266,7 @@ func TestServerByTypeUnauthorized(t *testing.T) {
 	params := make(url.Values)
 	params.Add("type", "Logstash")
 
-	_, err := to.ServersByType(params)
+	_, err := to.ServersByType(context.Background(), params)
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -278,7 +283,7 @@ func TestServer(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for Servers")
 
-	toserver, err := to.Server(resp.Response.HostName)
+	toserver, err := to.Server(context.Background(), resp.Response.HostName)
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -309,3 +314,119 @@ func TestServer(t *testing.T) {
 		testHelper.Success(t, "Should get \"CDN-1\" for \"CDNName\"")
 	}
 }
+
+func TestCreateServer(t *testing.T) {
+	tos := totest.NewServer()
+	defer tos.Close()
+	to := totestSession(t, tos)
+
+	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to create a Server")
+
+	result, err := to.CreateServer(context.Background(), &client.Server{HostName: "edge-alb-01", CachegroupID: 1, CDNID: 1, PhysLocationID: 1, ProfileID: 1, StatusID: 1, TypeID: 1})
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
+	}
+
+	actual := result.Response[0].HostName
+	if actual != "edge-alb-01" {
+		testHelper.Error(t, "Should get back \"edge-alb-01\" for \"Response[0].HostName\", got: %s", actual)
+	} else {
+		testHelper.Success(t, "Should get back \"edge-alb-01\" for \"Response[0].HostName\"")
+	}
+}
+
+func TestCreateServerUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to create a Server")
+
+	_, err := to.CreateServer(context.Background(), &client.Server{HostName: "edge-alb-01", CachegroupID: 1, CDNID: 1, PhysLocationID: 1, ProfileID: 1, StatusID: 1, TypeID: 1})
+	if err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+}
+
+func TestUpdateServer(t *testing.T) {
+	tos := totest.NewServer()
+	defer tos.Close()
+	tos.AddServer(fixtures.Servers().Response[0])
+	to := totestSession(t, tos)
+
+	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to update a Server")
+
+	result, err := to.UpdateServer(context.Background(), 1, &client.Server{HostName: "edge-alb-01"})
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
+	}
+
+	actual := result.Response[0].HostName
+	if actual != "edge-alb-01" {
+		testHelper.Error(t, "Should get back \"edge-alb-01\" for \"Response[0].HostName\", got: %s", actual)
+	} else {
+		testHelper.Success(t, "Should get back \"edge-alb-01\" for \"Response[0].HostName\"")
+	}
+}
+
+func TestUpdateServerUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to update a Server")
+
+	_, err := to.UpdateServer(context.Background(), 1, &client.Server{HostName: "edge-alb-01"})
+	if err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+}
+
+func TestDeleteServer(t *testing.T) {
+	tos := totest.NewServer()
+	defer tos.Close()
+	tos.AddServer(fixtures.Servers().Response[0])
+	to := totestSession(t, tos)
+
+	testHelper.Context(t, "Given the need to test a successful Traffic Ops request to delete a Server")
+
+	result, err := to.DeleteServer(context.Background(), 1)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should be able to make a request to Traffic Ops")
+	}
+
+	actual := result.Alerts[0].Level
+	if actual != "success" {
+		testHelper.Error(t, "Should get back \"success\" for \"Alerts[0].Level\", got: %s", actual)
+	} else {
+		testHelper.Success(t, "Should get back \"success\" for \"Alerts[0].Level\"")
+	}
+}
+
+func TestDeleteServerUnauthorized(t *testing.T) {
+	server := testHelper.InvalidHTTPServer(http.StatusUnauthorized)
+	defer server.Close()
+
+	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+
+	testHelper.Context(t, "Given the need to test a failed Traffic Ops request to delete a Server")
+
+	_, err := to.DeleteServer(context.Background(), 1)
+	if err == nil {
+		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
+	} else {
+		testHelper.Success(t, "Should not be able to make a request to Traffic Ops")
+	}
+}
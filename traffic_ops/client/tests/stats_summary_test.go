@@ -16,24 +16,27 @@
 package test
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
 	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
 	"github.com/apache/incubator-trafficcontrol/traffic_ops/client/fixtures"
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client/totest"
 	"github.com/jheitz200/test_helper"
 )
 
 func TestStatsSummary(t *testing.T) {
-	resp := fixtures.StatsSummary()
-	server := testHelper.ValidHTTPServer(resp)
-	defer server.Close()
-
-	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	tos := totest.NewServer()
+	defer tos.Close()
+	for _, summary := range fixtures.StatsSummary().Response {
+		tos.AddStatsSummary(summary)
+	}
+	to := totestSession(t, tos)
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for Stats Summary")
 
-	stats, err := to.SummaryStats("test-cdn", "test-ds1", "test-stat")
+	stats, err := to.SummaryStats(context.Background(), "test-cdn", "test-ds1", "test-stat")
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -81,7 +84,7 @@ func TestStatsSummaryUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for Stats Summary")
 
-	_, err := to.SummaryStats("test-cdn", "test-ds1", "test-stat")
+	_, err := to.SummaryStats(context.Background(), "test-cdn", "test-ds1", "test-stat")
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
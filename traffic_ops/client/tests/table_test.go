@@ -0,0 +1,270 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+	"github.com/jheitz200/test_helper"
+)
+
+// api20Server returns a server like the Traffic Ops 2.0 server, which responds to OPTIONS of tables, and to other requests with the given body, if they have the bearer token "key1". It also returns the count of OPTIONS requests, and records the method, path and body of the last request in last.
+func api20Server(body string, last *http.Request, lastBody *string) (*httptest.Server, *int32) {
+	probes := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			atomic.AddInt32(&probes, 1)
+			api20Options(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer key1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if last != nil {
+			*last = *r
+			b, _ := ioutil.ReadAll(r.Body)
+			*lastBody = string(b)
+		}
+		w.Write([]byte(body))
+	}))
+	return server, &probes
+}
+
+// api20Options responds to OPTIONS requests as the 2.0 server does: OK for its tables, and Not Found for others.
+func api20Options(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/api/2.0/cdn" {
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func api20Session(url string) *client.Session {
+	to := client.NewSession("", "", url, "", &http.Client{}, false)
+	to.Auth = client.AuthAPIKey
+	to.APIKey = "key1"
+	to.RetryPolicy = client.NoRetryPolicy
+	return to
+}
+
+type tableCDN struct {
+	Name string `json:"name"`
+}
+
+func TestGetTable(t *testing.T) {
+	req, body := http.Request{}, ""
+	server, _ := api20Server(`{"response":[{"name":"cdn1"},{"name":"cdn2"}],"version":2,"alerts":[{"level":"success","text":"2 rows returned."}]}`, &req, &body)
+	defer server.Close()
+
+	to := api20Session(server.URL)
+
+	testHelper.Context(t, "Given the need to test getting a table of the 2.0 API")
+
+	cdns := []tableCDN{}
+	if err := to.GetTable(context.Background(), "cdn", &cdns); err != nil {
+		testHelper.Fatal(t, "Should be able to get the table, got: %v", err)
+	}
+	if req.URL.Path != "/api/2.0/cdn" {
+		testHelper.Error(t, "Should request /api/2.0/cdn, got: %v", req.URL.Path)
+	}
+	if len(cdns) != 2 || cdns[0].Name != "cdn1" || cdns[1].Name != "cdn2" {
+		testHelper.Error(t, "Should get the rows, got: %+v", cdns)
+	} else {
+		testHelper.Success(t, "Should be able to get the table")
+	}
+
+	if err := to.GetTableRow(context.Background(), "deliveryservice_server", "deliveryservice/ds1/server/edge1", &cdns); err != nil {
+		testHelper.Fatal(t, "Should be able to get the row, got: %v", err)
+	}
+	if req.URL.Path != "/api/2.0/deliveryservice_server/deliveryservice/ds1/server/edge1" {
+		testHelper.Error(t, "Should request the row's path, got: %v", req.URL.Path)
+	} else {
+		testHelper.Success(t, "Should be able to get a row by its key")
+	}
+}
+
+func TestTableChanges(t *testing.T) {
+	req, body := http.Request{}, ""
+	server, _ := api20Server(`{"version":2,"alerts":[{"level":"success","text":"1 rows affected."}]}`, &req, &body)
+	defer server.Close()
+
+	to := api20Session(server.URL)
+	ctx := context.Background()
+
+	testHelper.Context(t, "Given the need to test changing rows of the 2.0 API")
+
+	alerts, err := to.CreateTableRow(ctx, "cdn", tableCDN{Name: "cdn3"})
+	if err != nil || len(alerts) != 1 || alerts[0].Text != "1 rows affected." {
+		testHelper.Error(t, "Should be able to create a row, got: %v %v", alerts, err)
+	} else if req.Method != http.MethodPost || req.URL.Path != "/api/2.0/cdn" || body != `{"name":"cdn3"}` {
+		testHelper.Error(t, "Should POST the row to /api/2.0/cdn, got: %v %v %v", req.Method, req.URL.Path, body)
+	} else {
+		testHelper.Success(t, "Should be able to create a row")
+	}
+
+	if _, err := to.UpdateTableRow(ctx, "cdn", "cdn3", tableCDN{Name: "cdn4"}); err != nil {
+		testHelper.Error(t, "Should be able to update a row, got: %v", err)
+	} else if req.Method != http.MethodPut || req.URL.Path != "/api/2.0/cdn/cdn3" || body != `{"name":"cdn4"}` {
+		testHelper.Error(t, "Should PUT the row to /api/2.0/cdn/cdn3, got: %v %v %v", req.Method, req.URL.Path, body)
+	} else {
+		testHelper.Success(t, "Should be able to update a row")
+	}
+
+	if _, err := to.DeleteTableRow(ctx, "cdn", "cdn4"); err != nil {
+		testHelper.Error(t, "Should be able to delete a row, got: %v", err)
+	} else if req.Method != http.MethodDelete || req.URL.Path != "/api/2.0/cdn/cdn4" {
+		testHelper.Error(t, "Should DELETE /api/2.0/cdn/cdn4, got: %v %v", req.Method, req.URL.Path)
+	} else {
+		testHelper.Success(t, "Should be able to delete a row")
+	}
+}
+
+func TestTableErrorAlerts(t *testing.T) {
+	server, _ := api20Server(`{"version":2,"alerts":[{"level":"error","text":"Internal error: pq: duplicate key"}]}`, nil, nil)
+	defer server.Close()
+
+	to := api20Session(server.URL)
+
+	testHelper.Context(t, "Given the need to test that error alerts of the 2.0 API are errors")
+
+	_, err := to.CreateTableRow(context.Background(), "cdn", tableCDN{Name: "cdn1"})
+	if alertsErr, ok := err.(*client.AlertsError); !ok || len(alertsErr.Alerts) != 1 {
+		testHelper.Error(t, "Should get an AlertsError, got: %v", err)
+	} else {
+		testHelper.Success(t, "Should get an AlertsError: %v", err)
+	}
+}
+
+func TestSupportedAPIVersions(t *testing.T) {
+	server, probes := api20Server(`{}`, nil, nil)
+	defer server.Close()
+	legacy := httptest.NewServer(http.NotFoundHandler())
+	defer legacy.Close()
+	// Traffic Ops 1.2 responds OK to OPTIONS of any path.
+	catchAll := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodOptions {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer catchAll.Close()
+
+	testHelper.Context(t, "Given the need to test discovering the API versions Traffic Ops supports")
+
+	to := api20Session(server.URL)
+	for i := 0; i < 2; i++ {
+		if ok, err := to.SupportsAPIVersion(context.Background(), client.APIVersion20); err != nil || !ok {
+			testHelper.Error(t, "Should support 2.0, got: %v %v", ok, err)
+		}
+	}
+	if n := atomic.LoadInt32(probes); n != 2 {
+		testHelper.Error(t, "Should discover the versions once with 2 probes, probed %v times", n)
+	} else {
+		testHelper.Success(t, "Should discover and cache that 2.0 is supported")
+	}
+
+	for name, url := range map[string]string{"without OPTIONS": legacy.URL, "with a catch-all OPTIONS": catchAll.URL} {
+		versions, err := api20Session(url).SupportedAPIVersions(context.Background())
+		if err != nil || len(versions) != 1 || versions[0] != client.APIVersion12 {
+			testHelper.Error(t, "Should only support 1.2 %v, got: %v %v", name, versions, err)
+		} else {
+			testHelper.Success(t, "Should discover that 2.0 isn't supported %v", name)
+		}
+	}
+}
+
+func TestCRConfigAPIVersion(t *testing.T) {
+	paths := make(chan string, 1)
+	handler := func(supports20 bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions {
+				if supports20 {
+					api20Options(w, r)
+				} else {
+					w.WriteHeader(http.StatusNotFound)
+				}
+				return
+			}
+			paths <- r.URL.Path
+			w.Write([]byte(`{}`))
+		}
+	}
+	v20 := httptest.NewServer(handler(true))
+	defer v20.Close()
+	v12 := httptest.NewServer(handler(false))
+	defer v12.Close()
+
+	testHelper.Context(t, "Given the need to test that the CRConfig is requested from the discovered API version")
+
+	expected := map[string]string{v20.URL: "/api/2.0/config/cr/cdn1/CRConfig.json", v12.URL: "/CRConfig-Snapshots/cdn1/CRConfig.json"}
+	for url, path := range expected {
+		to := api20Session(url)
+		to.API = client.APIVersionAuto
+		if _, _, err := to.GetCRConfig(context.Background(), "cdn1"); err != nil {
+			testHelper.Fatal(t, "Should be able to get the CRConfig, got: %v", err)
+		}
+		if actual := <-paths; actual != path {
+			testHelper.Error(t, "Should request %v, got: %v", path, actual)
+		} else {
+			testHelper.Success(t, "Should request %v", path)
+		}
+	}
+
+	to := api20Session(v20.URL)
+	if _, _, err := to.GetCRConfig(context.Background(), "cdn1"); err != nil {
+		testHelper.Fatal(t, "Should be able to get the CRConfig, got: %v", err)
+	}
+	if actual := <-paths; actual != "/CRConfig-Snapshots/cdn1/CRConfig.json" {
+		testHelper.Error(t, "Should request the 1.2 CRConfig by default, got: %v", actual)
+	}
+}
+
+func TestBulkTable(t *testing.T) {
+	req, body := http.Request{}, ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = *r
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"response":[{"index":0,"method":"POST","path":"cdn","status":"rolled back","rowsAffected":1},{"index":1,"method":"DELETE","path":"cdn/cdn2","status":"failed","error":"pq: violates foreign key constraint"}],"version":2,"alerts":[{"level":"error","text":"operation 1 failed, all operations were rolled back: pq: violates foreign key constraint"}]}`))
+	}))
+	defer server.Close()
+
+	to := api20Session(server.URL)
+
+	testHelper.Context(t, "Given the need to test bulk operations of the 2.0 API")
+
+	ops := []client.TableOperation{
+		{Method: http.MethodPost, Path: "cdn", Row: tableCDN{Name: "cdn1"}},
+		{Method: http.MethodDelete, Path: "cdn/cdn2"},
+	}
+	results, err := to.BulkTable(context.Background(), ops)
+	if req.Method != http.MethodPost || req.URL.Path != "/api/2.0/bulk" || body != `[{"method":"POST","path":"cdn","body":{"name":"cdn1"}},{"method":"DELETE","path":"cdn/cdn2"}]` {
+		testHelper.Error(t, "Should POST the operations to /api/2.0/bulk, got: %v %v %v", req.Method, req.URL.Path, body)
+	}
+	if _, ok := err.(*client.AlertsError); !ok {
+		testHelper.Error(t, "Should get an AlertsError when an operation fails, got: %v", err)
+	}
+	if len(results) != 2 || results[0].Status != "rolled back" || results[1].Status != "failed" || results[1].Error == "" {
+		testHelper.Error(t, "Should get the result of each operation, got: %+v", results)
+	} else {
+		testHelper.Success(t, "Should get the result of each operation when one fails")
+	}
+}
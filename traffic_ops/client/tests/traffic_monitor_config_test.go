@@ -16,24 +16,23 @@
 package test
 
 import (
-	"net/http"
+	"context"
 	"testing"
 
-	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
 	"github.com/apache/incubator-trafficcontrol/traffic_ops/client/fixtures"
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client/totest"
 	"github.com/jheitz200/test_helper"
 )
 
 func TestTMConfig(t *testing.T) {
-	resp := fixtures.TrafficMonitorConfig()
-	server := testHelper.ValidHTTPServer(resp)
-	defer server.Close()
-
-	to := client.NewSession("", "", server.URL, "", &http.Client{}, false)
+	tos := totest.NewServer()
+	defer tos.Close()
+	tos.SetMonitoringConfig("test-cdn", fixtures.TrafficMonitorConfig().Response)
+	to := totestSession(t, tos)
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for TM Config")
 
-	tm, err := to.TrafficMonitorConfigMap("test-cdn")
+	tm, err := to.TrafficMonitorConfigMap(context.Background(), "test-cdn")
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
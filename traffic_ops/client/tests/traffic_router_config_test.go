@@ -16,6 +16,7 @@
 package test
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
@@ -33,7 +34,7 @@ func TestTRConfig(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for TR Config")
 
-	tr, err := to.TrafficRouterConfigMap("title-vi")
+	tr, err := to.TrafficRouterConfigMap(context.Background(), "title-vi")
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
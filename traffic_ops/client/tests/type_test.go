@@ -16,6 +16,7 @@
 package test
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
@@ -33,7 +34,7 @@ func TestTypes(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for Types")
 
-	types, err := to.Types()
+	types, err := to.Types(context.Background())
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -75,7 +76,7 @@ func TestTypesUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for Types")
 
-	_, err := to.Types()
+	_, err := to.Types(context.Background())
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -16,6 +16,7 @@
 package test
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
@@ -33,7 +34,7 @@ func TestUsers(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a successful Traffic Ops request for Users")
 
-	users, err := to.Users()
+	users, err := to.Users(context.Background())
 	if err != nil {
 		testHelper.Error(t, "Should be able to make a request to Traffic Ops")
 	} else {
@@ -81,7 +82,7 @@ func TestUsersUnauthorized(t *testing.T) {
 
 	testHelper.Context(t, "Given the need to test a failed Traffic Ops request for Users")
 
-	_, err := to.Users()
+	_, err := to.Users(context.Background())
 	if err == nil {
 		testHelper.Error(t, "Should not be able to make a request to Traffic Ops")
 	} else {
@@ -0,0 +1,317 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+)
+
+// deliveryServicesFile is the declarative description of delivery services read by `diff` and `apply`, e.g.
+//
+//	deliveryServices:
+//	- xmlId: demo1
+//	  displayName: Demo 1
+//	  active: true
+//	  orgServerFqdn: http://origin.example.net
+//
+// Only the fields given are managed: other fields of existing delivery services are left as they are.
+type deliveryServicesFile struct {
+	DeliveryServices []map[string]interface{} `json:"deliveryServices"`
+}
+
+const (
+	changeCreate = "create"
+	changeUpdate = "update"
+	changeDelete = "delete"
+)
+
+// dsChange is a change to make to Traffic Ops so a delivery service matches its description.
+type dsChange struct {
+	action string
+	xmlID  string
+	// id is the Traffic Ops ID of the delivery service to update or delete.
+	id int
+	// ds is the delivery service to create, or the whole updated delivery service.
+	ds     client.DeliveryService
+	fields []fieldChange
+}
+
+type dsChangesByXMLID []dsChange
+
+func (c dsChangesByXMLID) Len() int           { return len(c) }
+func (c dsChangesByXMLID) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c dsChangesByXMLID) Less(i, j int) bool { return c[i].xmlID < c[j].xmlID }
+
+type fieldChange struct {
+	name string
+	old  interface{}
+	new  interface{}
+}
+
+func runDiff(c cmdContext) error {
+	changes, err := plan(c)
+	if err != nil {
+		return err
+	}
+	printPlan(c.stdout, changes)
+	return nil
+}
+
+func runApply(c cmdContext) error {
+	changes, err := plan(c)
+	if err != nil {
+		return err
+	}
+	printPlan(c.stdout, changes)
+	if c.to.ValidateDeliveryServices || c.to.DryRun {
+		if err := validatePlan(changes); err != nil {
+			return err
+		}
+	}
+	if c.to.DryRun {
+		fmt.Fprintln(c.stdout, "Dry run: nothing was changed.")
+		return nil
+	}
+	for _, change := range changes {
+		switch change.action {
+		case changeCreate:
+			_, err = c.to.CreateDeliveryService(c.ctx, &change.ds)
+		case changeUpdate:
+			_, err = c.to.UpdateDeliveryService(c.ctx, strconv.Itoa(change.id), &change.ds)
+		case changeDelete:
+			_, err = c.to.DeleteDeliveryService(c.ctx, strconv.Itoa(change.id))
+		}
+		if err != nil {
+			return fmt.Errorf("%v %v: %v", change.action, change.xmlID, err)
+		}
+		fmt.Fprintf(c.stdout, "%vd %v\n", strings.TrimSuffix(change.action, "e"), change.xmlID)
+	}
+	return nil
+}
+
+// validatePlan validates the delivery services of every create and update, so none are applied if any are invalid. The error lists the problems of all of them.
+func validatePlan(changes []dsChange) error {
+	msgs := []string{}
+	for _, change := range changes {
+		if change.action == changeDelete {
+			continue
+		}
+		if err := change.ds.Validate(); err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+	if len(msgs) > 0 {
+		return errors.New(strings.Join(msgs, "\n"))
+	}
+	return nil
+}
+
+// plan returns the changes to make Traffic Ops match the command's file.
+func plan(c cmdContext) ([]dsChange, error) {
+	body, err := readInput(c.file)
+	if err != nil {
+		return nil, err
+	}
+	file := deliveryServicesFile{}
+	if err := decodeStrict(body, &file); err != nil {
+		return nil, err
+	}
+	existing, err := c.to.ListDeliveryServices(c.ctx, client.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return planDeliveryServices(existing, file.DeliveryServices, c.prune)
+}
+
+// planDeliveryServices returns the changes to make the existing delivery services match the desired ones. Desired delivery services are matched to existing ones by xmlId. Delivery services which exist but aren't desired are deleted only if prune is true. Creates and updates are in the order of desired, followed by deletes.
+func planDeliveryServices(existing []client.DeliveryService, desired []map[string]interface{}, prune bool) ([]dsChange, error) {
+	fields := deliveryServiceFields()
+	existingByXMLID := map[string]client.DeliveryService{}
+	for _, ds := range existing {
+		existingByXMLID[ds.XMLID] = ds
+	}
+
+	changes := []dsChange{}
+	seen := map[string]struct{}{}
+	for i, want := range desired {
+		xmlID, ok := want["xmlId"].(string)
+		if !ok || xmlID == "" {
+			return nil, fmt.Errorf("delivery service %v: missing xmlId", i+1)
+		}
+		if _, ok := seen[xmlID]; ok {
+			return nil, fmt.Errorf("delivery service %v: duplicate xmlId", xmlID)
+		}
+		seen[xmlID] = struct{}{}
+		for key := range want {
+			if key == "id" {
+				return nil, fmt.Errorf("delivery service %v: id can't be given, delivery services are matched by xmlId", xmlID)
+			}
+			if _, ok := fields[key]; !ok {
+				return nil, fmt.Errorf("delivery service %v: unknown field '%v'", xmlID, key)
+			}
+		}
+
+		ds, ok := existingByXMLID[xmlID]
+		if !ok {
+			change, err := planCreate(xmlID, want)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, change)
+			continue
+		}
+		change, err := planUpdate(ds, want)
+		if err != nil {
+			return nil, err
+		}
+		if len(change.fields) > 0 {
+			changes = append(changes, change)
+		}
+	}
+
+	if !prune {
+		return changes, nil
+	}
+	deletes := []dsChange{}
+	for _, ds := range existing {
+		if _, ok := seen[ds.XMLID]; ok {
+			continue
+		}
+		deletes = append(deletes, dsChange{action: changeDelete, xmlID: ds.XMLID, id: ds.ID})
+	}
+	sort.Sort(dsChangesByXMLID(deletes))
+	return append(changes, deletes...), nil
+}
+
+func planCreate(xmlID string, want map[string]interface{}) (dsChange, error) {
+	change := dsChange{action: changeCreate, xmlID: xmlID}
+	normalized, err := normalizeDeliveryService(want, &change.ds)
+	if err != nil {
+		return dsChange{}, fmt.Errorf("delivery service %v: %v", xmlID, err)
+	}
+	for _, key := range sortedKeys(want) {
+		change.fields = append(change.fields, fieldChange{name: key, new: normalized[key]})
+	}
+	return change, nil
+}
+
+// planUpdate returns the update of the existing delivery service, to the given fields. The returned change has no fields if the delivery service already matches.
+func planUpdate(ds client.DeliveryService, want map[string]interface{}) (dsChange, error) {
+	change := dsChange{action: changeUpdate, xmlID: ds.XMLID, id: ds.ID}
+	generic, err := toGeneric(ds)
+	if err != nil {
+		return dsChange{}, err
+	}
+	current, _ := generic.(map[string]interface{})
+	merged := map[string]interface{}{}
+	for key, val := range current {
+		merged[key] = val
+	}
+	for key, val := range want {
+		merged[key] = val
+	}
+	normalized, err := normalizeDeliveryService(merged, &change.ds)
+	if err != nil {
+		return dsChange{}, fmt.Errorf("delivery service %v: %v", ds.XMLID, err)
+	}
+	for _, key := range sortedKeys(want) {
+		if !jsonEqual(current[key], normalized[key]) {
+			change.fields = append(change.fields, fieldChange{name: key, old: current[key], new: normalized[key]})
+		}
+	}
+	return change, nil
+}
+
+// normalizeDeliveryService decodes the given fields into ds, and returns ds's fields, so values are compared as Traffic Ops would store them, e.g. an omitted matchList and an empty one.
+func normalizeDeliveryService(fields map[string]interface{}, ds *client.DeliveryService) (map[string]interface{}, error) {
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	if err := decodeStrict(b, ds); err != nil {
+		return nil, err
+	}
+	generic, err := toGeneric(ds)
+	if err != nil {
+		return nil, err
+	}
+	normalized, _ := generic.(map[string]interface{})
+	return normalized, nil
+}
+
+// deliveryServiceFields returns the JSON field names of client.DeliveryService.
+func deliveryServiceFields() map[string]struct{} {
+	fields := map[string]struct{}{}
+	t := reflect.TypeOf(client.DeliveryService{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields[name] = struct{}{}
+		}
+	}
+	return fields
+}
+
+func jsonEqual(a, b interface{}) bool {
+	ab, aErr := json.Marshal(a)
+	bb, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && string(ab) == string(bb)
+}
+
+// printPlan writes the changes, in a diff-like format.
+func printPlan(w io.Writer, changes []dsChange) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No changes.")
+		return
+	}
+	counts := map[string]int{}
+	for _, change := range changes {
+		counts[change.action]++
+		switch change.action {
+		case changeCreate:
+			fmt.Fprintf(w, "+ deliveryservice %v\n", change.xmlID)
+			for _, field := range change.fields {
+				fmt.Fprintf(w, "    %v: %v\n", field.name, planValue(field.new))
+			}
+		case changeUpdate:
+			fmt.Fprintf(w, "~ deliveryservice %v (id %v)\n", change.xmlID, change.id)
+			for _, field := range change.fields {
+				fmt.Fprintf(w, "    %v: %v -> %v\n", field.name, planValue(field.old), planValue(field.new))
+			}
+		case changeDelete:
+			fmt.Fprintf(w, "- deliveryservice %v (id %v)\n", change.xmlID, change.id)
+		}
+	}
+	fmt.Fprintf(w, "Plan: %v to create, %v to update, %v to delete.\n", counts[changeCreate], counts[changeUpdate], counts[changeDelete])
+}
+
+// planValue formats a field value for a plan, as JSON, so e.g. an empty string is distinguishable from a missing value.
+func planValue(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
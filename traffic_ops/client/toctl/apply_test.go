@@ -0,0 +1,137 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+	"github.com/jheitz200/test_helper"
+)
+
+func existingDeliveryServices() []client.DeliveryService {
+	return []client.DeliveryService{
+		{ID: 1, XMLID: "ds-one", DisplayName: "DS One", Active: true, OrgServerFQDN: "http://one.example.net"},
+		{ID: 2, XMLID: "ds-two", DisplayName: "DS Two", Active: false, OrgServerFQDN: "http://two.example.net"},
+	}
+}
+
+func TestPlanNoChanges(t *testing.T) {
+	testHelper.Context(t, "Given delivery services which match their description")
+
+	desired := []map[string]interface{}{
+		{"xmlId": "ds-one", "displayName": "DS One", "active": true},
+		{"xmlId": "ds-two", "orgServerFqdn": "http://two.example.net"},
+	}
+	changes, err := planDeliveryServices(existingDeliveryServices(), desired, false)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to plan, got: %v", err)
+	}
+	if len(changes) != 0 {
+		testHelper.Error(t, "Should plan no changes, got: %+v", changes)
+	} else {
+		testHelper.Success(t, "Should plan no changes")
+	}
+
+	out := bytes.Buffer{}
+	printPlan(&out, changes)
+	if out.String() != "No changes.\n" {
+		testHelper.Error(t, "Should print no changes, got: %v", out.String())
+	}
+}
+
+func TestPlanChanges(t *testing.T) {
+	testHelper.Context(t, "Given a description which adds, changes and omits delivery services")
+
+	desired := []map[string]interface{}{
+		{"xmlId": "ds-three", "displayName": "DS Three", "active": true},
+		{"xmlId": "ds-one", "displayName": "DS One", "active": false},
+	}
+	changes, err := planDeliveryServices(existingDeliveryServices(), desired, true)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to plan, got: %v", err)
+	}
+	if len(changes) != 3 {
+		testHelper.Fatal(t, "Should plan 3 changes, got: %+v", changes)
+	}
+
+	create := changes[0]
+	if create.action != changeCreate || create.xmlID != "ds-three" || create.ds.DisplayName != "DS Three" || !create.ds.Active {
+		testHelper.Error(t, "Should create ds-three, got: %+v", create)
+	} else {
+		testHelper.Success(t, "Should create ds-three")
+	}
+
+	update := changes[1]
+	if update.action != changeUpdate || update.id != 1 || len(update.fields) != 1 || update.fields[0].name != "active" {
+		testHelper.Error(t, "Should update only active of ds-one, got: %+v", update)
+	} else if update.ds.Active || update.ds.OrgServerFQDN != "http://one.example.net" {
+		testHelper.Error(t, "Should update ds-one with its unmanaged fields unchanged, got: %+v", update.ds)
+	} else {
+		testHelper.Success(t, "Should update only active of ds-one")
+	}
+
+	del := changes[2]
+	if del.action != changeDelete || del.xmlID != "ds-two" || del.id != 2 {
+		testHelper.Error(t, "Should delete ds-two, got: %+v", del)
+	} else {
+		testHelper.Success(t, "Should delete ds-two when pruning")
+	}
+
+	out := bytes.Buffer{}
+	printPlan(&out, changes)
+	for _, line := range []string{"+ deliveryservice ds-three", "~ deliveryservice ds-one (id 1)", "    active: true -> false", "- deliveryservice ds-two (id 2)", "Plan: 1 to create, 1 to update, 1 to delete."} {
+		if !strings.Contains(out.String(), line+"\n") {
+			testHelper.Error(t, "Should print '%v', got: %v", line, out.String())
+		}
+	}
+}
+
+func TestPlanNoPrune(t *testing.T) {
+	testHelper.Context(t, "Given a description which omits delivery services, without pruning")
+
+	desired := []map[string]interface{}{{"xmlId": "ds-one", "active": true}}
+	changes, err := planDeliveryServices(existingDeliveryServices(), desired, false)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to plan, got: %v", err)
+	}
+	if len(changes) != 0 {
+		testHelper.Error(t, "Should not delete omitted delivery services, got: %+v", changes)
+	} else {
+		testHelper.Success(t, "Should not delete omitted delivery services")
+	}
+}
+
+func TestPlanInvalid(t *testing.T) {
+	testHelper.Context(t, "Given invalid descriptions of delivery services")
+
+	invalid := map[string][]map[string]interface{}{
+		"missing xmlId":   {{"displayName": "DS"}},
+		"duplicate xmlId": {{"xmlId": "ds-one"}, {"xmlId": "ds-one"}},
+		"id":              {{"xmlId": "ds-one", "id": 5}},
+		"unknown field":   {{"xmlId": "ds-one", "displayNmae": "DS"}},
+		"wrong type":      {{"xmlId": "ds-one", "active": "yes"}},
+	}
+	for name, desired := range invalid {
+		if _, err := planDeliveryServices(existingDeliveryServices(), desired, false); err == nil {
+			testHelper.Error(t, "Should get an error for a %v", name)
+		} else {
+			testHelper.Success(t, "Should get an error for a %v: %v", name, err)
+		}
+	}
+}
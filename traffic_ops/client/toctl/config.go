@@ -0,0 +1,105 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Config is the toctl config file, of named credential profiles, e.g.
+//
+//	default: prod
+//	profiles:
+//	  prod:
+//	    url: https://traffic-ops.example.net
+//	    user: admin
+//	    password: secret
+//	  staging:
+//	    url: https://traffic-ops.staging.example.net
+//	    apiKey: 0123456789abcdef
+//
+// Because it contains passwords, it should only be readable by its owner. Alternatively, passwordFile is the path of a file containing only the password, and credentials may be given in the TRAFFIC_OPS_ environment variables, see client.AuthConfigFromEnv.
+type Config struct {
+	Default  string             `yaml:"default"`
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+// Profile is the credentials of a Traffic Ops. Auth is cookie, token or apikey, see client.AuthMethod; if empty, apikey is used if there's an apiKey, and cookie otherwise.
+type Profile struct {
+	URL          string `yaml:"url"`
+	User         string `yaml:"user"`
+	Password     string `yaml:"password"`
+	PasswordFile string `yaml:"passwordFile"`
+	APIKey       string `yaml:"apiKey"`
+	Auth         string `yaml:"auth"`
+	Insecure     bool   `yaml:"insecure"`
+}
+
+// defaultConfigFile returns the config file used if none is given, `.toctl.yaml` in the user's home directory.
+func defaultConfigFile() string {
+	return filepath.Join(os.Getenv("HOME"), ".toctl.yaml")
+}
+
+// loadConfig loads the config file at the given path. A missing file is an empty config, so toctl can be used with only flags and the environment. Because it contains credentials, the file must not be readable or writable by group or others, see client.ReadCredentialsFile.
+func loadConfig(path string) (Config, error) {
+	cfg := Config{}
+	b, err := client.ReadCredentialsFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := yaml.UnmarshalStrict(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("parsing %v: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// profile returns the profile with the given name, or the default profile if the name is empty. If the name is empty and there's no default, the empty profile is returned.
+func (c Config) profile(name string) (Profile, error) {
+	if name == "" {
+		name = c.Default
+	}
+	if name == "" {
+		return Profile{}, nil
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("no profile '%v'", name)
+	}
+	return p, nil
+}
+
+// authConfig returns the profile's credentials, overridden by those in the environment, and then by the given flags, if they're set. There's no password flag, because arguments are visible to other users; passwords come from the profile or the environment.
+func (p Profile) authConfig(url, user string) client.AuthConfig {
+	cfg := client.AuthConfig{
+		URL:          p.URL,
+		User:         p.User,
+		Password:     p.Password,
+		PasswordFile: p.PasswordFile,
+		APIKey:       p.APIKey,
+		Auth:         client.AuthMethod(p.Auth),
+	}
+	cfg = cfg.Override(client.AuthConfigFromEnv())
+	return cfg.Override(client.AuthConfig{URL: url, User: user})
+}
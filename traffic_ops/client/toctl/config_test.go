@@ -0,0 +1,78 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jheitz200/test_helper"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "toctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "toctl.yaml")
+	if err := ioutil.WriteFile(path, []byte("default: prod\nprofiles:\n  prod:\n    url: https://to.example.net\n    user: admin\n    password: secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testHelper.Context(t, "Given the need to test loading the toctl config file")
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to load a config file only its owner can read, got: %v", err)
+	}
+	if p, err := cfg.profile(""); err != nil || p.Password != "secret" {
+		testHelper.Error(t, "Should get the default profile's password, got %+v %v", p, err)
+	} else {
+		testHelper.Success(t, "Should load the default profile")
+	}
+
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadConfig(path); err == nil {
+		testHelper.Error(t, "Should not load a config file readable by others")
+	} else {
+		testHelper.Success(t, "Should not load a config file readable by others")
+	}
+
+	if cfg, err := loadConfig(filepath.Join(dir, "missing.yaml")); err != nil || len(cfg.Profiles) != 0 {
+		testHelper.Error(t, "Should get an empty config for a missing file, got %+v %v", cfg, err)
+	} else {
+		testHelper.Success(t, "Should get an empty config for a missing file")
+	}
+}
+
+func TestProfileAuthConfig(t *testing.T) {
+	os.Setenv("TRAFFIC_OPS_PASSWORD", "env-secret")
+	defer os.Unsetenv("TRAFFIC_OPS_PASSWORD")
+
+	testHelper.Context(t, "Given the need to test that a profile's password is overridden by the environment")
+
+	auth := Profile{URL: "https://to.example.net", User: "admin", Password: "secret"}.authConfig("", "operator")
+	if auth.Password != "env-secret" || auth.User != "operator" || auth.URL != "https://to.example.net" {
+		testHelper.Error(t, "Should get the environment's password and the flag's user, got: %+v", auth)
+	} else {
+		testHelper.Success(t, "Should override the profile with the environment and flags")
+	}
+}
@@ -0,0 +1,311 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// toctl is a command-line tool for managing Traffic Ops resources with the Traffic Ops client.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+)
+
+// UserAgent is the User-Agent toctl sends to Traffic Ops.
+const UserAgent = "toctl/0.1"
+
+const usage = `Usage: toctl [flags] <command> [arguments]
+
+Commands:
+  list <resource> [key=value ...]    list resources, filtered by query parameters
+  show <resource> <id|name>          show a single resource
+  create <resource> -f <file>        create resources from a JSON or YAML file
+  update <resource> <id> -f <file>   update a resource from a JSON or YAML file
+  delete <resource> <id>             delete a resource
+  assign <xmlId> <hostName> ...      assign servers to a delivery service, replacing its servers
+  diff -f <file> [-prune]            show how Traffic Ops differs from a YAML description of delivery services
+  apply -f <file> [-prune]           change Traffic Ops to match a YAML description of delivery services
+
+With -validate, delivery services are checked against Traffic Ops' rules before any are sent.
+With -dry-run, delivery services are validated, and nothing is changed.
+
+Resources: %v
+
+Flags:
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs toctl with the given arguments, and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("toctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configFile := flags.String("config", defaultConfigFile(), "the config file of credential profiles")
+	profileName := flags.String("profile", "", "the credential profile to use, default the config file's default profile")
+	toURL := flags.String("url", "", "the Traffic Ops URL, overriding the profile")
+	toUser := flags.String("user", "", "the Traffic Ops user, overriding the profile. The password is read from the profile, or from "+client.EnvPassword+" or "+client.EnvPasswordFile)
+	insecure := flags.Bool("insecure", false, "whether to skip verifying the Traffic Ops certificate")
+	output := flags.String("o", formatTable, "the output format: table, json or yaml")
+	file := flags.String("f", "", "the JSON or YAML file to read, or - for stdin")
+	prune := flags.Bool("prune", false, "with diff and apply, delete delivery services which aren't in the file")
+	validate := flags.Bool("validate", false, "whether to validate delivery services before creating or updating them")
+	dryRun := flags.Bool("dry-run", false, "whether to validate delivery services without changing anything")
+	timeout := flags.Duration("timeout", client.DefaultTimeout, "the timeout of each request to Traffic Ops")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, usage, strings.Join(resourceNames(), ", "))
+		flags.PrintDefaults()
+	}
+
+	pos, err := parseInterspersed(flags, args)
+	if err != nil {
+		return 2
+	}
+	if len(pos) == 0 {
+		flags.Usage()
+		return 2
+	}
+	if !validFormat(*output) {
+		fmt.Fprintf(stderr, "invalid output format '%v', must be table, json or yaml\n", *output)
+		return 2
+	}
+
+	cmd, cmdArgs := pos[0], pos[1:]
+	command, ok := commands[cmd]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command '%v'\n", cmd)
+		flags.Usage()
+		return 2
+	}
+	if len(cmdArgs) < command.minArgs || (command.needsFile && *file == "") {
+		fmt.Fprintf(stderr, "usage: toctl %v\n", command.usage)
+		return 2
+	}
+
+	cfg, err := loadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "error loading config: %v\n", err)
+		return 1
+	}
+	profile, err := cfg.profile(*profileName)
+	if err != nil {
+		fmt.Fprintf(stderr, "error loading config: %v\n", err)
+		return 1
+	}
+	auth := profile.authConfig(*toURL, *toUser)
+	if auth.URL == "" {
+		fmt.Fprintf(stderr, "no Traffic Ops URL: set -url, %v, or a profile in %v\n", client.EnvURL, *configFile)
+		return 2
+	}
+
+	ctx := context.Background()
+	to, err := client.LoginWithAuthConfig(ctx, auth, profile.Insecure || *insecure, UserAgent, false, *timeout)
+	if err != nil {
+		fmt.Fprintf(stderr, "error logging in to %v: %v\n", auth.URL, err)
+		return 1
+	}
+	to.ValidateDeliveryServices = *validate
+	to.DryRun = *dryRun
+
+	c := cmdContext{ctx: ctx, to: to, args: cmdArgs, file: *file, prune: *prune, format: *output, stdout: stdout}
+	if err := command.run(c); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parseInterspersed parses the given arguments with the given flags, allowing flags after positional arguments, e.g. `create servers -f server.yaml`. Returns the positional arguments.
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	pos := []string{}
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		if flags.NArg() == 0 {
+			return pos, nil
+		}
+		pos = append(pos, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+}
+
+// cmdContext is what a command runs with.
+type cmdContext struct {
+	ctx    context.Context
+	to     *client.Session
+	args   []string
+	file   string
+	prune  bool
+	format string
+	stdout io.Writer
+}
+
+type command struct {
+	usage     string
+	minArgs   int
+	needsFile bool
+	run       func(c cmdContext) error
+}
+
+var commands = map[string]command{
+	"list":   {usage: "list <resource> [key=value ...]", minArgs: 1, run: runList},
+	"show":   {usage: "show <resource> <id|name>", minArgs: 2, run: runShow},
+	"create": {usage: "create <resource> -f <file>", minArgs: 1, needsFile: true, run: runCreate},
+	"update": {usage: "update <resource> <id> -f <file>", minArgs: 2, needsFile: true, run: runUpdate},
+	"delete": {usage: "delete <resource> <id>", minArgs: 2, run: runDelete},
+	"assign": {usage: "assign <xmlId> <hostName> ...", minArgs: 1, run: runAssign},
+	"diff":   {usage: "diff -f <file> [-prune]", needsFile: true, run: runDiff},
+	"apply":  {usage: "apply -f <file> [-prune]", needsFile: true, run: runApply},
+}
+
+func runList(c cmdContext) error {
+	r, err := getResource(c.args[0])
+	if err != nil {
+		return err
+	}
+	if r.list == nil {
+		return fmt.Errorf("%v can't be listed", r.name)
+	}
+	filters, err := parseFilters(c.args[1:])
+	if err != nil {
+		return err
+	}
+	items, err := r.list(c.ctx, c.to, filters)
+	if err != nil {
+		return err
+	}
+	return printOutput(c.stdout, c.format, r.columns, items)
+}
+
+func runShow(c cmdContext) error {
+	r, err := getResource(c.args[0])
+	if err != nil {
+		return err
+	}
+	if r.list == nil {
+		return fmt.Errorf("%v can't be shown", r.name)
+	}
+	filters, err := parseFilters(c.args[2:])
+	if err != nil {
+		return err
+	}
+	items, err := r.list(c.ctx, c.to, filters)
+	if err != nil {
+		return err
+	}
+	item, err := findItem(items, r.keys, c.args[1])
+	if err != nil {
+		return fmt.Errorf("%v %v", r.name, err)
+	}
+	return printOutput(c.stdout, c.format, r.columns, item)
+}
+
+func runCreate(c cmdContext) error {
+	r, err := getResource(c.args[0])
+	if err != nil {
+		return err
+	}
+	if err := checkDryRun(c, r); err != nil {
+		return err
+	}
+	if r.create == nil {
+		return fmt.Errorf("%v can't be created", r.name)
+	}
+	body, err := readInput(c.file)
+	if err != nil {
+		return err
+	}
+	result, err := r.create(c.ctx, c.to, body)
+	if err != nil {
+		return err
+	}
+	return printOutput(c.stdout, c.format, r.columns, result)
+}
+
+func runUpdate(c cmdContext) error {
+	r, err := getResource(c.args[0])
+	if err != nil {
+		return err
+	}
+	if err := checkDryRun(c, r); err != nil {
+		return err
+	}
+	if r.update == nil {
+		return fmt.Errorf("%v can't be updated", r.name)
+	}
+	body, err := readInput(c.file)
+	if err != nil {
+		return err
+	}
+	result, err := r.update(c.ctx, c.to, c.args[1], body)
+	if err != nil {
+		return err
+	}
+	return printOutput(c.stdout, c.format, r.columns, result)
+}
+
+func runDelete(c cmdContext) error {
+	r, err := getResource(c.args[0])
+	if err != nil {
+		return err
+	}
+	if err := checkDryRun(c, r); err != nil {
+		return err
+	}
+	if r.del == nil {
+		return fmt.Errorf("%v can't be deleted", r.name)
+	}
+	result, err := r.del(c.ctx, c.to, c.args[1])
+	if err != nil {
+		return err
+	}
+	return printOutput(c.stdout, c.format, nil, result)
+}
+
+func runAssign(c cmdContext) error {
+	result, err := c.to.AssignDeliveryServiceServers(c.ctx, c.args[0], c.args[1:])
+	if err != nil {
+		return err
+	}
+	return printOutput(c.stdout, c.format, []string{"xmlId", "serverNames"}, result)
+}
+
+// checkDryRun returns an error if the command is a dry run, which the client doesn't support for changing r, so nothing is changed by mistake.
+func checkDryRun(c cmdContext, r resource) error {
+	if c.to.DryRun && !r.dryRun {
+		return fmt.Errorf("%v can't be changed with -dry-run", r.name)
+	}
+	return nil
+}
+
+// parseFilters parses `key=value` arguments into query parameters.
+func parseFilters(args []string) (url.Values, error) {
+	filters := url.Values{}
+	for _, arg := range args {
+		i := strings.Index(arg, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid filter '%v', must be key=value", arg)
+		}
+		filters.Add(arg[:i], arg[i+1:])
+	}
+	return filters, nil
+}
@@ -0,0 +1,191 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client/totest"
+	"github.com/jheitz200/test_helper"
+)
+
+// missingConfigFile is a config file which doesn't exist, so toctl runs with only flags and the environment.
+var missingConfigFile = filepath.Join(os.TempDir(), "toctl-test-missing.yaml")
+
+// runTOCtl runs toctl against the given server with the given arguments, as the admin user with the password in the environment, and returns its exit code and output.
+func runTOCtl(tos *totest.Server, args ...string) (int, string, string) {
+	os.Setenv(client.EnvPassword, "password")
+	defer os.Unsetenv(client.EnvPassword)
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	args = append([]string{"-config", missingConfigFile, "-url", tos.URL, "-user", "admin"}, args...)
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunList(t *testing.T) {
+	tos := totest.NewServer()
+	defer tos.Close()
+	tos.AddUser("admin", "password")
+	tos.AddServer(client.Server{HostName: "edge1", Type: "EDGE"})
+	tos.AddServer(client.Server{HostName: "mid1", Type: "MID"})
+
+	testHelper.Context(t, "Given the need to test listing servers with toctl")
+
+	code, stdout, stderr := runTOCtl(tos, "list", "servers", "type=MID", "-o", "json")
+	if code != 0 {
+		testHelper.Fatal(t, "Should exit 0, got %v: %v", code, stderr)
+	}
+	if !strings.Contains(stdout, `"hostName": "mid1"`) || strings.Contains(stdout, "edge1") {
+		testHelper.Error(t, "Should list only the MID server, got: %v", stdout)
+	} else {
+		testHelper.Success(t, "Should list servers filtered by type")
+	}
+}
+
+func TestRunApply(t *testing.T) {
+	tos := totest.NewServer()
+	defer tos.Close()
+	tos.AddUser("admin", "password")
+	tos.AddDeliveryService(client.DeliveryService{XMLID: "ds-one", Active: false, OrgServerFQDN: "http://one.example.net"})
+	tos.AddDeliveryService(client.DeliveryService{XMLID: "ds-old"})
+
+	dir, err := ioutil.TempDir("", "toctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "ds.yaml")
+	desired := "deliveryServices:\n- xmlId: ds-one\n  active: true\n- xmlId: ds-new\n  displayName: New\n"
+	if err := ioutil.WriteFile(file, []byte(desired), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testHelper.Context(t, "Given the need to test applying a description of delivery services with toctl")
+
+	if code, stdout, stderr := runTOCtl(tos, "apply", "-f", file, "-prune"); code != 0 {
+		testHelper.Fatal(t, "Should exit 0, got %v: %v %v", code, stdout, stderr)
+	}
+	dses := tos.DeliveryServices()
+	if len(dses) != 2 || dses[0].XMLID != "ds-one" || !dses[0].Active || dses[0].OrgServerFQDN != "http://one.example.net" || dses[1].XMLID != "ds-new" || dses[1].DisplayName != "New" {
+		testHelper.Error(t, "Should update ds-one, create ds-new and delete ds-old, got: %+v", dses)
+	} else {
+		testHelper.Success(t, "Should apply the delivery services")
+	}
+
+	code, stdout, stderr := runTOCtl(tos, "diff", "-f", file, "-prune")
+	if code != 0 || stdout != "No changes.\n" {
+		testHelper.Error(t, "Should have no changes after applying, got %v: %v %v", code, stdout, stderr)
+	} else {
+		testHelper.Success(t, "Should have no changes after applying")
+	}
+}
+
+func TestRunApplyDryRun(t *testing.T) {
+	tos := totest.NewServer()
+	defer tos.Close()
+	tos.AddUser("admin", "password")
+
+	dir, err := ioutil.TempDir("", "toctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	valid := filepath.Join(dir, "valid.yaml")
+	desired := "deliveryServices:\n- xmlId: ds-new\n  displayName: New\n  type: HTTP\n  typeId: 1\n  cdnName: cdn1\n  cdnId: 1\n  orgServerFqdn: http://new.example.net\n"
+	if err := ioutil.WriteFile(valid, []byte(desired), 0600); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(dir, "invalid.yaml")
+	desired = "deliveryServices:\n- xmlId: ds-dns\n  displayName: DNS\n  type: DNS\n  cdnName: cdn1\n  httpBypassFqdn: bypass.example.net\n"
+	if err := ioutil.WriteFile(invalid, []byte(desired), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testHelper.Context(t, "Given the need to test applying delivery services with toctl in a dry run")
+
+	code, stdout, stderr := runTOCtl(tos, "apply", "-f", valid, "-dry-run")
+	if code != 0 || !strings.Contains(stdout, "Dry run: nothing was changed.") {
+		testHelper.Error(t, "Should exit 0 with a dry run message, got %v: %v %v", code, stdout, stderr)
+	} else if dses := tos.DeliveryServices(); len(dses) != 0 {
+		testHelper.Error(t, "Should not create delivery services in a dry run, got: %+v", dses)
+	} else {
+		testHelper.Success(t, "Should not change anything in a dry run")
+	}
+
+	code, stdout, stderr = runTOCtl(tos, "apply", "-f", invalid, "-validate")
+	if code != 1 || !strings.Contains(stderr, "orgServerFqdn") || !strings.Contains(stderr, "httpBypassFqdn") {
+		testHelper.Error(t, "Should exit 1 with the validation errors, got %v: %v %v", code, stdout, stderr)
+	} else if dses := tos.DeliveryServices(); len(dses) != 0 {
+		testHelper.Error(t, "Should not create invalid delivery services, got: %+v", dses)
+	} else {
+		testHelper.Success(t, "Should not apply invalid delivery services")
+	}
+
+	if code, _, stderr := runTOCtl(tos, "create", "servers", "-f", valid, "-dry-run"); code != 1 || !strings.Contains(stderr, "-dry-run") {
+		testHelper.Error(t, "Should refuse to create servers in a dry run, got %v: %v", code, stderr)
+	}
+}
+
+func TestRunProfileAPIKey(t *testing.T) {
+	tos := totest.NewServer()
+	defer tos.Close()
+	tos.AddAPIKey("key1")
+	tos.AddServer(client.Server{HostName: "edge1"})
+
+	dir, err := ioutil.TempDir("", "toctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	configFile := filepath.Join(dir, "toctl.yaml")
+	config := "default: test\nprofiles:\n  test:\n    url: " + tos.URL + "\n    apiKey: key1\n"
+	if err := ioutil.WriteFile(configFile, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testHelper.Context(t, "Given the need to test authenticating with a profile's API key")
+
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	if code := run([]string{"-config", configFile, "show", "servers", "edge1"}, &stdout, &stderr); code != 0 {
+		testHelper.Fatal(t, "Should exit 0, got %v: %v", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "edge1") {
+		testHelper.Error(t, "Should show edge1, got: %v", stdout.String())
+	} else {
+		testHelper.Success(t, "Should authenticate with the profile's API key")
+	}
+}
+
+func TestRunPasswordFlag(t *testing.T) {
+	tos := totest.NewServer()
+	defer tos.Close()
+	tos.AddUser("admin", "password")
+
+	testHelper.Context(t, "Given the need to test that toctl doesn't take a password in its arguments")
+
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	if code := run([]string{"-config", missingConfigFile, "-url", tos.URL, "-user", "admin", "-password", "password", "list", "servers"}, &stdout, &stderr); code != 2 {
+		testHelper.Error(t, "Should exit 2 for a -password flag, got %v: %v", code, stderr.String())
+	} else {
+		testHelper.Success(t, "Should refuse a -password flag")
+	}
+}
@@ -0,0 +1,235 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+func validFormat(format string) bool {
+	return format == formatTable || format == formatJSON || format == formatYAML
+}
+
+// printOutput writes v to w in the given format. In table format, a list is written as a row per item, of the given columns, or of all fields if columns is empty; a single object is written as a row per field; and the alerts of a Traffic Ops response are written before its response.
+func printOutput(w io.Writer, format string, columns []string, v interface{}) error {
+	switch format {
+	case formatJSON:
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	case formatYAML:
+		generic, err := toGeneric(v)
+		if err != nil {
+			return err
+		}
+		b, err := yaml.Marshal(generic)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	}
+
+	generic, err := toGeneric(v)
+	if err != nil {
+		return err
+	}
+	if obj, ok := generic.(map[string]interface{}); ok {
+		if alerts, ok := obj["alerts"]; ok {
+			printAlerts(w, alerts)
+			resp, ok := obj["response"]
+			if !ok || resp == nil {
+				return nil
+			}
+			generic = resp
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	switch val := generic.(type) {
+	case []interface{}:
+		printTable(tw, columns, val)
+	case map[string]interface{}:
+		keys := sortedKeys(val)
+		for _, key := range keys {
+			fmt.Fprintf(tw, "%v\t%v\n", key, formatValue(val[key]))
+		}
+	default:
+		fmt.Fprintln(tw, formatValue(val))
+	}
+	return tw.Flush()
+}
+
+// printTable writes a header row of the given columns, and a row per item. If columns is empty, the fields of the first item are used.
+func printTable(w io.Writer, columns []string, items []interface{}) {
+	if len(columns) == 0 && len(items) > 0 {
+		if obj, ok := items[0].(map[string]interface{}); ok {
+			columns = sortedKeys(obj)
+		}
+	}
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = strings.ToUpper(col)
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			fmt.Fprintln(w, formatValue(item))
+			continue
+		}
+		row := make([]string, len(columns))
+		for i, col := range columns {
+			row[i] = formatValue(obj[col])
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+}
+
+func printAlerts(w io.Writer, alerts interface{}) {
+	list, _ := alerts.([]interface{})
+	for _, alert := range list {
+		obj, ok := alert.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(w, "%v: %v\n", obj["level"], obj["text"])
+	}
+}
+
+// formatValue formats a generic JSON value for a table cell.
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case string:
+		return val
+	case []interface{}:
+		strs := make([]string, len(val))
+		for i, elem := range val {
+			strs[i] = formatValue(elem)
+		}
+		return strings.Join(strs, ",")
+	case map[string]interface{}:
+		b, _ := json.Marshal(val)
+		return string(b)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// toGeneric converts v to generic JSON values, of maps, slices, strings, float64s, bools and nil, by way of its JSON encoding, so output uses the JSON field names.
+func toGeneric(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// readInput reads the given file, or stdin if the path is "-", and returns it as JSON. The file may be JSON or YAML.
+func readInput(path string) ([]byte, error) {
+	var b []byte
+	var err error
+	if path == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return yamlToJSON(b)
+}
+
+// yamlToJSON converts YAML to JSON. Because JSON is YAML, JSON is returned unchanged, except for formatting.
+func yamlToJSON(b []byte) ([]byte, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(b, &v); err != nil {
+		return nil, fmt.Errorf("parsing input: %v", err)
+	}
+	v, err := jsonValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("parsing input: %v", err)
+	}
+	return json.Marshal(v)
+}
+
+// jsonValue converts a value decoded by yaml, whose maps are map[interface{}]interface{}, into one encoding/json can encode.
+func jsonValue(v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case map[interface{}]interface{}:
+		obj := make(map[string]interface{}, len(val))
+		for key, elem := range val {
+			str, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("non-string key '%v'", key)
+			}
+			converted, err := jsonValue(elem)
+			if err != nil {
+				return nil, err
+			}
+			obj[str] = converted
+		}
+		return obj, nil
+	case []interface{}:
+		list := make([]interface{}, len(val))
+		for i, elem := range val {
+			converted, err := jsonValue(elem)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = converted
+		}
+		return list, nil
+	default:
+		return val, nil
+	}
+}
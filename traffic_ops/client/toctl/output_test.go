@@ -0,0 +1,118 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+	"github.com/jheitz200/test_helper"
+)
+
+func TestPrintTable(t *testing.T) {
+	testHelper.Context(t, "Given the need to print a list of resources as a table")
+
+	cdns := []client.CDN{{ID: 1, Name: "cdn1", DomainName: "one.example.net"}, {ID: 20, Name: "cdn2"}}
+	out := bytes.Buffer{}
+	if err := printOutput(&out, formatTable, []string{"id", "name", "domainName"}, cdns); err != nil {
+		testHelper.Fatal(t, "Should be able to print, got: %v", err)
+	}
+	expected := "ID  NAME  DOMAINNAME\n1   cdn1  one.example.net\n20  cdn2  \n"
+	if out.String() != expected {
+		testHelper.Error(t, "Should print %q, got: %q", expected, out.String())
+	} else {
+		testHelper.Success(t, "Should print a row per resource")
+	}
+}
+
+func TestPrintResponse(t *testing.T) {
+	testHelper.Context(t, "Given the need to print the response of a change as a table")
+
+	resp := client.CreateServerResponse{
+		Response: []client.Server{{ID: 3, HostName: "edge"}},
+		Alerts:   []client.Alert{{Level: "success", Text: "Server created"}},
+	}
+	out := bytes.Buffer{}
+	if err := printOutput(&out, formatTable, []string{"id", "hostName"}, resp); err != nil {
+		testHelper.Fatal(t, "Should be able to print, got: %v", err)
+	}
+	expected := "success: Server created\nID  HOSTNAME\n3   edge\n"
+	if out.String() != expected {
+		testHelper.Error(t, "Should print %q, got: %q", expected, out.String())
+	} else {
+		testHelper.Success(t, "Should print the alerts, then the response")
+	}
+}
+
+func TestPrintYAML(t *testing.T) {
+	testHelper.Context(t, "Given the need to print resources as YAML")
+
+	out := bytes.Buffer{}
+	if err := printOutput(&out, formatYAML, nil, client.ProfileParameter{ProfileID: 1, ParameterID: 2}); err != nil {
+		testHelper.Fatal(t, "Should be able to print, got: %v", err)
+	}
+	expected := "parameterId: 2\nprofileId: 1\n"
+	if out.String() != expected {
+		testHelper.Error(t, "Should print %q, got: %q", expected, out.String())
+	} else {
+		testHelper.Success(t, "Should print YAML with the JSON field names")
+	}
+}
+
+func TestYAMLToJSON(t *testing.T) {
+	testHelper.Context(t, "Given the need to read YAML and JSON input")
+
+	inputs := []string{
+		"deliveryServices:\n- xmlId: ds-one\n  active: true\n  dscp: 10\n",
+		`{"deliveryServices": [{"xmlId": "ds-one", "active": true, "dscp": 10}]}`,
+	}
+	for _, input := range inputs {
+		b, err := yamlToJSON([]byte(input))
+		if err != nil {
+			testHelper.Fatal(t, "Should be able to convert %q, got: %v", input, err)
+		}
+		file := deliveryServicesFile{}
+		if err := json.Unmarshal(b, &file); err != nil {
+			testHelper.Fatal(t, "Should be able to decode %s, got: %v", b, err)
+		}
+		if len(file.DeliveryServices) != 1 || file.DeliveryServices[0]["xmlId"] != "ds-one" || file.DeliveryServices[0]["active"] != true || file.DeliveryServices[0]["dscp"] != float64(10) {
+			testHelper.Error(t, "Should convert %q, got: %s", input, b)
+		} else {
+			testHelper.Success(t, "Should convert %q to JSON", input)
+		}
+	}
+}
+
+func TestFindItem(t *testing.T) {
+	testHelper.Context(t, "Given the need to show a resource by ID or name")
+
+	cdns := []client.CDN{{ID: 1, Name: "cdn1"}, {ID: 2, Name: "cdn2"}}
+	for _, id := range []string{"2", "cdn2"} {
+		item, err := findItem(cdns, []string{"id", "name"}, id)
+		if err != nil {
+			testHelper.Error(t, "Should find %v, got: %v", id, err)
+		} else if item.(map[string]interface{})["name"] != "cdn2" {
+			testHelper.Error(t, "Should find cdn2 by %v, got: %v", id, item)
+		} else {
+			testHelper.Success(t, "Should find cdn2 by %v", id)
+		}
+	}
+	if _, err := findItem(cdns, []string{"id", "name"}, "cdn3"); err == nil {
+		testHelper.Error(t, "Should get an error for a missing resource")
+	}
+}
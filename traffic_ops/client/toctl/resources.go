@@ -0,0 +1,375 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+)
+
+// resource is a kind of Traffic Ops object toctl can manage. The funcs of operations Traffic Ops doesn't support for the resource are nil. Columns are the fields shown in table output, and keys are the fields `show` matches its argument against. DryRun is whether the client's dry run mode covers the resource's create, update and del.
+type resource struct {
+	name    string
+	aliases []string
+	columns []string
+	keys    []string
+	dryRun  bool
+	list    func(ctx context.Context, to *client.Session, filters url.Values) (interface{}, error)
+	create  func(ctx context.Context, to *client.Session, body []byte) (interface{}, error)
+	update  func(ctx context.Context, to *client.Session, id string, body []byte) (interface{}, error)
+	del     func(ctx context.Context, to *client.Session, id string) (interface{}, error)
+}
+
+var resources = []resource{
+	{
+		name:    "servers",
+		aliases: []string{"server"},
+		columns: []string{"id", "hostName", "domainName", "type", "status", "cachegroup", "profile", "cdnName", "ipAddress"},
+		keys:    []string{"id", "hostName"},
+		list: func(ctx context.Context, to *client.Session, filters url.Values) (interface{}, error) {
+			return to.ListServers(ctx, client.ListOptions{Filters: filters})
+		},
+		create: func(ctx context.Context, to *client.Session, body []byte) (interface{}, error) {
+			s := client.Server{}
+			if err := decodeStrict(body, &s); err != nil {
+				return nil, err
+			}
+			return to.CreateServer(ctx, &s)
+		},
+		update: func(ctx context.Context, to *client.Session, id string, body []byte) (interface{}, error) {
+			i, err := parseID(id)
+			if err != nil {
+				return nil, err
+			}
+			s := client.Server{}
+			if err := decodeStrict(body, &s); err != nil {
+				return nil, err
+			}
+			return to.UpdateServer(ctx, i, &s)
+		},
+		del: func(ctx context.Context, to *client.Session, id string) (interface{}, error) {
+			i, err := parseID(id)
+			if err != nil {
+				return nil, err
+			}
+			return to.DeleteServer(ctx, i)
+		},
+	},
+	{
+		name:    "deliveryservices",
+		aliases: []string{"deliveryservice", "ds"},
+		columns: []string{"id", "xmlId", "displayName", "type", "cdnName", "active", "orgServerFqdn", "profileName"},
+		keys:    []string{"id", "xmlId"},
+		dryRun:  true,
+		list: func(ctx context.Context, to *client.Session, filters url.Values) (interface{}, error) {
+			return to.ListDeliveryServices(ctx, client.ListOptions{Filters: filters})
+		},
+		create: func(ctx context.Context, to *client.Session, body []byte) (interface{}, error) {
+			ds := client.DeliveryService{}
+			if err := decodeStrict(body, &ds); err != nil {
+				return nil, err
+			}
+			return to.CreateDeliveryService(ctx, &ds)
+		},
+		update: func(ctx context.Context, to *client.Session, id string, body []byte) (interface{}, error) {
+			if _, err := parseID(id); err != nil {
+				return nil, err
+			}
+			ds := client.DeliveryService{}
+			if err := decodeStrict(body, &ds); err != nil {
+				return nil, err
+			}
+			return to.UpdateDeliveryService(ctx, id, &ds)
+		},
+		del: func(ctx context.Context, to *client.Session, id string) (interface{}, error) {
+			if _, err := parseID(id); err != nil {
+				return nil, err
+			}
+			return to.DeleteDeliveryService(ctx, id)
+		},
+	},
+	{
+		name:    "cachegroups",
+		aliases: []string{"cachegroup", "cg"},
+		columns: []string{"id", "name", "shortName", "typeName", "parentCachegroupName", "latitude", "longitude"},
+		keys:    []string{"id", "name", "shortName"},
+		list: func(ctx context.Context, to *client.Session, filters url.Values) (interface{}, error) {
+			return to.ListCacheGroups(ctx, client.ListOptions{Filters: filters})
+		},
+		create: func(ctx context.Context, to *client.Session, body []byte) (interface{}, error) {
+			cg := client.CacheGroup{}
+			if err := decodeStrict(body, &cg); err != nil {
+				return nil, err
+			}
+			return to.CreateCacheGroup(ctx, &cg)
+		},
+		update: func(ctx context.Context, to *client.Session, id string, body []byte) (interface{}, error) {
+			i, err := parseID(id)
+			if err != nil {
+				return nil, err
+			}
+			cg := client.CacheGroup{}
+			if err := decodeStrict(body, &cg); err != nil {
+				return nil, err
+			}
+			return to.UpdateCacheGroup(ctx, i, &cg)
+		},
+		del: func(ctx context.Context, to *client.Session, id string) (interface{}, error) {
+			i, err := parseID(id)
+			if err != nil {
+				return nil, err
+			}
+			return to.DeleteCacheGroup(ctx, i)
+		},
+	},
+	{
+		name:    "profiles",
+		aliases: []string{"profile"},
+		columns: []string{"id", "name", "description", "type", "cdn"},
+		keys:    []string{"id", "name"},
+		list: func(ctx context.Context, to *client.Session, filters url.Values) (interface{}, error) {
+			return to.ListProfiles(ctx, client.ListOptions{Filters: filters})
+		},
+		create: func(ctx context.Context, to *client.Session, body []byte) (interface{}, error) {
+			p := client.Profile{}
+			if err := decodeStrict(body, &p); err != nil {
+				return nil, err
+			}
+			return to.CreateProfile(ctx, &p)
+		},
+		update: func(ctx context.Context, to *client.Session, id string, body []byte) (interface{}, error) {
+			i, err := parseID(id)
+			if err != nil {
+				return nil, err
+			}
+			p := client.Profile{}
+			if err := decodeStrict(body, &p); err != nil {
+				return nil, err
+			}
+			return to.UpdateProfile(ctx, i, &p)
+		},
+		del: func(ctx context.Context, to *client.Session, id string) (interface{}, error) {
+			i, err := parseID(id)
+			if err != nil {
+				return nil, err
+			}
+			return to.DeleteProfile(ctx, i)
+		},
+	},
+	{
+		name:    "parameters",
+		aliases: []string{"parameter", "params", "param"},
+		columns: []string{"id", "name", "configFile", "value", "secure"},
+		keys:    []string{"id", "name"},
+		list: func(ctx context.Context, to *client.Session, filters url.Values) (interface{}, error) {
+			// Traffic Ops only lists parameters by profile.
+			profile := filters.Get("profile")
+			if profile == "" || len(filters) != 1 {
+				return nil, fmt.Errorf("parameters must be listed by profile, with the filter profile=NAME and no others")
+			}
+			return to.Parameters(ctx, profile)
+		},
+		create: func(ctx context.Context, to *client.Session, body []byte) (interface{}, error) {
+			params := []client.Parameter{}
+			if strings.HasPrefix(strings.TrimSpace(string(body)), "[") {
+				if err := decodeStrict(body, &params); err != nil {
+					return nil, err
+				}
+			} else {
+				p := client.Parameter{}
+				if err := decodeStrict(body, &p); err != nil {
+					return nil, err
+				}
+				params = append(params, p)
+			}
+			return to.CreateParameters(ctx, params)
+		},
+		update: func(ctx context.Context, to *client.Session, id string, body []byte) (interface{}, error) {
+			i, err := parseID(id)
+			if err != nil {
+				return nil, err
+			}
+			p := client.Parameter{}
+			if err := decodeStrict(body, &p); err != nil {
+				return nil, err
+			}
+			return to.UpdateParameter(ctx, i, &p)
+		},
+		del: func(ctx context.Context, to *client.Session, id string) (interface{}, error) {
+			i, err := parseID(id)
+			if err != nil {
+				return nil, err
+			}
+			return to.DeleteParameter(ctx, i)
+		},
+	},
+	{
+		name:    "cdns",
+		aliases: []string{"cdn"},
+		columns: []string{"id", "name", "domainName", "dnssecEnabled"},
+		keys:    []string{"id", "name"},
+		list: func(ctx context.Context, to *client.Session, filters url.Values) (interface{}, error) {
+			return to.ListCDNs(ctx, client.ListOptions{Filters: filters})
+		},
+		create: func(ctx context.Context, to *client.Session, body []byte) (interface{}, error) {
+			cdn := client.CDN{}
+			if err := decodeStrict(body, &cdn); err != nil {
+				return nil, err
+			}
+			return to.CreateCDN(ctx, &cdn)
+		},
+		update: func(ctx context.Context, to *client.Session, id string, body []byte) (interface{}, error) {
+			i, err := parseID(id)
+			if err != nil {
+				return nil, err
+			}
+			cdn := client.CDN{}
+			if err := decodeStrict(body, &cdn); err != nil {
+				return nil, err
+			}
+			return to.UpdateCDN(ctx, i, &cdn)
+		},
+		del: func(ctx context.Context, to *client.Session, id string) (interface{}, error) {
+			i, err := parseID(id)
+			if err != nil {
+				return nil, err
+			}
+			return to.DeleteCDN(ctx, i)
+		},
+	},
+	{
+		// profileparameters are identified by "profileId/parameterId", e.g. `toctl delete profileparameters 12/345`.
+		name:    "profileparameters",
+		aliases: []string{"profileparameter"},
+		columns: []string{"profileId", "parameterId"},
+		create: func(ctx context.Context, to *client.Session, body []byte) (interface{}, error) {
+			pps := []client.ProfileParameter{}
+			if strings.HasPrefix(strings.TrimSpace(string(body)), "[") {
+				if err := decodeStrict(body, &pps); err != nil {
+					return nil, err
+				}
+			} else {
+				pp := client.ProfileParameter{}
+				if err := decodeStrict(body, &pp); err != nil {
+					return nil, err
+				}
+				pps = append(pps, pp)
+			}
+			return to.CreateProfileParameters(ctx, pps)
+		},
+		del: func(ctx context.Context, to *client.Session, id string) (interface{}, error) {
+			ids := strings.Split(id, "/")
+			if len(ids) != 2 {
+				return nil, fmt.Errorf("invalid profile parameter '%v', must be profileId/parameterId", id)
+			}
+			profileID, err := parseID(ids[0])
+			if err != nil {
+				return nil, err
+			}
+			parameterID, err := parseID(ids[1])
+			if err != nil {
+				return nil, err
+			}
+			return to.DeleteProfileParameter(ctx, profileID, parameterID)
+		},
+	},
+	{
+		name:    "deliveryserviceservers",
+		aliases: []string{"deliveryserviceserver", "dss"},
+		columns: []string{"deliveryService", "server", "lastUpdated"},
+		list: func(ctx context.Context, to *client.Session, filters url.Values) (interface{}, error) {
+			dsses := []client.DeliveryServiceServer{}
+			it := to.IterateDeliveryServiceServers(ctx, client.ListOptions{Filters: filters})
+			for it.Next() {
+				dsses = append(dsses, it.DeliveryServiceServer())
+			}
+			return dsses, it.Err()
+		},
+	},
+}
+
+// getResource returns the resource with the given name or alias.
+func getResource(name string) (resource, error) {
+	name = strings.ToLower(name)
+	for _, r := range resources {
+		if r.name == name {
+			return r, nil
+		}
+		for _, alias := range r.aliases {
+			if alias == name {
+				return r, nil
+			}
+		}
+	}
+	return resource{}, fmt.Errorf("unknown resource '%v', must be one of %v", name, strings.Join(resourceNames(), ", "))
+}
+
+// resourceNames returns the sorted names of all resources.
+func resourceNames() []string {
+	names := []string{}
+	for _, r := range resources {
+		names = append(names, r.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseID parses a numeric Traffic Ops ID.
+func parseID(id string) (int, error) {
+	i, err := strconv.Atoi(id)
+	if err != nil || i <= 0 {
+		return 0, fmt.Errorf("invalid id '%v', must be a positive integer", id)
+	}
+	return i, nil
+}
+
+// decodeStrict decodes the JSON body into v, returning an error for fields v doesn't have, so typos in input files aren't silently ignored.
+func decodeStrict(body []byte, v interface{}) error {
+	dec := json.NewDecoder(strings.NewReader(string(body)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("decoding input: %v", err)
+	}
+	return nil
+}
+
+// findItem returns the item in the given list, whose value of one of the given keys is id.
+func findItem(items interface{}, keys []string, id string) (interface{}, error) {
+	generic, err := toGeneric(items)
+	if err != nil {
+		return nil, err
+	}
+	list, _ := generic.([]interface{})
+	for _, item := range list {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range keys {
+			if val, ok := obj[key]; ok && val != nil && fmt.Sprint(val) == id {
+				return obj, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("'%v' not found", id)
+}
@@ -0,0 +1,737 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package totest provides an in-memory Traffic Ops HTTP server, for testing Traffic Ops clients without a real Traffic Ops.
+//
+// The server implements cookie login, bearer token login and API keys, CRConfig snapshots, monitoring configs, servers, delivery services and stats summaries, with the same paths and JSON as the Traffic Ops 1.2 API. Tests set its data with its mutation methods, e.g.
+//
+//	tos := totest.NewServer()
+//	defer tos.Close()
+//	tos.AddUser("admin", "password")
+//	tos.SetMonitoringConfig("cdn1", fixtures.TrafficMonitorConfig().Response)
+//	session, err := client.LoginWithAgent(tos.URL, "admin", "password", true, "test", false, client.DefaultTimeout)
+//
+// The server is safe to mutate while it's serving requests.
+package totest
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+)
+
+// CookieName is the name of the session cookie the server sets on login, which is the name Traffic Ops uses.
+const CookieName = "mojolicious"
+
+const apiBase = "/api/1.2"
+
+// DefaultTokenTTL is how long tokens from the server's token login are valid, by default.
+const DefaultTokenTTL = time.Hour
+
+// Server is an in-memory Traffic Ops. Its embedded httptest.Server has the URL to give clients, and must be closed.
+type Server struct {
+	*httptest.Server
+
+	m                 sync.RWMutex
+	users             map[string]string
+	sessions          map[string]struct{}
+	tokens            map[string]time.Time
+	apiKeys           map[string]struct{}
+	tokenTTL          time.Duration
+	logins            int
+	crConfigs         map[string][]byte
+	monitoringConfigs map[string]client.TrafficMonitorConfig
+	servers           []client.Server
+	deliveryServices  []client.DeliveryService
+	statsSummaries    []client.StatsSummary
+	nextID            int
+	unavailable       bool
+	requests          int
+}
+
+// NewServer starts and returns a new server, with no users or data.
+func NewServer() *Server {
+	s := newServer()
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// NewTLSServer starts and returns a new server using TLS, with no users or data. Clients must be insecure, because the server's certificate is self-signed.
+func NewTLSServer() *Server {
+	s := newServer()
+	s.Server = httptest.NewTLSServer(s)
+	return s
+}
+
+func newServer() *Server {
+	return &Server{
+		users:             map[string]string{},
+		sessions:          map[string]struct{}{},
+		tokens:            map[string]time.Time{},
+		apiKeys:           map[string]struct{}{},
+		tokenTTL:          DefaultTokenTTL,
+		crConfigs:         map[string][]byte{},
+		monitoringConfigs: map[string]client.TrafficMonitorConfig{},
+		nextID:            1,
+	}
+}
+
+// AddUser adds a user who can log in with the given password, or changes the password of an existing user.
+func (s *Server) AddUser(user, password string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.users[user] = password
+}
+
+// AddAPIKey adds an API key which clients can authenticate with, as a bearer token.
+func (s *Server) AddAPIKey(key string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.apiKeys[key] = struct{}{}
+}
+
+// SetTokenTTL sets how long tokens from later token logins are valid.
+func (s *Server) SetTokenTTL(ttl time.Duration) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.tokenTTL = ttl
+}
+
+// ExpireSessions logs out all clients, so their next request is Unauthorized, as when their Traffic Ops session cookie or token expires. API keys are still valid.
+func (s *Server) ExpireSessions() {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.sessions = map[string]struct{}{}
+	s.tokens = map[string]time.Time{}
+}
+
+// Logins returns the number of successful cookie and token logins.
+func (s *Server) Logins() int {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	return s.logins
+}
+
+// SetUnavailable sets whether the server responds to all requests with Service Unavailable, as Traffic Ops does when it's down behind a proxy.
+func (s *Server) SetUnavailable(unavailable bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.unavailable = unavailable
+}
+
+// Requests returns the number of requests the server has received.
+func (s *Server) Requests() int {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	return s.requests
+}
+
+// SetCRConfig sets the CRConfig snapshot of the given CDN, which is served as-is.
+func (s *Server) SetCRConfig(cdn string, crConfig []byte) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.crConfigs[cdn] = append([]byte(nil), crConfig...)
+}
+
+// SetMonitoringConfig sets the monitoring config of the given CDN.
+func (s *Server) SetMonitoringConfig(cdn string, cfg client.TrafficMonitorConfig) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.monitoringConfigs[cdn] = cfg
+}
+
+// AddServer adds the given server, and returns it with its assigned ID. The server is stored as given: unlike Traffic Ops, its cachegroup, CDN, profile, status and type names aren't looked up from their IDs.
+func (s *Server) AddServer(server client.Server) client.Server {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.addServer(server)
+}
+
+func (s *Server) addServer(server client.Server) client.Server {
+	server.ID = s.newID()
+	server.LastUpdated = lastUpdated()
+	s.servers = append(s.servers, server)
+	return server
+}
+
+// SetServerStatus sets the status of the server with the given host name, in its server and in the monitoring configs of all CDNs, as Traffic Ops does when an operator changes it. Returns false if no server or monitoring config has the host name.
+func (s *Server) SetServerStatus(hostName, status string) bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+	found := false
+	for i := range s.servers {
+		if s.servers[i].HostName == hostName {
+			s.servers[i].Status = status
+			s.servers[i].LastUpdated = lastUpdated()
+			found = true
+		}
+	}
+	for cdn, cfg := range s.monitoringConfigs {
+		trafficServers := make([]client.TrafficServer, len(cfg.TrafficServers))
+		copy(trafficServers, cfg.TrafficServers)
+		for i := range trafficServers {
+			if trafficServers[i].HostName == hostName {
+				trafficServers[i].Status = status
+				found = true
+			}
+		}
+		cfg.TrafficServers = trafficServers
+		s.monitoringConfigs[cdn] = cfg
+	}
+	return found
+}
+
+// RemoveServer removes the server with the given host name. Returns false if there's no such server.
+func (s *Server) RemoveServer(hostName string) bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+	for i, server := range s.servers {
+		if server.HostName == hostName {
+			s.servers = append(s.servers[:i:i], s.servers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Servers returns the servers.
+func (s *Server) Servers() []client.Server {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	return append([]client.Server(nil), s.servers...)
+}
+
+// AddDeliveryService adds the given delivery service, and returns it with its assigned ID.
+func (s *Server) AddDeliveryService(ds client.DeliveryService) client.DeliveryService {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.addDeliveryService(ds)
+}
+
+func (s *Server) addDeliveryService(ds client.DeliveryService) client.DeliveryService {
+	ds.ID = s.newID()
+	ds.LastUpdated = lastUpdated()
+	s.deliveryServices = append(s.deliveryServices, ds)
+	return ds
+}
+
+// RemoveDeliveryService removes the delivery service with the given xmlId. Returns false if there's no such delivery service.
+func (s *Server) RemoveDeliveryService(xmlID string) bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+	for i, ds := range s.deliveryServices {
+		if ds.XMLID == xmlID {
+			s.deliveryServices = append(s.deliveryServices[:i:i], s.deliveryServices[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryServices returns the delivery services.
+func (s *Server) DeliveryServices() []client.DeliveryService {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	return append([]client.DeliveryService(nil), s.deliveryServices...)
+}
+
+// AddStatsSummary adds the given stats summary, as Traffic Stats does daily.
+func (s *Server) AddStatsSummary(summary client.StatsSummary) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.statsSummaries = append(s.statsSummaries, summary)
+}
+
+// StatsSummaries returns the stats summaries, including those created by clients.
+func (s *Server) StatsSummaries() []client.StatsSummary {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	return append([]client.StatsSummary(nil), s.statsSummaries...)
+}
+
+func (s *Server) newID() int {
+	id := s.nextID
+	s.nextID++
+	return id
+}
+
+// lastUpdated returns the current time in the format of Traffic Ops lastUpdated fields.
+func lastUpdated() string {
+	return time.Now().Format("2006-01-02 15:04:05-07")
+}
+
+// ServeHTTP serves the Traffic Ops API.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.m.Lock()
+	s.requests++
+	unavailable := s.unavailable
+	s.m.Unlock()
+	if unavailable {
+		writeAlerts(w, http.StatusServiceUnavailable, "error", "Service Unavailable")
+		return
+	}
+
+	path := r.URL.Path
+	if path == apiBase+"/user/login" {
+		s.login(w, r)
+		return
+	}
+	if path == client.DefaultTokenLoginPath {
+		s.loginToken(w, r)
+		return
+	}
+	if !s.loggedIn(r) {
+		writeAlerts(w, http.StatusUnauthorized, "error", "Unauthorized, please log in.")
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(path, "/CRConfig-Snapshots/") && strings.HasSuffix(path, "/CRConfig.json"):
+		s.getCRConfig(w, r, strings.TrimSuffix(strings.TrimPrefix(path, "/CRConfig-Snapshots/"), "/CRConfig.json"))
+	case strings.HasPrefix(path, apiBase+"/cdns/") && strings.HasSuffix(path, "/configs/monitoring.json"):
+		s.getMonitoringConfig(w, r, strings.TrimSuffix(strings.TrimPrefix(path, apiBase+"/cdns/"), "/configs/monitoring.json"))
+	case path == apiBase+"/servers.json" || path == apiBase+"/servers":
+		s.serveServers(w, r)
+	case strings.HasPrefix(path, apiBase+"/servers/"):
+		s.serveServer(w, r, strings.TrimPrefix(path, apiBase+"/servers/"))
+	case path == apiBase+"/deliveryservices.json" || path == apiBase+"/deliveryservices":
+		s.serveDeliveryServices(w, r)
+	case strings.HasPrefix(path, apiBase+"/deliveryservices/"):
+		s.serveDeliveryService(w, r, strings.TrimSuffix(strings.TrimPrefix(path, apiBase+"/deliveryservices/"), ".json"))
+	case path == apiBase+"/stats_summary.json":
+		s.getStatsSummary(w, r)
+	case path == apiBase+"/stats_summary/create":
+		s.createStatsSummary(w, r)
+	default:
+		writeAlerts(w, http.StatusNotFound, "error", "Resource not found.")
+	}
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.checkCredentials(w, r); !ok {
+		return
+	}
+	token := newToken()
+	s.m.Lock()
+	s.sessions[token] = struct{}{}
+	s.logins++
+	s.m.Unlock()
+
+	http.SetCookie(w, &http.Cookie{Name: CookieName, Value: token, Path: "/", HttpOnly: true})
+	writeAlerts(w, http.StatusOK, "success", "Successfully logged in.")
+}
+
+// loginToken serves the Traffic Ops 2.0 login, which returns a JWT to send as a bearer token. The JWT isn't signed: clients only read its expiration.
+func (s *Server) loginToken(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.checkCredentials(w, r)
+	if !ok {
+		return
+	}
+	s.m.Lock()
+	expires := time.Now().Add(s.tokenTTL)
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	claims, _ := json.Marshal(map[string]interface{}{"userid": user, "exp": expires.Unix()})
+	token := header + "." + base64.RawURLEncoding.EncodeToString(claims) + "." + newToken()
+	s.tokens[token] = expires
+	s.logins++
+	s.m.Unlock()
+
+	writeJSON(w, http.StatusOK, struct{ Token string }{token})
+}
+
+// checkCredentials decodes the login request, and returns its user if the password is correct. Otherwise, it writes an error and returns false.
+func (s *Server) checkCredentials(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w)
+		return "", false
+	}
+	creds := client.Credentials{}
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		writeAlerts(w, http.StatusBadRequest, "error", "Invalid request: "+err.Error())
+		return "", false
+	}
+
+	s.m.RLock()
+	password, ok := s.users[creds.Username]
+	s.m.RUnlock()
+	if !ok || password != creds.Password {
+		writeAlerts(w, http.StatusUnauthorized, "error", "Invalid username or password.")
+		return "", false
+	}
+	return creds.Username, true
+}
+
+// loggedIn returns whether the request has a session cookie, an unexpired bearer token or an API key.
+func (s *Server) loggedIn(r *http.Request) bool {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		bearer := strings.TrimPrefix(auth, "Bearer ")
+		if _, ok := s.apiKeys[bearer]; ok {
+			return true
+		}
+		if expires, ok := s.tokens[bearer]; ok && time.Now().Before(expires) {
+			return true
+		}
+	}
+	cookie, err := r.Cookie(CookieName)
+	if err != nil {
+		return false
+	}
+	_, ok := s.sessions[cookie.Value]
+	return ok
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic("reading random bytes: " + err.Error())
+	}
+	return hex.EncodeToString(b)
+}
+
+func (s *Server) getCRConfig(w http.ResponseWriter, r *http.Request, cdn string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	s.m.RLock()
+	crConfig, ok := s.crConfigs[cdn]
+	s.m.RUnlock()
+	if !ok {
+		writeAlerts(w, http.StatusNotFound, "error", "Resource not found.")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(crConfig)
+}
+
+func (s *Server) getMonitoringConfig(w http.ResponseWriter, r *http.Request, cdn string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	s.m.RLock()
+	cfg, ok := s.monitoringConfigs[cdn]
+	s.m.RUnlock()
+	if !ok {
+		writeAlerts(w, http.StatusNotFound, "error", fmt.Sprintf("CDN '%s' not found.", cdn))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"response": monitoringConfigJSON(cfg)})
+}
+
+// monitoringConfigJSON returns the monitoring config as Traffic Ops serves it, whose profile parameters are flat strings, e.g. `"health.threshold.loadavg": "<25"`, rather than the parsed client.TMParameters.
+func monitoringConfigJSON(cfg client.TrafficMonitorConfig) interface{} {
+	profiles := make([]map[string]interface{}, len(cfg.Profiles))
+	for i, profile := range cfg.Profiles {
+		params := map[string]interface{}{
+			"health.connection.timeout": profile.Parameters.HealthConnectionTimeout,
+			"health.polling.url":        profile.Parameters.HealthPollingURL,
+			"history.count":             profile.Parameters.HistoryCount,
+		}
+		if len(profile.Parameters.Interfaces) > 0 {
+			params["health.polling.interfaces"] = strings.Join(profile.Parameters.Interfaces, ",")
+		}
+		for stat, threshold := range profile.Parameters.Thresholds {
+			params["health.threshold."+stat] = threshold.Comparator + strconv.FormatFloat(threshold.Val, 'f', -1, 64)
+		}
+		profiles[i] = map[string]interface{}{"name": profile.Name, "type": profile.Type, "parameters": params}
+	}
+	return struct {
+		client.TrafficMonitorConfig
+		Profiles []map[string]interface{} `json:"profiles,omitempty"`
+	}{cfg, profiles}
+}
+
+// serveServers serves the servers collection.
+func (s *Server) serveServers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.m.RLock()
+		servers := filterServers(s.servers, r.URL.Query())
+		s.m.RUnlock()
+		writeJSON(w, http.StatusOK, client.ServerResponse{Response: servers})
+	case http.MethodPost:
+		server := client.Server{}
+		if !decodeBody(w, r, &server) {
+			return
+		}
+		if server.HostName == "" {
+			writeAlerts(w, http.StatusBadRequest, "error", "hostName is required")
+			return
+		}
+		s.m.Lock()
+		server = s.addServer(server)
+		s.m.Unlock()
+		writeJSON(w, http.StatusOK, client.CreateServerResponse{
+			Response: []client.Server{server},
+			Alerts:   successAlerts("Server created"),
+		})
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// filterServers returns the servers matching the query parameters Traffic Ops filters servers by.
+func filterServers(servers []client.Server, query url.Values) []client.Server {
+	filtered := []client.Server{}
+	for _, server := range servers {
+		if v := query.Get("type"); v != "" && v != server.Type {
+			continue
+		}
+		if v := query.Get("status"); v != "" && v != server.Status {
+			continue
+		}
+		if v := query.Get("cdn"); v != "" && v != strconv.Itoa(server.CDNID) {
+			continue
+		}
+		if v := query.Get("cachegroup"); v != "" && v != strconv.Itoa(server.CachegroupID) {
+			continue
+		}
+		if v := query.Get("profileId"); v != "" && v != strconv.Itoa(server.ProfileID) {
+			continue
+		}
+		filtered = append(filtered, server)
+	}
+	return filtered
+}
+
+// serveServer serves a single server, by ID.
+func (s *Server) serveServer(w http.ResponseWriter, r *http.Request, idStr string) {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		writeAlerts(w, http.StatusNotFound, "error", "Resource not found.")
+		return
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+	i := -1
+	for j, server := range s.servers {
+		if server.ID == id {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		writeAlerts(w, http.StatusNotFound, "error", fmt.Sprintf("Server with id: %d not found", id))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		server := client.Server{}
+		if !decodeBody(w, r, &server) {
+			return
+		}
+		server.ID = id
+		server.LastUpdated = lastUpdated()
+		s.servers[i] = server
+		writeJSON(w, http.StatusOK, client.UpdateServerResponse{
+			Response: []client.Server{server},
+			Alerts:   successAlerts("Server update was successful."),
+		})
+	case http.MethodDelete:
+		s.servers = append(s.servers[:i:i], s.servers[i+1:]...)
+		writeJSON(w, http.StatusOK, client.DeleteServerResponse{Alerts: successAlerts("Server was deleted.")})
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// serveDeliveryServices serves the delivery services collection.
+func (s *Server) serveDeliveryServices(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.m.RLock()
+		dses := append([]client.DeliveryService{}, s.deliveryServices...)
+		s.m.RUnlock()
+		writeJSON(w, http.StatusOK, client.GetDeliveryServiceResponse{Response: dses})
+	case http.MethodPost:
+		ds := client.DeliveryService{}
+		if !decodeBody(w, r, &ds) {
+			return
+		}
+		if ds.XMLID == "" {
+			writeAlerts(w, http.StatusBadRequest, "error", "xmlId is required")
+			return
+		}
+		s.m.Lock()
+		for _, existing := range s.deliveryServices {
+			if existing.XMLID == ds.XMLID {
+				s.m.Unlock()
+				writeAlerts(w, http.StatusBadRequest, "error", fmt.Sprintf("A deliveryservice with xmlId %s already exists.", ds.XMLID))
+				return
+			}
+		}
+		ds = s.addDeliveryService(ds)
+		s.m.Unlock()
+		writeJSON(w, http.StatusOK, client.CreateDeliveryServiceResponse{
+			Response: []client.DeliveryService{ds},
+			Alerts:   dsSuccessAlerts("Delivery service was created."),
+		})
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// serveDeliveryService serves a single delivery service, by ID.
+func (s *Server) serveDeliveryService(w http.ResponseWriter, r *http.Request, idStr string) {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		writeAlerts(w, http.StatusNotFound, "error", "Resource not found.")
+		return
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+	i := -1
+	for j, ds := range s.deliveryServices {
+		if ds.ID == id {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		writeAlerts(w, http.StatusNotFound, "error", fmt.Sprintf("Delivery service with id: %d not found", id))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, client.GetDeliveryServiceResponse{Response: []client.DeliveryService{s.deliveryServices[i]}})
+	case http.MethodPut:
+		ds := client.DeliveryService{}
+		if !decodeBody(w, r, &ds) {
+			return
+		}
+		ds.ID = id
+		ds.LastUpdated = lastUpdated()
+		s.deliveryServices[i] = ds
+		writeJSON(w, http.StatusOK, client.UpdateDeliveryServiceResponse{
+			Response: []client.DeliveryService{ds},
+			Alerts:   dsSuccessAlerts("Delivery service was updated."),
+		})
+	case http.MethodDelete:
+		s.deliveryServices = append(s.deliveryServices[:i:i], s.deliveryServices[i+1:]...)
+		writeJSON(w, http.StatusOK, client.DeleteDeliveryServiceResponse{Alerts: dsSuccessAlerts("Delivery service was deleted.")})
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// getStatsSummary serves the stats summaries, filtered by cdnName, deliveryServiceName and statName; or with lastSummaryDate=true, the latest summaryTime.
+func (s *Server) getStatsSummary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	query := r.URL.Query()
+	s.m.RLock()
+	summaries := []client.StatsSummary{}
+	for _, summary := range s.statsSummaries {
+		if v := query.Get("cdnName"); v != "" && v != summary.CDNName {
+			continue
+		}
+		if v := query.Get("deliveryServiceName"); v != "" && v != summary.DeliveryService {
+			continue
+		}
+		if v := query.Get("statName"); v != "" && v != summary.StatName {
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+	s.m.RUnlock()
+
+	if query.Get("lastSummaryDate") == "true" {
+		resp := client.LastUpdated{}
+		for _, summary := range summaries {
+			if summary.SummaryTime > resp.Response.SummaryTime {
+				resp.Response.SummaryTime = summary.SummaryTime
+			}
+		}
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+	writeJSON(w, http.StatusOK, client.StatsSummaryResponse{Response: summaries})
+}
+
+func (s *Server) createStatsSummary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w)
+		return
+	}
+	summary := client.StatsSummary{}
+	if !decodeBody(w, r, &summary) {
+		return
+	}
+	s.AddStatsSummary(summary)
+	writeAlerts(w, http.StatusOK, "success", "Stats Summary was successfully added.")
+}
+
+// decodeBody decodes the request body into v. If it fails, it writes a Bad Request and returns false.
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	b, err := ioutil.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(b, v)
+	}
+	if err != nil {
+		writeAlerts(w, http.StatusBadRequest, "error", "Invalid request: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func successAlerts(text string) []client.Alert {
+	return []client.Alert{{Level: "success", Text: text}}
+}
+
+func dsSuccessAlerts(text string) []client.DeliveryServiceAlert {
+	return []client.DeliveryServiceAlert{{Level: "success", Text: text}}
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	writeAlerts(w, http.StatusMethodNotAllowed, "error", "Method not allowed.")
+}
+
+func writeAlerts(w http.ResponseWriter, code int, level, text string) {
+	writeJSON(w, code, struct {
+		Alerts []client.Alert `json:"alerts"`
+	}{[]client.Alert{{Level: level, Text: text}}})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		code = http.StatusInternalServerError
+		b = []byte(`{"alerts":[{"level":"error","text":"Internal Server Error"}]}`)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(b)
+}
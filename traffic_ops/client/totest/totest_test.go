@@ -0,0 +1,254 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package totest
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client/fixtures"
+	"github.com/jheitz200/test_helper"
+)
+
+const (
+	testUser     = "admin"
+	testPassword = "password"
+)
+
+// newTestServer returns a server with the test user, and a Session logged in to it.
+func newTestServer(t *testing.T) (*Server, *client.Session) {
+	tos := NewServer()
+	tos.AddUser(testUser, testPassword)
+	to, err := client.LoginWithAgent(tos.URL, testUser, testPassword, true, "totest", false, client.DefaultTimeout)
+	if err != nil {
+		tos.Close()
+		testHelper.Fatal(t, "Should be able to log in, got: %v", err)
+	}
+	to.RetryPolicy = client.NoRetryPolicy
+	return tos, to
+}
+
+func TestLogin(t *testing.T) {
+	tos := NewServer()
+	defer tos.Close()
+	tos.AddUser(testUser, testPassword)
+
+	testHelper.Context(t, "Given the need to test logging in to the fake Traffic Ops")
+
+	if _, err := client.LoginWithAgent(tos.URL, testUser, "wrong", true, "totest", false, client.DefaultTimeout); err == nil {
+		testHelper.Error(t, "Should not be able to log in with the wrong password")
+	} else {
+		testHelper.Success(t, "Should not be able to log in with the wrong password")
+	}
+	if _, err := client.LoginWithAgent(tos.URL, testUser, testPassword, true, "totest", false, client.DefaultTimeout); err != nil {
+		testHelper.Error(t, "Should be able to log in, got: %v", err)
+	} else {
+		testHelper.Success(t, "Should be able to log in")
+	}
+}
+
+func TestExpireSessions(t *testing.T) {
+	tos, to := newTestServer(t)
+	defer tos.Close()
+	tos.SetCRConfig("cdn1", []byte(`{}`))
+
+	testHelper.Context(t, "Given the need to test that clients log in again when their session expires")
+
+	tos.ExpireSessions()
+	if _, _, err := to.GetCRConfig(context.Background(), "cdn1"); err != nil {
+		testHelper.Error(t, "Should log in again and get the CRConfig, got: %v", err)
+	} else {
+		testHelper.Success(t, "Should log in again and get the CRConfig")
+	}
+}
+
+func TestUnavailable(t *testing.T) {
+	tos, to := newTestServer(t)
+	defer tos.Close()
+
+	testHelper.Context(t, "Given the need to test clients when Traffic Ops is unavailable")
+
+	tos.SetUnavailable(true)
+	_, err := to.Servers(context.Background())
+	if httpErr, ok := err.(*client.HTTPError); !ok || httpErr.HTTPStatusCode != 503 {
+		testHelper.Error(t, "Should get a Service Unavailable error, got: %v", err)
+	} else {
+		testHelper.Success(t, "Should get a Service Unavailable error")
+	}
+
+	tos.SetUnavailable(false)
+	if _, err := to.Servers(context.Background()); err != nil {
+		testHelper.Error(t, "Should get servers once available, got: %v", err)
+	}
+}
+
+func TestCRConfig(t *testing.T) {
+	tos, to := newTestServer(t)
+	defer tos.Close()
+
+	testHelper.Context(t, "Given the need to test getting a CRConfig from the fake Traffic Ops")
+
+	crConfig := []byte(`{"stats":{"CDN_name":"cdn1"}}`)
+	tos.SetCRConfig("cdn1", crConfig)
+	b, _, err := to.GetCRConfig(context.Background(), "cdn1")
+	if err != nil || string(b) != string(crConfig) {
+		testHelper.Error(t, "Should get the CRConfig, got: %s %v", b, err)
+	} else {
+		testHelper.Success(t, "Should get the CRConfig")
+	}
+	if _, _, err := to.GetCRConfig(context.Background(), "cdn2"); err == nil {
+		testHelper.Error(t, "Should get an error for a CDN with no CRConfig")
+	}
+}
+
+func TestMonitoringConfig(t *testing.T) {
+	tos, to := newTestServer(t)
+	defer tos.Close()
+
+	testHelper.Context(t, "Given the need to test getting a monitoring config from the fake Traffic Ops")
+
+	expected := fixtures.TrafficMonitorConfig().Response
+	expected.Profiles[0].Parameters.MinFreeKbps = 0 // MinFreeKbps isn't a Traffic Ops parameter
+	tos.SetMonitoringConfig("cdn1", expected)
+
+	cfg, err := to.TrafficMonitorConfig(context.Background(), "cdn1")
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to get the monitoring config, got: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.Profiles, expected.Profiles) || !reflect.DeepEqual(cfg.TrafficServers, expected.TrafficServers) {
+		testHelper.Error(t, "Should get the monitoring config %+v, got: %+v", expected, *cfg)
+	} else {
+		testHelper.Success(t, "Should get the monitoring config, with its thresholds")
+	}
+
+	if !tos.SetServerStatus("edge-test-01", "REPORTED") {
+		testHelper.Fatal(t, "Should find the server edge-test-01")
+	}
+	cfgMap, err := to.TrafficMonitorConfigMap(context.Background(), "cdn1")
+	if err != nil {
+		testHelper.Fatal(t, "Should be able to get the monitoring config, got: %v", err)
+	}
+	if status := cfgMap.TrafficServer["edge-test-01"].Status; status != "REPORTED" {
+		testHelper.Error(t, "Should get the changed server status REPORTED, got: %v", status)
+	} else {
+		testHelper.Success(t, "Should get the changed server status")
+	}
+	if expected.TrafficServers[1].Status != "OFFLINE" {
+		testHelper.Error(t, "Should not change the config passed to SetMonitoringConfig")
+	}
+}
+
+func TestServers(t *testing.T) {
+	tos, to := newTestServer(t)
+	defer tos.Close()
+	ctx := context.Background()
+
+	testHelper.Context(t, "Given the need to test managing servers in the fake Traffic Ops")
+
+	tos.AddServer(client.Server{HostName: "edge1", Type: "EDGE", Status: "REPORTED"})
+	tos.AddServer(client.Server{HostName: "mid1", Type: "MID", Status: "REPORTED"})
+	created, err := to.CreateServer(ctx, &client.Server{HostName: "edge2", Type: "EDGE", Status: "OFFLINE"})
+	if err != nil || len(created.Response) != 1 || created.Response[0].ID == 0 {
+		testHelper.Fatal(t, "Should be able to create a server, got: %+v %v", created, err)
+	}
+
+	edges, err := to.ServersByType(ctx, url.Values{"type": []string{"EDGE"}})
+	if err != nil || len(edges) != 2 {
+		testHelper.Error(t, "Should get 2 EDGE servers, got: %+v %v", edges, err)
+	} else {
+		testHelper.Success(t, "Should filter servers by type")
+	}
+
+	server := created.Response[0]
+	server.Status = "REPORTED"
+	if _, err := to.UpdateServer(ctx, server.ID, &server); err != nil {
+		testHelper.Error(t, "Should be able to update a server, got: %v", err)
+	}
+	if _, err := to.DeleteServer(ctx, tos.Servers()[0].ID); err != nil {
+		testHelper.Error(t, "Should be able to delete a server, got: %v", err)
+	}
+	servers := tos.Servers()
+	if len(servers) != 2 || servers[0].HostName != "mid1" || servers[1].Status != "REPORTED" {
+		testHelper.Error(t, "Should have the updated servers, got: %+v", servers)
+	} else {
+		testHelper.Success(t, "Should be able to create, update and delete servers")
+	}
+
+	if _, err := to.DeleteServer(ctx, 999); err == nil {
+		testHelper.Error(t, "Should get an error deleting a nonexistent server")
+	}
+}
+
+func TestDeliveryServices(t *testing.T) {
+	tos, to := newTestServer(t)
+	defer tos.Close()
+	ctx := context.Background()
+
+	testHelper.Context(t, "Given the need to test managing delivery services in the fake Traffic Ops")
+
+	existing := tos.AddDeliveryService(client.DeliveryService{XMLID: "ds1", Active: true})
+	if _, err := to.CreateDeliveryService(ctx, &client.DeliveryService{XMLID: "ds1"}); err == nil {
+		testHelper.Error(t, "Should get an error creating a duplicate xmlId")
+	}
+	if _, err := to.CreateDeliveryService(ctx, &client.DeliveryService{XMLID: "ds2"}); err != nil {
+		testHelper.Fatal(t, "Should be able to create a delivery service, got: %v", err)
+	}
+
+	existing.Active = false
+	if _, err := to.UpdateDeliveryService(ctx, "1", &existing); err != nil {
+		testHelper.Error(t, "Should be able to update a delivery service, got: %v", err)
+	}
+	dses, err := to.DeliveryServices(ctx)
+	if err != nil || len(dses) != 2 || dses[0].Active || dses[1].XMLID != "ds2" {
+		testHelper.Error(t, "Should get the updated delivery services, got: %+v %v", dses, err)
+	} else {
+		testHelper.Success(t, "Should be able to create and update delivery services")
+	}
+
+	if !tos.RemoveDeliveryService("ds2") || len(tos.DeliveryServices()) != 1 {
+		testHelper.Error(t, "Should be able to remove a delivery service")
+	}
+}
+
+func TestStatsSummary(t *testing.T) {
+	tos, to := newTestServer(t)
+	defer tos.Close()
+	ctx := context.Background()
+
+	testHelper.Context(t, "Given the need to test stats summaries in the fake Traffic Ops")
+
+	tos.AddStatsSummary(fixtures.StatsSummary().Response[0])
+	if err := to.AddSummaryStats(ctx, client.StatsSummary{CDNName: "test-cdn", StatName: "daily_maxgbps", StatValue: "5", SummaryTime: "2017-01-02 00:00:00"}); err != nil {
+		testHelper.Fatal(t, "Should be able to add stats summaries, got: %v", err)
+	}
+
+	summaries, err := to.SummaryStats(ctx, "test-cdn", "", "daily_maxgbps")
+	if err != nil || len(summaries) != 1 || summaries[0].StatValue != "5" {
+		testHelper.Error(t, "Should get the added stats summary, got: %+v %v", summaries, err)
+	} else {
+		testHelper.Success(t, "Should filter stats summaries")
+	}
+
+	last, err := to.SummaryStatsLastUpdated(ctx, "")
+	if err != nil || last != "2017-01-02 00:00:00" {
+		testHelper.Error(t, "Should get the last summary time, got: %v %v", last, err)
+	} else {
+		testHelper.Success(t, "Should get the last summary time")
+	}
+}
@@ -16,6 +16,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -82,6 +83,8 @@ type TMParameters struct {
 	HistoryCount            int    `json:"history.count"`
 	MinFreeKbps             int64
 	Thresholds              map[string]HealthThreshold `json:"health_threshold"`
+	// Interfaces is the list of network interfaces whose bandwidth is summed for caches of this profile. If empty, the server's interfaceName is used.
+	Interfaces []string `json:"health.polling.interfaces"`
 }
 
 const DefaultHealthThresholdComparator = "<"
@@ -111,6 +114,19 @@ func strToThreshold(s string) (HealthThreshold, error) {
 	return HealthThreshold{Val: val, Comparator: DefaultHealthThresholdComparator}, nil
 }
 
+// strToInterfaces takes a comma-delimited string of interface names like "eth0, eth1" and returns the names, ignoring whitespace and empty names.
+func strToInterfaces(s string) []string {
+	interfaces := []string{}
+	for _, iface := range strings.Split(s, ",") {
+		iface = strings.TrimSpace(iface)
+		if iface == "" {
+			continue
+		}
+		interfaces = append(interfaces, iface)
+	}
+	return interfaces
+}
+
 func (params *TMParameters) UnmarshalJSON(bytes []byte) (err error) {
 	raw := map[string]interface{}{}
 	if err := json.Unmarshal(bytes, &raw); err != nil {
@@ -141,6 +157,22 @@ func (params *TMParameters) UnmarshalJSON(bytes []byte) (err error) {
 		}
 	}
 
+	// Traffic Ops returns interfaces as a comma-delimited string, but a list is also accepted, so marshalled TMParameters can be unmarshalled.
+	if vi, ok := raw["health.polling.interfaces"]; ok && vi != nil {
+		switch v := vi.(type) {
+		case string:
+			params.Interfaces = strToInterfaces(v)
+		case []interface{}:
+			for _, iface := range v {
+				if ifaceStr, ok := iface.(string); ok {
+					params.Interfaces = append(params.Interfaces, ifaceStr)
+				}
+			}
+		default:
+			return fmt.Errorf("Unmarshalling TMParameters health.polling.interfaces expected comma-delimited string, got %v", vi)
+		}
+	}
+
 	params.Thresholds = map[string]HealthThreshold{}
 	thresholdPrefix := "health.threshold."
 	for k, v := range raw {
@@ -158,8 +190,8 @@ func (params *TMParameters) UnmarshalJSON(bytes []byte) (err error) {
 }
 
 // TrafficMonitorConfigMap ...
-func (to *Session) TrafficMonitorConfigMap(cdn string) (*TrafficMonitorConfigMap, error) {
-	tmConfig, err := to.TrafficMonitorConfig(cdn)
+func (to *Session) TrafficMonitorConfigMap(ctx context.Context, cdn string) (*TrafficMonitorConfigMap, error) {
+	tmConfig, err := to.TrafficMonitorConfig(ctx, cdn)
 	if err != nil {
 		return nil, err
 	}
@@ -171,9 +203,9 @@ func (to *Session) TrafficMonitorConfigMap(cdn string) (*TrafficMonitorConfigMap
 }
 
 // TrafficMonitorConfig ...
-func (to *Session) TrafficMonitorConfig(cdn string) (*TrafficMonitorConfig, error) {
+func (to *Session) TrafficMonitorConfig(ctx context.Context, cdn string) (*TrafficMonitorConfig, error) {
 	url := fmt.Sprintf("/api/1.2/cdns/%s/configs/monitoring.json", cdn)
-	resp, err := to.request("GET", url, nil)
+	resp, err := to.request(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,55 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/jheitz200/test_helper"
+)
+
+func TestTMParametersInterfaces(t *testing.T) {
+	testHelper.Context(t, "Given the need to test that TMParameters unmarshals health.polling.interfaces")
+
+	tests := map[string][]string{
+		`{"health.polling.interfaces": "eth0, eth1,,"}`:        []string{"eth0", "eth1"},
+		`{"health.polling.interfaces": ["eth0", "eth1"]}`:      []string{"eth0", "eth1"},
+		`{"health.polling.url": "http://${hostname}/_astats"}`: nil,
+	}
+	for input, expected := range tests {
+		params := TMParameters{}
+		if err := json.Unmarshal([]byte(input), &params); err != nil {
+			testHelper.Error(t, "Should be able to unmarshal %s, got error: %v", input, err)
+			continue
+		}
+		if len(params.Interfaces) != 0 || len(expected) != 0 {
+			if !reflect.DeepEqual(params.Interfaces, expected) {
+				testHelper.Error(t, "Should get back %v interfaces for %s, got: %v", expected, input, params.Interfaces)
+				continue
+			}
+		}
+		testHelper.Success(t, "Should be able to get the interfaces of %s", input)
+	}
+
+	params := TMParameters{}
+	if err := json.Unmarshal([]byte(`{"health.polling.interfaces": 42}`), &params); err == nil {
+		testHelper.Error(t, "Should get an error unmarshalling a numeric health.polling.interfaces")
+	} else {
+		testHelper.Success(t, "Should not be able to unmarshal a numeric health.polling.interfaces")
+	}
+}
@@ -17,14 +17,17 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/cookiejar"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/net/publicsuffix"
@@ -36,37 +39,96 @@ type Session struct {
 	Password     string
 	URL          string
 	Client       *http.Client
-	cache        map[string]CacheEntry
-	cacheMutex   *sync.RWMutex
-	useCache     bool
 	UserAgentStr string
+	// Cache, if not nil, caches GET responses, honoring the Cache-Control, Expires, ETag and Last-Modified headers from Traffic Ops. It is a MemoryCacheStore if the Session was created with useCache, and may be changed before the Session is used.
+	Cache CacheStore
+	// CacheDefaultTTL is how long a cached response is fresh, if Traffic Ops doesn't say. It defaults to 0, so responses without a max-age or Expires are revalidated on every request.
+	CacheDefaultTTL time.Duration
+	// CRConfigCacheDefaultTTL is CacheDefaultTTL for the CRConfig and Traffic Router config, which are large, and which Traffic Ops 1.2 sends without cache headers. It defaults to DefaultCRConfigCacheTTL.
+	CRConfigCacheDefaultTTL time.Duration
+	// CacheStaleIfError is how long after a cached response expires it may still be used, if Traffic Ops can't be reached. It defaults to DefaultCacheStaleIfError.
+	CacheStaleIfError time.Duration
+	// RetryPolicy is how idempotent requests are retried. It defaults to DefaultRetryPolicy, and may be changed before the Session is used.
+	RetryPolicy RetryPolicy
+	// Auth is how the Session authenticates. The zero value is AuthCookie.
+	Auth AuthMethod
+	// APIKey is the key AuthAPIKey Sessions send.
+	APIKey string
+	// TokenLoginPath is the path AuthToken Sessions log in to. If empty, DefaultTokenLoginPath is used.
+	TokenLoginPath string
+	// TokenRefreshBefore is how long before its token expires an AuthToken Session logs in again. It defaults to DefaultTokenRefreshBefore.
+	TokenRefreshBefore time.Duration
+	// ValidateDeliveryServices is whether CreateDeliveryService and UpdateDeliveryService validate delivery services before sending them. Invalid delivery services return a *ValidationError, and no request is sent.
+	ValidateDeliveryServices bool
+	// DryRun is whether the methods which change delivery services, CreateDeliveryService, UpdateDeliveryService, DeleteDeliveryService and AssignDeliveryServiceServers, validate and return without sending any request. Their responses have an alert saying nothing was changed.
+	DryRun bool
+	// API is the API version used by methods whose endpoint Traffic Ops has in more than one version, such as GetCRConfig. The zero value is APIVersion12.
+	API             APIVersion
+	loginMutex      *sync.Mutex
+	loginGeneration *uint64
+	token           *atomic.Value
+	apiVersions     *atomic.Value
 }
 
 func NewSession(user, password, url, userAgent string, client *http.Client, useCache bool) *Session {
-	return &Session{
-		UserName:     user,
-		Password:     password,
-		URL:          url,
-		Client:       client,
-		cache:        map[string]CacheEntry{},
-		cacheMutex:   &sync.RWMutex{},
-		useCache:     useCache,
-		UserAgentStr: userAgent,
+	to := &Session{
+		UserName:                user,
+		Password:                password,
+		URL:                     url,
+		Client:                  client,
+		UserAgentStr:            userAgent,
+		CacheStaleIfError:       DefaultCacheStaleIfError,
+		CRConfigCacheDefaultTTL: DefaultCRConfigCacheTTL,
+		RetryPolicy:             DefaultRetryPolicy,
+		TokenRefreshBefore:      DefaultTokenRefreshBefore,
+		loginMutex:              &sync.Mutex{},
+		loginGeneration:         new(uint64),
+		token:                   &atomic.Value{},
+		apiVersions:             &atomic.Value{},
 	}
+	if useCache {
+		to.Cache = NewMemoryCacheStore(DefaultCacheEntries)
+	}
+	return to
 }
 
 const DefaultTimeout = time.Second * time.Duration(30)
 
-// HTTPError is returned on Update Session failure.
+// HTTPError is returned when Traffic Ops responds with a status other than OK. Alerts are the alerts in the response body, if it had any, which usually say why the request failed.
 type HTTPError struct {
 	HTTPStatusCode int
 	HTTPStatus     string
 	URL            string
+	Alerts         []Alert
+	body           []byte
 }
 
 // Error implements the error interface for our customer error type.
 func (e *HTTPError) Error() string {
-	return fmt.Sprintf("%s[%d] - Error requesting Traffic Ops %s", e.HTTPStatus, e.HTTPStatusCode, e.URL)
+	msg := fmt.Sprintf("%s[%d] - Error requesting Traffic Ops %s", e.HTTPStatus, e.HTTPStatusCode, e.URL)
+	for _, alert := range e.Alerts {
+		msg += fmt.Sprintf(" - %s: %s", alert.Level, alert.Text)
+	}
+	return msg
+}
+
+// newHTTPError returns an HTTPError for the given response, with the alerts parsed from its body. The body is read, but not closed.
+func newHTTPError(resp *http.Response, url string) *HTTPError {
+	e := HTTPError{
+		HTTPStatus:     resp.Status,
+		HTTPStatusCode: resp.StatusCode,
+		URL:            url,
+	}
+	result := struct {
+		Alerts []Alert `json:"alerts"`
+	}{}
+	if body, err := ioutil.ReadAll(resp.Body); err == nil {
+		e.body = body
+		if json.Unmarshal(body, &result) == nil {
+			e.Alerts = result.Alerts
+		}
+	}
+	return &e
 }
 
 // Result {"response":[{"level":"success","text":"Successfully logged in."}],"version":"1.1"}
@@ -80,21 +142,12 @@ type Alert struct {
 	Text  string `json:"text"`
 }
 
-// CacheEntry ...
-type CacheEntry struct {
-	Entered int64
-	Bytes   []byte
-}
-
 // Credentials contains Traffic Ops login credentials
 type Credentials struct {
 	Username string `json:"u"`
 	Password string `json:"p"`
 }
 
-// TODO JvD
-const tmPollingInterval = 60
-
 // loginCreds gathers login credentials for Traffic Ops.
 func loginCreds(toUser string, toPasswd string) ([]byte, error) {
 	credentials := Credentials{
@@ -120,11 +173,24 @@ func Login(toURL string, toUser string, toPasswd string, insecure bool) (*Sessio
 //     to := traffic_ops.Login("user", "passwd", true)
 // subsequent calls like to.GetData("datadeliveryservice") will be authenticated.
 func LoginWithAgent(toURL string, toUser string, toPasswd string, insecure bool, userAgent string, useCache bool, requestTimeout time.Duration) (*Session, error) {
-	credentials, err := loginCreds(toUser, toPasswd)
+	return LoginWithContext(context.Background(), toURL, toUser, toPasswd, insecure, userAgent, useCache, requestTimeout)
+}
+
+// LoginWithContext is LoginWithAgent, with a context for the login request.
+func LoginWithContext(ctx context.Context, toURL string, toUser string, toPasswd string, insecure bool, userAgent string, useCache bool, requestTimeout time.Duration) (*Session, error) {
+	client, err := newHTTPClient(insecure, requestTimeout)
 	if err != nil {
 		return nil, err
 	}
+	to := NewSession(toUser, toPasswd, toURL, userAgent, client, useCache)
+	if err := to.login(ctx); err != nil {
+		return nil, err
+	}
+	return to, nil
+}
 
+// newHTTPClient returns the HTTP client of a new Session, with a cookie jar for its session cookie.
+func newHTTPClient(insecure bool, requestTimeout time.Duration) (*http.Client, error) {
 	options := cookiejar.Options{
 		PublicSuffixList: publicsuffix.List,
 	}
@@ -134,24 +200,38 @@ func LoginWithAgent(toURL string, toUser string, toPasswd string, insecure bool,
 		return nil, err
 	}
 
-	to := NewSession(toUser, toPasswd, toURL, userAgent, &http.Client{
+	return &http.Client{
 		Timeout: requestTimeout,
 		Transport: &http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
 		},
 		Jar: jar,
-	}, useCache)
+	}, nil
+}
 
-	path := "/api/1.2/user/login"
-	resp, err := to.request("POST", path, credentials)
+const loginPath = "/api/1.2/user/login"
+
+// login logs in with the Session's user and password, which sets the session cookie in the client's cookie jar, or for AuthToken, gets a bearer token. AuthAPIKey Sessions can't log in, so an API key which is rejected stays rejected.
+func (to *Session) login(ctx context.Context) error {
+	switch to.Auth {
+	case AuthToken:
+		return to.loginToken(ctx)
+	case AuthAPIKey:
+		return fmt.Errorf("API key sessions can't log in")
+	}
+	credentials, err := loginCreds(to.UserName, to.Password)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	resp, err := to.request(ctx, "POST", loginPath, credentials)
+	if err != nil {
+		return err
 	}
 	defer resp.Body.Close()
 
 	var result Result
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+		return err
 	}
 
 	success := false
@@ -163,49 +243,110 @@ func LoginWithAgent(toURL string, toUser string, toPasswd string, insecure bool,
 	}
 
 	if !success {
-		err := fmt.Errorf("Login failed, result string: %+v", result)
-		return nil, err
+		return fmt.Errorf("Login failed, result string: %+v", result)
 	}
+	return nil
+}
 
-	return to, nil
+// request performs the actual HTTP request to Traffic Ops. GET requests are served from the Session's Cache, if it has one, see cachedGet.
+func (to *Session) request(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	if method == http.MethodGet && to.Cache != nil {
+		body, _, err := to.cachedGet(ctx, path, to.CacheDefaultTTL)
+		if err != nil {
+			return nil, err
+		}
+		return cachedResponse(body), nil
+	}
+	return to.send(ctx, method, path, body, nil)
 }
 
-// request performs the actual HTTP request to Traffic Ops
-func (to *Session) request(method, path string, body []byte) (*http.Response, error) {
+// send makes the HTTP request to Traffic Ops, with the given extra headers. A Not Modified response is returned as a success, for conditional requests. Idempotent requests which fail with a network error or a temporary status are retried per the Session's RetryPolicy. If Traffic Ops responds Unauthorized because the session cookie or token expired, the Session logs in again and retries once. AuthToken Sessions also log in again before their token expires.
+func (to *Session) send(ctx context.Context, method, path string, body []byte, header http.Header) (*http.Response, error) {
+	if to.Auth == AuthToken && !to.isLoginPath(path) {
+		if err := to.refreshToken(ctx); err != nil {
+			return nil, err
+		}
+	}
 	url := fmt.Sprintf("%s%s", to.URL, path)
+	relogged := false
+	for attempt := 1; ; attempt++ {
+		generation := atomic.LoadUint64(to.loginGeneration)
+		resp, err := to.do(ctx, method, url, body, header)
+		if err == nil && (resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNotModified) {
+			return resp, nil
+		}
 
-	var req *http.Request
-	var err error
+		retryAfter := time.Duration(0)
+		if err == nil {
+			httpErr := newHTTPError(resp, url)
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusUnauthorized && !to.isLoginPath(path) && !relogged {
+				relogged = true
+				if to.relogin(ctx, generation) == nil {
+					attempt--
+					continue
+				}
+			}
+			err = httpErr
+			if !retryableStatus(resp.StatusCode) {
+				return nil, err
+			}
+		} else if ctx.Err() != nil {
+			return nil, err
+		}
 
-	if body != nil {
-		req, err = http.NewRequest(method, url, bytes.NewBuffer(body))
-		if err != nil {
+		if !idempotent(method) || attempt >= to.RetryPolicy.MaxAttempts {
 			return nil, err
 		}
-		req.Header.Set("Content-Type", "application/json")
-	} else {
-		req, err = http.NewRequest(method, url, nil)
-		if err != nil {
+		wait := to.RetryPolicy.Backoff(attempt)
+		if retryAfter > wait && retryAfter <= to.RetryPolicy.MaxBackoff {
+			wait = retryAfter
+		}
+		select {
+		case <-ctx.Done():
 			return nil, err
+		case <-time.After(wait):
 		}
 	}
+}
 
-	req.Header.Set("User-Agent", to.UserAgentStr)
-	resp, err := to.Client.Do(req)
+// do makes a single HTTP request to the given URL, with the given extra headers.
+func (to *Session) do(ctx context.Context, method, url string, body []byte, header http.Header) (*http.Response, error) {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, reqBody)
 	if err != nil {
 		return nil, err
 	}
-
-	if resp.StatusCode != http.StatusOK {
-		e := HTTPError{
-			HTTPStatus:     resp.Status,
-			HTTPStatusCode: resp.StatusCode,
-			URL:            url,
-		}
-		return nil, &e
+	req = req.WithContext(ctx)
+	for name, vals := range header {
+		req.Header[name] = vals
 	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("User-Agent", to.UserAgentStr)
+	if auth := to.authorization(); auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	return to.Client.Do(req)
+}
 
-	return resp, nil
+// relogin logs in again, after a request made at the given login generation was Unauthorized. If another request already logged in since then, it doesn't log in again, so concurrent requests whose session expired only log in once.
+func (to *Session) relogin(ctx context.Context, generation uint64) error {
+	to.loginMutex.Lock()
+	defer to.loginMutex.Unlock()
+	if atomic.LoadUint64(to.loginGeneration) != generation {
+		return nil
+	}
+	if err := to.login(ctx); err != nil {
+		return err
+	}
+	atomic.AddUint64(to.loginGeneration, 1)
+	return nil
 }
 
 type CacheHitStatus string
@@ -213,6 +354,12 @@ type CacheHitStatus string
 const CacheHitStatusHit = CacheHitStatus("hit")
 const CacheHitStatusExpired = CacheHitStatus("expired")
 const CacheHitStatusMiss = CacheHitStatus("miss")
+
+// CacheHitStatusRevalidated is a cached response which Traffic Ops confirmed is unchanged.
+const CacheHitStatusRevalidated = CacheHitStatus("revalidated")
+
+// CacheHitStatusStale is an expired cached response, used because Traffic Ops couldn't be reached.
+const CacheHitStatusStale = CacheHitStatus("stale")
 const CacheHitStatusInvalid = CacheHitStatus("")
 
 func (s CacheHitStatus) String() string {
@@ -228,81 +375,36 @@ func StringToCacheHitStatus(s string) CacheHitStatus {
 		return CacheHitStatusExpired
 	case "miss":
 		return CacheHitStatusMiss
+	case "revalidated":
+		return CacheHitStatusRevalidated
+	case "stale":
+		return CacheHitStatusStale
 	default:
 		return CacheHitStatusInvalid
 	}
 }
 
-// setCache Sets the given cache key and value. This is threadsafe for multiple goroutines.
-func (to *Session) setCache(path string, entry CacheEntry) {
-	if !to.useCache {
-		return
-	}
-	to.cacheMutex.Lock()
-	defer to.cacheMutex.Unlock()
-	to.cache[path] = entry
-}
-
-// getCache gets the cache value at the given key, or false if it doesn't exist. This is threadsafe for multiple goroutines.
-func (to *Session) getCache(path string) (CacheEntry, bool) {
-	to.cacheMutex.RLock()
-	defer to.cacheMutex.RUnlock()
-	cacheEntry, ok := to.cache[path]
-	return cacheEntry, ok
+// GetBytes - get []bytes array for a certain path on the to session.
+// returns the raw body, and whether it was from the Session's cache
+func (to *Session) getBytes(ctx context.Context, path string) ([]byte, CacheHitStatus, error) {
+	return to.getBytesWithTTL(ctx, path, to.CacheDefaultTTL)
 }
 
-//if cacheEntry, ok := to.Cache[path]; ok {
-
-// getBytesWithTTL - get the path, and cache in the session
-// return from cache is found and the ttl isn't expired, otherwise get it and
-// store it in cache
-func (to *Session) getBytesWithTTL(path string, ttl int64) ([]byte, CacheHitStatus, error) {
-	var body []byte
-	var err error
-	var cacheHitStatus CacheHitStatus
-	getFresh := false
-	if cacheEntry, ok := to.getCache(path); ok {
-		if cacheEntry.Entered > time.Now().Unix()-ttl {
-			cacheHitStatus = CacheHitStatusHit
-			body = cacheEntry.Bytes
-		} else {
-			cacheHitStatus = CacheHitStatusExpired
-			getFresh = true
-		}
-	} else {
-		cacheHitStatus = CacheHitStatusMiss
-		getFresh = true
-	}
-
-	if getFresh {
-		body, err = to.getBytes(path)
-		if err != nil {
-			return nil, CacheHitStatusInvalid, err
-		}
-
-		newEntry := CacheEntry{
-			Entered: time.Now().Unix(),
-			Bytes:   body,
-		}
-		to.setCache(path, newEntry)
+// getBytesWithTTL is getBytes, with the given default TTL for a cached response Traffic Ops doesn't say the freshness of.
+func (to *Session) getBytesWithTTL(ctx context.Context, path string, defaultTTL time.Duration) ([]byte, CacheHitStatus, error) {
+	if to.Cache != nil {
+		return to.cachedGet(ctx, path, defaultTTL)
 	}
-
-	return body, cacheHitStatus, nil
-}
-
-// GetBytes - get []bytes array for a certain path on the to session.
-// returns the raw body
-func (to *Session) getBytes(path string) ([]byte, error) {
-	resp, err := to.request("GET", path, nil)
+	resp, err := to.send(ctx, http.MethodGet, path, nil, nil)
 	if err != nil {
-		return nil, err
+		return nil, CacheHitStatusInvalid, err
 	}
 	defer resp.Body.Close()
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, CacheHitStatusInvalid, err
 	}
 
-	return body, nil
+	return body, CacheHitStatusMiss, nil
 }
@@ -16,6 +16,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -160,14 +161,14 @@ type SOA struct {
 }
 
 // TrafficRouterConfigMap Deprecated: use GetTrafficRouterConfigMap instead.
-func (to *Session) TrafficRouterConfigMap(cdn string) (*TrafficRouterConfigMap, error) {
-	cfg, _, err := to.GetTrafficRouterConfigMap(cdn)
+func (to *Session) TrafficRouterConfigMap(ctx context.Context, cdn string) (*TrafficRouterConfigMap, error) {
+	cfg, _, err := to.GetTrafficRouterConfigMap(ctx, cdn)
 	return cfg, err
 }
 
 // TrafficRouterConfigMap gets a bunch of maps
-func (to *Session) GetTrafficRouterConfigMap(cdn string) (*TrafficRouterConfigMap, CacheHitStatus, error) {
-	trConfig, cacheHitStatus, err := to.GetTrafficRouterConfig(cdn)
+func (to *Session) GetTrafficRouterConfigMap(ctx context.Context, cdn string) (*TrafficRouterConfigMap, CacheHitStatus, error) {
+	trConfig, cacheHitStatus, err := to.GetTrafficRouterConfig(ctx, cdn)
 	if err != nil {
 		return nil, CacheHitStatusInvalid, err
 	}
@@ -177,15 +178,15 @@ func (to *Session) GetTrafficRouterConfigMap(cdn string) (*TrafficRouterConfigMa
 }
 
 // TrafficRouterConfig Deprecated: use GetTrafficRouterConfig instead.
-func (to *Session) TrafficRouterConfig(cdn string) (*TrafficRouterConfig, error) {
-	cfg, _, err := to.GetTrafficRouterConfig(cdn)
+func (to *Session) TrafficRouterConfig(ctx context.Context, cdn string) (*TrafficRouterConfig, error) {
+	cfg, _, err := to.GetTrafficRouterConfig(ctx, cdn)
 	return cfg, err
 }
 
 // GetTrafficRouterConfig gets the json arrays
-func (to *Session) GetTrafficRouterConfig(cdn string) (*TrafficRouterConfig, CacheHitStatus, error) {
+func (to *Session) GetTrafficRouterConfig(ctx context.Context, cdn string) (*TrafficRouterConfig, CacheHitStatus, error) {
 	url := fmt.Sprintf("/api/1.2/cdns/%s/configs/routing.json", cdn)
-	body, cacheHitStatus, err := to.getBytesWithTTL(url, tmPollingInterval)
+	body, cacheHitStatus, err := to.getBytesWithTTL(ctx, url, to.CRConfigCacheDefaultTTL)
 	if err != nil {
 		return nil, CacheHitStatusInvalid, err
 	}
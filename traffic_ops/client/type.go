@@ -16,6 +16,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 )
@@ -35,14 +36,14 @@ type Type struct {
 
 // Types gets an array of Types.
 // optional parameter: userInTable
-func (to *Session) Types(useInTable ...string) ([]Type, error) {
+func (to *Session) Types(ctx context.Context, useInTable ...string) ([]Type, error) {
 
 	if len(useInTable) > 1 {
 		return nil, errors.New("Please pass in a single value for the 'useInTable' parameter")
 	}
 
 	url := "/api/1.2/types.json"
-	resp, err := to.request("GET", url, nil)
+	resp, err := to.request(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
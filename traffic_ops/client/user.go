@@ -15,7 +15,10 @@
 
 package client
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+)
 
 // UserResponse ...
 type UserResponse struct {
@@ -39,9 +42,9 @@ type User struct {
 }
 
 // Users gets an array of Users.
-func (to *Session) Users() ([]User, error) {
+func (to *Session) Users(ctx context.Context) ([]User, error) {
 	url := "/api/1.2/users.json"
-	resp, err := to.request("GET", url, nil)
+	resp, err := to.request(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
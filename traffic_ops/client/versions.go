@@ -0,0 +1,109 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// APIVersion is a version of the Traffic Ops API.
+type APIVersion string
+
+const (
+	// APIVersion12 is the Traffic Ops 1.2 API, under /api/1.2, which every Traffic Ops serves.
+	APIVersion12 = APIVersion("1.2")
+	// APIVersion20 is the Traffic Ops 2.0 API, under /api/2.0, whose generic table endpoints are served by the Traffic Ops 2.0 server, see GetTable.
+	APIVersion20 = APIVersion("2.0")
+	// APIVersionAuto uses the newest version Traffic Ops supports, which is discovered on first use, see SupportedAPIVersions.
+	APIVersionAuto = APIVersion("auto")
+)
+
+const apiBase20 = "/api/2.0"
+
+// apiVersionProbePath is requested to discover whether Traffic Ops serves the 2.0 API. OPTIONS of a 2.0 table doesn't require authentication.
+const apiVersionProbePath = apiBase20 + "/cdn"
+
+// apiVersionControlPath is a 2.0 table which doesn't exist. The 2.0 server responds Not Found to OPTIONS of it, but the 1.2 Traffic Ops responds OK to OPTIONS of any path, so it's requested to tell them apart.
+const apiVersionControlPath = apiBase20 + "/no-such-table"
+
+// SupportedAPIVersions returns the API versions Traffic Ops supports, oldest first. The 2.0 API is supported if Traffic Ops responds OK to an OPTIONS request for one of its tables, but not for a table which doesn't exist. The result is cached by the Session, unless discovery fails.
+func (to *Session) SupportedAPIVersions(ctx context.Context) ([]APIVersion, error) {
+	if versions, ok := to.apiVersions.Load().([]APIVersion); ok {
+		return versions, nil
+	}
+	versions := []APIVersion{APIVersion12}
+	ok, err := to.probeOptions(ctx, apiVersionProbePath)
+	if err != nil {
+		return nil, fmt.Errorf("discovering API versions: %v", err)
+	}
+	if ok {
+		catchAll, err := to.probeOptions(ctx, apiVersionControlPath)
+		if err != nil {
+			return nil, fmt.Errorf("discovering API versions: %v", err)
+		}
+		if !catchAll {
+			versions = append(versions, APIVersion20)
+		}
+	}
+	to.apiVersions.Store(versions)
+	return versions, nil
+}
+
+// probeOptions makes an OPTIONS request for the given path, and returns whether Traffic Ops responded OK. A server error is returned as an error, because it says nothing about the path.
+func (to *Session) probeOptions(ctx context.Context, path string) (bool, error) {
+	resp, err := to.do(ctx, http.MethodOptions, to.URL+path, nil, nil)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return false, newHTTPError(resp, to.URL+path)
+	}
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+// SupportsAPIVersion returns whether Traffic Ops supports the given API version, see SupportedAPIVersions.
+func (to *Session) SupportsAPIVersion(ctx context.Context, version APIVersion) (bool, error) {
+	versions, err := to.SupportedAPIVersions(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, v := range versions {
+		if v == version {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// apiVersion returns the API version to use for endpoints Traffic Ops has in more than one version. That is the Session's API, if it's set to a version, or the newest supported version, if it's APIVersionAuto. The default is APIVersion12, so Sessions only use the 2.0 API if told to.
+func (to *Session) apiVersion(ctx context.Context) (APIVersion, error) {
+	switch to.API {
+	case "", APIVersion12:
+		return APIVersion12, nil
+	case APIVersion20:
+		return APIVersion20, nil
+	case APIVersionAuto:
+		versions, err := to.SupportedAPIVersions(ctx)
+		if err != nil {
+			return "", err
+		}
+		return versions[len(versions)-1], nil
+	}
+	return "", fmt.Errorf("unknown API version '%v'", to.API)
+}
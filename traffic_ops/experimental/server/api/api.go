@@ -14,6 +14,7 @@
 package api
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
@@ -63,7 +64,16 @@ func (methods ApiMethods) String() string {
 	return s
 }
 
-type ApiHandlerFunc func(pathParams map[string]string, payload []byte, dbb *sqlx.DB) (interface{}, error)
+// DB is the database handlers query and change, either a *sqlx.DB, or the *sqlx.Tx of a bulk request so its operations are atomic.
+type DB interface {
+	sqlx.Ext
+	NamedExec(query string, arg interface{}) (sql.Result, error)
+	PrepareNamed(query string) (*sqlx.NamedStmt, error)
+	Select(dest interface{}, query string, args ...interface{}) error
+	Get(dest interface{}, query string, args ...interface{}) error
+}
+
+type ApiHandlerFunc func(pathParams map[string]string, payload []byte, dbb DB) (interface{}, error)
 type ApiHandlerFuncMap map[ApiMethod]ApiHandlerFunc
 
 func (handlerMap ApiHandlerFuncMap) Methods() ApiMethods {
@@ -146,25 +156,25 @@ func ApiHandlers() map[string]ApiHandlerFuncMap {
 	}
 }
 
-type EmptyHandlerFunc func(db *sqlx.DB) (interface{}, error)
-type Int64HandlerFunc func(key int64, db *sqlx.DB) (interface{}, error)
-type BodyHandlerFunc func(payload []byte, db *sqlx.DB) (interface{}, error)
-type Int64BodyHandlerFunc func(key int64, payload []byte, db *sqlx.DB) (interface{}, error)
-type StringHandlerFunc func(key string, db *sqlx.DB) (interface{}, error)
-type StringBodyHandlerFunc func(key string, payload []byte, db *sqlx.DB) (interface{}, error)
-type StringInt64HandlerFunc func(key0 string, key1 int64, db *sqlx.DB) (interface{}, error)
-type StringInt64BodyHandlerFunc func(key0 string, key1 int64, payload []byte, db *sqlx.DB) (interface{}, error)
-type Int64StringHandlerFunc func(key0 int64, key1 string, db *sqlx.DB) (interface{}, error)
-type Int64StringBodyHandlerFunc func(key0 int64, key1 string, payload []byte, db *sqlx.DB) (interface{}, error)
-type StringStringHandlerFunc func(key0 string, key1 string, db *sqlx.DB) (interface{}, error)
-type StringStringBodyHandlerFunc func(key0 string, key1 string, payload []byte, db *sqlx.DB) (interface{}, error)
-type Int64Int64HandlerFunc func(key0 int64, key1 int64, db *sqlx.DB) (interface{}, error)
-type Int64Int64BodyHandlerFunc func(key0 int64, key1 int64, payload []byte, db *sqlx.DB) (interface{}, error)
-type StringStringStringTimeHandlerFunc func(key0 string, key1 string, key2 string, key3 time.Time, db *sqlx.DB) (interface{}, error)
-type StringStringStringTimeBodyHandlerFunc func(key0 string, key1 string, key2 string, key3 time.Time, payload []byte, db *sqlx.DB) (interface{}, error)
+type EmptyHandlerFunc func(db DB) (interface{}, error)
+type Int64HandlerFunc func(key int64, db DB) (interface{}, error)
+type BodyHandlerFunc func(payload []byte, db DB) (interface{}, error)
+type Int64BodyHandlerFunc func(key int64, payload []byte, db DB) (interface{}, error)
+type StringHandlerFunc func(key string, db DB) (interface{}, error)
+type StringBodyHandlerFunc func(key string, payload []byte, db DB) (interface{}, error)
+type StringInt64HandlerFunc func(key0 string, key1 int64, db DB) (interface{}, error)
+type StringInt64BodyHandlerFunc func(key0 string, key1 int64, payload []byte, db DB) (interface{}, error)
+type Int64StringHandlerFunc func(key0 int64, key1 string, db DB) (interface{}, error)
+type Int64StringBodyHandlerFunc func(key0 int64, key1 string, payload []byte, db DB) (interface{}, error)
+type StringStringHandlerFunc func(key0 string, key1 string, db DB) (interface{}, error)
+type StringStringBodyHandlerFunc func(key0 string, key1 string, payload []byte, db DB) (interface{}, error)
+type Int64Int64HandlerFunc func(key0 int64, key1 int64, db DB) (interface{}, error)
+type Int64Int64BodyHandlerFunc func(key0 int64, key1 int64, payload []byte, db DB) (interface{}, error)
+type StringStringStringTimeHandlerFunc func(key0 string, key1 string, key2 string, key3 time.Time, db DB) (interface{}, error)
+type StringStringStringTimeBodyHandlerFunc func(key0 string, key1 string, key2 string, key3 time.Time, payload []byte, db DB) (interface{}, error)
 
 func int64BodyWrap(f Int64BodyHandlerFunc) ApiHandlerFunc {
-	return func(pathParams map[string]string, payload []byte, db *sqlx.DB) (interface{}, error) {
+	return func(pathParams map[string]string, payload []byte, db DB) (interface{}, error) {
 		if strKey, ok := pathParams["key"]; !ok {
 			return nil, errors.New("int64 key missing")
 		} else if key, err := strconv.Atoi(strKey); err != nil {
@@ -176,19 +186,19 @@ func int64BodyWrap(f Int64BodyHandlerFunc) ApiHandlerFunc {
 }
 
 func int64Wrap(f Int64HandlerFunc) ApiHandlerFunc {
-	return int64BodyWrap(func(key int64, payload []byte, db *sqlx.DB) (interface{}, error) {
+	return int64BodyWrap(func(key int64, payload []byte, db DB) (interface{}, error) {
 		return f(key, db)
 	})
 }
 
 func bodyWrap(f BodyHandlerFunc) ApiHandlerFunc {
-	return func(pathParams map[string]string, payload []byte, db *sqlx.DB) (interface{}, error) {
+	return func(pathParams map[string]string, payload []byte, db DB) (interface{}, error) {
 		return f(payload, db)
 	}
 }
 
 func stringBodyWrap(f StringBodyHandlerFunc) ApiHandlerFunc {
-	return func(pathParams map[string]string, payload []byte, db *sqlx.DB) (interface{}, error) {
+	return func(pathParams map[string]string, payload []byte, db DB) (interface{}, error) {
 		if key, ok := pathParams["key"]; !ok {
 			return nil, errors.New("string key missing")
 		} else {
@@ -198,13 +208,13 @@ func stringBodyWrap(f StringBodyHandlerFunc) ApiHandlerFunc {
 }
 
 func stringWrap(f StringHandlerFunc) ApiHandlerFunc {
-	return stringBodyWrap(func(key string, payload []byte, db *sqlx.DB) (interface{}, error) {
+	return stringBodyWrap(func(key string, payload []byte, db DB) (interface{}, error) {
 		return f(key, db)
 	})
 }
 
 func stringInt64BodyWrap(f StringInt64BodyHandlerFunc) ApiHandlerFunc {
-	return func(pathParams map[string]string, payload []byte, db *sqlx.DB) (interface{}, error) {
+	return func(pathParams map[string]string, payload []byte, db DB) (interface{}, error) {
 		var key0 string
 		var key1 int
 		var err error
@@ -222,13 +232,13 @@ func stringInt64BodyWrap(f StringInt64BodyHandlerFunc) ApiHandlerFunc {
 }
 
 func stringInt64Wrap(f StringInt64HandlerFunc) ApiHandlerFunc {
-	return stringInt64BodyWrap(func(key0 string, key1 int64, payload []byte, db *sqlx.DB) (interface{}, error) {
+	return stringInt64BodyWrap(func(key0 string, key1 int64, payload []byte, db DB) (interface{}, error) {
 		return f(key0, key1, db)
 	})
 }
 
 func int64StringBodyWrap(f Int64StringBodyHandlerFunc) ApiHandlerFunc {
-	return func(pathParams map[string]string, payload []byte, db *sqlx.DB) (interface{}, error) {
+	return func(pathParams map[string]string, payload []byte, db DB) (interface{}, error) {
 		var key0 int
 		var key1 string
 		var err error
@@ -246,13 +256,13 @@ func int64StringBodyWrap(f Int64StringBodyHandlerFunc) ApiHandlerFunc {
 }
 
 func int64StringWrap(f Int64StringHandlerFunc) ApiHandlerFunc {
-	return int64StringBodyWrap(func(key0 int64, key1 string, payload []byte, db *sqlx.DB) (interface{}, error) {
+	return int64StringBodyWrap(func(key0 int64, key1 string, payload []byte, db DB) (interface{}, error) {
 		return f(key0, key1, db)
 	})
 }
 
 func stringStringBodyWrap(f StringStringBodyHandlerFunc) ApiHandlerFunc {
-	return func(pathParams map[string]string, payload []byte, db *sqlx.DB) (interface{}, error) {
+	return func(pathParams map[string]string, payload []byte, db DB) (interface{}, error) {
 		var key0 string
 		var key1 string
 		var ok bool
@@ -267,13 +277,13 @@ func stringStringBodyWrap(f StringStringBodyHandlerFunc) ApiHandlerFunc {
 }
 
 func stringStringWrap(f StringStringHandlerFunc) ApiHandlerFunc {
-	return stringStringBodyWrap(func(key0 string, key1 string, payload []byte, db *sqlx.DB) (interface{}, error) {
+	return stringStringBodyWrap(func(key0 string, key1 string, payload []byte, db DB) (interface{}, error) {
 		return f(key0, key1, db)
 	})
 }
 
 func int64Int64BodyWrap(f Int64Int64BodyHandlerFunc) ApiHandlerFunc {
-	return func(pathParams map[string]string, payload []byte, db *sqlx.DB) (interface{}, error) {
+	return func(pathParams map[string]string, payload []byte, db DB) (interface{}, error) {
 		var key0 int
 		var key1 int
 		var err error
@@ -292,13 +302,13 @@ func int64Int64BodyWrap(f Int64Int64BodyHandlerFunc) ApiHandlerFunc {
 }
 
 func int64Int64Wrap(f Int64Int64HandlerFunc) ApiHandlerFunc {
-	return int64Int64BodyWrap(func(key0 int64, key1 int64, payload []byte, db *sqlx.DB) (interface{}, error) {
+	return int64Int64BodyWrap(func(key0 int64, key1 int64, payload []byte, db DB) (interface{}, error) {
 		return f(key0, key1, db)
 	})
 }
 
 func stringStringStringTimeBodyWrap(f StringStringStringTimeBodyHandlerFunc) ApiHandlerFunc {
-	return func(pathParams map[string]string, payload []byte, db *sqlx.DB) (interface{}, error) {
+	return func(pathParams map[string]string, payload []byte, db DB) (interface{}, error) {
 		var key0 string
 		var key1 string
 		var key2 string
@@ -324,13 +334,13 @@ func stringStringStringTimeBodyWrap(f StringStringStringTimeBodyHandlerFunc) Api
 }
 
 func stringStringStringTimeWrap(f StringStringStringTimeHandlerFunc) ApiHandlerFunc {
-	return stringStringStringTimeBodyWrap(func(key0 string, key1 string, key2 string, key3 time.Time, payload []byte, db *sqlx.DB) (interface{}, error) {
+	return stringStringStringTimeBodyWrap(func(key0 string, key1 string, key2 string, key3 time.Time, payload []byte, db DB) (interface{}, error) {
 		return f(key0, key1, key2, key3, db)
 	})
 }
 
 func emptyWrap(f EmptyHandlerFunc) ApiHandlerFunc {
-	return func(pathParams map[string]string, payload []byte, db *sqlx.DB) (interface{}, error) {
+	return func(pathParams map[string]string, payload []byte, db DB) (interface{}, error) {
 		return f(db)
 	}
 }
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -42,7 +41,7 @@ type AsnsLinks struct {
 // @Success 200 {array}    Asns
 // @Resource /api/2.0
 // @Router /api/2.0/asns/{id} [get]
-func getAsn(asn int64, db *sqlx.DB) (interface{}, error) {
+func getAsn(asn int64, db DB) (interface{}, error) {
 	ret := []Asns{}
 	arg := Asns{}
 	arg.Asn = asn
@@ -65,7 +64,7 @@ func getAsn(asn int64, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Asns
 // @Resource /api/2.0
 // @Router /api/2.0/asns [get]
-func getAsns(db *sqlx.DB) (interface{}, error) {
+func getAsns(db DB) (interface{}, error) {
 	ret := []Asns{}
 	queryStr := "select *, concat('" + API_PATH + "asns/', asn) as self"
 	queryStr += ", concat('" + API_PATH + "cachegroups/', cachegroups) as cachegroups_name_ref"
@@ -85,7 +84,7 @@ func getAsns(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/asns [post]
-func postAsn(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postAsn(payload []byte, db DB) (interface{}, error) {
 	var v Asns
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -117,7 +116,7 @@ func postAsn(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/asns/{id}  [put]
-func putAsn(asn int64, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putAsn(asn int64, payload []byte, db DB) (interface{}, error) {
 	var arg Asns
 	err := json.Unmarshal(payload, &arg)
 	arg.Asn = asn
@@ -145,7 +144,7 @@ func putAsn(asn int64, payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Asns
 // @Resource /api/2.0
 // @Router /api/2.0/asns/{id} [delete]
-func delAsn(asn int64, db *sqlx.DB) (interface{}, error) {
+func delAsn(asn int64, db DB) (interface{}, error) {
 	arg := Asns{}
 	arg.Asn = asn
 	result, err := db.NamedExec("DELETE FROM asns WHERE asn=:asn", arg)
@@ -0,0 +1,182 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"log"
+	"strings"
+)
+
+// BulkMaxOperations is the most operations a bulk request may have.
+const BulkMaxOperations = 1000
+
+// The statuses of the results of bulk operations.
+const (
+	BulkStatusOK         = "ok"
+	BulkStatusFailed     = "failed"
+	BulkStatusRolledBack = "rolled back"
+	BulkStatusSkipped    = "skipped"
+)
+
+// BulkOperation is one operation of a bulk request. Method is POST to create a row, PUT to update one, or DELETE to delete one. Path is the path of the table or row under API_PATH, as for a single request, e.g. "server" or "server/host_name/edge1/tcp_port/80". Body is the row to create or update.
+type BulkOperation struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// BulkTxError is returned by Bulk when the transaction itself fails, e.g. the database can't be reached, rather than the operations.
+type BulkTxError struct {
+	Err error
+}
+
+func (e *BulkTxError) Error() string {
+	return "bulk transaction failed: " + e.Err.Error()
+}
+
+// BulkResult is the result of the bulk operation at Index. If any operation fails, its status is failed with the Error, the operations before it are rolled back, and the operations after it are skipped.
+type BulkResult struct {
+	Index        int    `json:"index"`
+	Method       string `json:"method"`
+	Path         string `json:"path"`
+	Status       string `json:"status"`
+	RowsAffected int64  `json:"rowsAffected"`
+	Error        string `json:"error,omitempty"`
+}
+
+// Bulk executes the given operations in order, in one transaction, and returns the result of each. If any operation fails, the transaction is rolled back, and the error says which operation failed. A PUT or DELETE which matches no row fails, so a mistyped path doesn't silently do nothing. If the transaction can't begin or commit, the error is a *BulkTxError.
+func Bulk(ops []BulkOperation, db *sqlx.DB) ([]BulkResult, error) {
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("no operations")
+	}
+	if len(ops) > BulkMaxOperations {
+		return nil, fmt.Errorf("%d operations is more than the maximum of %d", len(ops), BulkMaxOperations)
+	}
+
+	results := make([]BulkResult, len(ops))
+	handlers := make([]ApiHandlerFunc, len(ops))
+	params := make([]map[string]string, len(ops))
+	for i, op := range ops {
+		results[i] = BulkResult{Index: i, Method: op.Method, Path: op.Path, Status: BulkStatusSkipped}
+		f, p, err := bulkHandler(op)
+		if err != nil {
+			results[i].Status = BulkStatusFailed
+			results[i].Error = err.Error()
+			return results, fmt.Errorf("operation %d: %v", i, err)
+		}
+		handlers[i], params[i] = f, p
+	}
+
+	tx, err := db.Beginx()
+	if err != nil {
+		log.Println(err)
+		return nil, &BulkTxError{Err: err}
+	}
+	for i, op := range ops {
+		rowsAffected, err := execBulkOperation(handlers[i], params[i], op, tx)
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				log.Println(rollbackErr)
+			}
+			for j := 0; j < i; j++ {
+				results[j].Status = BulkStatusRolledBack
+			}
+			results[i].Status = BulkStatusFailed
+			results[i].Error = err.Error()
+			return results, fmt.Errorf("operation %d failed, all operations were rolled back: %v", i, err)
+		}
+		results[i].Status = BulkStatusOK
+		results[i].RowsAffected = rowsAffected
+	}
+	if err := tx.Commit(); err != nil {
+		log.Println(err)
+		for i := range results {
+			results[i].Status = BulkStatusRolledBack
+		}
+		return results, &BulkTxError{Err: err}
+	}
+	return results, nil
+}
+
+// execBulkOperation calls the handler of a bulk operation in the given transaction, and returns the rows it affected. A PUT or DELETE which affects no rows is an error.
+func execBulkOperation(f ApiHandlerFunc, params map[string]string, op BulkOperation, tx *sqlx.Tx) (int64, error) {
+	resp, err := f(params, op.Body, tx)
+	if err != nil {
+		return 0, err
+	}
+	result, ok := resp.(sql.Result)
+	if !ok {
+		return 0, nil
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if rowsAffected == 0 && strings.ToUpper(op.Method) != "POST" {
+		return 0, fmt.Errorf("no row matches path '%s'", op.Path)
+	}
+	return rowsAffected, nil
+}
+
+// bulkHandler returns the handler and path parameters of the given bulk operation, or an error if its method isn't a change or its path doesn't match a route.
+func bulkHandler(op BulkOperation) (ApiHandlerFunc, map[string]string, error) {
+	var method ApiMethod
+	switch strings.ToUpper(op.Method) {
+	case "POST":
+		method = POST
+	case "PUT":
+		method = PUT
+	case "DELETE":
+		method = DELETE
+	default:
+		return nil, nil, fmt.Errorf("method '%s' is not POST, PUT or DELETE", op.Method)
+	}
+	path := strings.Trim(strings.TrimPrefix(op.Path, API_PATH), "/")
+	for route, funcs := range ApiHandlers() {
+		params, ok := matchRoute(route, path)
+		if !ok {
+			continue
+		}
+		f, ok := funcs[method]
+		if !ok {
+			return nil, nil, fmt.Errorf("%s is not allowed for %s", method, op.Path)
+		}
+		return f, params, nil
+	}
+	return nil, nil, fmt.Errorf("no route for path '%s'", op.Path)
+}
+
+// matchRoute returns whether the path matches the route, e.g. "server/host_name/edge1/tcp_port/80" matches "server/host_name/{key0}/tcp_port/{key1}", and the values of the route's parameters.
+func matchRoute(route string, path string) (map[string]string, bool) {
+	routeParts := strings.Split(route, "/")
+	pathParts := strings.Split(path, "/")
+	if len(routeParts) != len(pathParts) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, routePart := range routeParts {
+		if strings.HasPrefix(routePart, "{") && strings.HasSuffix(routePart, "}") {
+			if pathParts[i] == "" {
+				return nil, false
+			}
+			params[routePart[1:len(routePart)-1]] = pathParts[i]
+		} else if routePart != pathParts[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
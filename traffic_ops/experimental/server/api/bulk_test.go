@@ -0,0 +1,129 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestBulk(t *testing.T) {
+	t.Log("Testing Bulk...")
+
+	// setup mock database
+	db, mock, err := sqlmock.New()
+	xdb := sqlx.NewDb(db, "postgres")
+	assert.Nil(t, err, "error opening stub database")
+	defer db.Close()
+
+	ops := []BulkOperation{
+		{Method: "POST", Path: "cdn", Body: json.RawMessage(`{"name":"cdn1"}`)},
+		{Method: "PUT", Path: "/api/2.0/cdn/cdn1", Body: json.RawMessage(`{"name":"cdn1"}`)},
+		{Method: "DELETE", Path: "cdn/cdn2"},
+	}
+
+	// all operations succeed and are committed
+	mock.ExpectBegin()
+	mock.ExpectExec("^INSERT INTO cdns.*").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^UPDATE cdns SET .* WHERE name=").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^DELETE FROM cdns WHERE name=").WithArgs("cdn2").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	results, err := Bulk(ops, xdb)
+	require.Nil(t, err, "bulk operations should succeed")
+	require.Equal(t, 3, len(results), "there should be a result per operation")
+	for i, result := range results {
+		assert.Equal(t, i, result.Index, "index should match")
+		assert.Equal(t, BulkStatusOK, result.Status, "status should be ok")
+	}
+	assert.Equal(t, int64(2), results[2].RowsAffected, "rows affected should match")
+	assert.Nil(t, mock.ExpectationsWereMet(), "operations should be committed in one transaction")
+
+	// the second operation fails, so the first is rolled back and the third skipped
+	mock.ExpectBegin()
+	mock.ExpectExec("^INSERT INTO cdns.*").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^UPDATE cdns SET .*").WillReturnError(fmt.Errorf("Testing Database Error"))
+	mock.ExpectRollback()
+	results, err = Bulk(ops, xdb)
+	assert.NotNil(t, err, "Database error should be passed in error")
+	require.Equal(t, 3, len(results), "there should be a result per operation")
+	assert.Equal(t, BulkStatusRolledBack, results[0].Status, "first operation should be rolled back")
+	assert.Equal(t, BulkStatusFailed, results[1].Status, "second operation should fail")
+	assert.Equal(t, "Testing Database Error", results[1].Error, "second operation error should match")
+	assert.Equal(t, BulkStatusSkipped, results[2].Status, "third operation should be skipped")
+	assert.Nil(t, mock.ExpectationsWereMet(), "operations should be rolled back")
+
+	// a PUT or DELETE which matches no row fails, so the operations before it are rolled back
+	mock.ExpectBegin()
+	mock.ExpectExec("^INSERT INTO cdns.*").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("^UPDATE cdns SET .*").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	results, err = Bulk(ops, xdb)
+	assert.NotNil(t, err, "an update of no rows should return an error")
+	require.Equal(t, 3, len(results), "there should be a result per operation")
+	assert.Equal(t, BulkStatusRolledBack, results[0].Status, "first operation should be rolled back")
+	assert.Equal(t, BulkStatusFailed, results[1].Status, "update of no rows should fail")
+	assert.Equal(t, BulkStatusSkipped, results[2].Status, "third operation should be skipped")
+	assert.Nil(t, mock.ExpectationsWereMet(), "operations should be rolled back")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^DELETE FROM cdns WHERE name=").WithArgs("cdn2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	results, err = Bulk(ops[2:], xdb)
+	assert.NotNil(t, err, "a delete of no rows should return an error")
+	require.Equal(t, 1, len(results), "there should be a result per operation")
+	assert.Equal(t, BulkStatusFailed, results[0].Status, "delete of no rows should fail")
+	assert.Nil(t, mock.ExpectationsWereMet(), "operation should be rolled back")
+
+	// the commit fails, so every operation is rolled back
+	mock.ExpectBegin()
+	mock.ExpectExec("^DELETE FROM cdns WHERE name=").WithArgs("cdn2").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("Testing Commit Error"))
+	results, err = Bulk(ops[2:], xdb)
+	_, isTxErr := err.(*BulkTxError)
+	assert.True(t, isTxErr, "commit error should be a BulkTxError")
+	assert.Equal(t, BulkStatusRolledBack, results[0].Status, "operation should be rolled back")
+	assert.Nil(t, mock.ExpectationsWereMet(), "commit should fail")
+
+	// invalid operations fail before the transaction begins
+	invalid := [][]BulkOperation{
+		{},
+		{{Method: "GET", Path: "cdn"}},
+		{{Method: "POST", Path: "nonexistent_table"}},
+		{{Method: "POST", Path: "cdn/cdn1"}},
+	}
+	for _, ops := range invalid {
+		_, err = Bulk(ops, xdb)
+		assert.NotNil(t, err, "invalid operations should return an error")
+	}
+	assert.Nil(t, mock.ExpectationsWereMet(), "invalid operations should not begin a transaction")
+}
+
+func TestMatchRoute(t *testing.T) {
+	t.Log("Testing matchRoute...")
+
+	params, ok := matchRoute("server/host_name/{key0}/tcp_port/{key1}", "server/host_name/edge1/tcp_port/80")
+	require.True(t, ok, "path should match route")
+	assert.Equal(t, map[string]string{"key0": "edge1", "key1": "80"}, params, "params should match")
+
+	_, ok = matchRoute("server/host_name/{key0}/tcp_port/{key1}", "server/host_name/edge1/port/80")
+	assert.False(t, ok, "path with a different literal should not match")
+	_, ok = matchRoute("cdn/{key}", "cdn")
+	assert.False(t, ok, "path with fewer parts should not match")
+	_, ok = matchRoute("cdn/{key}", "cdn/")
+	assert.False(t, ok, "path with an empty key should not match")
+}
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	null "gopkg.in/guregu/null.v3"
 	"log"
 	"time"
@@ -52,7 +51,7 @@ type CachegroupsLink struct {
 // @Success 200 {array}    Cachegroups
 // @Resource /api/2.0
 // @Router /api/2.0/cachegroups/{id} [get]
-func getCachegroup(name string, db *sqlx.DB) (interface{}, error) {
+func getCachegroup(name string, db DB) (interface{}, error) {
 	ret := []Cachegroups{}
 	arg := Cachegroups{}
 	arg.Name = name
@@ -76,7 +75,7 @@ func getCachegroup(name string, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Cachegroups
 // @Resource /api/2.0
 // @Router /api/2.0/cachegroups [get]
-func getCachegroups(db *sqlx.DB) (interface{}, error) {
+func getCachegroups(db DB) (interface{}, error) {
 	ret := []Cachegroups{}
 	queryStr := "select *, concat('" + API_PATH + "cachegroups/', name) as self"
 	queryStr += ", concat('" + API_PATH + "cachegroups/', parent_cachegroup) as cachegroups_name_ref"
@@ -97,7 +96,7 @@ func getCachegroups(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/cachegroups [post]
-func postCachegroup(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postCachegroup(payload []byte, db DB) (interface{}, error) {
 	var v Cachegroups
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -137,7 +136,7 @@ func postCachegroup(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/cachegroups/{id}  [put]
-func putCachegroup(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putCachegroup(name string, payload []byte, db DB) (interface{}, error) {
 	var arg Cachegroups
 	err := json.Unmarshal(payload, &arg)
 	arg.Name = name
@@ -169,7 +168,7 @@ func putCachegroup(name string, payload []byte, db *sqlx.DB) (interface{}, error
 // @Success 200 {array}    Cachegroups
 // @Resource /api/2.0
 // @Router /api/2.0/cachegroups/{id} [delete]
-func delCachegroup(name string, db *sqlx.DB) (interface{}, error) {
+func delCachegroup(name string, db DB) (interface{}, error) {
 	arg := Cachegroups{}
 	arg.Name = name
 	result, err := db.NamedExec("DELETE FROM cachegroups WHERE name=:name", arg)
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -42,7 +41,7 @@ type CachegroupsParametersLinks struct {
 // @Success 200 {array}    CachegroupsParameters
 // @Resource /api/2.0
 // @Router /api/2.0/cachegroups_parameters/{id} [get]
-func getCachegroupsParameter(cachegroup string, parameterId int64, db *sqlx.DB) (interface{}, error) {
+func getCachegroupsParameter(cachegroup string, parameterId int64, db DB) (interface{}, error) {
 	ret := []CachegroupsParameters{}
 	arg := CachegroupsParameters{}
 	arg.Links.CachegroupsLink.ID = cachegroup
@@ -67,7 +66,7 @@ func getCachegroupsParameter(cachegroup string, parameterId int64, db *sqlx.DB)
 // @Success 200 {array}    CachegroupsParameters
 // @Resource /api/2.0
 // @Router /api/2.0/cachegroups_parameters [get]
-func getCachegroupsParameters(db *sqlx.DB) (interface{}, error) {
+func getCachegroupsParameters(db DB) (interface{}, error) {
 	ret := []CachegroupsParameters{}
 	queryStr := "select *, concat('" + API_PATH + "cachegroups_parameters', '/cachegroup/', cachegroup, '/parameter_id/', parameter_id) as self"
 	queryStr += ", concat('" + API_PATH + "cachegroups/', cachegroup) as cachegroups_name_ref"
@@ -88,7 +87,7 @@ func getCachegroupsParameters(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/cachegroups_parameters [post]
-func postCachegroupsParameter(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postCachegroupsParameter(payload []byte, db DB) (interface{}, error) {
 	var v CachegroupsParameters
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -120,7 +119,7 @@ func postCachegroupsParameter(payload []byte, db *sqlx.DB) (interface{}, error)
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/cachegroups_parameters/{id}  [put]
-func putCachegroupsParameter(cachegroup string, parameterId int64, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putCachegroupsParameter(cachegroup string, parameterId int64, payload []byte, db DB) (interface{}, error) {
 	var arg CachegroupsParameters
 	err := json.Unmarshal(payload, &arg)
 	arg.Links.CachegroupsLink.ID = cachegroup
@@ -149,7 +148,7 @@ func putCachegroupsParameter(cachegroup string, parameterId int64, payload []byt
 // @Success 200 {array}    CachegroupsParameters
 // @Resource /api/2.0
 // @Router /api/2.0/cachegroups_parameters/{id} [delete]
-func delCachegroupsParameter(cachegroup string, parameterId int64, db *sqlx.DB) (interface{}, error) {
+func delCachegroupsParameter(cachegroup string, parameterId int64, db DB) (interface{}, error) {
 	arg := CachegroupsParameters{}
 	arg.Links.CachegroupsLink.ID = cachegroup
 	arg.Links.ParametersLink.ID = parameterId
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -47,7 +46,7 @@ type CachegroupsTypesLink struct {
 // @Success 200 {array}    CachegroupsTypes
 // @Resource /api/2.0
 // @Router /api/2.0/cachegroups_types/{id} [get]
-func getCachegroupsType(name string, db *sqlx.DB) (interface{}, error) {
+func getCachegroupsType(name string, db DB) (interface{}, error) {
 	ret := []CachegroupsTypes{}
 	arg := CachegroupsTypes{}
 	arg.Name = name
@@ -69,7 +68,7 @@ func getCachegroupsType(name string, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    CachegroupsTypes
 // @Resource /api/2.0
 // @Router /api/2.0/cachegroups_types [get]
-func getCachegroupsTypes(db *sqlx.DB) (interface{}, error) {
+func getCachegroupsTypes(db DB) (interface{}, error) {
 	ret := []CachegroupsTypes{}
 	queryStr := "select *, concat('" + API_PATH + "cachegroups_types/', name) as self"
 	queryStr += " from cachegroups_types"
@@ -88,7 +87,7 @@ func getCachegroupsTypes(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/cachegroups_types [post]
-func postCachegroupsType(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postCachegroupsType(payload []byte, db DB) (interface{}, error) {
 	var v CachegroupsTypes
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -120,7 +119,7 @@ func postCachegroupsType(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/cachegroups_types/{id}  [put]
-func putCachegroupsType(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putCachegroupsType(name string, payload []byte, db DB) (interface{}, error) {
 	var arg CachegroupsTypes
 	err := json.Unmarshal(payload, &arg)
 	arg.Name = name
@@ -148,7 +147,7 @@ func putCachegroupsType(name string, payload []byte, db *sqlx.DB) (interface{},
 // @Success 200 {array}    CachegroupsTypes
 // @Resource /api/2.0
 // @Router /api/2.0/cachegroups_types/{id} [delete]
-func delCachegroupsType(name string, db *sqlx.DB) (interface{}, error) {
+func delCachegroupsType(name string, db DB) (interface{}, error) {
 	arg := CachegroupsTypes{}
 	arg.Name = name
 	result, err := db.NamedExec("DELETE FROM cachegroups_types WHERE name=:name", arg)
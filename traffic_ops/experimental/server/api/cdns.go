@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -46,7 +45,7 @@ type CdnsLink struct {
 // @Success 200 {array}    Cdns
 // @Resource /api/2.0
 // @Router /api/2.0/cdns/{id} [get]
-func getCdn(name string, db *sqlx.DB) (interface{}, error) {
+func getCdn(name string, db DB) (interface{}, error) {
 	ret := []Cdns{}
 	arg := Cdns{}
 	arg.Name = name
@@ -68,7 +67,7 @@ func getCdn(name string, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Cdns
 // @Resource /api/2.0
 // @Router /api/2.0/cdns [get]
-func getCdns(db *sqlx.DB) (interface{}, error) {
+func getCdns(db DB) (interface{}, error) {
 	ret := []Cdns{}
 	queryStr := "select *, concat('" + API_PATH + "cdns/', name) as self"
 	queryStr += " from cdns"
@@ -87,7 +86,7 @@ func getCdns(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/cdns [post]
-func postCdn(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postCdn(payload []byte, db DB) (interface{}, error) {
 	var v Cdns
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -117,7 +116,7 @@ func postCdn(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/cdns/{id}  [put]
-func putCdn(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putCdn(name string, payload []byte, db DB) (interface{}, error) {
 	var arg Cdns
 	err := json.Unmarshal(payload, &arg)
 	arg.Name = name
@@ -144,7 +143,7 @@ func putCdn(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Cdns
 // @Resource /api/2.0
 // @Router /api/2.0/cdns/{id} [delete]
-func delCdn(name string, db *sqlx.DB) (interface{}, error) {
+func delCdn(name string, db DB) (interface{}, error) {
 	arg := Cdns{}
 	arg.Name = name
 	result, err := db.NamedExec("DELETE FROM cdns WHERE name=:name", arg)
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -42,7 +41,7 @@ type CrconfigSnapshotsLinks struct {
 // @Success 200 {array}    CrconfigSnapshots
 // @Resource /api/2.0
 // @Router /api/2.0/crconfig_snapshots/{id} [get]
-func getCrconfigSnapshot(cdn string, createdAt time.Time, db *sqlx.DB) (interface{}, error) {
+func getCrconfigSnapshot(cdn string, createdAt time.Time, db DB) (interface{}, error) {
 	ret := []CrconfigSnapshots{}
 	arg := CrconfigSnapshots{}
 	arg.Links.CdnsLink.ID = cdn
@@ -66,7 +65,7 @@ func getCrconfigSnapshot(cdn string, createdAt time.Time, db *sqlx.DB) (interfac
 // @Success 200 {array}    CrconfigSnapshots
 // @Resource /api/2.0
 // @Router /api/2.0/crconfig_snapshots [get]
-func getCrconfigSnapshots(db *sqlx.DB) (interface{}, error) {
+func getCrconfigSnapshots(db DB) (interface{}, error) {
 	ret := []CrconfigSnapshots{}
 	queryStr := "select *, concat('" + API_PATH + "crconfig_snapshots', '/cdn/', cdn, '/created_at/', created_at) as self"
 	queryStr += ", concat('" + API_PATH + "cdns/', cdn) as cdns_name_ref"
@@ -86,7 +85,7 @@ func getCrconfigSnapshots(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/crconfig_snapshots [post]
-func postCrconfigSnapshot(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postCrconfigSnapshot(payload []byte, db DB) (interface{}, error) {
 	var v CrconfigSnapshots
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -118,7 +117,7 @@ func postCrconfigSnapshot(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/crconfig_snapshots/{id}  [put]
-func putCrconfigSnapshot(cdn string, createdAt time.Time, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putCrconfigSnapshot(cdn string, createdAt time.Time, payload []byte, db DB) (interface{}, error) {
 	var arg CrconfigSnapshots
 	err := json.Unmarshal(payload, &arg)
 	arg.Links.CdnsLink.ID = cdn
@@ -147,7 +146,7 @@ func putCrconfigSnapshot(cdn string, createdAt time.Time, payload []byte, db *sq
 // @Success 200 {array}    CrconfigSnapshots
 // @Resource /api/2.0
 // @Router /api/2.0/crconfig_snapshots/{id} [delete]
-func delCrconfigSnapshot(cdn string, createdAt time.Time, db *sqlx.DB) (interface{}, error) {
+func delCrconfigSnapshot(cdn string, createdAt time.Time, db DB) (interface{}, error) {
 	arg := CrconfigSnapshots{}
 	arg.Links.CdnsLink.ID = cdn
 	arg.CreatedAt = createdAt
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	null "gopkg.in/guregu/null.v3"
 	"log"
 	"time"
@@ -81,7 +80,7 @@ type DeliveryservicesLinks struct {
 // @Success 200 {array}    Deliveryservices
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices/{id} [get]
-func getDeliveryservice(name string, db *sqlx.DB) (interface{}, error) {
+func getDeliveryservice(name string, db DB) (interface{}, error) {
 	ret := []Deliveryservices{}
 	arg := Deliveryservices{}
 	arg.Name = name
@@ -107,7 +106,7 @@ func getDeliveryservice(name string, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Deliveryservices
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices [get]
-func getDeliveryservices(db *sqlx.DB) (interface{}, error) {
+func getDeliveryservices(db DB) (interface{}, error) {
 	ret := []Deliveryservices{}
 	queryStr := "select *, concat('" + API_PATH + "deliveryservices/', name) as self"
 	queryStr += ", concat('" + API_PATH + "cdns/', cdn) as cdns_name_ref"
@@ -130,7 +129,7 @@ func getDeliveryservices(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices [post]
-func postDeliveryservice(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postDeliveryservice(payload []byte, db DB) (interface{}, error) {
 	var v Deliveryservices
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -238,7 +237,7 @@ func postDeliveryservice(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices/{id}  [put]
-func putDeliveryservice(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putDeliveryservice(name string, payload []byte, db DB) (interface{}, error) {
 	var arg Deliveryservices
 	err := json.Unmarshal(payload, &arg)
 	arg.Name = name
@@ -304,7 +303,7 @@ func putDeliveryservice(name string, payload []byte, db *sqlx.DB) (interface{},
 // @Success 200 {array}    Deliveryservices
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices/{id} [delete]
-func delDeliveryservice(name string, db *sqlx.DB) (interface{}, error) {
+func delDeliveryservice(name string, db DB) (interface{}, error) {
 	arg := Deliveryservices{}
 	arg.Name = name
 	result, err := db.NamedExec("DELETE FROM deliveryservices WHERE name=:name", arg)
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	null "gopkg.in/guregu/null.v3"
 	"log"
 )
@@ -42,7 +41,7 @@ type DeliveryservicesRegexesLinks struct {
 // @Success 200 {array}    DeliveryservicesRegexes
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_regexes/{id} [get]
-func getDeliveryservicesRegex(deliveryservice string, regexId int64, db *sqlx.DB) (interface{}, error) {
+func getDeliveryservicesRegex(deliveryservice string, regexId int64, db DB) (interface{}, error) {
 	ret := []DeliveryservicesRegexes{}
 	arg := DeliveryservicesRegexes{}
 	arg.Deliveryservice = deliveryservice
@@ -65,7 +64,7 @@ func getDeliveryservicesRegex(deliveryservice string, regexId int64, db *sqlx.DB
 // @Success 200 {array}    DeliveryservicesRegexes
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_regexes [get]
-func getDeliveryservicesRegexes(db *sqlx.DB) (interface{}, error) {
+func getDeliveryservicesRegexes(db DB) (interface{}, error) {
 	ret := []DeliveryservicesRegexes{}
 	queryStr := "select *, concat('" + API_PATH + "deliveryservices_regexes', '/deliveryservice/', deliveryservice, '/regex_id/', regex_id) as self"
 	queryStr += " from deliveryservices_regexes"
@@ -84,7 +83,7 @@ func getDeliveryservicesRegexes(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_regexes [post]
-func postDeliveryservicesRegex(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postDeliveryservicesRegex(payload []byte, db DB) (interface{}, error) {
 	var v DeliveryservicesRegexes
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -116,7 +115,7 @@ func postDeliveryservicesRegex(payload []byte, db *sqlx.DB) (interface{}, error)
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_regexes/{id}  [put]
-func putDeliveryservicesRegex(deliveryservice string, regexId int64, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putDeliveryservicesRegex(deliveryservice string, regexId int64, payload []byte, db DB) (interface{}, error) {
 	var arg DeliveryservicesRegexes
 	err := json.Unmarshal(payload, &arg)
 	arg.Deliveryservice = deliveryservice
@@ -145,7 +144,7 @@ func putDeliveryservicesRegex(deliveryservice string, regexId int64, payload []b
 // @Success 200 {array}    DeliveryservicesRegexes
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_regexes/{id} [delete]
-func delDeliveryservicesRegex(deliveryservice string, regexId int64, db *sqlx.DB) (interface{}, error) {
+func delDeliveryservicesRegex(deliveryservice string, regexId int64, db DB) (interface{}, error) {
 	arg := DeliveryservicesRegexes{}
 	arg.Deliveryservice = deliveryservice
 	arg.RegexId = regexId
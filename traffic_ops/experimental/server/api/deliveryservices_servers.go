@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -42,7 +41,7 @@ type DeliveryservicesServersLinks struct {
 // @Success 200 {array}    DeliveryservicesServers
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_servers/{id} [get]
-func getDeliveryservicesServer(deliveryservice string, server string, db *sqlx.DB) (interface{}, error) {
+func getDeliveryservicesServer(deliveryservice string, server string, db DB) (interface{}, error) {
 	ret := []DeliveryservicesServers{}
 	arg := DeliveryservicesServers{}
 	arg.Deliveryservice = deliveryservice
@@ -65,7 +64,7 @@ func getDeliveryservicesServer(deliveryservice string, server string, db *sqlx.D
 // @Success 200 {array}    DeliveryservicesServers
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_servers [get]
-func getDeliveryservicesServers(db *sqlx.DB) (interface{}, error) {
+func getDeliveryservicesServers(db DB) (interface{}, error) {
 	ret := []DeliveryservicesServers{}
 	queryStr := "select *, concat('" + API_PATH + "deliveryservices_servers', '/deliveryservice/', deliveryservice, '/server/', server) as self"
 	queryStr += " from deliveryservices_servers"
@@ -84,7 +83,7 @@ func getDeliveryservicesServers(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_servers [post]
-func postDeliveryservicesServer(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postDeliveryservicesServer(payload []byte, db DB) (interface{}, error) {
 	var v DeliveryservicesServers
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -116,7 +115,7 @@ func postDeliveryservicesServer(payload []byte, db *sqlx.DB) (interface{}, error
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_servers/{id}  [put]
-func putDeliveryservicesServer(deliveryservice string, server string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putDeliveryservicesServer(deliveryservice string, server string, payload []byte, db DB) (interface{}, error) {
 	var arg DeliveryservicesServers
 	err := json.Unmarshal(payload, &arg)
 	arg.Deliveryservice = deliveryservice
@@ -145,7 +144,7 @@ func putDeliveryservicesServer(deliveryservice string, server string, payload []
 // @Success 200 {array}    DeliveryservicesServers
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_servers/{id} [delete]
-func delDeliveryservicesServer(deliveryservice string, server string, db *sqlx.DB) (interface{}, error) {
+func delDeliveryservicesServer(deliveryservice string, server string, db DB) (interface{}, error) {
 	arg := DeliveryservicesServers{}
 	arg.Deliveryservice = deliveryservice
 	arg.Server = server
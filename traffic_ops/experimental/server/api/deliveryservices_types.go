@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -47,7 +46,7 @@ type DeliveryservicesTypesLink struct {
 // @Success 200 {array}    DeliveryservicesTypes
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_types/{id} [get]
-func getDeliveryservicesType(name string, db *sqlx.DB) (interface{}, error) {
+func getDeliveryservicesType(name string, db DB) (interface{}, error) {
 	ret := []DeliveryservicesTypes{}
 	arg := DeliveryservicesTypes{}
 	arg.Name = name
@@ -69,7 +68,7 @@ func getDeliveryservicesType(name string, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    DeliveryservicesTypes
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_types [get]
-func getDeliveryservicesTypes(db *sqlx.DB) (interface{}, error) {
+func getDeliveryservicesTypes(db DB) (interface{}, error) {
 	ret := []DeliveryservicesTypes{}
 	queryStr := "select *, concat('" + API_PATH + "deliveryservices_types/', name) as self"
 	queryStr += " from deliveryservices_types"
@@ -88,7 +87,7 @@ func getDeliveryservicesTypes(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_types [post]
-func postDeliveryservicesType(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postDeliveryservicesType(payload []byte, db DB) (interface{}, error) {
 	var v DeliveryservicesTypes
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -120,7 +119,7 @@ func postDeliveryservicesType(payload []byte, db *sqlx.DB) (interface{}, error)
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_types/{id}  [put]
-func putDeliveryservicesType(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putDeliveryservicesType(name string, payload []byte, db DB) (interface{}, error) {
 	var arg DeliveryservicesTypes
 	err := json.Unmarshal(payload, &arg)
 	arg.Name = name
@@ -148,7 +147,7 @@ func putDeliveryservicesType(name string, payload []byte, db *sqlx.DB) (interfac
 // @Success 200 {array}    DeliveryservicesTypes
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_types/{id} [delete]
-func delDeliveryservicesType(name string, db *sqlx.DB) (interface{}, error) {
+func delDeliveryservicesType(name string, db DB) (interface{}, error) {
 	arg := DeliveryservicesTypes{}
 	arg.Name = name
 	result, err := db.NamedExec("DELETE FROM deliveryservices_types WHERE name=:name", arg)
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -42,7 +41,7 @@ type DeliveryservicesUsersLinks struct {
 // @Success 200 {array}    DeliveryservicesUsers
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_users/{id} [get]
-func getDeliveryservicesUser(deliveryservice string, username string, db *sqlx.DB) (interface{}, error) {
+func getDeliveryservicesUser(deliveryservice string, username string, db DB) (interface{}, error) {
 	ret := []DeliveryservicesUsers{}
 	arg := DeliveryservicesUsers{}
 	arg.Deliveryservice = deliveryservice
@@ -65,7 +64,7 @@ func getDeliveryservicesUser(deliveryservice string, username string, db *sqlx.D
 // @Success 200 {array}    DeliveryservicesUsers
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_users [get]
-func getDeliveryservicesUsers(db *sqlx.DB) (interface{}, error) {
+func getDeliveryservicesUsers(db DB) (interface{}, error) {
 	ret := []DeliveryservicesUsers{}
 	queryStr := "select *, concat('" + API_PATH + "deliveryservices_users', '/deliveryservice/', deliveryservice, '/username/', username) as self"
 	queryStr += " from deliveryservices_users"
@@ -84,7 +83,7 @@ func getDeliveryservicesUsers(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_users [post]
-func postDeliveryservicesUser(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postDeliveryservicesUser(payload []byte, db DB) (interface{}, error) {
 	var v DeliveryservicesUsers
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -116,7 +115,7 @@ func postDeliveryservicesUser(payload []byte, db *sqlx.DB) (interface{}, error)
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_users/{id}  [put]
-func putDeliveryservicesUser(deliveryservice string, username string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putDeliveryservicesUser(deliveryservice string, username string, payload []byte, db DB) (interface{}, error) {
 	var arg DeliveryservicesUsers
 	err := json.Unmarshal(payload, &arg)
 	arg.Deliveryservice = deliveryservice
@@ -145,7 +144,7 @@ func putDeliveryservicesUser(deliveryservice string, username string, payload []
 // @Success 200 {array}    DeliveryservicesUsers
 // @Resource /api/2.0
 // @Router /api/2.0/deliveryservices_users/{id} [delete]
-func delDeliveryservicesUser(deliveryservice string, username string, db *sqlx.DB) (interface{}, error) {
+func delDeliveryservicesUser(deliveryservice string, username string, db DB) (interface{}, error) {
 	arg := DeliveryservicesUsers{}
 	arg.Deliveryservice = deliveryservice
 	arg.Username = username
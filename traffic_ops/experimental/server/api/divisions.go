@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -41,7 +40,7 @@ type DivisionsLinks struct {
 // @Success 200 {array}    Divisions
 // @Resource /api/2.0
 // @Router /api/2.0/divisions/{id} [get]
-func getDivision(name string, db *sqlx.DB) (interface{}, error) {
+func getDivision(name string, db DB) (interface{}, error) {
 	ret := []Divisions{}
 	arg := Divisions{}
 	arg.Name = name
@@ -63,7 +62,7 @@ func getDivision(name string, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Divisions
 // @Resource /api/2.0
 // @Router /api/2.0/divisions [get]
-func getDivisions(db *sqlx.DB) (interface{}, error) {
+func getDivisions(db DB) (interface{}, error) {
 	ret := []Divisions{}
 	queryStr := "select *, concat('" + API_PATH + "divisions/', name) as self"
 	queryStr += " from divisions"
@@ -82,7 +81,7 @@ func getDivisions(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/divisions [post]
-func postDivision(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postDivision(payload []byte, db DB) (interface{}, error) {
 	var v Divisions
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -112,7 +111,7 @@ func postDivision(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/divisions/{id}  [put]
-func putDivision(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putDivision(name string, payload []byte, db DB) (interface{}, error) {
 	var arg Divisions
 	err := json.Unmarshal(payload, &arg)
 	arg.Name = name
@@ -139,7 +138,7 @@ func putDivision(name string, payload []byte, db *sqlx.DB) (interface{}, error)
 // @Success 200 {array}    Divisions
 // @Resource /api/2.0
 // @Router /api/2.0/divisions/{id} [delete]
-func delDivision(name string, db *sqlx.DB) (interface{}, error) {
+func delDivision(name string, db DB) (interface{}, error) {
 	arg := Divisions{}
 	arg.Name = name
 	result, err := db.NamedExec("DELETE FROM divisions WHERE name=:name", arg)
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	null "gopkg.in/guregu/null.v3"
 	"log"
 	"time"
@@ -76,7 +75,7 @@ type DomainsLink struct {
 // @Success 200 {array}    Domains
 // @Resource /api/2.0
 // @Router /api/2.0/domains/{id} [get]
-func getDomain(name string, db *sqlx.DB) (interface{}, error) {
+func getDomain(name string, db DB) (interface{}, error) {
 	ret := []Domains{}
 	arg := Domains{}
 	arg.Name = name
@@ -99,7 +98,7 @@ func getDomain(name string, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Domains
 // @Resource /api/2.0
 // @Router /api/2.0/domains [get]
-func getDomains(db *sqlx.DB) (interface{}, error) {
+func getDomains(db DB) (interface{}, error) {
 	ret := []Domains{}
 	queryStr := "select *, concat('" + API_PATH + "domains/', name) as self"
 	queryStr += ", concat('" + API_PATH + "cdns/', cdn) as cdns_name_ref"
@@ -119,7 +118,7 @@ func getDomains(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/domains [post]
-func postDomain(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postDomain(payload []byte, db DB) (interface{}, error) {
 	var v Domains
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -207,7 +206,7 @@ func postDomain(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/domains/{id}  [put]
-func putDomain(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putDomain(name string, payload []byte, db DB) (interface{}, error) {
 	var arg Domains
 	err := json.Unmarshal(payload, &arg)
 	arg.Name = name
@@ -263,7 +262,7 @@ func putDomain(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Domains
 // @Resource /api/2.0
 // @Router /api/2.0/domains/{id} [delete]
-func delDomain(name string, db *sqlx.DB) (interface{}, error) {
+func delDomain(name string, db DB) (interface{}, error) {
 	arg := Domains{}
 	arg.Name = name
 	result, err := db.NamedExec("DELETE FROM domains WHERE name=:name", arg)
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	null "gopkg.in/guregu/null.v3"
 	"log"
 	"time"
@@ -50,7 +49,7 @@ type ExtensionsLinks struct {
 // @Success 200 {array}    Extensions
 // @Resource /api/2.0
 // @Router /api/2.0/extensions/{id} [get]
-func getExtension(name string, db *sqlx.DB) (interface{}, error) {
+func getExtension(name string, db DB) (interface{}, error) {
 	ret := []Extensions{}
 	arg := Extensions{}
 	arg.Name = name
@@ -73,7 +72,7 @@ func getExtension(name string, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Extensions
 // @Resource /api/2.0
 // @Router /api/2.0/extensions [get]
-func getExtensions(db *sqlx.DB) (interface{}, error) {
+func getExtensions(db DB) (interface{}, error) {
 	ret := []Extensions{}
 	queryStr := "select *, concat('" + API_PATH + "extensions/', name) as self"
 	queryStr += ", concat('" + API_PATH + "extensions_types/', type) as extensions_types_name_ref"
@@ -93,7 +92,7 @@ func getExtensions(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/extensions [post]
-func postExtension(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postExtension(payload []byte, db DB) (interface{}, error) {
 	var v Extensions
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -139,7 +138,7 @@ func postExtension(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/extensions/{id}  [put]
-func putExtension(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putExtension(name string, payload []byte, db DB) (interface{}, error) {
 	var arg Extensions
 	err := json.Unmarshal(payload, &arg)
 	arg.Name = name
@@ -174,7 +173,7 @@ func putExtension(name string, payload []byte, db *sqlx.DB) (interface{}, error)
 // @Success 200 {array}    Extensions
 // @Resource /api/2.0
 // @Router /api/2.0/extensions/{id} [delete]
-func delExtension(name string, db *sqlx.DB) (interface{}, error) {
+func delExtension(name string, db DB) (interface{}, error) {
 	arg := Extensions{}
 	arg.Name = name
 	result, err := db.NamedExec("DELETE FROM extensions WHERE name=:name", arg)
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -47,7 +46,7 @@ type ExtensionsTypesLink struct {
 // @Success 200 {array}    ExtensionsTypes
 // @Resource /api/2.0
 // @Router /api/2.0/extensions_types/{id} [get]
-func getExtensionsType(name string, db *sqlx.DB) (interface{}, error) {
+func getExtensionsType(name string, db DB) (interface{}, error) {
 	ret := []ExtensionsTypes{}
 	arg := ExtensionsTypes{}
 	arg.Name = name
@@ -69,7 +68,7 @@ func getExtensionsType(name string, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    ExtensionsTypes
 // @Resource /api/2.0
 // @Router /api/2.0/extensions_types [get]
-func getExtensionsTypes(db *sqlx.DB) (interface{}, error) {
+func getExtensionsTypes(db DB) (interface{}, error) {
 	ret := []ExtensionsTypes{}
 	queryStr := "select *, concat('" + API_PATH + "extensions_types/', name) as self"
 	queryStr += " from extensions_types"
@@ -88,7 +87,7 @@ func getExtensionsTypes(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/extensions_types [post]
-func postExtensionsType(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postExtensionsType(payload []byte, db DB) (interface{}, error) {
 	var v ExtensionsTypes
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -120,7 +119,7 @@ func postExtensionsType(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/extensions_types/{id}  [put]
-func putExtensionsType(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putExtensionsType(name string, payload []byte, db DB) (interface{}, error) {
 	var arg ExtensionsTypes
 	err := json.Unmarshal(payload, &arg)
 	arg.Name = name
@@ -148,7 +147,7 @@ func putExtensionsType(name string, payload []byte, db *sqlx.DB) (interface{}, e
 // @Success 200 {array}    ExtensionsTypes
 // @Resource /api/2.0
 // @Router /api/2.0/extensions_types/{id} [delete]
-func delExtensionsType(name string, db *sqlx.DB) (interface{}, error) {
+func delExtensionsType(name string, db DB) (interface{}, error) {
 	arg := ExtensionsTypes{}
 	arg.Name = name
 	result, err := db.NamedExec("DELETE FROM extensions_types WHERE name=:name", arg)
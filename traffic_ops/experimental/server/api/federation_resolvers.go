@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -43,7 +42,7 @@ type FederationResolversLinks struct {
 // @Success 200 {array}    FederationResolvers
 // @Resource /api/2.0
 // @Router /api/2.0/federation_resolvers/{id} [get]
-func getFederationResolver(id int64, db *sqlx.DB) (interface{}, error) {
+func getFederationResolver(id int64, db DB) (interface{}, error) {
 	ret := []FederationResolvers{}
 	arg := FederationResolvers{}
 	arg.Id = id
@@ -65,7 +64,7 @@ func getFederationResolver(id int64, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    FederationResolvers
 // @Resource /api/2.0
 // @Router /api/2.0/federation_resolvers [get]
-func getFederationResolvers(db *sqlx.DB) (interface{}, error) {
+func getFederationResolvers(db DB) (interface{}, error) {
 	ret := []FederationResolvers{}
 	queryStr := "select *, concat('" + API_PATH + "federation_resolvers/', id) as self"
 	queryStr += " from federation_resolvers"
@@ -84,7 +83,7 @@ func getFederationResolvers(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/federation_resolvers [post]
-func postFederationResolver(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postFederationResolver(payload []byte, db DB) (interface{}, error) {
 	var v FederationResolvers
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -116,7 +115,7 @@ func postFederationResolver(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/federation_resolvers/{id}  [put]
-func putFederationResolver(id int64, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putFederationResolver(id int64, payload []byte, db DB) (interface{}, error) {
 	var arg FederationResolvers
 	err := json.Unmarshal(payload, &arg)
 	arg.Id = id
@@ -144,7 +143,7 @@ func putFederationResolver(id int64, payload []byte, db *sqlx.DB) (interface{},
 // @Success 200 {array}    FederationResolvers
 // @Resource /api/2.0
 // @Router /api/2.0/federation_resolvers/{id} [delete]
-func delFederationResolver(id int64, db *sqlx.DB) (interface{}, error) {
+func delFederationResolver(id int64, db DB) (interface{}, error) {
 	arg := FederationResolvers{}
 	arg.Id = id
 	result, err := db.NamedExec("DELETE FROM federation_resolvers WHERE id=:id", arg)
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -43,7 +42,7 @@ type FederationUsersLinks struct {
 // @Success 200 {array}    FederationUsers
 // @Resource /api/2.0
 // @Router /api/2.0/federation_users/{id} [get]
-func getFederationUser(federationId int64, username string, db *sqlx.DB) (interface{}, error) {
+func getFederationUser(federationId int64, username string, db DB) (interface{}, error) {
 	ret := []FederationUsers{}
 	arg := FederationUsers{}
 	arg.FederationId = federationId
@@ -66,7 +65,7 @@ func getFederationUser(federationId int64, username string, db *sqlx.DB) (interf
 // @Success 200 {array}    FederationUsers
 // @Resource /api/2.0
 // @Router /api/2.0/federation_users [get]
-func getFederationUsers(db *sqlx.DB) (interface{}, error) {
+func getFederationUsers(db DB) (interface{}, error) {
 	ret := []FederationUsers{}
 	queryStr := "select *, concat('" + API_PATH + "federation_users', '/federation_id/', federation_id, '/username/', username) as self"
 	queryStr += " from federation_users"
@@ -85,7 +84,7 @@ func getFederationUsers(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/federation_users [post]
-func postFederationUser(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postFederationUser(payload []byte, db DB) (interface{}, error) {
 	var v FederationUsers
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -119,7 +118,7 @@ func postFederationUser(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/federation_users/{id}  [put]
-func putFederationUser(federationId int64, username string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putFederationUser(federationId int64, username string, payload []byte, db DB) (interface{}, error) {
 	var arg FederationUsers
 	err := json.Unmarshal(payload, &arg)
 	arg.FederationId = federationId
@@ -149,7 +148,7 @@ func putFederationUser(federationId int64, username string, payload []byte, db *
 // @Success 200 {array}    FederationUsers
 // @Resource /api/2.0
 // @Router /api/2.0/federation_users/{id} [delete]
-func delFederationUser(federationId int64, username string, db *sqlx.DB) (interface{}, error) {
+func delFederationUser(federationId int64, username string, db DB) (interface{}, error) {
 	arg := FederationUsers{}
 	arg.FederationId = federationId
 	arg.Username = username
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	null "gopkg.in/guregu/null.v3"
 	"log"
 	"time"
@@ -45,7 +44,7 @@ type FederationsLinks struct {
 // @Success 200 {array}    Federations
 // @Resource /api/2.0
 // @Router /api/2.0/federations/{id} [get]
-func getFederation(id int64, db *sqlx.DB) (interface{}, error) {
+func getFederation(id int64, db DB) (interface{}, error) {
 	ret := []Federations{}
 	arg := Federations{}
 	arg.Id = id
@@ -67,7 +66,7 @@ func getFederation(id int64, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Federations
 // @Resource /api/2.0
 // @Router /api/2.0/federations [get]
-func getFederations(db *sqlx.DB) (interface{}, error) {
+func getFederations(db DB) (interface{}, error) {
 	ret := []Federations{}
 	queryStr := "select *, concat('" + API_PATH + "federations/', id) as self"
 	queryStr += " from federations"
@@ -86,7 +85,7 @@ func getFederations(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/federations [post]
-func postFederation(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postFederation(payload []byte, db DB) (interface{}, error) {
 	var v Federations
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -120,7 +119,7 @@ func postFederation(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/federations/{id}  [put]
-func putFederation(id int64, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putFederation(id int64, payload []byte, db DB) (interface{}, error) {
 	var arg Federations
 	err := json.Unmarshal(payload, &arg)
 	arg.Id = id
@@ -149,7 +148,7 @@ func putFederation(id int64, payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Federations
 // @Resource /api/2.0
 // @Router /api/2.0/federations/{id} [delete]
-func delFederation(id int64, db *sqlx.DB) (interface{}, error) {
+func delFederation(id int64, db DB) (interface{}, error) {
 	arg := Federations{}
 	arg.Id = id
 	result, err := db.NamedExec("DELETE FROM federations WHERE id=:id", arg)
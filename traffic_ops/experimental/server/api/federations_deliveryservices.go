@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -42,7 +41,7 @@ type FederationsDeliveryservicesLinks struct {
 // @Success 200 {array}    FederationsDeliveryservices
 // @Resource /api/2.0
 // @Router /api/2.0/federations_deliveryservices/{id} [get]
-func getFederationsDeliveryservice(federationId int64, deliveryservice string, db *sqlx.DB) (interface{}, error) {
+func getFederationsDeliveryservice(federationId int64, deliveryservice string, db DB) (interface{}, error) {
 	ret := []FederationsDeliveryservices{}
 	arg := FederationsDeliveryservices{}
 	arg.FederationId = federationId
@@ -65,7 +64,7 @@ func getFederationsDeliveryservice(federationId int64, deliveryservice string, d
 // @Success 200 {array}    FederationsDeliveryservices
 // @Resource /api/2.0
 // @Router /api/2.0/federations_deliveryservices [get]
-func getFederationsDeliveryservices(db *sqlx.DB) (interface{}, error) {
+func getFederationsDeliveryservices(db DB) (interface{}, error) {
 	ret := []FederationsDeliveryservices{}
 	queryStr := "select *, concat('" + API_PATH + "federations_deliveryservices', '/federation_id/', federation_id, '/deliveryservice/', deliveryservice) as self"
 	queryStr += " from federations_deliveryservices"
@@ -84,7 +83,7 @@ func getFederationsDeliveryservices(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/federations_deliveryservices [post]
-func postFederationsDeliveryservice(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postFederationsDeliveryservice(payload []byte, db DB) (interface{}, error) {
 	var v FederationsDeliveryservices
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -116,7 +115,7 @@ func postFederationsDeliveryservice(payload []byte, db *sqlx.DB) (interface{}, e
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/federations_deliveryservices/{id}  [put]
-func putFederationsDeliveryservice(federationId int64, deliveryservice string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putFederationsDeliveryservice(federationId int64, deliveryservice string, payload []byte, db DB) (interface{}, error) {
 	var arg FederationsDeliveryservices
 	err := json.Unmarshal(payload, &arg)
 	arg.FederationId = federationId
@@ -145,7 +144,7 @@ func putFederationsDeliveryservice(federationId int64, deliveryservice string, p
 // @Success 200 {array}    FederationsDeliveryservices
 // @Resource /api/2.0
 // @Router /api/2.0/federations_deliveryservices/{id} [delete]
-func delFederationsDeliveryservice(federationId int64, deliveryservice string, db *sqlx.DB) (interface{}, error) {
+func delFederationsDeliveryservice(federationId int64, deliveryservice string, db DB) (interface{}, error) {
 	arg := FederationsDeliveryservices{}
 	arg.FederationId = federationId
 	arg.Deliveryservice = deliveryservice
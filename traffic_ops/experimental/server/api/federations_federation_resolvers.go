@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -42,7 +41,7 @@ type FederationsFederationResolversLinks struct {
 // @Success 200 {array}    FederationsFederationResolvers
 // @Resource /api/2.0
 // @Router /api/2.0/federations_federation_resolvers/{id} [get]
-func getFederationsFederationResolver(federationId int64, federationResolver int64, db *sqlx.DB) (interface{}, error) {
+func getFederationsFederationResolver(federationId int64, federationResolver int64, db DB) (interface{}, error) {
 	ret := []FederationsFederationResolvers{}
 	arg := FederationsFederationResolvers{}
 	arg.FederationId = federationId
@@ -65,7 +64,7 @@ func getFederationsFederationResolver(federationId int64, federationResolver int
 // @Success 200 {array}    FederationsFederationResolvers
 // @Resource /api/2.0
 // @Router /api/2.0/federations_federation_resolvers [get]
-func getFederationsFederationResolvers(db *sqlx.DB) (interface{}, error) {
+func getFederationsFederationResolvers(db DB) (interface{}, error) {
 	ret := []FederationsFederationResolvers{}
 	queryStr := "select *, concat('" + API_PATH + "federations_federation_resolvers', '/federation_id/', federation_id, '/federation_resolver/', federation_resolver) as self"
 	queryStr += " from federations_federation_resolvers"
@@ -84,7 +83,7 @@ func getFederationsFederationResolvers(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/federations_federation_resolvers [post]
-func postFederationsFederationResolver(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postFederationsFederationResolver(payload []byte, db DB) (interface{}, error) {
 	var v FederationsFederationResolvers
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -116,7 +115,7 @@ func postFederationsFederationResolver(payload []byte, db *sqlx.DB) (interface{}
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/federations_federation_resolvers/{id}  [put]
-func putFederationsFederationResolver(federationId int64, federationResolver int64, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putFederationsFederationResolver(federationId int64, federationResolver int64, payload []byte, db DB) (interface{}, error) {
 	var arg FederationsFederationResolvers
 	err := json.Unmarshal(payload, &arg)
 	arg.FederationId = federationId
@@ -145,7 +144,7 @@ func putFederationsFederationResolver(federationId int64, federationResolver int
 // @Success 200 {array}    FederationsFederationResolvers
 // @Resource /api/2.0
 // @Router /api/2.0/federations_federation_resolvers/{id} [delete]
-func delFederationsFederationResolver(federationId int64, federationResolver int64, db *sqlx.DB) (interface{}, error) {
+func delFederationsFederationResolver(federationId int64, federationResolver int64, db DB) (interface{}, error) {
 	arg := FederationsFederationResolvers{}
 	arg.FederationId = federationId
 	arg.FederationResolver = federationResolver
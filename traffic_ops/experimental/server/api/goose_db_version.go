@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	null "gopkg.in/guregu/null.v3"
 	"log"
 	"time"
@@ -40,7 +39,7 @@ type GooseDbVersion struct {
 // @Success 200 {array}    GooseDbVersion
 // @Resource /api/2.0
 // @Router /api/2.0/goose_db_version/{id} [get]
-func getGooseDbVersionById(id int, db *sqlx.DB) (interface{}, error) {
+func getGooseDbVersionById(id int, db DB) (interface{}, error) {
 	ret := []GooseDbVersion{}
 	arg := GooseDbVersion{Id: int64(id)}
 	nstmt, err := db.PrepareNamed(`select * from goose_db_version where id=:id`)
@@ -59,7 +58,7 @@ func getGooseDbVersionById(id int, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    GooseDbVersion
 // @Resource /api/2.0
 // @Router /api/2.0/goose_db_version [get]
-func getGooseDbVersions(db *sqlx.DB) (interface{}, error) {
+func getGooseDbVersions(db DB) (interface{}, error) {
 	ret := []GooseDbVersion{}
 	queryStr := "select * from goose_db_version"
 	err := db.Select(&ret, queryStr)
@@ -77,7 +76,7 @@ func getGooseDbVersions(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/goose_db_version [post]
-func postGooseDbVersion(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postGooseDbVersion(payload []byte, db DB) (interface{}, error) {
 	var v GooseDbVersion
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -110,7 +109,7 @@ func postGooseDbVersion(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/goose_db_version/{id}  [put]
-func putGooseDbVersion(id int, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putGooseDbVersion(id int, payload []byte, db DB) (interface{}, error) {
 	var v GooseDbVersion
 	err := json.Unmarshal(payload, &v)
 	v.Id = int64(id) // overwrite the id in the payload
@@ -139,7 +138,7 @@ func putGooseDbVersion(id int, payload []byte, db *sqlx.DB) (interface{}, error)
 // @Success 200 {array}    GooseDbVersion
 // @Resource /api/2.0
 // @Router /api/2.0/goose_db_version/{id} [delete]
-func delGooseDbVersion(id int, db *sqlx.DB) (interface{}, error) {
+func delGooseDbVersion(id int, db DB) (interface{}, error) {
 	arg := GooseDbVersion{Id: int64(id)}
 	result, err := db.NamedExec("DELETE FROM goose_db_version WHERE id=:id", arg)
 	if err != nil {
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	null "gopkg.in/guregu/null.v3"
 	"log"
 	"time"
@@ -46,7 +45,7 @@ type LogLinks struct {
 // @Success 200 {array}    Log
 // @Resource /api/2.0
 // @Router /api/2.0/log/{id} [get]
-func getLog(id int64, db *sqlx.DB) (interface{}, error) {
+func getLog(id int64, db DB) (interface{}, error) {
 	ret := []Log{}
 	arg := Log{}
 	arg.Id = id
@@ -68,7 +67,7 @@ func getLog(id int64, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Log
 // @Resource /api/2.0
 // @Router /api/2.0/log [get]
-func getLogs(db *sqlx.DB) (interface{}, error) {
+func getLogs(db DB) (interface{}, error) {
 	ret := []Log{}
 	queryStr := "select *, concat('" + API_PATH + "log/', id) as self"
 	queryStr += " from log"
@@ -87,7 +86,7 @@ func getLogs(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/log [post]
-func postLog(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postLog(payload []byte, db DB) (interface{}, error) {
 	var v Log
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -123,7 +122,7 @@ func postLog(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/log/{id}  [put]
-func putLog(id int64, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putLog(id int64, payload []byte, db DB) (interface{}, error) {
 	var arg Log
 	err := json.Unmarshal(payload, &arg)
 	arg.Id = id
@@ -153,7 +152,7 @@ func putLog(id int64, payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Log
 // @Resource /api/2.0
 // @Router /api/2.0/log/{id} [delete]
-func delLog(id int64, db *sqlx.DB) (interface{}, error) {
+func delLog(id int64, db DB) (interface{}, error) {
 	arg := Log{}
 	arg.Id = id
 	result, err := db.NamedExec("DELETE FROM log WHERE id=:id", arg)
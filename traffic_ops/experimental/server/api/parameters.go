@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	null "gopkg.in/guregu/null.v3"
 	"log"
 	"time"
@@ -82,7 +81,7 @@ type ParametersLink struct {
 // @Success 200 {array}    Parameters
 // @Resource /api/2.0
 // @Router /api/2.0/parameters/{id} [get]
-func getParameter(id int64, db *sqlx.DB) (interface{}, error) {
+func getParameter(id int64, db DB) (interface{}, error) {
 	ret := []Parameters{}
 	arg := Parameters{}
 	arg.Id = id
@@ -104,7 +103,7 @@ func getParameter(id int64, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Parameters
 // @Resource /api/2.0
 // @Router /api/2.0/parameters [get]
-func getParameters(db *sqlx.DB) (interface{}, error) {
+func getParameters(db DB) (interface{}, error) {
 	ret := []Parameters{}
 	queryStr := "select *, concat('" + API_PATH + "parameters/', id) as self"
 	queryStr += " from parameters"
@@ -123,7 +122,7 @@ func getParameters(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/parameters [post]
-func postParameter(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postParameter(payload []byte, db DB) (interface{}, error) {
 	var v Parameters
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -221,7 +220,7 @@ func postParameter(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/parameters/{id}  [put]
-func putParameter(id int64, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putParameter(id int64, payload []byte, db DB) (interface{}, error) {
 	var arg Parameters
 	err := json.Unmarshal(payload, &arg)
 	arg.Id = id
@@ -282,7 +281,7 @@ func putParameter(id int64, payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Parameters
 // @Resource /api/2.0
 // @Router /api/2.0/parameters/{id} [delete]
-func delParameter(id int64, db *sqlx.DB) (interface{}, error) {
+func delParameter(id int64, db DB) (interface{}, error) {
 	arg := Parameters{}
 	arg.Id = id
 	result, err := db.NamedExec("DELETE FROM parameters WHERE id=:id", arg)
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	null "gopkg.in/guregu/null.v3"
 	"log"
 	"time"
@@ -52,7 +51,7 @@ type PhysLocationsLinks struct {
 // @Success 200 {array}    PhysLocations
 // @Resource /api/2.0
 // @Router /api/2.0/phys_locations/{id} [get]
-func getPhysLocation(name string, db *sqlx.DB) (interface{}, error) {
+func getPhysLocation(name string, db DB) (interface{}, error) {
 	ret := []PhysLocations{}
 	arg := PhysLocations{}
 	arg.Name = name
@@ -74,7 +73,7 @@ func getPhysLocation(name string, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    PhysLocations
 // @Resource /api/2.0
 // @Router /api/2.0/phys_locations [get]
-func getPhysLocations(db *sqlx.DB) (interface{}, error) {
+func getPhysLocations(db DB) (interface{}, error) {
 	ret := []PhysLocations{}
 	queryStr := "select *, concat('" + API_PATH + "phys_locations/', name) as self"
 	queryStr += " from phys_locations"
@@ -93,7 +92,7 @@ func getPhysLocations(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/phys_locations [post]
-func postPhysLocation(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postPhysLocation(payload []byte, db DB) (interface{}, error) {
 	var v PhysLocations
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -143,7 +142,7 @@ func postPhysLocation(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/phys_locations/{id}  [put]
-func putPhysLocation(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putPhysLocation(name string, payload []byte, db DB) (interface{}, error) {
 	var arg PhysLocations
 	err := json.Unmarshal(payload, &arg)
 	arg.Name = name
@@ -180,7 +179,7 @@ func putPhysLocation(name string, payload []byte, db *sqlx.DB) (interface{}, err
 // @Success 200 {array}    PhysLocations
 // @Resource /api/2.0
 // @Router /api/2.0/phys_locations/{id} [delete]
-func delPhysLocation(name string, db *sqlx.DB) (interface{}, error) {
+func delPhysLocation(name string, db DB) (interface{}, error) {
 	arg := PhysLocations{}
 	arg.Name = name
 	result, err := db.NamedExec("DELETE FROM phys_locations WHERE name=:name", arg)
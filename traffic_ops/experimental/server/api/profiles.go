@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -47,7 +46,7 @@ type ProfilesLink struct {
 // @Success 200 {array}    Profiles
 // @Resource /api/2.0
 // @Router /api/2.0/profiles/{id} [get]
-func getProfile(name string, db *sqlx.DB) (interface{}, error) {
+func getProfile(name string, db DB) (interface{}, error) {
 	ret := []Profiles{}
 	arg := Profiles{}
 	arg.Name = name
@@ -69,7 +68,7 @@ func getProfile(name string, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Profiles
 // @Resource /api/2.0
 // @Router /api/2.0/profiles [get]
-func getProfiles(db *sqlx.DB) (interface{}, error) {
+func getProfiles(db DB) (interface{}, error) {
 	ret := []Profiles{}
 	queryStr := "select *, concat('" + API_PATH + "profiles/', name) as self"
 	queryStr += " from profiles"
@@ -88,7 +87,7 @@ func getProfiles(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/profiles [post]
-func postProfile(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postProfile(payload []byte, db DB) (interface{}, error) {
 	var v Profiles
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -120,7 +119,7 @@ func postProfile(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/profiles/{id}  [put]
-func putProfile(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putProfile(name string, payload []byte, db DB) (interface{}, error) {
 	var arg Profiles
 	err := json.Unmarshal(payload, &arg)
 	arg.Name = name
@@ -148,7 +147,7 @@ func putProfile(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Profiles
 // @Resource /api/2.0
 // @Router /api/2.0/profiles/{id} [delete]
-func delProfile(name string, db *sqlx.DB) (interface{}, error) {
+func delProfile(name string, db DB) (interface{}, error) {
 	arg := Profiles{}
 	arg.Name = name
 	result, err := db.NamedExec("DELETE FROM profiles WHERE name=:name", arg)
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -42,7 +41,7 @@ type ProfilesParametersLinks struct {
 // @Success 200 {array}    ProfilesParameters
 // @Resource /api/2.0
 // @Router /api/2.0/profiles_parameters/{id} [get]
-func getProfilesParameter(profile string, parameterId int64, db *sqlx.DB) (interface{}, error) {
+func getProfilesParameter(profile string, parameterId int64, db DB) (interface{}, error) {
 	ret := []ProfilesParameters{}
 	arg := ProfilesParameters{}
 	arg.Profile = profile
@@ -65,7 +64,7 @@ func getProfilesParameter(profile string, parameterId int64, db *sqlx.DB) (inter
 // @Success 200 {array}    ProfilesParameters
 // @Resource /api/2.0
 // @Router /api/2.0/profiles_parameters [get]
-func getProfilesParameters(db *sqlx.DB) (interface{}, error) {
+func getProfilesParameters(db DB) (interface{}, error) {
 	ret := []ProfilesParameters{}
 	queryStr := "select *, concat('" + API_PATH + "profiles_parameters', '/profile/', profile, '/parameter_id/', parameter_id) as self"
 	queryStr += " from profiles_parameters"
@@ -84,7 +83,7 @@ func getProfilesParameters(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/profiles_parameters [post]
-func postProfilesParameter(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postProfilesParameter(payload []byte, db DB) (interface{}, error) {
 	var v ProfilesParameters
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -116,7 +115,7 @@ func postProfilesParameter(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/profiles_parameters/{id}  [put]
-func putProfilesParameter(profile string, parameterId int64, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putProfilesParameter(profile string, parameterId int64, payload []byte, db DB) (interface{}, error) {
 	var arg ProfilesParameters
 	err := json.Unmarshal(payload, &arg)
 	arg.Profile = profile
@@ -145,7 +144,7 @@ func putProfilesParameter(profile string, parameterId int64, payload []byte, db
 // @Success 200 {array}    ProfilesParameters
 // @Resource /api/2.0
 // @Router /api/2.0/profiles_parameters/{id} [delete]
-func delProfilesParameter(profile string, parameterId int64, db *sqlx.DB) (interface{}, error) {
+func delProfilesParameter(profile string, parameterId int64, db DB) (interface{}, error) {
 	arg := ProfilesParameters{}
 	arg.Profile = profile
 	arg.ParameterId = parameterId
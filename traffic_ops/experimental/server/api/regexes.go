@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -43,7 +42,7 @@ type RegexesLinks struct {
 // @Success 200 {array}    Regexes
 // @Resource /api/2.0
 // @Router /api/2.0/regexes/{id} [get]
-func getRegex(id int64, db *sqlx.DB) (interface{}, error) {
+func getRegex(id int64, db DB) (interface{}, error) {
 	ret := []Regexes{}
 	arg := Regexes{}
 	arg.Id = id
@@ -66,7 +65,7 @@ func getRegex(id int64, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Regexes
 // @Resource /api/2.0
 // @Router /api/2.0/regexes [get]
-func getRegexes(db *sqlx.DB) (interface{}, error) {
+func getRegexes(db DB) (interface{}, error) {
 	ret := []Regexes{}
 	queryStr := "select *, concat('" + API_PATH + "regexes/', id) as self"
 	queryStr += ", concat('" + API_PATH + "regexes_types/', type) as regexes_types_name_ref"
@@ -86,7 +85,7 @@ func getRegexes(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/regexes [post]
-func postRegex(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postRegex(payload []byte, db DB) (interface{}, error) {
 	var v Regexes
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -118,7 +117,7 @@ func postRegex(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/regexes/{id}  [put]
-func putRegex(id int64, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putRegex(id int64, payload []byte, db DB) (interface{}, error) {
 	var arg Regexes
 	err := json.Unmarshal(payload, &arg)
 	arg.Id = id
@@ -146,7 +145,7 @@ func putRegex(id int64, payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Regexes
 // @Resource /api/2.0
 // @Router /api/2.0/regexes/{id} [delete]
-func delRegex(id int64, db *sqlx.DB) (interface{}, error) {
+func delRegex(id int64, db DB) (interface{}, error) {
 	arg := Regexes{}
 	arg.Id = id
 	result, err := db.NamedExec("DELETE FROM regexes WHERE id=:id", arg)
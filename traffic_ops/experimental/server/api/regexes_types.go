@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -47,7 +46,7 @@ type RegexesTypesLink struct {
 // @Success 200 {array}    RegexesTypes
 // @Resource /api/2.0
 // @Router /api/2.0/regexes_types/{id} [get]
-func getRegexesType(name string, db *sqlx.DB) (interface{}, error) {
+func getRegexesType(name string, db DB) (interface{}, error) {
 	ret := []RegexesTypes{}
 	arg := RegexesTypes{}
 	arg.Name = name
@@ -69,7 +68,7 @@ func getRegexesType(name string, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    RegexesTypes
 // @Resource /api/2.0
 // @Router /api/2.0/regexes_types [get]
-func getRegexesTypes(db *sqlx.DB) (interface{}, error) {
+func getRegexesTypes(db DB) (interface{}, error) {
 	ret := []RegexesTypes{}
 	queryStr := "select *, concat('" + API_PATH + "regexes_types/', name) as self"
 	queryStr += " from regexes_types"
@@ -88,7 +87,7 @@ func getRegexesTypes(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/regexes_types [post]
-func postRegexesType(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postRegexesType(payload []byte, db DB) (interface{}, error) {
 	var v RegexesTypes
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -120,7 +119,7 @@ func postRegexesType(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/regexes_types/{id}  [put]
-func putRegexesType(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putRegexesType(name string, payload []byte, db DB) (interface{}, error) {
 	var arg RegexesTypes
 	err := json.Unmarshal(payload, &arg)
 	arg.Name = name
@@ -148,7 +147,7 @@ func putRegexesType(name string, payload []byte, db *sqlx.DB) (interface{}, erro
 // @Success 200 {array}    RegexesTypes
 // @Resource /api/2.0
 // @Router /api/2.0/regexes_types/{id} [delete]
-func delRegexesType(name string, db *sqlx.DB) (interface{}, error) {
+func delRegexesType(name string, db DB) (interface{}, error) {
 	arg := RegexesTypes{}
 	arg.Name = name
 	result, err := db.NamedExec("DELETE FROM regexes_types WHERE name=:name", arg)
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -42,7 +41,7 @@ type RegionsLinks struct {
 // @Success 200 {array}    Regions
 // @Resource /api/2.0
 // @Router /api/2.0/regions/{id} [get]
-func getRegion(name string, db *sqlx.DB) (interface{}, error) {
+func getRegion(name string, db DB) (interface{}, error) {
 	ret := []Regions{}
 	arg := Regions{}
 	arg.Name = name
@@ -64,7 +63,7 @@ func getRegion(name string, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Regions
 // @Resource /api/2.0
 // @Router /api/2.0/regions [get]
-func getRegions(db *sqlx.DB) (interface{}, error) {
+func getRegions(db DB) (interface{}, error) {
 	ret := []Regions{}
 	queryStr := "select *, concat('" + API_PATH + "regions/', name) as self"
 	queryStr += " from regions"
@@ -83,7 +82,7 @@ func getRegions(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/regions [post]
-func postRegion(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postRegion(payload []byte, db DB) (interface{}, error) {
 	var v Regions
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -115,7 +114,7 @@ func postRegion(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/regions/{id}  [put]
-func putRegion(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putRegion(name string, payload []byte, db DB) (interface{}, error) {
 	var arg Regions
 	err := json.Unmarshal(payload, &arg)
 	arg.Name = name
@@ -143,7 +142,7 @@ func putRegion(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Regions
 // @Resource /api/2.0
 // @Router /api/2.0/regions/{id} [delete]
-func delRegion(name string, db *sqlx.DB) (interface{}, error) {
+func delRegion(name string, db DB) (interface{}, error) {
 	arg := Regions{}
 	arg.Name = name
 	result, err := db.NamedExec("DELETE FROM regions WHERE name=:name", arg)
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	null "gopkg.in/guregu/null.v3"
 	"log"
 )
@@ -47,7 +46,7 @@ type RolesLink struct {
 // @Success 200 {array}    Roles
 // @Resource /api/2.0
 // @Router /api/2.0/roles/{id} [get]
-func getRole(name string, db *sqlx.DB) (interface{}, error) {
+func getRole(name string, db DB) (interface{}, error) {
 	ret := []Roles{}
 	arg := Roles{}
 	arg.Name = name
@@ -69,7 +68,7 @@ func getRole(name string, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Roles
 // @Resource /api/2.0
 // @Router /api/2.0/roles [get]
-func getRoles(db *sqlx.DB) (interface{}, error) {
+func getRoles(db DB) (interface{}, error) {
 	ret := []Roles{}
 	queryStr := "select *, concat('" + API_PATH + "roles/', name) as self"
 	queryStr += " from roles"
@@ -88,7 +87,7 @@ func getRoles(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/roles [post]
-func postRole(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postRole(payload []byte, db DB) (interface{}, error) {
 	var v Roles
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -120,7 +119,7 @@ func postRole(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/roles/{id}  [put]
-func putRole(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putRole(name string, payload []byte, db DB) (interface{}, error) {
 	var arg Roles
 	err := json.Unmarshal(payload, &arg)
 	arg.Name = name
@@ -148,7 +147,7 @@ func putRole(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Roles
 // @Resource /api/2.0
 // @Router /api/2.0/roles/{id} [delete]
-func delRole(name string, db *sqlx.DB) (interface{}, error) {
+func delRole(name string, db DB) (interface{}, error) {
 	arg := Roles{}
 	arg.Name = name
 	result, err := db.NamedExec("DELETE FROM roles WHERE name=:name", arg)
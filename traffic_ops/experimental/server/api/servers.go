@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	null "gopkg.in/guregu/null.v3"
 	"log"
 	"time"
@@ -68,7 +67,7 @@ type ServersLinks struct {
 // @Success 200 {array}    Servers
 // @Resource /api/2.0
 // @Router /api/2.0/servers/{id} [get]
-func GetServer(hostName string, tcpPort int64, db *sqlx.DB) (interface{}, error) {
+func GetServer(hostName string, tcpPort int64, db DB) (interface{}, error) {
 	ret := []Servers{}
 	arg := Servers{}
 	arg.HostName = hostName
@@ -93,7 +92,7 @@ func GetServer(hostName string, tcpPort int64, db *sqlx.DB) (interface{}, error)
 // @Success 200 {array}    Servers
 // @Resource /api/2.0
 // @Router /api/2.0/servers [get]
-func getServers(db *sqlx.DB) (interface{}, error) {
+func getServers(db DB) (interface{}, error) {
 	ret := []Servers{}
 	queryStr := "select *, concat('" + API_PATH + "servers', '/host_name/', host_name, '/tcp_port/', tcp_port) as self"
 	queryStr += ", concat('" + API_PATH + "servers_types/', type) as servers_types_name_ref"
@@ -114,7 +113,7 @@ func getServers(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/servers [post]
-func postServer(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postServer(payload []byte, db DB) (interface{}, error) {
 	var v Servers
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -196,7 +195,7 @@ func postServer(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/servers/{id}  [put]
-func putServer(hostName string, tcpPort int64, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putServer(hostName string, tcpPort int64, payload []byte, db DB) (interface{}, error) {
 	var arg Servers
 	err := json.Unmarshal(payload, &arg)
 	arg.HostName = hostName
@@ -250,7 +249,7 @@ func putServer(hostName string, tcpPort int64, payload []byte, db *sqlx.DB) (int
 // @Success 200 {array}    Servers
 // @Resource /api/2.0
 // @Router /api/2.0/servers/{id} [delete]
-func delServer(hostName string, tcpPort int64, db *sqlx.DB) (interface{}, error) {
+func delServer(hostName string, tcpPort int64, db DB) (interface{}, error) {
 	arg := Servers{}
 	arg.HostName = hostName
 	arg.TcpPort = tcpPort
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -47,7 +46,7 @@ type ServersTypesLink struct {
 // @Success 200 {array}    ServersTypes
 // @Resource /api/2.0
 // @Router /api/2.0/servers_types/{id} [get]
-func getServersType(name string, db *sqlx.DB) (interface{}, error) {
+func getServersType(name string, db DB) (interface{}, error) {
 	ret := []ServersTypes{}
 	arg := ServersTypes{}
 	arg.Name = name
@@ -69,7 +68,7 @@ func getServersType(name string, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    ServersTypes
 // @Resource /api/2.0
 // @Router /api/2.0/servers_types [get]
-func getServersTypes(db *sqlx.DB) (interface{}, error) {
+func getServersTypes(db DB) (interface{}, error) {
 	ret := []ServersTypes{}
 	queryStr := "select *, concat('" + API_PATH + "servers_types/', name) as self"
 	queryStr += " from servers_types"
@@ -88,7 +87,7 @@ func getServersTypes(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/servers_types [post]
-func postServersType(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postServersType(payload []byte, db DB) (interface{}, error) {
 	var v ServersTypes
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -120,7 +119,7 @@ func postServersType(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/servers_types/{id}  [put]
-func putServersType(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putServersType(name string, payload []byte, db DB) (interface{}, error) {
 	var arg ServersTypes
 	err := json.Unmarshal(payload, &arg)
 	arg.Name = name
@@ -148,7 +147,7 @@ func putServersType(name string, payload []byte, db *sqlx.DB) (interface{}, erro
 // @Success 200 {array}    ServersTypes
 // @Resource /api/2.0
 // @Router /api/2.0/servers_types/{id} [delete]
-func delServersType(name string, db *sqlx.DB) (interface{}, error) {
+func delServersType(name string, db DB) (interface{}, error) {
 	arg := ServersTypes{}
 	arg.Name = name
 	result, err := db.NamedExec("DELETE FROM servers_types WHERE name=:name", arg)
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	null "gopkg.in/guregu/null.v3"
 	"log"
 	"time"
@@ -49,7 +48,7 @@ type StaticdnsentriesLinks struct {
 // @Success 200 {array}    Staticdnsentries
 // @Resource /api/2.0
 // @Router /api/2.0/staticdnsentries/{id} [get]
-func getStaticdnsentry(id int64, db *sqlx.DB) (interface{}, error) {
+func getStaticdnsentry(id int64, db DB) (interface{}, error) {
 	ret := []Staticdnsentries{}
 	arg := Staticdnsentries{}
 	arg.Id = id
@@ -72,7 +71,7 @@ func getStaticdnsentry(id int64, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Staticdnsentries
 // @Resource /api/2.0
 // @Router /api/2.0/staticdnsentries [get]
-func getStaticdnsentries(db *sqlx.DB) (interface{}, error) {
+func getStaticdnsentries(db DB) (interface{}, error) {
 	ret := []Staticdnsentries{}
 	queryStr := "select *, concat('" + API_PATH + "staticdnsentries/', id) as self"
 	queryStr += ", concat('" + API_PATH + "staticdnsentries_types/', type) as staticdnsentries_types_name_ref"
@@ -92,7 +91,7 @@ func getStaticdnsentries(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/staticdnsentries [post]
-func postStaticdnsentry(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postStaticdnsentry(payload []byte, db DB) (interface{}, error) {
 	var v Staticdnsentries
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -134,7 +133,7 @@ func postStaticdnsentry(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/staticdnsentries/{id}  [put]
-func putStaticdnsentry(id int64, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putStaticdnsentry(id int64, payload []byte, db DB) (interface{}, error) {
 	var arg Staticdnsentries
 	err := json.Unmarshal(payload, &arg)
 	arg.Id = id
@@ -167,7 +166,7 @@ func putStaticdnsentry(id int64, payload []byte, db *sqlx.DB) (interface{}, erro
 // @Success 200 {array}    Staticdnsentries
 // @Resource /api/2.0
 // @Router /api/2.0/staticdnsentries/{id} [delete]
-func delStaticdnsentry(id int64, db *sqlx.DB) (interface{}, error) {
+func delStaticdnsentry(id int64, db DB) (interface{}, error) {
 	arg := Staticdnsentries{}
 	arg.Id = id
 	result, err := db.NamedExec("DELETE FROM staticdnsentries WHERE id=:id", arg)
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -47,7 +46,7 @@ type StaticdnsentriesTypesLink struct {
 // @Success 200 {array}    StaticdnsentriesTypes
 // @Resource /api/2.0
 // @Router /api/2.0/staticdnsentries_types/{id} [get]
-func getStaticdnsentriesType(name string, db *sqlx.DB) (interface{}, error) {
+func getStaticdnsentriesType(name string, db DB) (interface{}, error) {
 	ret := []StaticdnsentriesTypes{}
 	arg := StaticdnsentriesTypes{}
 	arg.Name = name
@@ -69,7 +68,7 @@ func getStaticdnsentriesType(name string, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    StaticdnsentriesTypes
 // @Resource /api/2.0
 // @Router /api/2.0/staticdnsentries_types [get]
-func getStaticdnsentriesTypes(db *sqlx.DB) (interface{}, error) {
+func getStaticdnsentriesTypes(db DB) (interface{}, error) {
 	ret := []StaticdnsentriesTypes{}
 	queryStr := "select *, concat('" + API_PATH + "staticdnsentries_types/', name) as self"
 	queryStr += " from staticdnsentries_types"
@@ -88,7 +87,7 @@ func getStaticdnsentriesTypes(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/staticdnsentries_types [post]
-func postStaticdnsentriesType(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postStaticdnsentriesType(payload []byte, db DB) (interface{}, error) {
 	var v StaticdnsentriesTypes
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -120,7 +119,7 @@ func postStaticdnsentriesType(payload []byte, db *sqlx.DB) (interface{}, error)
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/staticdnsentries_types/{id}  [put]
-func putStaticdnsentriesType(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putStaticdnsentriesType(name string, payload []byte, db DB) (interface{}, error) {
 	var arg StaticdnsentriesTypes
 	err := json.Unmarshal(payload, &arg)
 	arg.Name = name
@@ -148,7 +147,7 @@ func putStaticdnsentriesType(name string, payload []byte, db *sqlx.DB) (interfac
 // @Success 200 {array}    StaticdnsentriesTypes
 // @Resource /api/2.0
 // @Router /api/2.0/staticdnsentries_types/{id} [delete]
-func delStaticdnsentriesType(name string, db *sqlx.DB) (interface{}, error) {
+func delStaticdnsentriesType(name string, db DB) (interface{}, error) {
 	arg := StaticdnsentriesTypes{}
 	arg.Name = name
 	result, err := db.NamedExec("DELETE FROM staticdnsentries_types WHERE name=:name", arg)
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	"log"
 	"time"
 )
@@ -45,7 +44,7 @@ type StatsSummaryLinks struct {
 // @Success 200 {array}    StatsSummary
 // @Resource /api/2.0
 // @Router /api/2.0/stats_summary/{id} [get]
-func getStatsSummary(cdnName string, deliveryservice string, statName string, statDate time.Time, db *sqlx.DB) (interface{}, error) {
+func getStatsSummary(cdnName string, deliveryservice string, statName string, statDate time.Time, db DB) (interface{}, error) {
 	ret := []StatsSummary{}
 	arg := StatsSummary{}
 	arg.CdnName = cdnName
@@ -70,7 +69,7 @@ func getStatsSummary(cdnName string, deliveryservice string, statName string, st
 // @Success 200 {array}    StatsSummary
 // @Resource /api/2.0
 // @Router /api/2.0/stats_summary [get]
-func getStatsSummaries(db *sqlx.DB) (interface{}, error) {
+func getStatsSummaries(db DB) (interface{}, error) {
 	ret := []StatsSummary{}
 	queryStr := "select *, concat('" + API_PATH + "stats_summary', '/cdn_name/', cdn_name, '/deliveryservice/', deliveryservice, '/stat_name/', stat_name, '/stat_date/', stat_date) as self"
 	queryStr += " from stats_summary"
@@ -89,7 +88,7 @@ func getStatsSummaries(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/stats_summary [post]
-func postStatsSummary(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postStatsSummary(payload []byte, db DB) (interface{}, error) {
 	var v StatsSummary
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -127,7 +126,7 @@ func postStatsSummary(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/stats_summary/{id}  [put]
-func putStatsSummary(cdnName string, deliveryservice string, statName string, statDate time.Time, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putStatsSummary(cdnName string, deliveryservice string, statName string, statDate time.Time, payload []byte, db DB) (interface{}, error) {
 	var arg StatsSummary
 	err := json.Unmarshal(payload, &arg)
 	arg.CdnName = cdnName
@@ -161,7 +160,7 @@ func putStatsSummary(cdnName string, deliveryservice string, statName string, st
 // @Success 200 {array}    StatsSummary
 // @Resource /api/2.0
 // @Router /api/2.0/stats_summary/{id} [delete]
-func delStatsSummary(cdnName string, deliveryservice string, statName string, statDate time.Time, db *sqlx.DB) (interface{}, error) {
+func delStatsSummary(cdnName string, deliveryservice string, statName string, statDate time.Time, db DB) (interface{}, error) {
 	arg := StatsSummary{}
 	arg.CdnName = cdnName
 	arg.Deliveryservice = deliveryservice
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	null "gopkg.in/guregu/null.v3"
 	"log"
 	"time"
@@ -43,7 +42,7 @@ type StatusesLinks struct {
 // @Success 200 {array}    Statuses
 // @Resource /api/2.0
 // @Router /api/2.0/statuses/{id} [get]
-func getStatus(name string, db *sqlx.DB) (interface{}, error) {
+func getStatus(name string, db DB) (interface{}, error) {
 	ret := []Statuses{}
 	arg := Statuses{}
 	arg.Name = name
@@ -65,7 +64,7 @@ func getStatus(name string, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Statuses
 // @Resource /api/2.0
 // @Router /api/2.0/statuses [get]
-func getStatuses(db *sqlx.DB) (interface{}, error) {
+func getStatuses(db DB) (interface{}, error) {
 	ret := []Statuses{}
 	queryStr := "select *, concat('" + API_PATH + "statuses/', name) as self"
 	queryStr += " from statuses"
@@ -84,7 +83,7 @@ func getStatuses(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/statuses [post]
-func postStatus(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postStatus(payload []byte, db DB) (interface{}, error) {
 	var v Statuses
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -116,7 +115,7 @@ func postStatus(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/statuses/{id}  [put]
-func putStatus(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putStatus(name string, payload []byte, db DB) (interface{}, error) {
 	var arg Statuses
 	err := json.Unmarshal(payload, &arg)
 	arg.Name = name
@@ -144,7 +143,7 @@ func putStatus(name string, payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Statuses
 // @Resource /api/2.0
 // @Router /api/2.0/statuses/{id} [delete]
-func delStatus(name string, db *sqlx.DB) (interface{}, error) {
+func delStatus(name string, db DB) (interface{}, error) {
 	arg := Statuses{}
 	arg.Name = name
 	result, err := db.NamedExec("DELETE FROM statuses WHERE name=:name", arg)
@@ -19,7 +19,6 @@ package api
 import (
 	"encoding/json"
 	_ "github.com/apache/incubator-trafficcontrol/traffic_ops/experimental/server/output_format" // needed for swagger
-	"github.com/jmoiron/sqlx"
 	null "gopkg.in/guregu/null.v3"
 	"log"
 	"time"
@@ -47,7 +46,7 @@ type UsersLinks struct {
 // @Success 200 {array}    Users
 // @Resource /api/2.0
 // @Router /api/2.0/users/{id} [get]
-func GetUser(username string, db *sqlx.DB) (interface{}, error) {
+func GetUser(username string, db DB) (interface{}, error) {
 	ret := []Users{}
 	arg := Users{}
 	arg.Username = username
@@ -70,7 +69,7 @@ func GetUser(username string, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {array}    Users
 // @Resource /api/2.0
 // @Router /api/2.0/users [get]
-func getUsers(db *sqlx.DB) (interface{}, error) {
+func getUsers(db DB) (interface{}, error) {
 	ret := []Users{}
 	queryStr := "select *, concat('" + API_PATH + "users/', username) as self"
 	queryStr += ", concat('" + API_PATH + "roles/', role) as roles_name_ref"
@@ -90,7 +89,7 @@ func getUsers(db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/users [post]
-func postUser(payload []byte, db *sqlx.DB) (interface{}, error) {
+func postUser(payload []byte, db DB) (interface{}, error) {
 	var v Users
 	err := json.Unmarshal(payload, &v)
 	if err != nil {
@@ -130,7 +129,7 @@ func postUser(payload []byte, db *sqlx.DB) (interface{}, error) {
 // @Success 200 {object}    output_format.ApiWrapper
 // @Resource /api/2.0
 // @Router /api/2.0/users/{id}  [put]
-func putUser(username string, payload []byte, db *sqlx.DB) (interface{}, error) {
+func putUser(username string, payload []byte, db DB) (interface{}, error) {
 	var arg Users
 	err := json.Unmarshal(payload, &arg)
 	arg.Username = username
@@ -162,7 +161,7 @@ func putUser(username string, payload []byte, db *sqlx.DB) (interface{}, error)
 // @Success 200 {array}    Users
 // @Resource /api/2.0
 // @Router /api/2.0/users/{id} [delete]
-func delUser(username string, db *sqlx.DB) (interface{}, error) {
+func delUser(username string, db DB) (interface{}, error) {
 	arg := Users{}
 	arg.Username = username
 	result, err := db.NamedExec("DELETE FROM users WHERE username=:username", arg)
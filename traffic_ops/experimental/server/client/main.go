@@ -13,46 +13,63 @@
    limitations under the License.
 */
 
+// This is an example of using the Traffic Ops client with the 2.0 API of the Traffic Ops 2.0 server.
+//
+// Usage: go run main.go <url> <user> <password>
 package main
 
 import (
-	"./client"
+	"context"
 	"fmt"
 	"os"
+
+	"github.com/apache/incubator-trafficcontrol/traffic_ops/client"
 )
 
+type asn struct {
+	ASN        int64 `json:"asn"`
+	Cachegroup int64 `json:"cachegroup"`
+}
+
 func main() {
-	client, err := client.Login(os.Args[1], os.Args[2], os.Args[3], true)
+	if len(os.Args) < 4 {
+		fmt.Println("usage: " + os.Args[0] + " <url> <user> <password>")
+		os.Exit(1)
+	}
+	ctx := context.Background()
+	to, err := client.LoginWithToken(ctx, os.Args[1], os.Args[2], os.Args[3], true, "traffic-ops-2-client-example", false, client.DefaultTimeout)
 	if err != nil {
 		fmt.Println("err 00", err)
+		os.Exit(1)
 	}
 
-	resp, err := client.GetText("/api/2.0/asn")
-	if err != nil {
+	asns := []map[string]interface{}{}
+	if err := to.GetTable(ctx, "asn", &asns); err != nil {
 		fmt.Println("err 11:", err)
 	}
-	fmt.Println(resp)
-	ret, e := client.PostJson("/api/2.0/asn", []byte("{\"asn\":45454, \"cachegroup\":28}"))
-	if e != nil {
-		fmt.Println("err 22:", e)
+	fmt.Println(asns)
+
+	alerts, err := to.CreateTableRow(ctx, "asn", asn{ASN: 45454, Cachegroup: 28})
+	if err != nil {
+		fmt.Println("err 22:", err)
 	}
-	fmt.Println(string(ret))
+	fmt.Println(alerts)
 
-	ret, e = client.PutJson("/api/2.0/asn/51", []byte("{\"asn\":33933, \"cachegroup\":28}"))
-	if e != nil {
-		fmt.Println("err 32:", e)
+	alerts, err = to.UpdateTableRow(ctx, "asn", "51", asn{ASN: 33933, Cachegroup: 28})
+	if err != nil {
+		fmt.Println("err 32:", err)
 	}
-	fmt.Println(string(ret))
+	fmt.Println(alerts)
 
-	ret, e = client.Delete("/api/2.0/asn/52")
-	if e != nil {
-		fmt.Println("err 42:", e)
+	alerts, err = to.DeleteTableRow(ctx, "asn", "52")
+	if err != nil {
+		fmt.Println("err 42:", err)
 	}
-	fmt.Println(string(ret))
+	fmt.Println(alerts)
 
-	ret, e = client.Delete("/api/2.0/cachegroup/3")
-	if e != nil {
-		fmt.Println("err 52:", e)
+	alerts, err = to.DeleteTableRow(ctx, "cachegroup", "3")
+	if err != nil {
+		fmt.Println("err 52:", err)
 	}
-	fmt.Println(string(ret))
+	fmt.Println(alerts)
 }
@@ -39,6 +39,8 @@ func CreateRouter(db *sqlx.DB) http.Handler {
 	router := mux.NewRouter().StrictSlash(true)
 	router.HandleFunc(apiPath+"login", wrapHeaders(auth.GetLoginOptionsFunc(), []api.ApiMethod{api.OPTIONS, api.POST})).Methods("OPTIONS")
 	router.HandleFunc(apiPath+"login", wrapHeaders(auth.GetLoginFunc(db), []api.ApiMethod{api.OPTIONS, api.POST})).Methods("POST")
+	router.HandleFunc(apiPath+"bulk", func(w http.ResponseWriter, r *http.Request) { setHeaders(w, bulkMethods) }).Methods("OPTIONS")
+	router.HandleFunc(apiPath+"bulk", auth.Use(getHandleBulkFunc(db), auth.RequireLogin)).Methods("POST")
 	router.HandleFunc(apiPath+"{table}", auth.Use(optionsHandler, auth.DONTRequireLogin)).Methods("OPTIONS")
 	router.HandleFunc(apiPath+"{table}/{id}", auth.Use(optionsHandler, auth.DONTRequireLogin)).Methods("OPTIONS")
 	router.HandleFunc(apiPath+"config/cr/{cdn}/CRConfig.json", auth.Use(getHandleCRConfigFunc(db), auth.RequireLogin))
@@ -112,6 +114,45 @@ func addApiHandlers(router *mux.Router, db *sqlx.DB) {
 	}
 }
 
+var bulkMethods = api.ApiMethods{api.OPTIONS, api.POST}
+
+// getHandleBulkFunc returns a func which handles requests to the bulk endpoint, executing
+// the requested list of operations in one transaction, and writing the result of each.
+//
+// The operations are all applied, or, if any fails, none are. Either way the response
+// is the list of api.BulkResult, with an error alert saying which operation failed.
+// Invalid or failed operations are a Bad Request, and a failed transaction is an
+// Internal Server Error.
+func getHandleBulkFunc(db *sqlx.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		setHeaders(w, bulkMethods)
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+
+		ops := []api.BulkOperation{}
+		if err := json.NewDecoder(r.Body).Decode(&ops); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			enc.Encode(output.MakeApiResponse(nil, nil, fmt.Errorf("decoding bulk operations: %v", err)))
+			return
+		}
+
+		results, err := api.Bulk(ops, db)
+		var alerts []output.Alert
+		if err != nil {
+			log.Println(err)
+			alerts = output.MakeAlert(err.Error(), "error")
+			if _, ok := err.(*api.BulkTxError); ok {
+				w.WriteHeader(http.StatusInternalServerError)
+			} else {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		} else {
+			alerts = output.MakeAlert(strconv.Itoa(len(results))+" operations applied.", "success")
+		}
+		enc.Encode(output.ApiWrapper{Resp: results, Version: output.APIVERSION, Alerts: alerts})
+	}
+}
+
 func getCrconfigSnapshot(cdn string, db *sqlx.DB) (string, error) {
 	queryStr := `select snapshot from crconfig_snapshots where cdn = $1 and created_at = (select max(created_at) created_at from crconfig_snapshots where cdn = $1);`
 	rows, err := db.Query(queryStr, cdn)
@@ -127,7 +127,6 @@ func writeFile(schemas []ColumnSchema, table string) (int, error) {
 	header := "package " + config.PkgName + "\n\n"
 	header += "import (\n"
 	header += "\"log\"\n"
-	header += "\"github.com/jmoiron/sqlx\"\n"
 
 	sString := structString(schemas, table)
 
@@ -335,7 +334,7 @@ func handleString(schemas []ColumnSchema, table string) string {
 	out += "// @Success 200 {array}    " + formatName(table) + "\n"
 	out += "// @Resource /api/2.0\n"
 	out += "// @Router /api/2.0/" + table + "/{id} [get]\n"
-	out += "func get" + inflector.Singularize(formatName(table)) + "(" + getPkGoFuncParamString(pk) + ", db *sqlx.DB) (interface{}, error) {\n"
+	out += "func get" + inflector.Singularize(formatName(table)) + "(" + getPkGoFuncParamString(pk) + ", db DB) (interface{}, error) {\n"
 	out += "    ret := []" + formatName(table) + "{}\n"
 	out += "    arg := " + formatName(table) + "{}\n"
 	out += setStructPkFields(pk)
@@ -358,7 +357,7 @@ func handleString(schemas []ColumnSchema, table string) string {
 	out += "// @Success 200 {array}    " + formatName(table) + "\n"
 	out += "// @Resource /api/2.0\n"
 	out += "// @Router /api/2.0/" + table + " [get]\n"
-	out += "func get" + inflector.Pluralize(formatName(table)) + "(db *sqlx.DB) (interface{}, error) {\n"
+	out += "func get" + inflector.Pluralize(formatName(table)) + "(db DB) (interface{}, error) {\n"
 	out += "    ret := []" + formatName(table) + "{}\n"
 	out += "    queryStr := \"select *, " + selfQueryStr(pk, table) + "\"\n"
 	out += setFkHALQueryStr(schemas, table)
@@ -378,7 +377,7 @@ func handleString(schemas []ColumnSchema, table string) string {
 	out += "// @Success 200 {object}    output_format.ApiWrapper\n"
 	out += "// @Resource /api/2.0\n"
 	out += "// @Router /api/2.0/" + table + " [post]\n"
-	out += "func post" + inflector.Singularize(formatName(table)) + "(payload []byte, db *sqlx.DB) (interface{}, error) {\n"
+	out += "func post" + inflector.Singularize(formatName(table)) + "(payload []byte, db DB) (interface{}, error) {\n"
 	out += "	var v " + formatName(table) + "\n"
 	out += "	err := json.Unmarshal(payload, &v)\n"
 	out += "	if err != nil {\n"
@@ -402,7 +401,7 @@ func handleString(schemas []ColumnSchema, table string) string {
 	out += "// @Success 200 {object}    output_format.ApiWrapper\n"
 	out += "// @Resource /api/2.0\n"
 	out += "// @Router /api/2.0/" + table + "/{id}  [put]\n"
-	out += "func put" + inflector.Singularize(formatName(table)) + "(" + getPkGoFuncParamString(pk) + ", payload []byte, db *sqlx.DB) (interface{}, error) {\n"
+	out += "func put" + inflector.Singularize(formatName(table)) + "(" + getPkGoFuncParamString(pk) + ", payload []byte, db DB) (interface{}, error) {\n"
 	out += "    var arg " + formatName(table) + "\n"
 	out += "    err := json.Unmarshal(payload, &arg)\n"
 	out += setStructPkFields(pk)
@@ -429,7 +428,7 @@ func handleString(schemas []ColumnSchema, table string) string {
 	out += "// @Success 200 {array}    " + formatName(table) + "\n"
 	out += "// @Resource /api/2.0\n"
 	out += "// @Router /api/2.0/" + table + "/{id} [delete]\n"
-	out += "func del" + inflector.Singularize(formatName(table)) + "(" + getPkGoFuncParamString(pk) + ", db *sqlx.DB) (interface{}, error) {\n"
+	out += "func del" + inflector.Singularize(formatName(table)) + "(" + getPkGoFuncParamString(pk) + ", db DB) (interface{}, error) {\n"
 	out += "    arg := " + formatName(table) + "{}\n"
 	out += setStructPkFields(pk)
 	out += "    result, err := db.NamedExec(\"DELETE FROM " + table + " " + pkWhereStr(pk) + "\", arg)\n"
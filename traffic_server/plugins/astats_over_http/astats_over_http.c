@@ -337,31 +337,75 @@ static int getSpeed(char *interface, char *buffer, int bufferSize) {
 	return speed;
 }
 
+// appendInterfaceProcNetDev appends the /proc/net/dev line of the given interface in procNetDev to out, separated from any previous lines by an escaped newline. The line must start with the whole interface name, after any spaces, followed by a ':', so e.g. eth1 doesn't match the eth10 line.
+static void appendInterfaceProcNetDev(char *procNetDev, char *interface, char *out, int outSize) {
+	char *str;
+	char *end;
+	int len;
+	int infLen = strlen(interface);
+
+	for (str = procNetDev; str; str = end ? end + 1 : NULL) {
+		end = strstr(str, "\n");
+		while (*str == ' ')
+			str++;
+		if (strncmp(str, interface, infLen) == 0 && str[infLen] == ':')
+			break;
+	}
+	if (!str)
+		return;
+	end = strstr(str, "\n");
+	len = end ? end - str : strlen(str);
+	if (strlen(out) + len + 3 >= outSize)
+		return;
+	if (out[0])
+		strcat(out, "\\n");
+	strncat(out, str, len);
+}
+
 static void appendSystemState(stats_state *my_state) {
 	char *interface = my_state->interfaceName;
 	char buffer[2024];
 	int bsize = 2024;
+	char procNetDev[2024];
+	char speeds[1024];
+	char b[256];
+	char interfaces[256];
+	char *inf;
+	char *saveptr;
 	char *str;
 	char *end;
 	int speed = 0;
+	int infSpeed = 0;
 
 	APPEND_STAT("inf.name", "\"%s\"", interface);
 
-	speed = getSpeed(interface, buffer, bsize);
-
-	APPEND_STAT("inf.speed", "%d", speed);
+	// inf.name may be a comma-delimited list of interfaces, whose speeds are summed, and whose proc.net.dev lines are all returned.
+	procNetDev[0] = 0;
+	speeds[0] = 0;
+	if (interface) {
+		snprintf(interfaces, sizeof(interfaces), "%s", interface);
+		str = getFile("/proc/net/dev", buffer, bsize);
+		for (inf = strtok_r(interfaces, ",", &saveptr); inf; inf = strtok_r(NULL, ",", &saveptr)) {
+			if (str)
+				appendInterfaceProcNetDev(str, inf, procNetDev, sizeof(procNetDev));
+		}
 
-	str = getFile("/proc/net/dev", buffer, bsize);
-	if (str && interface) {
-		str = strstr(str, interface);
-		if (str) {
-			end = strstr(str, "\n");
-			if (end)
-				*end = 0;
-			APPEND_STAT("proc.net.dev", "\"%s\"", str);
+		snprintf(interfaces, sizeof(interfaces), "%s", interface);
+		for (inf = strtok_r(interfaces, ",", &saveptr); inf; inf = strtok_r(NULL, ",", &saveptr)) {
+			infSpeed = getSpeed(inf, buffer, bsize);
+			speed += infSpeed;
+			snprintf(b, sizeof(b), "%s%s:%d", speeds[0] ? "," : "", inf, infSpeed);
+			if (strlen(speeds) + strlen(b) < sizeof(speeds))
+				strcat(speeds, b);
 		}
 	}
 
+	APPEND_STAT("inf.speed", "%d", speed);
+	APPEND_STAT("inf.speeds", "\"%s\"", speeds);
+
+	if (procNetDev[0])
+		APPEND_STAT("proc.net.dev", "\"%s\"", procNetDev);
+
 	str = getFile("/proc/loadavg", buffer, bsize);
 	if (str) {
 		end = strstr(str, "\n");
@@ -20,6 +20,7 @@ under the License.
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -65,6 +66,8 @@ type StartupConfig struct {
 	ToUser                      string   `json:"toUser"`
 	ToPasswd                    string   `json:"toPasswd"`
 	ToURL                       string   `json:"toUrl"`
+	ToAPIKey                    string   `json:"toApiKey"`
+	ToCredentialsFile           string   `json:"toCredentialsFile"`
 	InfluxUser                  string   `json:"influxUser"`
 	InfluxPassword              string   `json:"influxPassword"`
 	InfluxURLs                  []string `json:"influxUrls"`
@@ -414,14 +417,28 @@ func queryDB(con influx.Client, cmd string, database string) (res []influx.Resul
 	return
 }
 
+// loginToTrafficOps logs in to Traffic Ops with the credentials in the config, overridden by those in the credentials file, if there is one, and then by those in the TRAFFIC_OPS_ environment variables.
+func loginToTrafficOps(config StartupConfig) (*traffic_ops.Session, error) {
+	auth := traffic_ops.AuthConfig{URL: config.ToURL, User: config.ToUser, Password: config.ToPasswd, APIKey: config.ToAPIKey}
+	if config.ToCredentialsFile != "" {
+		fileAuth, err := traffic_ops.LoadAuthConfig(config.ToCredentialsFile)
+		if err != nil {
+			return nil, err
+		}
+		auth = auth.Override(fileAuth)
+	}
+	auth = auth.Override(traffic_ops.AuthConfigFromEnv())
+	return traffic_ops.LoginWithAuthConfig(context.Background(), auth, true, UserAgent, false, TrafficOpsRequestTimeout)
+}
+
 func writeSummaryStats(config StartupConfig, statsSummary traffic_ops.StatsSummary) {
-	to, err := traffic_ops.LoginWithAgent(config.ToURL, config.ToUser, config.ToPasswd, true, UserAgent, false, TrafficOpsRequestTimeout)
+	to, err := loginToTrafficOps(config)
 	if err != nil {
 		newErr := fmt.Errorf("Could not store summary stats! Error logging in to %v: %v", config.ToURL, err)
 		log.Error(newErr)
 		return
 	}
-	err = to.AddSummaryStats(statsSummary)
+	err = to.AddSummaryStats(context.Background(), statsSummary)
 	if err != nil {
 		log.Error(err)
 	}
@@ -429,7 +446,7 @@ func writeSummaryStats(config StartupConfig, statsSummary traffic_ops.StatsSumma
 
 func getToData(config StartupConfig, init bool, configChan chan RunningConfig) {
 	var runningConfig RunningConfig
-	to, err := traffic_ops.LoginWithAgent(config.ToURL, config.ToUser, config.ToPasswd, true, UserAgent, false, TrafficOpsRequestTimeout)
+	to, err := loginToTrafficOps(config)
 	if err != nil {
 		msg := fmt.Sprintf("Error logging in to %v: %v", config.ToURL, err)
 		if init {
@@ -439,7 +456,7 @@ func getToData(config StartupConfig, init bool, configChan chan RunningConfig) {
 		return
 	}
 
-	servers, err := to.Servers()
+	servers, err := to.Servers(context.Background())
 	if err != nil {
 		msg := fmt.Sprintf("Error getting server list from %v: %v ", config.ToURL, err)
 		if init {
@@ -456,7 +473,7 @@ func getToData(config StartupConfig, init bool, configChan chan RunningConfig) {
 
 	cacheStatPath := "/publish/CacheStats?hc=1&stats="
 	dsStatPath := "/publish/DsStats?hc=1&wildcard=1&stats="
-	parameters, err := to.Parameters("TRAFFIC_STATS")
+	parameters, err := to.Parameters(context.Background(), "TRAFFIC_STATS")
 	if err != nil {
 		msg := fmt.Sprintf("Error getting parameter list from %v: %v", config.ToURL, err)
 		if init {
@@ -501,7 +518,7 @@ func getToData(config StartupConfig, init bool, configChan chan RunningConfig) {
 		}
 	}
 
-	lastSummaryTimeStr, err := to.SummaryStatsLastUpdated("daily_maxgbps")
+	lastSummaryTimeStr, err := to.SummaryStatsLastUpdated(context.Background(), "daily_maxgbps")
 	if err != nil {
 		errHndlr(err, ERROR)
 	} else {